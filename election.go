@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	configLoader "github.com/edgexfoundry/device-sdk-go/internal/config"
+	"github.com/edgexfoundry/device-sdk-go/internal/registry"
+)
+
+// defaultElectionInterval is the default time between the updates of the
+// election groups.
+const defaultElectionInterval = 10 * time.Second
+
+// elector campaigns for the leadership of the election groups of the
+// Devices in cache, see common.ElectionGroup.
+type elector struct {
+	client    registry.Client
+	prefix    string
+	ttl       string
+	stop      chan struct{}
+	campaigns map[string]chan struct{}
+}
+
+// initLeaderElection starts campaigning for the Devices, if the leader
+// election is enabled, until the Service is stopped.
+func (s *Service) initLeaderElection() {
+	cfg := common.CurrentConfig.LeaderElection
+	if !cfg.Enabled {
+		return
+	}
+	if !common.UseRegistry || configLoader.RegistryClient == nil {
+		common.LoggingClient.Warn("Leader election requires the registry, all devices are polled")
+		return
+	}
+
+	prefix := cfg.KeyPrefix
+	if prefix == "" {
+		prefix = "leader/" + common.ServiceName + "/"
+	}
+	interval := time.Duration(cfg.Interval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultElectionInterval
+	}
+
+	common.EnableLeaderElection(true)
+	e := &elector{
+		client:    configLoader.RegistryClient,
+		prefix:    prefix,
+		ttl:       cfg.SessionTTL,
+		stop:      make(chan struct{}),
+		campaigns: make(map[string]chan struct{}),
+	}
+	s.electionStop = e.stop
+	go e.run(interval)
+}
+
+// run updates the campaigns every interval, until stopped.
+func (e *elector) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.update()
+		select {
+		case <-e.stop:
+			for group, stop := range e.campaigns {
+				close(stop)
+				delete(e.campaigns, group)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update campaigns for the election groups of new Devices, and withdraws
+// from those left without Devices.
+func (e *elector) update() {
+	groups := make(map[string]bool)
+	for _, d := range cache.Devices().All() {
+		groups[common.ElectionGroup(d)] = true
+	}
+
+	for group := range groups {
+		if _, ok := e.campaigns[group]; ok {
+			continue
+		}
+		stop := make(chan struct{})
+		e.campaigns[group] = stop
+		go e.campaign(group, stop)
+	}
+	for group, stop := range e.campaigns {
+		if !groups[group] {
+			close(stop)
+			delete(e.campaigns, group)
+		}
+	}
+}
+
+func (e *elector) campaign(group string, stop <-chan struct{}) {
+	e.client.Campaign(e.prefix+group, e.ttl, stop, func(leader bool) {
+		common.SetLeader(group, leader)
+		if leader {
+			common.LoggingClient.Info(fmt.Sprintf("Elected leader of %s", group))
+		} else {
+			common.LoggingClient.Info(fmt.Sprintf("No longer leader of %s", group))
+		}
+	})
+}
@@ -0,0 +1,26 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package provides an example of a device service delegating the
+// protocol to an external tool, configured by the Tool settings of the
+// Driver section. Its res directory runs a stub tool, res/cli-tool.sh,
+// storing the values written in its working directory.
+package main
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/example/driver"
+	"github.com/edgexfoundry/device-sdk-go/pkg/startup"
+)
+
+const (
+	version     string = "0.1"
+	serviceName string = "device-cli"
+)
+
+func main() {
+	d := driver.CLIDriver{}
+	startup.Bootstrap(serviceName, version, &d)
+}
@@ -0,0 +1,59 @@
+#!/bin/sh
+#
+# Copyright (C) 2018 IOTech Ltd
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Stub of the external tool of device-cli, storing the values written in
+# its working directory instead of talking to a real bus:
+#
+#   cli-tool.sh read address=<address> resource=<resource> type=<type>
+#   cli-tool.sh write address=<address> resource=<resource> type=<type>
+#
+# A read prints the value on its standard output, a write reads it from its
+# standard input. The exit code is 1 for an exception of the Device (code
+# and description on the standard error), 2 for an offline Device and 3
+# for an unsupported operation. The address "offline" is never reachable.
+
+op=
+address=
+resource=
+for arg in "$@"; do
+	case "$arg" in
+	address=*) address=${arg#address=} ;;
+	resource=*) resource=${arg#resource=} ;;
+	*=*) ;;
+	*) op=$arg ;;
+	esac
+done
+
+# the values end up in file names, so only plain names are accepted
+case "$address$resource" in
+''|*[!A-Za-z0-9._-]*|.*)
+	echo "0x02 Illegal Data Address" >&2
+	exit 1
+	;;
+esac
+if [ "$address" = "offline" ]; then
+	echo "no response from $address" >&2
+	exit 2
+fi
+
+file="cli-$address-$resource"
+case "$op" in
+read)
+	if [ -f "$file" ]; then
+		cat "$file"
+	else
+		echo 0
+	fi
+	;;
+write)
+	read -r value || true
+	echo "$value" > "$file"
+	;;
+*)
+	echo "unsupported operation $op" >&2
+	exit 3
+	;;
+esac
@@ -0,0 +1,25 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package provides an example of a Modbus device service, for the TCP
+// servers and the RTU devices of serial buses, configured by the settings
+// of the Driver section.
+package main
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/example/driver"
+	"github.com/edgexfoundry/device-sdk-go/pkg/startup"
+)
+
+const (
+	version     string = "0.1"
+	serviceName string = "device-modbus"
+)
+
+func main() {
+	d := driver.ModbusDriver{}
+	startup.Bootstrap(serviceName, version, &d)
+}
@@ -0,0 +1,25 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package hosts the simple example driver in a separate process, e.g.
+// started by: device-simple -driver exec:device-simple-driver
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/edgexfoundry/device-sdk-go/example/driver"
+	"github.com/edgexfoundry/device-sdk-go/pkg/driverhost"
+)
+
+func main() {
+	sd := driver.SimpleDriver{}
+	if err := driverhost.Serve(&sd); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,361 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Settings of the CLIDriver in the Driver section of the configuration.
+const (
+	// CLITool is the path of the tool, e.g. "/usr/bin/modbus-cli", relative
+	// to the working directory of the DS unless absolute.
+	CLITool = "Tool"
+	// CLIToolArgs are the space separated arguments preceding the
+	// operation, e.g. "--serial /dev/ttyUSB0 --baud 9600".
+	CLIToolArgs = "ToolArgs"
+	// CLIToolTimeout is the time (in milliseconds) given to a run of the
+	// tool before it is killed.
+	CLIToolTimeout = "ToolTimeout"
+	// CLIToolMaxOutput is the maximum size (in bytes) of the output of a
+	// run of the tool.
+	CLIToolMaxOutput = "ToolMaxOutput"
+	// CLIToolMaxRuns is the maximum number of runs of the tool at once.
+	CLIToolMaxRuns = "ToolMaxRuns"
+	// CLIToolWorkDir is the working directory of the tool, the state
+	// directory of the driver by default.
+	CLIToolWorkDir = "ToolWorkDir"
+)
+
+const (
+	defaultToolTimeout   = 5000
+	defaultToolMaxOutput = 4096
+	defaultToolMaxRuns   = 4
+)
+
+// Exit codes of the tool, the other non-zero codes being plain failures.
+const (
+	// exitException is an exception of the Device, the first line of the
+	// standard error being its code then its description, e.g.
+	// "0x02 Illegal Data Address".
+	exitException = 1
+	// exitOffline is a Device which can't be reached.
+	exitOffline = 2
+	// exitNotSupported is an operation the tool doesn't support.
+	exitNotSupported = 3
+)
+
+// errStopped is the error of the runs of the tool killed by Stop.
+var errStopped = errors.New("CLIDriver: stopped")
+
+// toolEnv is the whole environment of the tool, so that it neither
+// inherits the credentials of the DS nor prints localized numbers.
+var toolEnv = []string{"PATH=/usr/local/bin:/usr/bin:/bin", "LC_ALL=C"}
+
+// CLIDriver is an example of a driver delegating the protocol to an
+// external tool, e.g. an existing C utility for an exotic bus, run once
+// per resource read or written. The tool is run without a shell, with the
+// arguments
+//
+//	<Tool> <ToolArgs> read address=<address> resource=<resource> type=<type>
+//	<Tool> <ToolArgs> write address=<address> resource=<resource> type=<type>
+//
+// where address is the Address of the Addressable of the Device, resource
+// the "register" attribute of the device resource (its name by default)
+// and type the Type of its value, e.g. Uint16. A read prints the value on
+// the first line of its standard output, a write reads it from its standard
+// input, so that no value read from the network ends up as an argument.
+// The exit code tells the failures apart, see exitException.
+type CLIDriver struct {
+	lc        logger.LoggingClient
+	tool      string
+	args      []string
+	timeout   time.Duration
+	maxOutput int
+	workDir   string
+	runs      chan struct{}
+	// stopped is canceled by Stop, killing the runs in progress.
+	stopped context.Context
+	stop    context.CancelFunc
+}
+
+// Initialize initializes the driver with the default settings, which
+// requires the Tool setting; see InitializeWithContext.
+func (d *CLIDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return d.InitializeWithContext(ds_models.DriverContext{Logger: lc, AsyncCh: asyncCh})
+}
+
+// InitializeWithContext initializes the driver with the settings of the
+// Driver section of the configuration.
+func (d *CLIDriver) InitializeWithContext(ctx ds_models.DriverContext) error {
+	d.lc = ctx.Logger
+	d.tool = ctx.Config[CLITool]
+	if d.tool == "" {
+		return fmt.Errorf("CLIDriver: no %s configured", CLITool)
+	}
+	// the tool isn't looked up in the PATH, lest another one be run
+	tool, err := filepath.Abs(d.tool)
+	if err != nil {
+		return fmt.Errorf("CLIDriver: %v", err)
+	}
+	if _, err = os.Stat(tool); err != nil {
+		return fmt.Errorf("CLIDriver: %v", err)
+	}
+	d.tool = tool
+	d.args = strings.Fields(ctx.Config[CLIToolArgs])
+
+	timeout, err := intSetting(ctx.Config, CLIToolTimeout, defaultToolTimeout)
+	if err != nil {
+		return err
+	}
+	d.timeout = time.Duration(timeout) * time.Millisecond
+	if d.maxOutput, err = intSetting(ctx.Config, CLIToolMaxOutput, defaultToolMaxOutput); err != nil {
+		return err
+	}
+	maxRuns, err := intSetting(ctx.Config, CLIToolMaxRuns, defaultToolMaxRuns)
+	if err != nil {
+		return err
+	}
+	d.runs = make(chan struct{}, maxRuns)
+
+	d.workDir = ctx.Config[CLIToolWorkDir]
+	if d.workDir == "" {
+		d.workDir = ctx.StateDir
+	}
+	if d.workDir == "" {
+		d.workDir = os.TempDir()
+	}
+	d.stopped, d.stop = context.WithCancel(context.Background())
+	return nil
+}
+
+// intSetting returns a positive integer setting, def if not configured.
+func intSetting(config map[string]string, name string, def int) (int, error) {
+	s, ok := config[name]
+	if !ok || s == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("CLIDriver: invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+// DisconnectDevice handles protocol-specific cleanup when a device
+// is removed.
+func (d *CLIDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+// HandleReadCommands runs the tool for each resource read.
+func (d *CLIDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	res := make([]*ds_models.CommandValue, len(reqs))
+	for i := range reqs {
+		t, err := ds_models.ParseValueType(reqs[i].DeviceObject.Properties.Value.Type)
+		if err != nil {
+			return nil, ds_models.NewDriverError(ds_models.ErrorNotSupported, err)
+		}
+		out, err := d.run(d.toolArgs("read", addr, reqs[i], t), nil)
+		if err != nil {
+			return nil, err
+		}
+		line, _ := bufio.NewReader(bytes.NewReader(out)).ReadString('\n')
+		now := time.Now().UnixNano() / int64(time.Millisecond)
+		if res[i], err = parseToolValue(strings.TrimSpace(line), t, &reqs[i].RO, now); err != nil {
+			return nil, fmt.Errorf("CLIDriver: unexpected output of %s for %s: %v", d.tool, reqs[i].DeviceObject.Name, err)
+		}
+	}
+	return res, nil
+}
+
+// HandleWriteCommands runs the tool for each resource written, passing the
+// value on its standard input.
+func (d *CLIDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest,
+	params []*ds_models.CommandValue) error {
+
+	if len(reqs) != len(params) {
+		return fmt.Errorf("CLIDriver.HandleWriteCommands: %d parameters for %d command requests", len(params), len(reqs))
+	}
+	for i := range reqs {
+		input := params[i].ValueToString() + "\n"
+		if _, err := d.run(d.toolArgs("write", addr, reqs[i], params[i].Type), strings.NewReader(input)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toolArgs returns the arguments of a run of the tool. They are passed as
+// name=value so that none can be mistaken for an option.
+func (d *CLIDriver) toolArgs(op string, addr *models.Addressable, req ds_models.CommandRequest, t ds_models.ValueType) []string {
+	resource := req.DeviceObject.Name
+	if v, ok := req.DeviceObject.Attributes["register"]; ok && v != nil {
+		resource = fmt.Sprint(v)
+	}
+	args := append([]string{}, d.args...)
+	return append(args, op, "address="+addr.Address, "resource="+resource, "type="+t.Name())
+}
+
+// run runs the tool with args and returns its standard output, killing it
+// after the timeout or when the driver stops. The runs are bounded by
+// ToolMaxRuns.
+func (d *CLIDriver) run(args []string, stdin *strings.Reader) ([]byte, error) {
+	select {
+	case d.runs <- struct{}{}:
+	case <-d.stopped.Done():
+		return nil, errStopped
+	}
+	defer func() { <-d.runs }()
+
+	ctx, cancel := context.WithTimeout(d.stopped, d.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.tool, args...)
+	cmd.Env = toolEnv
+	cmd.Dir = d.workDir
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	stdout := &limitedBuffer{max: d.maxOutput}
+	stderr := &limitedBuffer{max: d.maxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	d.lc.Debug(fmt.Sprintf("CLIDriver: running %s %v", d.tool, args))
+	err := cmd.Run()
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return nil, ds_models.NewDriverError(ds_models.ErrorTimeout, fmt.Errorf("%s killed after %v", d.tool, d.timeout))
+	case context.Canceled:
+		return nil, errStopped
+	}
+	if err != nil {
+		return nil, toolError(err, stderr.String())
+	}
+	if stdout.overflow {
+		return nil, fmt.Errorf("CLIDriver: the output of %s exceeds %d bytes", d.tool, d.maxOutput)
+	}
+	return stdout.Bytes(), nil
+}
+
+// toolError returns the error of a failed run of the tool, given its
+// standard error.
+func toolError(err error, stderr string) error {
+	msg := strings.TrimSpace(stderr)
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return err
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return err
+	}
+	switch status.ExitStatus() {
+	case exitException:
+		code, description := msg, ""
+		if i := strings.IndexByte(msg, ' '); i >= 0 {
+			code, description = msg[:i], strings.TrimSpace(msg[i+1:])
+		}
+		return ds_models.NewProtocolError(code, description, nil)
+	case exitOffline:
+		return ds_models.NewDriverError(ds_models.ErrorDeviceOffline, errors.New(msg))
+	case exitNotSupported:
+		return ds_models.NewDriverError(ds_models.ErrorNotSupported, errors.New(msg))
+	}
+	return fmt.Errorf("%v: %s", err, msg)
+}
+
+// parseToolValue parses a value printed by the tool.
+func parseToolValue(s string, t ds_models.ValueType, ro *models.ResourceOperation, origin int64) (*ds_models.CommandValue, error) {
+	b := ds_models.NewCommandValueBuilder().Resource(ro).Origin(origin)
+	switch t {
+	case ds_models.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		b.Bool(v)
+	case ds_models.String:
+		b.String(s)
+	case ds_models.Uint8, ds_models.Uint16, ds_models.Uint32, ds_models.Uint64:
+		v, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		b.Value(v, t)
+	case ds_models.Int8, ds_models.Int16, ds_models.Int32, ds_models.Int64:
+		v, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		b.Value(v, t)
+	case ds_models.Float32, ds_models.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		b.Value(v, t)
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", t.Name())
+	}
+	return b.Build()
+}
+
+// limitedBuffer is a buffer discarding what is written beyond max bytes,
+// so that a runaway tool can't exhaust the memory of the DS.
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.max - b.Len(); len(p) > room {
+		b.overflow = true
+		if room < 0 {
+			room = 0
+		}
+		p = p[:room]
+	}
+	b.Buffer.Write(p)
+	return n, nil
+}
+
+// Stop kills the runs of the tool in progress, and makes the following
+// reads and writes fail.
+func (d *CLIDriver) Stop(force bool) error {
+	d.lc.Debug(fmt.Sprintf("CLIDriver.Stop called: force=%v", force))
+	if d.stop != nil {
+		d.stop()
+	}
+	return nil
+}
+
+// APIVersion returns the SDK API version the driver was built against.
+func (d *CLIDriver) APIVersion() string {
+	return ds_models.APIVersion
+}
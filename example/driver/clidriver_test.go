@@ -0,0 +1,185 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var lc = logger.NewClient("driver_test", false, "", "DEBUG")
+
+func float64String(ro *models.ResourceOperation, v float64) string {
+	cv, _ := ds_models.NewFloat64Value(ro, 0, v)
+	return cv.ValueToString()
+}
+
+func TestParseToolValue(t *testing.T) {
+	ro := &models.ResourceOperation{Object: "Setpoint", Parameter: "Setpoint"}
+	tests := []struct {
+		s        string
+		t        ds_models.ValueType
+		expected string
+		valid    bool
+	}{
+		{"true", ds_models.Bool, "true", true},
+		{"yes", ds_models.Bool, "", false},
+		{"215", ds_models.Int16, "215", true},
+		{"-215", ds_models.Int16, "-215", true},
+		{"0x10", ds_models.Uint16, "16", true},
+		{"-1", ds_models.Uint16, "", false},
+		{"1.5", ds_models.Float64, float64String(ro, 1.5), true},
+		{"n/a", ds_models.Float32, "", false},
+		{"on", ds_models.String, "on", true},
+	}
+	for _, tt := range tests {
+		cv, err := parseToolValue(tt.s, tt.t, ro, 1)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseToolValue(%q, %s): unexpected error %v", tt.s, tt.t.Name(), err)
+			continue
+		}
+		if err == nil && (cv.ValueToString() != tt.expected || cv.Type != tt.t || cv.Origin != 1) {
+			t.Errorf("parseToolValue(%q, %s) = %s %s, expected %s", tt.s, tt.t.Name(), cv.Type.Name(), cv.ValueToString(), tt.expected)
+		}
+	}
+}
+
+// exitError returns the error of a process exiting with the given code.
+func exitError(code string) error {
+	return exec.Command("/bin/sh", "-c", "exit "+code).Run()
+}
+
+func TestToolError(t *testing.T) {
+	err := toolError(exitError("1"), "0x02 Illegal Data Address\ndetails\n")
+	if de, ok := err.(*ds_models.DriverError); !ok || de.Category != ds_models.ErrorProtocolException ||
+		de.Code != "0x02" || de.Description != "Illegal Data Address" {
+		t.Errorf("Exception %v", err)
+	}
+
+	tests := []struct {
+		code     string
+		category ds_models.ErrorCategory
+	}{
+		{"2", ds_models.ErrorDeviceOffline},
+		{"3", ds_models.ErrorNotSupported},
+	}
+	for _, tt := range tests {
+		err = toolError(exitError(tt.code), "failed")
+		if de, ok := err.(*ds_models.DriverError); !ok || de.Category != tt.category {
+			t.Errorf("Exit code %s: %v, expected %s", tt.code, err, tt.category)
+		}
+	}
+
+	err = toolError(exitError("4"), "out of memory\n")
+	if _, ok := err.(*ds_models.DriverError); ok || err == nil {
+		t.Errorf("Plain failure %v", err)
+	}
+	_, notFound := exec.Command("/nonexistent").Output()
+	if err = toolError(notFound, ""); err != notFound {
+		t.Errorf("Failure to start %v, expected %v", err, notFound)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 8}
+	if n, err := b.Write([]byte("12345")); n != 5 || err != nil || b.overflow {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	// the writes beyond max are reported as complete, so that the tool
+	// isn't killed by a broken pipe, but discarded
+	if n, err := b.Write([]byte("67890")); n != 5 || err != nil || !b.overflow {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if n, _ := b.Write([]byte("x")); n != 1 || b.String() != "12345678" {
+		t.Errorf("Buffer %q", b.String())
+	}
+}
+
+func initCLIDriver(t *testing.T, tool string, config map[string]string) *CLIDriver {
+	config[CLITool] = tool
+	d := &CLIDriver{}
+	if err := d.InitializeWithContext(ds_models.DriverContext{Logger: lc, Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestStubTool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clidriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := initCLIDriver(t, "../cmd/device-cli/res/cli-tool.sh", map[string]string{CLIToolWorkDir: dir})
+	if !filepath.IsAbs(d.tool) {
+		t.Errorf("Tool %s not resolved", d.tool)
+	}
+
+	do := models.DeviceObject{Name: "Setpoint", Attributes: map[string]interface{}{"register": "40001"}}
+	do.Properties.Value = models.PropertyValue{Type: "Int16"}
+	reqs := []ds_models.CommandRequest{{RO: models.ResourceOperation{Object: "Setpoint", Parameter: "Setpoint"}, DeviceObject: do}}
+	param, _ := ds_models.NewInt16Value(&reqs[0].RO, 0, 215)
+	addr := &models.Addressable{Address: "cli01"}
+	if err = d.HandleWriteCommands(addr, reqs, []*ds_models.CommandValue{param}); err != nil {
+		t.Fatal(err)
+	}
+	cvs, err := d.HandleReadCommands(addr, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := cvs[0].ValueToString(); v != "215" {
+		t.Errorf("Read %s after writing 215", v)
+	}
+
+	_, err = d.HandleReadCommands(&models.Addressable{Address: "offline"}, reqs)
+	if de, ok := err.(*ds_models.DriverError); !ok || de.Category != ds_models.ErrorDeviceOffline {
+		t.Errorf("Offline Device %v", err)
+	}
+}
+
+func TestStopKillsRuns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clidriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tool := filepath.Join(dir, "slow.sh")
+	if err = ioutil.WriteFile(tool, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := initCLIDriver(t, tool, map[string]string{CLIToolTimeout: "30000", CLIToolWorkDir: dir})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.run([]string{"read"}, nil)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	d.Stop(false)
+
+	select {
+	case err = <-done:
+		if err != errStopped {
+			t.Errorf("Run killed by Stop returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop didn't kill the run in progress")
+	}
+	if _, err = d.run([]string{"read"}, nil); err != errStopped {
+		t.Errorf("Run after Stop returned %v", err)
+	}
+}
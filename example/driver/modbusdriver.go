@@ -0,0 +1,311 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/bus"
+	"github.com/edgexfoundry/device-sdk-go/pkg/codec"
+	"github.com/edgexfoundry/device-sdk-go/pkg/modbus"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Settings of the ModbusDriver in the Driver section of the configuration,
+// along with those of modbus.PoolConfigFromSettings and
+// modbus.ProtectedRangesSetting.
+const (
+	// ModbusMaxGap is the number of unread registers between two device
+	// resources up to which they are read by a single request.
+	ModbusMaxGap = "MaxGap"
+	// ModbusBaudRate is the baud rate of the serial ports, determining the
+	// silent interval between the frames of the RTU devices. The ports
+	// themselves are set up beforehand, e.g. by stty.
+	ModbusBaudRate = "BaudRate"
+)
+
+// Attributes of the device resources of the ModbusDriver.
+const (
+	// attrTable is the table of the registers of a device resource,
+	// "HOLDING_REGISTERS" (the default) or "INPUT_REGISTERS".
+	attrTable = "primaryTable"
+	// attrAddress is the address of the first register of a device
+	// resource.
+	attrAddress = "startingAddress"
+	// attrQuantity is the number of registers of a device resource, given
+	// by the size of its value type by default.
+	attrQuantity = "quantity"
+)
+
+const inputRegisters = "INPUT_REGISTERS"
+
+// ModbusDriver is an example of a Modbus driver, for the TCP servers and the
+// RTU devices of serial buses, their value being decoded by the codec
+// package (codec.Binary unless given by the Codec attribute, see
+// codec.Binary for the byte order attributes). The Addressable
+// of a Device gives its server ("TCP" Protocol, Address and Port, 502 by
+// default) or its serial port (Address), and its unit identifier (Path, 1
+// by default).
+//
+// The registers of the device resources read together are read in as few
+// requests as possible, see modbus.Coalesce. The TCP connections are kept
+// open in a modbus.Pool, and the transactions of the Devices sharing a
+// serial port are arbitrated by a bus.Manager, so that the AutoEvents and
+// the commands of the REST API don't collide.
+type ModbusDriver struct {
+	lc       logger.LoggingClient
+	settings map[string]string
+	maxGap   int
+	baudRate int
+	pool     *modbus.Pool
+	buses    *bus.Manager
+
+	mutex sync.Mutex
+	// ports are the RTU clients, by serial port.
+	ports map[string]*modbus.RTUClient
+}
+
+// Initialize initializes the driver with the default settings; see
+// InitializeWithContext.
+func (d *ModbusDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return d.InitializeWithContext(ds_models.DriverContext{Logger: lc, AsyncCh: asyncCh})
+}
+
+// InitializeWithContext initializes the driver with the settings of the
+// Driver section of the configuration.
+func (d *ModbusDriver) InitializeWithContext(ctx ds_models.DriverContext) error {
+	var err error
+	d.lc = ctx.Logger
+	d.settings = ctx.Config
+	if d.maxGap, err = countSetting(ctx.Config, ModbusMaxGap); err != nil {
+		return err
+	}
+	if d.baudRate, err = countSetting(ctx.Config, ModbusBaudRate); err != nil {
+		return err
+	}
+	cfg, err := modbus.PoolConfigFromSettings(modbus.DefaultPoolConfig, ctx.Config)
+	if err != nil {
+		return fmt.Errorf("ModbusDriver: %v", err)
+	}
+	d.pool = modbus.NewPool(cfg)
+	d.buses = bus.NewManager(modbus.SilentInterval(d.baudRate))
+	d.ports = make(map[string]*modbus.RTUClient)
+	return nil
+}
+
+// countSetting returns the value of a setting which can't be negative, 0 if
+// not set.
+func countSetting(config map[string]string, name string) (int, error) {
+	s := strings.TrimSpace(config[name])
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("ModbusDriver: invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+// modbusClient is the client of a TCP server or of a serial port.
+type modbusClient interface {
+	ReadRanges(unit byte, ranges []modbus.Range, maxGap int) ([][]byte, error)
+	WriteRegisters(unit byte, address uint16, values []byte, function string) error
+}
+
+// do calls op with the client of the Device of an Addressable and its unit.
+func (d *ModbusDriver) do(addr *models.Addressable, op func(c modbusClient, unit byte) error) error {
+	unit := byte(1)
+	if addr.Path != "" {
+		n, err := strconv.ParseUint(strings.TrimPrefix(addr.Path, "/"), 0, 8)
+		if err != nil {
+			return fmt.Errorf("ModbusDriver: invalid unit %s: %v", addr.Path, err)
+		}
+		unit = byte(n)
+	}
+
+	if !strings.EqualFold(addr.Protocol, "TCP") {
+		c, err := d.rtuClient(addr.Address)
+		if err != nil {
+			return ds_models.NewDriverError(ds_models.ErrorDeviceOffline, err)
+		}
+		return op(c, unit)
+	}
+	port := addr.Port
+	if port == 0 {
+		port = 502
+	}
+	return d.pool.Do(net.JoinHostPort(addr.Address, strconv.Itoa(port)), func(c *modbus.TCPClient) error {
+		return op(c, unit)
+	})
+}
+
+// rtuClient returns the client of a serial port, opened on first use.
+func (d *ModbusDriver) rtuClient(path string) (*modbus.RTUClient, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if c, ok := d.ports[path]; ok {
+		return c, nil
+	}
+	port, err := os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+	c := &modbus.RTUClient{Port: port, Bus: path, Buses: d.buses, BaudRate: d.baudRate}
+	if err = c.Configure(d.settings); err != nil {
+		port.Close()
+		return nil, err
+	}
+	d.ports[path] = c
+	return c, nil
+}
+
+// registers returns the registers of a device resource.
+func registers(req ds_models.CommandRequest) (modbus.Range, error) {
+	do := req.DeviceObject
+	r := modbus.Range{Function: modbus.ReadHoldingRegisters}
+	if table, _ := do.Attributes[attrTable].(string); strings.EqualFold(table, inputRegisters) {
+		r.Function = modbus.ReadInputRegisters
+	}
+
+	address, err := strconv.ParseUint(fmt.Sprintf("%v", do.Attributes[attrAddress]), 0, 16)
+	if err != nil {
+		return r, fmt.Errorf("ModbusDriver: invalid %s of %s: %v", attrAddress, do.Name, err)
+	}
+	r.Address = uint16(address)
+
+	if q, ok := do.Attributes[attrQuantity]; ok {
+		quantity, err := strconv.ParseUint(fmt.Sprintf("%v", q), 0, 8)
+		if err != nil || quantity == 0 || quantity > modbus.MaxReadQuantity {
+			return r, fmt.Errorf("ModbusDriver: invalid %s of %s: %v", attrQuantity, do.Name, q)
+		}
+		r.Quantity = uint16(quantity)
+		return r, nil
+	}
+	t, err := ds_models.ParseValueType(do.Properties.Value.Type)
+	if err != nil || t.Size() == 0 {
+		return r, fmt.Errorf("ModbusDriver: %s of %s requires the %s attribute", do.Properties.Value.Type, do.Name, attrQuantity)
+	}
+	r.Quantity = uint16((t.Size() + 1) / 2)
+	return r, nil
+}
+
+// codecFor returns the Codec of a device resource, codec.Binary by default.
+func codecFor(req ds_models.CommandRequest) (codec.Codec, error) {
+	if _, ok := req.DeviceObject.Attributes[codec.AttrCodec]; !ok {
+		return codec.Binary{}, nil
+	}
+	return codec.For(req)
+}
+
+// driverError returns the DriverError of a failure, if known.
+func driverError(err error) error {
+	if e, ok := err.(modbus.Exception); ok {
+		return ds_models.NewProtocolError(fmt.Sprintf("0x%02x", e.Code), "", err)
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return ds_models.NewDriverError(ds_models.ErrorTimeout, err)
+	}
+	return err
+}
+
+func (d *ModbusDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	ranges := make([]modbus.Range, len(reqs))
+	for i, req := range reqs {
+		r, err := registers(req)
+		if err != nil {
+			return nil, err
+		}
+		ranges[i] = r
+	}
+
+	var values [][]byte
+	err := d.do(addr, func(c modbusClient, unit byte) error {
+		var err error
+		values, err = c.ReadRanges(unit, ranges, d.maxGap)
+		return err
+	})
+	if err != nil {
+		return nil, driverError(err)
+	}
+
+	cvs := make([]*ds_models.CommandValue, len(reqs))
+	for i, req := range reqs {
+		c, err := codecFor(req)
+		if err != nil {
+			return nil, fmt.Errorf("ModbusDriver: %v", err)
+		}
+		cv, err := c.Decode(req, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("ModbusDriver: %s: %v", req.DeviceObject.Name, err)
+		}
+		cvs[i] = cv
+	}
+	return cvs, nil
+}
+
+func (d *ModbusDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	for i, req := range reqs {
+		r, err := registers(req)
+		if err != nil {
+			return err
+		}
+		if r.Function != modbus.ReadHoldingRegisters {
+			return ds_models.NewDriverError(ds_models.ErrorNotSupported, fmt.Errorf("ModbusDriver: %s is read-only", req.DeviceObject.Name))
+		}
+		c, err := codecFor(req)
+		if err != nil {
+			return fmt.Errorf("ModbusDriver: %v", err)
+		}
+		data, err := c.Encode(req, params[i])
+		if err != nil {
+			return fmt.Errorf("ModbusDriver: %s: %v", req.DeviceObject.Name, err)
+		}
+		err = d.do(addr, func(c modbusClient, unit byte) error {
+			return c.WriteRegisters(unit, r.Address, data, modbus.WriteMultiple)
+		})
+		if err != nil {
+			return driverError(err)
+		}
+	}
+	return nil
+}
+
+// Peers returns the state of the connections to the TCP servers.
+func (d *ModbusDriver) Peers() []ds_models.PeerStatus {
+	return d.pool.Peers()
+}
+
+// DisconnectDevice does nothing, the idle connections being closed by the
+// pool.
+func (d *ModbusDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+// Stop closes the connections and the serial ports.
+func (d *ModbusDriver) Stop(force bool) error {
+	d.pool.Close()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for path, c := range d.ports {
+		if f, ok := c.Port.(*os.File); ok {
+			f.Close()
+		}
+		delete(d.ports, path)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/modbus"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// modbusServer is a Modbus TCP server of 16 holding registers, counting the
+// requests.
+type modbusServer struct {
+	listener  net.Listener
+	mutex     sync.Mutex
+	registers [16]uint16
+	requests  int
+}
+
+func newModbusServer(t *testing.T) *modbusServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &modbusServer{listener: l}
+	for i := range s.registers {
+		s.registers[i] = uint16(i)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *modbusServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		pdu := make([]byte, binary.BigEndian.Uint16(header[4:])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.requests++
+		address := binary.BigEndian.Uint16(pdu[1:])
+		quantity := binary.BigEndian.Uint16(pdu[3:])
+		response := []byte{pdu[0]}
+		switch pdu[0] {
+		case modbus.ReadHoldingRegisters:
+			response = append(response, byte(2*quantity))
+			for i := address; i < address+quantity; i++ {
+				response = append(response, byte(s.registers[i]>>8), byte(s.registers[i]))
+			}
+		default: // write multiple registers
+			for i := uint16(0); i < quantity; i++ {
+				s.registers[address+i] = binary.BigEndian.Uint16(pdu[6+2*i:])
+			}
+			response = append(response, pdu[1:5]...)
+		}
+		s.mutex.Unlock()
+
+		binary.BigEndian.PutUint16(header[4:], uint16(len(response)+1))
+		conn.Write(append(header, response...))
+	}
+}
+
+func (s *modbusServer) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests
+}
+
+func registerRequest(name string, valueType string, address int) ds_models.CommandRequest {
+	do := models.DeviceObject{Name: name, Attributes: map[string]interface{}{"startingAddress": address}}
+	do.Properties.Value = models.PropertyValue{Type: valueType}
+	return ds_models.CommandRequest{RO: models.ResourceOperation{Object: name, Parameter: name}, DeviceObject: do}
+}
+
+func TestModbusDriver(t *testing.T) {
+	s := newModbusServer(t)
+	defer s.listener.Close()
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	addr := &models.Addressable{Protocol: "TCP", Address: host, Port: p}
+
+	d := &ModbusDriver{}
+	err := d.InitializeWithContext(ds_models.DriverContext{Logger: lc, Config: map[string]string{
+		ModbusMaxGap:                  "1",
+		modbus.ProtectedRangesSetting: "8-15",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Stop(false)
+
+	// the registers 1, 2-3 and 5 are read by a single request
+	reqs := []ds_models.CommandRequest{
+		registerRequest("Setpoint", "Uint16", 1),
+		registerRequest("Energy", "Uint32", 2),
+		registerRequest("Alarm", "Int16", 5),
+	}
+	cvs, err := d.HandleReadCommands(addr, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"1", strconv.Itoa(2<<16 | 3), "5"}
+	for i, cv := range cvs {
+		if cv.ValueToString() != expected[i] {
+			t.Errorf("Read %s = %s, expected %s", reqs[i].DeviceObject.Name, cv.ValueToString(), expected[i])
+		}
+	}
+	if n := s.requestCount(); n != 1 {
+		t.Errorf("Registers read by %d requests", n)
+	}
+
+	param, _ := ds_models.NewUint16Value(&reqs[0].RO, 0, 215)
+	if err = d.HandleWriteCommands(addr, reqs[:1], []*ds_models.CommandValue{param}); err != nil {
+		t.Fatal(err)
+	}
+	cvs, err = d.HandleReadCommands(addr, reqs[:1])
+	if err != nil || cvs[0].ValueToString() != "215" {
+		t.Errorf("Read %v after writing 215: %v", cvs, err)
+	}
+
+	// the protected registers aren't written
+	protected := registerRequest("Calibration", "Uint16", 9)
+	param, _ = ds_models.NewUint16Value(&protected.RO, 0, 1)
+	err = d.HandleWriteCommands(addr, []ds_models.CommandRequest{protected}, []*ds_models.CommandValue{param})
+	if _, ok := err.(modbus.ProtectedRegisterError); !ok {
+		t.Errorf("Write of a protected register returned %v", err)
+	}
+	if len(d.Peers()) != 1 {
+		t.Errorf("Peers %v", d.Peers())
+	}
+}
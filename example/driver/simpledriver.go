@@ -91,3 +91,8 @@ func (s *SimpleDriver) Stop(force bool) error {
 	s.lc.Debug(fmt.Sprintf("SimpleDriver.Stop called: force=%v", force))
 	return nil
 }
+
+// APIVersion returns the SDK API version the driver was built against.
+func (s *SimpleDriver) APIVersion() string {
+	return ds_models.APIVersion
+}
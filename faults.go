@@ -0,0 +1,41 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build faults
+// +build faults
+
+package device
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/buildinfo"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/pkg/fault"
+)
+
+func init() {
+	buildinfo.Tags = append(buildinfo.Tags, "faults")
+}
+
+// faultyDriver is set once the driver has been wrapped, as it's kept on
+// restart unlike the clients.
+var faultyDriver bool
+
+// initFaults injects the configured faults into the driver and Core Data
+// client.
+func initFaults() {
+	cfg := common.CurrentConfig.Faults
+	if !cfg.Enabled {
+		fault.Disable()
+		return
+	}
+	common.LoggingClient.Warn("Fault injection enabled, not for production use")
+	fault.Enable(cfg.Config)
+	common.EventClient = fault.EventClient(common.EventClient)
+	if !faultyDriver {
+		common.Driver = fault.Driver(common.Driver)
+		faultyDriver = true
+	}
+}
@@ -0,0 +1,297 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package anomaly checks the readings before they're published: the values
+// listed in the InvalidValues attribute of their device resource are
+// suppressed, the detector selected by its AnomalyDetector attribute flags
+// spikes or outliers, and the Driver may add its own checks by implementing
+// AnomalyDetector.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Detectors, as named in the AnomalyDetector attribute
+const (
+	// Spike flags a value differing from the previous one by more than
+	// AnomalyThreshold.
+	Spike = "spike"
+	// ZScore flags a value more than AnomalyThreshold standard deviations
+	// away from the mean of the last AnomalyWindow values.
+	ZScore = "zscore"
+)
+
+// DefaultWindow is the number of values of the ZScore detector, unless set
+// by the AnomalyWindow attribute.
+const DefaultWindow = 30
+
+// minSamples is the number of values the ZScore detector needs before it
+// flags outliers.
+const minSamples = 5
+
+// Stats counts the anomalous readings.
+type Stats struct {
+	Tagged     uint64 `json:"tagged"`
+	Alarms     uint64 `json:"alarms"`
+	Suppressed uint64 `json:"suppressed"`
+}
+
+type detector interface {
+	// detect checks a value and returns the reason if it's anomalous.
+	detect(value float64) (string, bool)
+}
+
+var (
+	mutex     sync.Mutex
+	detectors = make(map[string]map[string]detector) // keys are Device and resource names
+	stats     Stats
+)
+
+// Invalid returns true if the raw value of a device resource, before its
+// transformation, is listed in its InvalidValues attribute, e.g. "0xFFFF".
+// The reading is then suppressed.
+func Invalid(deviceName string, cv *ds_models.CommandValue, do models.DeviceObject) bool {
+	list, ok := common.DeviceObjectAttribute(do, common.AttrInvalidValues)
+	if !ok {
+		return false
+	}
+
+	value, numeric := transformer.NumericValue(cv)
+	str := cv.ValueToString()
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		invalid := s == str
+		if n, err := parseNumber(s); err == nil && numeric {
+			invalid = invalid || n == value
+		}
+		if invalid {
+			common.LoggingClient.Warn(fmt.Sprintf("Suppressed invalid value %s of Device %s resource %s", str, deviceName, cv.RO.Object))
+			mutex.Lock()
+			stats.Suppressed++
+			mutex.Unlock()
+			return true
+		}
+	}
+	return false
+}
+
+// Detect checks a reading with the detector of the Driver, if any, and then
+// the one of its device resource. It returns nil for a normal reading.
+func Detect(deviceName string, cv *ds_models.CommandValue, do models.DeviceObject) *ds_models.Anomaly {
+	var a *ds_models.Anomaly
+	if d, ok := common.Driver.(ds_models.AnomalyDetector); ok {
+		a = d.DetectAnomaly(deviceName, cv)
+	}
+	if a == nil {
+		a = detect(deviceName, cv, do)
+	}
+	if a == nil {
+		return nil
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	switch a.Action {
+	case ds_models.AnomalySuppress:
+		common.LoggingClient.Warn(fmt.Sprintf("Suppressed anomalous value %s of Device %s resource %s: %s", cv.ValueToString(), deviceName, cv.RO.Object, a.Reason))
+		stats.Suppressed++
+	case ds_models.AnomalyAlarm:
+		common.LoggingClient.Error(fmt.Sprintf("Anomaly alarm for Device %s resource %s: %s", deviceName, cv.RO.Object, a.Reason))
+		stats.Alarms++
+	default:
+		stats.Tagged++
+	}
+	return a
+}
+
+// Reading returns the additional reading describing an anomaly.
+func Reading(deviceName string, cv *ds_models.CommandValue, a *ds_models.Anomaly) models.Reading {
+	reading := models.Reading{Name: common.AnomalyReadingName, Device: deviceName, Value: cv.RO.Object + ": " + a.Reason}
+	reading.Origin = cv.Origin
+	return reading
+}
+
+// Enabled returns true if the Driver detects anomalies, or a device resource
+// of a cached profile selects a detector.
+func Enabled() bool {
+	if _, ok := common.Driver.(ds_models.AnomalyDetector); ok {
+		return true
+	}
+	for _, p := range cache.Profiles().All() {
+		for _, do := range p.DeviceResources {
+			if _, ok := common.DeviceObjectAttribute(do, common.AttrAnomalyDetector); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveDevice forgets the values of a Device, e.g. when it's removed.
+func RemoveDevice(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(detectors, deviceName)
+}
+
+// CurrentStats returns the number of anomalous readings.
+func CurrentStats() Stats {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return stats
+}
+
+// detect checks a numeric reading with the detector of its device resource.
+func detect(deviceName string, cv *ds_models.CommandValue, do models.DeviceObject) *ds_models.Anomaly {
+	kind, ok := common.DeviceObjectAttribute(do, common.AttrAnomalyDetector)
+	if !ok {
+		return nil
+	}
+	value, ok := transformer.NumericValue(cv)
+	if !ok {
+		return nil
+	}
+
+	mutex.Lock()
+	d, err := detectorFor(deviceName, do, kind)
+	var reason string
+	var anomalous bool
+	if err == nil {
+		reason, anomalous = d.detect(value)
+	}
+	mutex.Unlock()
+
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Invalid anomaly detector of Device %s resource %s: %v", deviceName, do.Name, err))
+		return nil
+	}
+	if !anomalous {
+		return nil
+	}
+	return &ds_models.Anomaly{Action: action(do), Reason: reason}
+}
+
+// detectorFor returns the detector of a device resource, creating it on
+// the first reading. The mutex must be locked.
+func detectorFor(deviceName string, do models.DeviceObject, kind string) (detector, error) {
+	if d, ok := detectors[deviceName][do.Name]; ok {
+		return d, nil
+	}
+
+	s, _ := common.DeviceObjectAttribute(do, common.AttrAnomalyThreshold)
+	threshold, err := strconv.ParseFloat(s, 64)
+	if err != nil || threshold <= 0 {
+		return nil, fmt.Errorf("invalid %s %q", common.AttrAnomalyThreshold, s)
+	}
+
+	var d detector
+	switch strings.ToLower(kind) {
+	case Spike:
+		d = &spike{threshold: threshold}
+	case ZScore:
+		window := DefaultWindow
+		if s, ok := common.DeviceObjectAttribute(do, common.AttrAnomalyWindow); ok {
+			window, err = strconv.Atoi(s)
+			if err != nil || window < minSamples {
+				return nil, fmt.Errorf("invalid %s %q", common.AttrAnomalyWindow, s)
+			}
+		}
+		d = &zscore{threshold: threshold, size: window}
+	default:
+		return nil, fmt.Errorf("unknown detector %q", kind)
+	}
+
+	if detectors[deviceName] == nil {
+		detectors[deviceName] = make(map[string]detector)
+	}
+	detectors[deviceName][do.Name] = d
+	return d, nil
+}
+
+// action returns the action of the AnomalyAction attribute, tagging the
+// readings by default.
+func action(do models.DeviceObject) ds_models.AnomalyAction {
+	s, _ := common.DeviceObjectAttribute(do, common.AttrAnomalyAction)
+	switch strings.ToLower(s) {
+	case "alarm":
+		return ds_models.AnomalyAlarm
+	case "suppress":
+		return ds_models.AnomalySuppress
+	}
+	return ds_models.AnomalyTag
+}
+
+func parseNumber(s string) (float64, error) {
+	if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return float64(n), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+type spike struct {
+	threshold float64
+	last      float64
+	hasLast   bool
+}
+
+func (s *spike) detect(value float64) (string, bool) {
+	delta := value - s.last
+	anomalous := s.hasLast && math.Abs(delta) > s.threshold
+	s.last, s.hasLast = value, true
+	if !anomalous {
+		return "", false
+	}
+	return fmt.Sprintf("spike of %g", delta), true
+}
+
+type zscore struct {
+	threshold float64
+	size      int
+	values    []float64
+	next      int
+}
+
+func (z *zscore) detect(value float64) (string, bool) {
+	var reason string
+	anomalous := false
+	if len(z.values) >= minSamples {
+		var sum, sumSq float64
+		for _, v := range z.values {
+			sum += v
+			sumSq += v * v
+		}
+		n := float64(len(z.values))
+		mean := sum / n
+		std := math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+		if std > 0 {
+			if score := (value - mean) / std; math.Abs(score) > z.threshold {
+				reason, anomalous = fmt.Sprintf("z-score of %.2f", score), true
+			}
+		}
+	}
+
+	if len(z.values) < z.size {
+		z.values = append(z.values, value)
+	} else {
+		z.values[z.next] = value
+		z.next = (z.next + 1) % z.size
+	}
+	return reason, anomalous
+}
@@ -0,0 +1,87 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("anomaly_test", false, "", "DEBUG")
+}
+
+func TestSpike(t *testing.T) {
+	s := &spike{threshold: 10}
+	for _, v := range []float64{20, 25, 18} {
+		if _, anomalous := s.detect(v); anomalous {
+			t.Errorf("value %v flagged", v)
+		}
+	}
+	if reason, anomalous := s.detect(40); !anomalous || reason != "spike of 22" {
+		t.Errorf("spike not flagged: %q", reason)
+	}
+}
+
+func TestZScore(t *testing.T) {
+	z := &zscore{threshold: 3, size: 10}
+	for i := 0; i < 20; i++ {
+		if _, anomalous := z.detect(float64(50 + i%3)); anomalous {
+			t.Errorf("value %v flagged", 50+i%3)
+		}
+	}
+	if _, anomalous := z.detect(90); !anomalous {
+		t.Errorf("outlier not flagged")
+	}
+	if len(z.values) != 10 {
+		t.Errorf("window of %d values", len(z.values))
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	do := models.DeviceObject{Name: "Temperature", Attributes: map[string]interface{}{common.AttrInvalidValues: "0xFFFF, -1"}}
+	ro := &models.ResourceOperation{Object: "Temperature"}
+
+	cv, _ := ds_models.NewUint16Value(ro, 0, 0xFFFF)
+	if !Invalid("Sensor", cv, do) {
+		t.Errorf("0xFFFF not suppressed")
+	}
+	cv, _ = ds_models.NewUint16Value(ro, 0, 215)
+	if Invalid("Sensor", cv, do) {
+		t.Errorf("valid value suppressed")
+	}
+	if CurrentStats().Suppressed != 1 {
+		t.Errorf("unexpected stats %v", CurrentStats())
+	}
+}
+
+func TestDetect(t *testing.T) {
+	do := models.DeviceObject{Name: "Power", Attributes: map[string]interface{}{
+		common.AttrAnomalyDetector:  "spike",
+		common.AttrAnomalyThreshold: "100",
+		common.AttrAnomalyAction:    "alarm",
+	}}
+	ro := &models.ResourceOperation{Object: "Power"}
+	defer RemoveDevice("Meter")
+
+	cv, _ := ds_models.NewFloat64Value(ro, 0, 500)
+	if a := Detect("Meter", cv, do); a != nil {
+		t.Errorf("first value flagged: %v", a)
+	}
+	cv, _ = ds_models.NewFloat64Value(ro, 0, 900)
+	a := Detect("Meter", cv, do)
+	if a == nil || a.Action != ds_models.AnomalyAlarm {
+		t.Fatalf("spike not raised as alarm: %v", a)
+	}
+	if r := Reading("Meter", cv, a); r.Name != common.AnomalyReadingName || r.Value != "Power: spike of 400" {
+		t.Errorf("unexpected reading %v", r)
+	}
+}
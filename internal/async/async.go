@@ -0,0 +1,211 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 Canonical Ltd
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package async processes the readings pushed asynchronously by the Driver:
+// they are transformed, cached as the last values of their resources, and
+// the readings of a Device arriving within a batching window are pushed to
+// Core Data as a single Event.
+package async
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/anomaly"
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/capture"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// batch holds the readings of a Device waiting to be pushed.
+type batch struct {
+	readings []models.Reading
+}
+
+var (
+	mutex       sync.Mutex
+	window      time.Duration
+	maxReadings int
+	pending     = make(map[string]*batch)
+	sendEvent   = common.SendEvent
+)
+
+// Init sets the batching window, during which the readings of a Device are
+// accumulated, and the number of readings after which a batch is pushed
+// early. A zero window pushes an Event per AsyncValues, and a zero
+// maximum doesn't limit the batches.
+func Init(batchWindow time.Duration, batchMaxReadings int) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	window = batchWindow
+	maxReadings = batchMaxReadings
+}
+
+// Process transforms the values pushed by the Driver, and pushes them to
+// Core Data when their batch is complete.
+func Process(acv *ds_models.AsyncValues) {
+	device, ok := cache.Devices().ForName(acv.DeviceName)
+	if !ok {
+		if current, renamed := common.ResolveDeviceName(acv.DeviceName); renamed {
+			device, ok = cache.Devices().ForName(current)
+		}
+	}
+	if !ok {
+		common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - recieved Device %s not found in cache", acv.DeviceName))
+		return
+	}
+
+	if acv.Capture != nil {
+		if err := capture.Add(device.Name, acv.Capture); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - %v", err))
+		} else if acv.Capture.Last {
+			common.LoggingClient.Info(fmt.Sprintf("processAsyncResults - Capture %s of Device %s completed", acv.Capture.ID, device.Name))
+		}
+		if len(acv.CommandValues) == 0 {
+			return
+		}
+	}
+
+	add(device.Name, toReadings(device, acv.CommandValues))
+}
+
+// toReadings transforms the values of a Device, checks their assertions,
+// applies their mappings, checks them for anomalies and caches them.
+func toReadings(device models.Device, cvs []*ds_models.CommandValue) []models.Reading {
+	readings := make([]models.Reading, 0, len(cvs))
+	props := common.DeviceProperties(device)
+	for _, cv := range cvs {
+		// get the device resource associated with the rsp.RO
+		do, ok := cache.Profiles().DeviceObject(device.Profile.Name, cv.RO.Object)
+		if !ok {
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Device Resource %s not found in Device %s", cv.RO.Object, device.Name))
+			continue
+		}
+
+		if anomaly.Invalid(device.Name, cv, do) {
+			continue
+		}
+
+		if common.CurrentConfig.Device.DataTransform {
+			err := transformer.TransformReadResult(cv, transformer.ResolveProperties(do.Properties.Value, props))
+			if err == nil {
+				err = transformer.ApplyRatio(cv, do, props)
+			}
+			if err != nil {
+				common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - CommandValue (%s) transformed failed: %v", cv.String(), err))
+				cv = ds_models.NewStringValue(cv.RO, cv.Origin, fmt.Sprintf("Transformation failed for device resource, with value: %s, property value: %v, and error: %v", cv.String(), do.Properties.Value, err))
+			}
+		}
+
+		// the values failing their assertion aren't pushed, the Device
+		// being disabled
+		err := transformer.CheckAssertion(cv, do.Properties.Value.Assertion, &device)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Assertion failed for device resource: %s of Device %s, %v", cv.RO.Object, device.Name, err))
+			continue
+		}
+
+		if len(cv.RO.Mappings) > 0 {
+			newCV, ok := transformer.MapCommandValue(cv)
+			if ok {
+				cv = newCV
+			} else {
+				common.LoggingClient.Warn(fmt.Sprintf("processAsyncResults - Mapping failed for Device Resource Operation: %v, with value: %s, %v", cv.RO, cv.String(), err))
+			}
+		}
+
+		a := anomaly.Detect(device.Name, cv, do)
+		if a != nil && a.Action == ds_models.AnomalySuppress {
+			continue
+		}
+
+		cache.Readings().Add(device.Name, cv)
+		readings = append(readings, transformer.CommandValueToReadings(cv, device.Name, do)...)
+		if a != nil {
+			readings = append(readings, anomaly.Reading(device.Name, cv, a))
+		}
+	}
+	return readings
+}
+
+// add adds the readings of a Device to its batch, which is pushed once the
+// window has elapsed or it's full.
+func add(deviceName string, readings []models.Reading) {
+	mutex.Lock()
+	if window <= 0 {
+		mutex.Unlock()
+		send(deviceName, readings)
+		return
+	}
+
+	b, ok := pending[deviceName]
+	if !ok {
+		b = &batch{}
+		pending[deviceName] = b
+		time.AfterFunc(window, func() { flush(deviceName, b) })
+	}
+	b.readings = append(b.readings, readings...)
+	full := maxReadings > 0 && len(b.readings) >= maxReadings
+	if full {
+		delete(pending, deviceName)
+	}
+	mutex.Unlock()
+
+	if full {
+		send(deviceName, b.readings)
+	}
+}
+
+// flush pushes a batch, unless it has already been pushed.
+func flush(deviceName string, b *batch) {
+	mutex.Lock()
+	if pending[deviceName] != b {
+		mutex.Unlock()
+		return
+	}
+	delete(pending, deviceName)
+	mutex.Unlock()
+
+	send(deviceName, b.readings)
+}
+
+// Flush pushes the pending batches, e.g. before the DS stops.
+func Flush() {
+	mutex.Lock()
+	batches := pending
+	pending = make(map[string]*batch)
+	mutex.Unlock()
+
+	for deviceName, b := range batches {
+		send(deviceName, b.readings)
+	}
+}
+
+// Pending returns the number of readings waiting to be pushed.
+func Pending() int {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	n := 0
+	for _, b := range pending {
+		n += len(b.readings)
+	}
+	return n
+}
+
+func send(deviceName string, readings []models.Reading) {
+	if len(readings) == 0 {
+		return
+	}
+	// push to Core Data
+	sendEvent(&models.Event{Device: deviceName, Readings: readings})
+}
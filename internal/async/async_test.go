@@ -0,0 +1,88 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package async
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// recorder collects the events sent.
+type recorder struct {
+	sync.Mutex
+	events []*models.Event
+}
+
+func (r *recorder) send(e *models.Event) {
+	r.Lock()
+	defer r.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.events)
+}
+
+func record(batchWindow time.Duration, batchMaxReadings int) *recorder {
+	r := &recorder{}
+	sendEvent = r.send
+	Init(batchWindow, batchMaxReadings)
+	return r
+}
+
+func reading(name string, value string) []models.Reading {
+	return []models.Reading{{Name: name, Value: value}}
+}
+
+func TestNoBatching(t *testing.T) {
+	r := record(0, 0)
+	add("meter", reading("Voltage", "230"))
+	add("meter", reading("Current", "5"))
+	if r.count() != 2 {
+		t.Errorf("Expected an event per push, got %d", r.count())
+	}
+}
+
+func TestBatchWindow(t *testing.T) {
+	r := record(20*time.Millisecond, 0)
+	add("meter", reading("Voltage", "230"))
+	add("meter", reading("Current", "5"))
+	add("pump", reading("Flow", "12"))
+	if r.count() != 0 || Pending() != 3 {
+		t.Fatalf("Expected the readings to be pending")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if r.count() != 2 || Pending() != 0 {
+		t.Fatalf("Expected an event per device, got %d", r.count())
+	}
+	for _, e := range r.events {
+		if e.Device == "meter" && len(e.Readings) != 2 {
+			t.Errorf("Expected the readings of meter to be batched, got %v", e.Readings)
+		}
+	}
+}
+
+func TestBatchMaxReadings(t *testing.T) {
+	r := record(time.Hour, 2)
+	add("meter", reading("Voltage", "230"))
+	add("meter", reading("Current", "5"))
+	add("meter", reading("Voltage", "231"))
+	if r.count() != 1 || Pending() != 1 {
+		t.Fatalf("Expected a full batch to be pushed, got %d", r.count())
+	}
+
+	Flush()
+	if r.count() != 2 || Pending() != 0 {
+		t.Errorf("Expected the pending batch to be flushed, got %d", r.count())
+	}
+}
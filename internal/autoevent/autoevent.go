@@ -0,0 +1,249 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package autoevent runs the AutoEvents of the Devices, i.e. the periodic
+// reads of their resources pushed to Core Data, only when the readings
+// change for the AutoEvents with OnChange. They're defined in Core Metadata
+// by the device resources of the profiles of the Devices, with an
+// AutoEventFrequency attribute, as Core Metadata has no AutoEvents of its
+// own in this release. Each Device has its own executors, started, stopped
+// and restarted as the Device is added, removed or updated.
+package autoevent
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var (
+	mutex       sync.Mutex
+	started     bool
+	definitions = make(map[string][]ds_models.AutoEvent)
+	executors   = make(map[string][]*executor)
+)
+
+type executor struct {
+	device   string
+	ae       ds_models.AutoEvent
+	interval time.Duration
+	done     chan struct{}
+	runs     uint64
+	last     []models.Reading
+}
+
+// SetAutoEvents replaces the AutoEvents set for a Device, restarting its
+// executors. They override those of its profile for the same resources. No
+// AutoEvents removes them.
+func SetAutoEvents(deviceName string, aes []ds_models.AutoEvent) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(aes) == 0 {
+		delete(definitions, deviceName)
+	} else {
+		definitions[deviceName] = aes
+	}
+	if started {
+		stopDevice(deviceName)
+		startDevice(deviceName)
+	}
+}
+
+// AutoEvents returns the AutoEvents of a Device.
+func AutoEvents(deviceName string) []ds_models.AutoEvent {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return deviceAutoEvents(deviceName)
+}
+
+// deviceAutoEvents returns the AutoEvents defined by the profile of a
+// Device, with those set for the Device replacing them by resource.
+func deviceAutoEvents(deviceName string) []ds_models.AutoEvent {
+	set := definitions[deviceName]
+	var aes []ds_models.AutoEvent
+	for _, ae := range profileAutoEvents(deviceName) {
+		overridden := false
+		for _, s := range set {
+			overridden = overridden || s.Resource == ae.Resource
+		}
+		if !overridden {
+			aes = append(aes, ae)
+		}
+	}
+	return append(aes, set...)
+}
+
+// profileAutoEvents returns the AutoEvents of the device resources of the
+// profile of a Device with an AutoEventFrequency attribute, e.g. "10s".
+func profileAutoEvents(deviceName string) []ds_models.AutoEvent {
+	device, ok := cache.Devices().ForName(deviceName)
+	if !ok {
+		return nil
+	}
+	profile, ok := cache.Profiles().ForName(device.Profile.Name)
+	if !ok {
+		profile = device.Profile
+	}
+	var aes []ds_models.AutoEvent
+	for _, do := range profile.DeviceResources {
+		frequency, ok := common.DeviceObjectAttribute(do, common.AttrAutoEventFrequency)
+		if !ok || frequency == "" {
+			continue
+		}
+		ae := ds_models.AutoEvent{Frequency: frequency, Resource: do.Name}
+		if v, ok := common.DeviceObjectAttribute(do, common.AttrAutoEventOnChange); ok {
+			ae.OnChange, _ = strconv.ParseBool(v)
+		}
+		aes = append(aes, ae)
+	}
+	return aes
+}
+
+// Start starts the executors of the AutoEvents of all the Devices.
+func Start() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if started {
+		return
+	}
+	started = true
+	for _, d := range cache.Devices().All() {
+		startDevice(d.Name)
+	}
+	for name := range definitions {
+		startDevice(name)
+	}
+}
+
+// Stop stops all the executors.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	started = false
+	for name := range executors {
+		stopDevice(name)
+	}
+}
+
+// StartForDevice starts the executors of a Device, e.g. once it's added.
+func StartForDevice(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if started {
+		startDevice(deviceName)
+	}
+}
+
+// StopForDevice stops the executors of a Device, e.g. once it's removed.
+func StopForDevice(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	stopDevice(deviceName)
+}
+
+// RestartForDevice restarts the executors of a Device, e.g. once it's
+// updated, the readings of the AutoEvents with OnChange being pushed again.
+func RestartForDevice(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	stopDevice(deviceName)
+	if started {
+		startDevice(deviceName)
+	}
+}
+
+func startDevice(deviceName string) {
+	if len(executors[deviceName]) > 0 {
+		return
+	}
+	for _, ae := range deviceAutoEvents(deviceName) {
+		interval, err := time.ParseDuration(ae.Frequency)
+		if err != nil || interval <= 0 {
+			common.LoggingClient.Error(fmt.Sprintf("AutoEvent of %s for Device %s has an invalid Frequency %q", ae.Resource, deviceName, ae.Frequency))
+			continue
+		}
+		e := &executor{device: deviceName, ae: ae, interval: interval, done: make(chan struct{})}
+		executors[deviceName] = append(executors[deviceName], e)
+		go e.run()
+	}
+}
+
+func stopDevice(deviceName string) {
+	for _, e := range executors[deviceName] {
+		close(e.done)
+	}
+	delete(executors, deviceName)
+}
+
+func (e *executor) run() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+			e.execute()
+		}
+	}
+}
+
+// execute reads the resource, and pushes the readings unless skipped.
+func (e *executor) execute() {
+	if handler.Draining() || common.InStandby() || !throttle.Admit(&e.runs) {
+		return
+	}
+	device, ok := cache.Devices().ForName(e.device)
+	if !ok {
+		common.LoggingClient.Info(fmt.Sprintf("Device %s removed, its AutoEvents stopped", e.device))
+		StopForDevice(e.device)
+		return
+	}
+	if !common.IsLeader(device) {
+		return
+	}
+
+	event, appErr := handler.AutoEventReadHandler(e.device, e.ae.Resource)
+	if appErr != nil {
+		common.LoggingClient.Error(fmt.Sprintf("AutoEvent of %s for Device %s failed: %s", e.ae.Resource, e.device, appErr.Message()))
+		return
+	}
+	if e.ae.OnChange && !changed(e.last, event.Readings) {
+		common.LoggingClient.Debug(fmt.Sprintf("AutoEvent of %s for Device %s unchanged", e.ae.Resource, e.device))
+		return
+	}
+	e.last = event.Readings
+	go common.SendEvent(event)
+}
+
+// changed tells if the readings differ from the last ones, matched by their
+// names whatever their order.
+func changed(last []models.Reading, readings []models.Reading) bool {
+	if last == nil || len(last) != len(readings) {
+		return true
+	}
+	values := make(map[string]string, len(last))
+	for _, r := range last {
+		values[r.Name] = r.Value
+	}
+	for _, r := range readings {
+		if v, ok := values[r.Name]; !ok || v != r.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestChanged(t *testing.T) {
+	last := []models.Reading{{Name: "Voltage", Value: "230"}, {Name: "Current", Value: "5"}}
+	if changed(last, []models.Reading{{Name: "Voltage", Value: "230"}, {Name: "Current", Value: "5"}}) {
+		t.Error("Same readings changed")
+	}
+	if !changed(last, []models.Reading{{Name: "Voltage", Value: "231"}, {Name: "Current", Value: "5"}}) {
+		t.Error("New value unchanged")
+	}
+	if changed(last, []models.Reading{{Name: "Current", Value: "5"}, {Name: "Voltage", Value: "230"}}) {
+		t.Error("Same readings in another order changed")
+	}
+	if !changed(last, []models.Reading{{Name: "Voltage", Value: "230"}, {Name: "Power", Value: "5"}}) {
+		t.Error("Other reading unchanged")
+	}
+	if !changed(last, last[:1]) {
+		t.Error("Missing reading unchanged")
+	}
+	if !changed(nil, nil) {
+		t.Error("First readings unchanged")
+	}
+}
+
+var cacheOnce sync.Once
+
+func initCache(t *testing.T) {
+	cacheOnce.Do(func() { loadCache(t) })
+}
+
+func loadCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "autoevent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	profile := models.DeviceProfile{Name: "Meter"}
+	profile.DeviceResources = []models.DeviceObject{
+		{Name: "Voltage", Attributes: map[string]interface{}{common.AttrAutoEventFrequency: "1h", common.AttrAutoEventOnChange: "true"}},
+		{Name: "Energy", Attributes: map[string]interface{}{common.AttrAutoEventFrequency: "1m"}},
+		{Name: "Power"},
+	}
+	devices := []models.Device{{Name: "meter", Profile: profile}, {Name: "switch"}}
+	snap, _ := json.Marshal(cache.Snapshot{Devices: devices, Profiles: []models.DeviceProfile{profile}})
+	file := filepath.Join(dir, "cache.json")
+	if err = statedir.WriteFile(file, snap); err != nil {
+		t.Fatal(err)
+	}
+	if err = cache.InitCacheFromFile(file); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecutors(t *testing.T) {
+	common.LoggingClient = logger.NewClient("autoevent_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+	initCache(t)
+	defer Stop()
+
+	aes := AutoEvents("meter")
+	if len(aes) != 2 || aes[0].Resource != "Voltage" || !aes[0].OnChange || aes[1].Frequency != "1m" {
+		t.Fatalf("AutoEvents of the profile %v", aes)
+	}
+
+	SetAutoEvents("meter", []ds_models.AutoEvent{{Frequency: "1h", Resource: "Energy"}, {Frequency: "often", Resource: "Power"}})
+	if n := len(executors["meter"]); n != 0 {
+		t.Fatalf("%d executors before the start", n)
+	}
+	if aes = AutoEvents("meter"); len(aes) != 3 || aes[1].Frequency != "1h" {
+		t.Errorf("AutoEvents of the profile overridden %v", aes)
+	}
+	Start()
+	if n := len(executors["meter"]); n != 2 {
+		t.Fatalf("%d executors started, the invalid Frequency included", n)
+	}
+	if n := len(executors["switch"]); n != 0 {
+		t.Errorf("%d executors started without AutoEvents", n)
+	}
+
+	StopForDevice("meter")
+	if n := len(executors["meter"]); n != 0 {
+		t.Errorf("%d executors left after the Device stopped", n)
+	}
+	RestartForDevice("meter")
+	if n := len(executors["meter"]); n != 2 {
+		t.Errorf("%d executors after the Device restarted", n)
+	}
+
+	SetAutoEvents("meter", nil)
+	if n := len(executors["meter"]); n != 2 || len(AutoEvents("meter")) != 2 {
+		t.Errorf("%d executors after the AutoEvents set removed", n)
+	}
+}
+
+// meterDriver reads the Power of the meter, counting the reads.
+type meterDriver struct {
+	mutex sync.Mutex
+	reads int
+}
+
+func (d *meterDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+func (d *meterDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return nil
+}
+
+func (d *meterDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.reads++
+	cv, _ := ds_models.NewInt32Value(&reqs[0].RO, 0, int32(1000+d.reads))
+	return []*ds_models.CommandValue{cv}, nil
+}
+
+func (d *meterDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	return nil
+}
+
+func (d *meterDriver) Stop(force bool) error {
+	return nil
+}
+
+func (d *meterDriver) readCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.reads
+}
+
+// eventClient passes the events pushed to Core Data to events.
+type eventClient struct {
+	coredata.EventClient
+	events chan *models.Event
+}
+
+func (c *eventClient) Add(event *models.Event) (string, error) {
+	c.events <- event
+	return "", nil
+}
+
+func TestExecuteInStandby(t *testing.T) {
+	common.LoggingClient = logger.NewClient("autoevent_test", false, "", "DEBUG")
+	previousConfig, previousDriver, previousClient := common.CurrentConfig, common.Driver, common.EventClient
+	defer func() {
+		common.CurrentConfig, common.Driver, common.EventClient = previousConfig, previousDriver, previousClient
+	}()
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	driver := &meterDriver{}
+	common.Driver = driver
+	client := &eventClient{events: make(chan *models.Event, 1)}
+	common.EventClient = client
+	initCache(t)
+	defer common.SetStandby(false)
+
+	e := &executor{device: "meter", ae: ds_models.AutoEvent{Frequency: "1h", Resource: "Power"}, interval: time.Hour}
+	common.SetStandby(true)
+	e.execute()
+	if n := driver.readCount(); n != 0 {
+		t.Fatalf("%d reads in standby mode", n)
+	}
+
+	// the promoted DS reads the Device and pushes the event
+	common.SetStandby(false)
+	e.execute()
+	if n := driver.readCount(); n != 1 {
+		t.Fatalf("%d reads after the promotion", n)
+	}
+	select {
+	case event := <-client.events:
+		if event.Device != "meter" || len(event.Readings) != 1 || event.Readings[0].Value != "1001" {
+			t.Errorf("Unexpected event %v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("No event pushed after the promotion")
+	}
+}
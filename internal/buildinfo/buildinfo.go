@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package buildinfo describes the build of the DS, so that support can
+// identify exactly which build a gateway runs. Commit and BuildDate are set
+// at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/edgexfoundry/device-sdk-go/internal/buildinfo.Commit=$(git rev-parse HEAD)
+//	  -X github.com/edgexfoundry/device-sdk-go/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+var (
+	// Commit is the source revision, set by -ldflags.
+	Commit string
+	// BuildDate is the build time, set by -ldflags.
+	BuildDate string
+	// Tags are the optional build tags, added by the files they enable.
+	Tags []string
+)
+
+// Info describes a build of the DS.
+type Info struct {
+	Name          string   `json:"name"`
+	Version       string   `json:"version"`
+	SDKAPIVersion string   `json:"sdkApiVersion"`
+	Commit        string   `json:"commit"`
+	BuildDate     string   `json:"buildDate"`
+	GoVersion     string   `json:"goVersion"`
+	Platform      string   `json:"platform"`
+	Tags          []string `json:"tags"`
+	Features      []string `json:"features"`
+}
+
+// Get returns the build information, with the given service name, version,
+// SDK API version and enabled features.
+func Get(name string, version string, apiVersion string, features []string) Info {
+	tags := append([]string{}, Tags...)
+	sort.Strings(tags)
+	if features == nil {
+		features = []string{}
+	}
+	return Info{
+		Name:          name,
+		Version:       version,
+		SDKAPIVersion: apiVersion,
+		Commit:        valueOrUnknown(Commit),
+		BuildDate:     valueOrUnknown(BuildDate),
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		Tags:          tags,
+		Features:      features,
+	}
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// String formats the information as a single key=value line, for the
+// startup log.
+func (i Info) String() string {
+	return fmt.Sprintf("name=%s version=%s sdkApiVersion=%s commit=%s buildDate=%s goVersion=%s platform=%s tags=%s features=%s",
+		i.Name, i.Version, i.SDKAPIVersion, i.Commit, i.BuildDate, i.GoVersion, i.Platform, strings.Join(i.Tags, ","), strings.Join(i.Features, ","))
+}
@@ -0,0 +1,25 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	Commit = "0123abc"
+	defer func() { Commit = "" }()
+
+	info := Get("device-simple", "1.0.0", "1.1", []string{"history"})
+	if info.Commit != "0123abc" || info.BuildDate != "unknown" || info.GoVersion == "" || len(info.Tags) != 0 {
+		t.Errorf("Unexpected info %v", info)
+	}
+	if s := info.String(); !strings.Contains(s, "commit=0123abc") || !strings.Contains(s, "features=history") {
+		t.Errorf("Unexpected log line %s", s)
+	}
+}
@@ -0,0 +1,161 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// Stats describes the usage of a cache and how often its limit was hit.
+type Stats struct {
+	// Size is the number of entries in the cache.
+	Size int `json:"size"`
+	// Limit is the maximum number of entries, zero if unbounded.
+	Limit int `json:"limit"`
+	// Hits and Misses count the lookups by name.
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	// Evictions is the number of least recently used entries evicted.
+	Evictions uint64 `json:"evictions"`
+	// Rejections is the number of entries not added as the cache was full.
+	Rejections uint64 `json:"rejections"`
+}
+
+// counters keeps the Stats of a cache, except for its size.
+type counters struct {
+	mutex  sync.Mutex
+	name   string
+	stats  Stats
+	warned bool
+}
+
+func newCounters(name string, limit int) *counters {
+	return &counters{name: name, stats: Stats{Limit: limit}}
+}
+
+func (c *counters) lookup(found bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if found {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+}
+
+// full returns true if a cache of the given size can't take another entry.
+func (c *counters) full(size int) bool {
+	return c.stats.Limit > 0 && size >= c.stats.Limit
+}
+
+func (c *counters) evicted(entry string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.Evictions++
+	c.warnLimit()
+	common.LoggingClient.Debug(fmt.Sprintf("%s cache full, evicted %s", c.name, entry))
+}
+
+func (c *counters) rejected(entry string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.Rejections++
+	msg := fmt.Sprintf("%s cache limit of %d reached, %s not added", c.name, c.stats.Limit, entry)
+	common.LoggingClient.Warn(msg)
+	return errors.New(msg)
+}
+
+// hasEvicted returns true if any entry has been evicted, i.e. a lookup miss
+// may concern an entry which exists but isn't cached anymore.
+func (c *counters) hasEvicted() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats.Evictions > 0
+}
+
+// warnLimit logs a warning the first time the limit is hit.
+func (c *counters) warnLimit() {
+	if !c.warned {
+		c.warned = true
+		common.LoggingClient.Warn(fmt.Sprintf("%s cache limit of %d reached, evicting least recently used entries", c.name, c.stats.Limit))
+	}
+}
+
+func (c *counters) snapshot(size int) Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.stats
+	s.Size = size
+	return s
+}
+
+// lruIndex keeps track of the order in which the entries of a cache have
+// been used, so that the least recently used one can be evicted.
+type lruIndex struct {
+	order *list.List               // front is the most recently used
+	elems map[string]*list.Element // key is the entry name
+}
+
+func newLRUIndex() *lruIndex {
+	return &lruIndex{order: list.New(), elems: make(map[string]*list.Element)}
+}
+
+func (l *lruIndex) touch(name string) {
+	if e, ok := l.elems[name]; ok {
+		l.order.MoveToFront(e)
+		return
+	}
+	l.elems[name] = l.order.PushFront(name)
+}
+
+func (l *lruIndex) remove(name string) {
+	if e, ok := l.elems[name]; ok {
+		l.order.Remove(e)
+		delete(l.elems, name)
+	}
+}
+
+func (l *lruIndex) oldest() (string, bool) {
+	e := l.order.Back()
+	if e == nil {
+		return "", false
+	}
+	return e.Value.(string), true
+}
+
+// cacheLimit returns the configured limit of a cache, zero if unbounded.
+func cacheLimit(limit func(info common.CacheInfo) int) int {
+	if common.CurrentConfig == nil {
+		return 0
+	}
+	return limit(common.CurrentConfig.Cache)
+}
+
+// Metrics returns the Stats of the bounded caches.
+func Metrics() map[string]Stats {
+	metrics := make(map[string]Stats, 3)
+	if dc != nil {
+		metrics["devices"] = dc.counters.snapshot(len(dc.dMap))
+	}
+	if pc != nil {
+		metrics["profiles"] = pc.counters.snapshot(len(pc.dpMap))
+	}
+	if vdc != nil {
+		metrics["valueDescriptors"] = vdc.counters.snapshot(len(vdc.vdMap))
+	}
+	return metrics
+}
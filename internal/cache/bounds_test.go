@@ -0,0 +1,66 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("cache_test", false, "", "DEBUG")
+}
+
+func TestValueDescriptorEviction(t *testing.T) {
+	common.CurrentConfig = &common.Config{Cache: common.CacheInfo{MaxValueDescriptors: 2}}
+	defer func() { common.CurrentConfig = nil }()
+
+	newValueDescriptorCache([]models.ValueDescriptor{
+		{Id: bson.NewObjectId(), Name: "a"},
+		{Id: bson.NewObjectId(), Name: "b"},
+	})
+	if _, ok := vdc.ForName("a"); !ok {
+		t.Fatal("Value Descriptor a not found")
+	}
+	vdc.Add(models.ValueDescriptor{Id: bson.NewObjectId(), Name: "c"})
+
+	if _, ok := vdc.vdMap["b"]; ok {
+		t.Error("Least recently used Value Descriptor b not evicted")
+	}
+	for _, name := range []string{"a", "c"} {
+		if _, ok := vdc.vdMap[name]; !ok {
+			t.Errorf("Value Descriptor %s evicted", name)
+		}
+	}
+
+	stats := Metrics()["valueDescriptors"]
+	if stats.Size != 2 || stats.Limit != 2 || stats.Evictions != 1 || stats.Hits != 1 {
+		t.Errorf("Unexpected Value Descriptor cache stats: %+v", stats)
+	}
+}
+
+func TestDeviceRejection(t *testing.T) {
+	common.CurrentConfig = &common.Config{Cache: common.CacheInfo{MaxDevices: 1}}
+	defer func() { common.CurrentConfig = nil }()
+
+	newDeviceCache([]models.Device{{Id: bson.NewObjectId(), Name: "d1"}})
+	if err := dc.Add(models.Device{Id: bson.NewObjectId(), Name: "d2"}); err == nil {
+		t.Error("Device added beyond the cache limit")
+	}
+	if _, ok := dc.ForName("d1"); !ok {
+		t.Error("Device d1 evicted")
+	}
+
+	stats := Metrics()["devices"]
+	if stats.Size != 1 || stats.Rejections != 1 {
+		t.Errorf("Unexpected Device cache stats: %+v", stats)
+	}
+}
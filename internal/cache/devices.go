@@ -10,6 +10,7 @@ package cache
 import (
 	"fmt"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -30,13 +31,16 @@ type DeviceCache interface {
 }
 
 type deviceCache struct {
-	dMap    map[string]*models.Device // key is Device name
-	nameMap map[string]string         // key is id, and value is Device name
+	dMap     map[string]*models.Device // key is Device name
+	nameMap  map[string]string         // key is id, and value is Device name
+	counters *counters
 }
 
 // ForName returns a Device with the given name.
 func (d *deviceCache) ForName(name string) (models.Device, bool) {
-	if device, ok := d.dMap[name]; ok {
+	device, ok := d.dMap[name]
+	d.counters.lookup(ok)
+	if ok {
 		return *device, ok
 	} else {
 		return models.Device{}, ok
@@ -70,11 +74,15 @@ func (d *deviceCache) All() []models.Device {
 
 // Adds a new device to the cache. This method is used to populate the
 // devices cache with pre-existing devices from Core Metadata, as well
-// as create new devices returned in a ScanList during discovery.
+// as create new devices returned in a ScanList during discovery. Devices
+// are never evicted, so the device is rejected if the cache is full.
 func (d *deviceCache) Add(device models.Device) error {
 	if _, ok := d.dMap[device.Name]; ok {
 		return fmt.Errorf("device %s has already existed in cache", device.Name)
 	}
+	if d.counters.full(len(d.dMap)) {
+		return d.counters.rejected(device.Name)
+	}
 	d.dMap[device.Name] = &device
 	d.nameMap[device.Id.Hex()] = device.Name
 	return nil
@@ -149,6 +157,10 @@ func newDeviceCache(devices []models.Device) DeviceCache {
 		nameMap[d.Id.Hex()] = d.Name
 	}
 	dc = &deviceCache{dMap: dMap, nameMap: nameMap}
+	dc.counters = newCounters("Device", cacheLimit(func(info common.CacheInfo) int { return info.MaxDevices }))
+	if dc.counters.full(len(dMap) + 1) {
+		common.LoggingClient.Warn(fmt.Sprintf("%d Devices exceed the Device cache limit of %d", len(dMap), dc.counters.stats.Limit))
+	}
 	return dc
 }
 
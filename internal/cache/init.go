@@ -21,44 +21,64 @@ var (
 // Init basic state for cache
 func InitCache() {
 	initOnce.Do(func() {
-		vds, err := common.ValueDescriptorClient.ValueDescriptors()
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Value Descriptor cache initialization failed: %v", err))
-			vds = make([]models.ValueDescriptor, 0)
-		}
-		newValueDescriptorCache(vds)
+		restore(fetch())
+	})
+}
 
-		ds, err := common.DeviceClient.DevicesForServiceByName(common.ServiceName)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Device cache initialization failed: %v", err))
-			ds = make([]models.Device, 0)
-		}
-		newDeviceCache(ds)
+// fetch retrieves the objects of the DS from Core Metadata and Core Data.
+func fetch() Snapshot {
+	vds, err := common.ValueDescriptorClient.ValueDescriptors()
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Value Descriptor cache initialization failed: %v", err))
+		vds = make([]models.ValueDescriptor, 0)
+	}
 
-		dps := make([]models.DeviceProfile, len(ds))
-		for i, d := range ds {
-			dps[i] = d.Profile
-		}
-		newProfileCache(dps)
+	ds, err := common.DeviceClient.DevicesForServiceByName(common.ServiceName)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Device cache initialization failed: %v", err))
+		ds = make([]models.Device, 0)
+	}
 
-		ses, err := common.ScheduleEventClient.ScheduleEventsForServiceByName(common.ServiceName)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Schedule Event cache initialization failed: %v", err))
-			ses = make([]models.ScheduleEvent, 0)
-		}
-		newScheduleEventCache(ses)
+	ses, err := common.ScheduleEventClient.ScheduleEventsForServiceByName(common.ServiceName)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Schedule Event cache initialization failed: %v", err))
+		ses = make([]models.ScheduleEvent, 0)
+	}
 
-		schMap := make(map[string]models.Schedule, len(ses))
-		for _, se := range ses {
-			if _, ok := schMap[se.Schedule]; !ok {
-				sc, err := common.ScheduleClient.ScheduleForName(se.Schedule)
-				if err != nil {
-					common.LoggingClient.Error(fmt.Sprintf("Schedule %s cannot be found in Core Metadata", se.Schedule))
-					continue
-				}
-				schMap[sc.Name] = sc
+	schs := make([]models.Schedule, 0, len(ses))
+	schMap := make(map[string]bool, len(ses))
+	for _, se := range ses {
+		if _, ok := schMap[se.Schedule]; !ok {
+			sc, err := common.ScheduleClient.ScheduleForName(se.Schedule)
+			if err != nil {
+				common.LoggingClient.Error(fmt.Sprintf("Schedule %s cannot be found in Core Metadata", se.Schedule))
+				continue
 			}
+			schMap[sc.Name] = true
+			schs = append(schs, sc)
 		}
-		newScheduleCache(schMap)
-	})
+	}
+
+	return Snapshot{ValueDescriptors: vds, Devices: ds, ScheduleEvents: ses, Schedules: schs}
+}
+
+// restore creates the caches from the given snapshot.
+func restore(snap Snapshot) {
+	newValueDescriptorCache(snap.ValueDescriptors)
+	newDeviceCache(snap.Devices)
+
+	dps := make([]models.DeviceProfile, len(snap.Devices), len(snap.Devices)+len(snap.Profiles))
+	for i, d := range snap.Devices {
+		dps[i] = d.Profile
+	}
+	dps = append(dps, snap.Profiles...)
+	newProfileCache(dps)
+
+	newScheduleEventCache(snap.ScheduleEvents)
+
+	schMap := make(map[string]models.Schedule, len(snap.Schedules))
+	for _, sc := range snap.Schedules {
+		schMap[sc.Name] = sc
+	}
+	newScheduleCache(schMap)
 }
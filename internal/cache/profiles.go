@@ -9,8 +9,10 @@ package cache
 
 import (
 	"fmt"
-	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
 const (
@@ -43,10 +45,12 @@ type profileCache struct {
 	getOpMap map[string]map[string][]models.ResourceOperation
 	setOpMap map[string]map[string][]models.ResourceOperation
 	cmdMap   map[string]map[string]models.Command
+	counters *counters
 }
 
 func (p *profileCache) ForName(name string) (models.DeviceProfile, bool) {
 	dp, ok := p.dpMap[name]
+	p.counters.lookup(ok)
 	return dp, ok
 }
 
@@ -70,10 +74,16 @@ func (p *profileCache) All() []models.DeviceProfile {
 	return ps
 }
 
+// Add adds a new profile to the cache. Profiles are never evicted, as they
+// are needed by the Devices in cache, so the profile is rejected if the
+// cache is full.
 func (p *profileCache) Add(profile models.DeviceProfile) error {
 	if _, ok := p.dpMap[profile.Name]; ok {
 		return fmt.Errorf("device profile %s has already existed in cache", profile.Name)
 	}
+	if p.counters.full(len(p.dpMap)) {
+		return p.counters.rejected(profile.Name)
+	}
 	p.dpMap[profile.Name] = profile
 	p.nameMap[profile.Id.Hex()] = profile.Name
 	p.doMap[profile.Name] = deviceObjectSliceToMap(profile.DeviceResources)
@@ -155,6 +165,8 @@ func (p *profileCache) DeviceObject(profileName string, objectName string) (mode
 
 // CommandExists returns a bool indicating whether the specified command exists for the
 // specified (by name) device. If the specified device doesn't exist, an error is returned.
+// CommandExists returns whether a profile defines a command, either a core
+// command or a device command, i.e. a set of resource operations.
 func (p *profileCache) CommandExists(profileName string, cmd string) (bool, error) {
 	commands, ok := p.cmdMap[profileName]
 	if !ok {
@@ -162,16 +174,22 @@ func (p *profileCache) CommandExists(profileName string, cmd string) (bool, erro
 		return false, err
 	}
 
-	if _, ok := commands[cmd]; !ok {
-		return false, nil
+	if _, ok := commands[cmd]; ok {
+		return true, nil
 	}
-
-	return true, nil
+	if _, ok := p.getOpMap[profileName][cmd]; ok {
+		return true, nil
+	}
+	_, ok = p.setOpMap[profileName][cmd]
+	return ok, nil
 }
 
-// Get ResourceOperations
+// ResourceOperations returns the resource operations of a command, executed
+// together by the driver. The operations referencing another device command
+// by their Resource are replaced by the operations of that command, and a
+// command without device command reads or writes the device resource of the
+// same name.
 func (p *profileCache) ResourceOperations(profileName string, cmd string, method string) ([]models.ResourceOperation, error) {
-	var resOps []models.ResourceOperation
 	var rosMap map[string][]models.ResourceOperation
 	var ok bool
 	if strings.ToLower(method) == getOpsStr {
@@ -184,10 +202,49 @@ func (p *profileCache) ResourceOperations(profileName string, cmd string, method
 		}
 	}
 
-	if resOps, ok = rosMap[cmd]; !ok {
+	if _, ok = rosMap[cmd]; !ok {
+		if _, ok = p.doMap[profileName][cmd]; ok {
+			op := strings.ToLower(method)
+			return []models.ResourceOperation{{Index: "1", Operation: op, Object: cmd, Parameter: cmd}}, nil
+		}
 		return nil, fmt.Errorf("profiles: ResourceOperations: specified cmd: %s not found", cmd)
 	}
-	return resOps, nil
+	return expandResourceOperations(rosMap, cmd, make(map[string]bool))
+}
+
+// expandResourceOperations returns the resource operations of a command,
+// replacing those referencing another command by its operations.
+func expandResourceOperations(rosMap map[string][]models.ResourceOperation, cmd string, visiting map[string]bool) ([]models.ResourceOperation, error) {
+	if visiting[cmd] {
+		return nil, fmt.Errorf("profiles: ResourceOperations: cmd: %s references itself", cmd)
+	}
+	visiting[cmd] = true
+	defer delete(visiting, cmd)
+
+	resOps := rosMap[cmd]
+	var result []models.ResourceOperation
+	for i, ro := range resOps {
+		ref, ok := rosMap[ro.Resource]
+		if ro.Resource == "" || !ok || len(ref) == 0 {
+			if result != nil {
+				result = append(result, ro)
+			}
+			continue
+		}
+		expanded, err := expandResourceOperations(rosMap, ro.Resource, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = append(make([]models.ResourceOperation, 0, len(resOps)+len(expanded)), resOps[:i]...)
+		}
+		result = append(result, expanded...)
+	}
+	if result == nil {
+		// no reference, the operations of the profile are returned as is
+		return resOps, nil
+	}
+	return result, nil
 }
 
 // Return the first matched ResourceOperation
@@ -238,6 +295,10 @@ func newProfileCache(profiles []models.DeviceProfile) ProfileCache {
 		cmdMap[dp.Name] = commandSliceToMap(dp.Commands)
 	}
 	pc = &profileCache{dpMap: dpMap, nameMap: nameMap, doMap: doMap, getOpMap: getOpMap, setOpMap: setOpMap, cmdMap: cmdMap}
+	pc.counters = newCounters("Device Profile", cacheLimit(func(info common.CacheInfo) int { return info.MaxProfiles }))
+	if pc.counters.full(len(dpMap) + 1) {
+		common.LoggingClient.Warn(fmt.Sprintf("%d Device Profiles exceed the Device Profile cache limit of %d", len(dpMap), pc.counters.stats.Limit))
+	}
 	return pc
 }
 
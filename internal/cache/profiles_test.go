@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func objects(ros []models.ResourceOperation) []string {
+	result := make([]string, len(ros))
+	for i, ro := range ros {
+		result[i] = ro.Object
+	}
+	return result
+}
+
+func TestResourceOperations(t *testing.T) {
+	common.CurrentConfig = &common.Config{}
+	defer func() { common.CurrentConfig = nil }()
+
+	newProfileCache([]models.DeviceProfile{{
+		Id:   bson.NewObjectId(),
+		Name: "Meter",
+		DeviceResources: []models.DeviceObject{
+			{Name: "Voltage"}, {Name: "Current"}, {Name: "Energy"},
+		},
+		Resources: []models.ProfileResource{
+			{Name: "Power", Get: []models.ResourceOperation{{Object: "Voltage"}, {Object: "Current"}}},
+			{Name: "All", Get: []models.ResourceOperation{{Resource: "Power"}, {Object: "Energy"}}},
+			{Name: "Loop", Get: []models.ResourceOperation{{Resource: "Loop2"}}},
+			{Name: "Loop2", Get: []models.ResourceOperation{{Resource: "Loop"}}},
+			{Name: "Reset", Set: []models.ResourceOperation{{Object: "Energy", Parameter: "Energy"}}},
+		},
+		Commands: []models.Command{{Name: "Power"}},
+	}})
+
+	tests := []struct {
+		name    string
+		cmd     string
+		method  string
+		objects []string
+		fails   bool
+	}{
+		{"Multiple", "Power", "get", []string{"Voltage", "Current"}, false},
+		{"Reference", "All", "get", []string{"Voltage", "Current", "Energy"}, false},
+		{"Cycle", "Loop", "get", nil, true},
+		{"DeviceResource", "Energy", "get", []string{"Energy"}, false},
+		{"Set", "Reset", "set", []string{"Energy"}, false},
+		{"Unknown", "Frequency", "get", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ros, err := pc.ResourceOperations("Meter", tt.cmd, tt.method)
+			if tt.fails {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", ros)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !common.CompareStrings(objects(ros), tt.objects) {
+				t.Errorf("Objects %v, expected %v", objects(ros), tt.objects)
+			}
+		})
+	}
+
+	for cmd, exists := range map[string]bool{"Power": true, "All": true, "Reset": true, "Frequency": false} {
+		if ok, _ := pc.CommandExists("Meter", cmd); ok != exists {
+			t.Errorf("Command %s exists: %v", cmd, ok)
+		}
+	}
+}
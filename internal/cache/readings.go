@@ -0,0 +1,92 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+type ReadingCache interface {
+	ForResource(deviceName string, resource string) (CachedValue, bool)
+	ForDevice(deviceName string) map[string]CachedValue
+	Add(deviceName string, cv *ds_models.CommandValue)
+	RemoveDevice(deviceName string)
+}
+
+// CachedValue is the last value read from a device resource, after its
+// transformations, and when it was read.
+type CachedValue struct {
+	Value     *ds_models.CommandValue
+	Timestamp time.Time
+}
+
+// Stale returns true if the value is older than maxAge. A zero maxAge never
+// makes it stale.
+func (v CachedValue) Stale(maxAge time.Duration) bool {
+	return maxAge > 0 && time.Since(v.Timestamp) > maxAge
+}
+
+var (
+	rcOnce  sync.Once
+	rcCache *readingCache
+)
+
+// readingCache holds the last value of each resource of the Devices, read
+// by commands or pushed asynchronously. Unlike the other caches it isn't
+// loaded from Core Metadata, and it's safe for concurrent use.
+type readingCache struct {
+	mutex sync.RWMutex
+	rcMap map[string]map[string]CachedValue // keys are Device and resource names
+}
+
+func (r *readingCache) ForResource(deviceName string, resource string) (CachedValue, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	v, ok := r.rcMap[deviceName][resource]
+	return v, ok
+}
+
+func (r *readingCache) ForDevice(deviceName string) map[string]CachedValue {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	values := make(map[string]CachedValue, len(r.rcMap[deviceName]))
+	for name, v := range r.rcMap[deviceName] {
+		values[name] = v
+	}
+	return values
+}
+
+func (r *readingCache) Add(deviceName string, cv *ds_models.CommandValue) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	values, ok := r.rcMap[deviceName]
+	if !ok {
+		values = make(map[string]CachedValue)
+		r.rcMap[deviceName] = values
+	}
+	values[cv.RO.Object] = CachedValue{Value: cv, Timestamp: time.Now()}
+}
+
+func (r *readingCache) RemoveDevice(deviceName string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.rcMap, deviceName)
+}
+
+func Readings() ReadingCache {
+	rcOnce.Do(func() {
+		rcCache = &readingCache{rcMap: make(map[string]map[string]CachedValue)}
+	})
+	return rcCache
+}
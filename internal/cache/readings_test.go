@@ -0,0 +1,38 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestReadingCache(t *testing.T) {
+	ro := &models.ResourceOperation{Object: "Voltage"}
+	Readings().Add("meter", ds_models.NewStringValue(ro, 0, "230"))
+	Readings().Add("meter", ds_models.NewStringValue(ro, 0, "231"))
+
+	v, ok := Readings().ForResource("meter", "Voltage")
+	if !ok || v.Value.ValueToString() != "231" {
+		t.Fatalf("Expected the last value to be cached, got %v", v.Value)
+	}
+	if v.Stale(0) || v.Stale(time.Minute) {
+		t.Errorf("Expected a fresh value")
+	}
+	v.Timestamp = v.Timestamp.Add(-time.Hour)
+	if !v.Stale(time.Minute) {
+		t.Errorf("Expected a stale value")
+	}
+
+	Readings().RemoveDevice("meter")
+	if _, ok := Readings().ForResource("meter", "Voltage"); ok || len(Readings().ForDevice("meter")) != 0 {
+		t.Errorf("Expected the values of the device to be removed")
+	}
+}
@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Snapshot holds the contents of the caches, so that they can be persisted
+// and used to initialize the caches on a warm start.
+type Snapshot struct {
+	ValueDescriptors []models.ValueDescriptor `json:"valueDescriptors"`
+	Devices          []models.Device          `json:"devices"`
+	Profiles         []models.DeviceProfile   `json:"profiles"`
+	ScheduleEvents   []models.ScheduleEvent   `json:"scheduleEvents"`
+	Schedules        []models.Schedule        `json:"schedules"`
+}
+
+// snapshotSchema versions the format of the snapshots.
+var snapshotSchema = statedir.Schema{Version: 1, Migrations: []statedir.Migration{statedir.Unversioned}}
+
+// InitCacheFromFile initializes the caches from a snapshot previously saved
+// to the given file, instead of retrieving their contents from Core Metadata.
+func InitCacheFromFile(file string) error {
+	contents, err := statedir.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var snap Snapshot
+	if err = snapshotSchema.Unmarshal(contents, &snap); err != nil {
+		return err
+	}
+
+	initialized := false
+	initOnce.Do(func() {
+		restore(snap)
+		initialized = true
+	})
+	if !initialized {
+		return fmt.Errorf("cache has already been initialized")
+	}
+	return nil
+}
+
+// SaveToFile saves a snapshot of the caches to the given file.
+func SaveToFile(file string) error {
+	snap := Snapshot{
+		ValueDescriptors: ValueDescriptors().All(),
+		Devices:          Devices().All(),
+		Profiles:         Profiles().All(),
+		ScheduleEvents:   ScheduleEvents().All(),
+		Schedules:        Schedules().All(),
+	}
+	contents, err := snapshotSchema.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(file, contents)
+}
+
+// Persist saves a snapshot of the caches to the configured CacheFile, if any.
+func Persist() {
+	file := common.CurrentConfig.Service.CacheFile
+	if file == "" {
+		return
+	}
+	if err := SaveToFile(file); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't save the caches to %s: %v", file, err))
+	}
+}
+
+// Reconcile retrieves the objects of the DS from Core Metadata and Core Data
+// and updates the caches accordingly. Objects are replaced by name, as their
+// ids change if they have been recreated. Devices which no longer exist in
+// Core Metadata are removed from the cache. It returns the Schedule Events
+// retrieved, to be run instead of those loaded.
+func Reconcile() []models.ScheduleEvent {
+	snap := fetch()
+
+	for _, vd := range snap.ValueDescriptors {
+		ValueDescriptors().RemoveByName(vd.Name)
+		ValueDescriptors().Add(vd)
+	}
+
+	names := make(map[string]bool, len(snap.Devices))
+	for _, d := range snap.Devices {
+		names[d.Name] = true
+		Profiles().RemoveByName(d.Profile.Name)
+		Profiles().Add(d.Profile)
+		Devices().RemoveByName(d.Name)
+		Devices().Add(d)
+	}
+	for _, d := range Devices().All() {
+		if !names[d.Name] {
+			common.LoggingClient.Info(fmt.Sprintf("Device %s no longer exists in Core Metadata, removing it from cache", d.Name))
+			Devices().RemoveByName(d.Name)
+		}
+	}
+
+	for _, sc := range snap.Schedules {
+		Schedules().RemoveByName(sc.Name)
+		Schedules().Add(sc)
+	}
+	for _, se := range snap.ScheduleEvents {
+		ScheduleEvents().RemoveByName(se.Name)
+		ScheduleEvents().Add(se)
+	}
+	return snap.ScheduleEvents
+}
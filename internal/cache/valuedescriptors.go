@@ -9,6 +9,8 @@ package cache
 
 import (
 	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -25,14 +27,33 @@ type ValueDescriptorCache interface {
 	RemoveByName(name string) error
 }
 
+// valueDescriptorCache evicts the least recently used Value Descriptors when
+// its limit is hit, as they can always be retrieved again from Core Data.
 type valueDescriptorCache struct {
-	vdMap   map[string]models.ValueDescriptor // key is ValueDescriptor name
-	nameMap map[string]string                 // key is id, and value is ValueDescriptor name
+	vdMap    map[string]models.ValueDescriptor // key is ValueDescriptor name
+	nameMap  map[string]string                 // key is id, and value is ValueDescriptor name
+	lru      *lruIndex
+	counters *counters
 }
 
 func (v *valueDescriptorCache) ForName(name string) (models.ValueDescriptor, bool) {
 	vd, ok := v.vdMap[name]
-	return vd, ok
+	v.counters.lookup(ok)
+	if ok {
+		v.lru.touch(name)
+		return vd, ok
+	}
+
+	// the Value Descriptor may have been evicted
+	if !v.counters.hasEvicted() {
+		return vd, ok
+	}
+	vd, err := common.ValueDescriptorClient.ValueDescriptorForName(name)
+	if err != nil {
+		return models.ValueDescriptor{}, false
+	}
+	v.Add(vd)
+	return vd, true
 }
 
 func (v *valueDescriptorCache) All() []models.ValueDescriptor {
@@ -50,8 +71,15 @@ func (v *valueDescriptorCache) Add(descriptor models.ValueDescriptor) error {
 	if ok {
 		return fmt.Errorf("value descriptor %s has already existed in cache", descriptor.Name)
 	}
+	if v.counters.full(len(v.vdMap)) {
+		if oldest, ok := v.lru.oldest(); ok {
+			v.RemoveByName(oldest)
+			v.counters.evicted(oldest)
+		}
+	}
 	v.vdMap[descriptor.Name] = descriptor
 	v.nameMap[descriptor.Id.Hex()] = descriptor.Name
+	v.lru.touch(descriptor.Name)
 	return nil
 }
 
@@ -78,6 +106,7 @@ func (v *valueDescriptorCache) RemoveByName(name string) error {
 	}
 	delete(v.nameMap, vd.Id.Hex())
 	delete(v.vdMap, name)
+	v.lru.remove(name)
 	return nil
 }
 
@@ -85,11 +114,11 @@ func newValueDescriptorCache(descriptors []models.ValueDescriptor) ValueDescript
 	defaultSize := len(descriptors) * 2
 	vdMap := make(map[string]models.ValueDescriptor, defaultSize)
 	nameMap := make(map[string]string, defaultSize)
+	vdc = &valueDescriptorCache{vdMap: vdMap, nameMap: nameMap, lru: newLRUIndex()}
+	vdc.counters = newCounters("Value Descriptor", cacheLimit(func(info common.CacheInfo) int { return info.MaxValueDescriptors }))
 	for _, vd := range descriptors {
-		vdMap[vd.Name] = vd
-		nameMap[vd.Id.Hex()] = vd.Name
+		vdc.Add(vd)
 	}
-	vdc = &valueDescriptorCache{vdMap: vdMap, nameMap: nameMap}
 	return vdc
 }
 
@@ -0,0 +1,229 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package capture assembles the chunks of long-running captures, such as
+// the waveform files exported by power-quality meters, streamed by the
+// driver. Completed captures are stored as files in the state directory,
+// the oldest ones being removed beyond a maximum count.
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const (
+	StateInProgress = "InProgress"
+	StateComplete   = "Complete"
+	StateFailed     = "Failed"
+
+	indexFile = "index"
+)
+
+// ErrNotFound is returned for an unknown capture.
+var ErrNotFound = errors.New("capture not found")
+
+// Status describes a capture.
+type Status struct {
+	ID     string `json:"id"`
+	Device string `json:"device"`
+	State  string `json:"state"`
+	// Chunks and Size are the number of chunks and bytes received so far.
+	Chunks int `json:"chunks"`
+	Size   int `json:"size"`
+	// Started and Updated are the times (in milliseconds) of the first and
+	// last chunks.
+	Started int64  `json:"started"`
+	Updated int64  `json:"updated"`
+	Error   string `json:"error,omitempty"`
+}
+
+type capture struct {
+	status Status
+	data   []byte
+}
+
+var (
+	mutex    sync.Mutex
+	dir      string
+	max      int
+	captures = make(map[string]*capture)
+)
+
+// Init sets the directory, relative to the state directory, the captures
+// are stored in and the maximum number of completed captures retained. The
+// captures stored by a previous run are loaded.
+func Init(captureDir string, maxCaptures int) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	dir = captureDir
+	max = maxCaptures
+	captures = make(map[string]*capture)
+	if dir != "" {
+		if err := os.MkdirAll(statedir.Path(dir), 0755); err != nil {
+			return err
+		}
+	}
+
+	contents, err := statedir.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var statuses []Status
+	if err = json.Unmarshal(contents, &statuses); err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		captures[s.ID] = &capture{status: s}
+	}
+	return nil
+}
+
+// Add appends a chunk streamed by the driver to its capture, storing the
+// capture once the last chunk is received.
+func Add(deviceName string, chunk *ds_models.CaptureChunk) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	c, ok := captures[chunk.ID]
+	if !ok || c.status.State != StateInProgress {
+		if chunk.Sequence != 0 {
+			return fmt.Errorf("chunk %d of unknown capture %s", chunk.Sequence, chunk.ID)
+		}
+		c = &capture{status: Status{ID: chunk.ID, Device: deviceName, State: StateInProgress, Started: now}}
+		captures[chunk.ID] = c
+	}
+	c.status.Updated = now
+
+	switch {
+	case chunk.Error != "":
+		return c.fail(chunk.Error)
+	case chunk.Sequence != c.status.Chunks:
+		return c.fail(fmt.Sprintf("chunk %d received, %d expected", chunk.Sequence, c.status.Chunks))
+	}
+	c.data = append(c.data, chunk.Data...)
+	c.status.Chunks++
+	c.status.Size = len(c.data)
+	if !chunk.Last {
+		return nil
+	}
+
+	if err := statedir.WriteFile(dataFile(c.status.ID), c.data); err != nil {
+		return c.fail(err.Error())
+	}
+	c.status.State = StateComplete
+	c.data = nil
+	prune()
+	return saveIndex()
+}
+
+func (c *capture) fail(reason string) error {
+	c.status.State = StateFailed
+	c.status.Error = reason
+	c.data = nil
+	saveIndex()
+	return fmt.Errorf("capture %s failed: %s", c.status.ID, reason)
+}
+
+// ForID returns the status of a capture.
+func ForID(id string) (Status, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	c, ok := captures[id]
+	if !ok {
+		return Status{}, false
+	}
+	return c.status, true
+}
+
+// ForDevice returns the status of the captures of the named Device, oldest
+// first.
+func ForDevice(deviceName string) []Status {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	result := make([]Status, 0)
+	for _, c := range captures {
+		if c.status.Device == deviceName {
+			result = append(result, c.status)
+		}
+	}
+	sortByStart(result)
+	return result
+}
+
+// Data returns the contents of a completed capture.
+func Data(id string) ([]byte, error) {
+	s, ok := ForID(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if s.State != StateComplete {
+		return nil, fmt.Errorf("capture %s is %s", id, s.State)
+	}
+	return statedir.ReadFile(dataFile(id))
+}
+
+// prune removes the oldest completed captures beyond the maximum count.
+func prune() {
+	if max <= 0 {
+		return
+	}
+	var complete []Status
+	for _, c := range captures {
+		if c.status.State == StateComplete {
+			complete = append(complete, c.status)
+		}
+	}
+	sortByStart(complete)
+	for i := 0; i < len(complete)-max; i++ {
+		delete(captures, complete[i].ID)
+		os.Remove(statedir.Path(dataFile(complete[i].ID)))
+	}
+}
+
+func saveIndex() error {
+	statuses := make([]Status, 0, len(captures))
+	for _, c := range captures {
+		if c.status.State != StateInProgress {
+			statuses = append(statuses, c.status)
+		}
+	}
+	sortByStart(statuses)
+	contents, err := json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(filepath.Join(dir, indexFile), contents)
+}
+
+func dataFile(id string) string {
+	return filepath.Join(dir, filepath.Base(id)+".dat")
+}
+
+func sortByStart(statuses []Status) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Started != statuses[j].Started {
+			return statuses[i].Started < statuses[j].Started
+		}
+		return statuses[i].ID < statuses[j].ID
+	})
+}
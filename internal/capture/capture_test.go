@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package capture
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+func initTestDir(t *testing.T, maxCaptures int) func() {
+	tmp, err := ioutil.TempDir("", "capture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = statedir.Init(tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err = Init("captures", maxCaptures); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		statedir.Init("")
+		os.RemoveAll(tmp)
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	defer initTestDir(t, 0)()
+
+	chunks := []string{"wave", "form", "data"}
+	for i, data := range chunks {
+		chunk := &ds_models.CaptureChunk{ID: "w1", Sequence: i, Data: []byte(data), Last: i == len(chunks)-1}
+		if err := Add("meter", chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, ok := ForID("w1")
+	if !ok || s.State != StateComplete || s.Chunks != 3 || s.Size != 12 {
+		t.Fatalf("Unexpected status %+v", s)
+	}
+	data, err := Data("w1")
+	if err != nil || string(data) != "waveformdata" {
+		t.Fatalf("Unexpected data %q: %v", data, err)
+	}
+
+	// the completed captures survive a restart
+	if err = Init("captures", 0); err != nil {
+		t.Fatal(err)
+	}
+	if ss := ForDevice("meter"); len(ss) != 1 || ss[0].State != StateComplete {
+		t.Fatalf("Unexpected statuses after restart %+v", ss)
+	}
+}
+
+func TestOutOfSequence(t *testing.T) {
+	defer initTestDir(t, 0)()
+
+	Add("meter", &ds_models.CaptureChunk{ID: "w2", Sequence: 0, Data: []byte("a")})
+	if err := Add("meter", &ds_models.CaptureChunk{ID: "w2", Sequence: 2, Data: []byte("c")}); err == nil {
+		t.Fatal("Out of sequence chunk accepted")
+	}
+	if s, _ := ForID("w2"); s.State != StateFailed {
+		t.Errorf("Unexpected state %s", s.State)
+	}
+	if _, err := Data("w2"); err == nil {
+		t.Error("Data of failed capture returned")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	defer initTestDir(t, 1)()
+
+	for _, id := range []string{"w3", "w4"} {
+		Add("meter", &ds_models.CaptureChunk{ID: id, Data: []byte(id), Last: true})
+	}
+	if _, ok := ForID("w3"); ok {
+		t.Error("Oldest capture not pruned")
+	}
+	if _, ok := ForID("w4"); !ok {
+		t.Error("Newest capture pruned")
+	}
+}
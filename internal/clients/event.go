@@ -0,0 +1,48 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+)
+
+const contentTypeCBOR = "application/cbor"
+
+// eventClient is a Core Data client which also pushes CBOR encoded events,
+// through the default transport like the other clients.
+type eventClient struct {
+	coredata.EventClient
+}
+
+// newEventClient wraps a Core Data client to push CBOR encoded events.
+func newEventClient(c coredata.EventClient) coredata.EventClient {
+	return eventClient{c}
+}
+
+func (c eventClient) AddCBOR(body []byte) (string, error) {
+	info := common.CurrentConfig.Clients[common.ClientData]
+	client := http.Client{Timeout: time.Duration(info.Timeout) * time.Millisecond}
+	resp, err := client.Post(info.Url()+clients.ApiEventRoute, contentTypeCBOR, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	msg, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("Core Data returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return string(msg), nil
+}
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+)
+
+func TestAddCBOR(test *testing.T) {
+	var path, contentType string
+	var body []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		contentType = req.Header.Get("Content-Type")
+		body, _ = ioutil.ReadAll(req.Body)
+		w.WriteHeader(status)
+		w.Write([]byte("5b9a4f9a0e6a6b0001a1b2c3"))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{Clients: map[string]common.ClientInfo{
+		common.ClientData: {Protocol: "http", Host: u.Hostname(), Port: port, Timeout: 1000},
+	}}
+
+	c, ok := newEventClient(nil).(common.CBOREventClient)
+	if !ok {
+		test.Fatal("Core Data client can't push CBOR encoded events")
+	}
+	id, err := c.AddCBOR([]byte{0xa0})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if path != clients.ApiEventRoute || contentType != contentTypeCBOR || !bytes.Equal(body, []byte{0xa0}) {
+		test.Errorf("Pushed % x as %s to %s", body, contentType, path)
+	}
+	if id != "5b9a4f9a0e6a6b0001a1b2c3" {
+		test.Errorf("Unexpected event id %s", id)
+	}
+
+	status = http.StatusInternalServerError
+	if _, err = c.AddCBOR([]byte{0xa0}); err == nil {
+		test.Error("Failure of Core Data not returned")
+	}
+}
@@ -27,6 +27,35 @@ import (
 
 const clientCount int = 8
 
+// Overrides are the clients injected by an application embedding the DS,
+// used instead of those created from the configuration. The nil clients are
+// created as usual.
+type Overrides struct {
+	Logging         logger.LoggingClient
+	Event           coredata.EventClient
+	ValueDescriptor coredata.ValueDescriptorClient
+	Addressable     metadata.AddressableClient
+	Device          metadata.DeviceClient
+	DeviceService   metadata.DeviceServiceClient
+	DeviceProfile   metadata.DeviceProfileClient
+	Schedule        metadata.ScheduleClient
+	ScheduleEvent   metadata.ScheduleEventClient
+}
+
+var overrides Overrides
+
+// Override sets the clients injected by the application.
+func Override(o Overrides) {
+	overrides = o
+}
+
+// complete tells if all the clients of Core Data and Core Metadata are
+// injected, the DS then not depending on the services themselves.
+func (o Overrides) complete() bool {
+	return o.Event != nil && o.ValueDescriptor != nil && o.Addressable != nil && o.Device != nil &&
+		o.DeviceService != nil && o.DeviceProfile != nil && o.Schedule != nil && o.ScheduleEvent != nil
+}
+
 // InitDependencyClients triggers Service Client Initializer to establish connection to Metadata and Core Data Services
 // through Metadata Client and Core Data Client.
 // Service Client Initializer also needs to check the service status of Metadata and Core Data Services,
@@ -38,12 +67,20 @@ func InitDependencyClients() error {
 	}
 
 	initializeLoggingClient()
+	config.SetRegistryLogger(common.LoggingClient)
+
+	if overrides.complete() {
+		applyOverrides()
+		common.LoggingClient.Info("Service clients injected.")
+		return nil
+	}
 
 	if err := checkDependencyServices(); err != nil {
 		return err
 	}
 
 	initializeClients()
+	applyOverrides()
 
 	common.LoggingClient.Info("Service clients initialize successful.")
 	return nil
@@ -73,6 +110,11 @@ func validateClientConfig() error {
 }
 
 func initializeLoggingClient() {
+	if overrides.Logging != nil {
+		common.LoggingClient = overrides.Logging
+		return
+	}
+
 	var logTarget string
 	config := common.CurrentConfig
 
@@ -84,7 +126,17 @@ func initializeLoggingClient() {
 		fmt.Println("EnableRemote is false, using local log file")
 	}
 
-	common.LoggingClient = logger.NewClient(common.ServiceName, config.Logging.EnableRemote, logTarget, config.Logging.Level)
+	level := config.Logging.Level
+	if config.Writable.LogLevel != "" {
+		level = config.Writable.LogLevel
+	}
+	common.LoggingClient = logger.NewClient(common.ServiceName, config.Logging.EnableRemote, logTarget, level)
+}
+
+// ResetLoggingClient replaces the logging client, e.g. after a change of the
+// log level.
+func ResetLoggingClient() {
+	initializeLoggingClient()
 }
 
 func checkDependencyServices() error {
@@ -151,6 +203,11 @@ func checkServiceAvailableByPing(serviceId string) error {
 	return err
 }
 
+// Ping checks the reachability of a service dependency, e.g. ClientData.
+func Ping(serviceId string) error {
+	return checkServiceAvailableByPing(serviceId)
+}
+
 func checkServiceAvailableByConsul(serviceConsulId string) bool {
 	common.LoggingClient.Info(fmt.Sprintf("Check %v service's status by Consul...", serviceConsulId))
 
@@ -257,7 +314,7 @@ func initializeClients() {
 
 	params.Path = clients.ApiEventRoute
 	params.Url = dataAddr + params.Path
-	common.EventClient = coredata.NewEventClient(params, consulEndpoint)
+	common.EventClient = newEventClient(coredata.NewEventClient(params, consulEndpoint))
 
 	params.Path = common.APIValueDescriptorRoute
 	params.Url = dataAddr + params.Path
@@ -268,3 +325,32 @@ func initializeClients() {
 		waitGroup.Wait()
 	}
 }
+
+// applyOverrides replaces the clients created from the configuration by the
+// injected ones.
+func applyOverrides() {
+	if overrides.Event != nil {
+		common.EventClient = overrides.Event
+	}
+	if overrides.ValueDescriptor != nil {
+		common.ValueDescriptorClient = overrides.ValueDescriptor
+	}
+	if overrides.Addressable != nil {
+		common.AddressableClient = overrides.Addressable
+	}
+	if overrides.Device != nil {
+		common.DeviceClient = overrides.Device
+	}
+	if overrides.DeviceService != nil {
+		common.DeviceServiceClient = overrides.DeviceService
+	}
+	if overrides.DeviceProfile != nil {
+		common.DeviceProfileClient = overrides.DeviceProfile
+	}
+	if overrides.Schedule != nil {
+		common.ScheduleClient = overrides.Schedule
+	}
+	if overrides.ScheduleEvent != nil {
+		common.ScheduleEventClient = overrides.ScheduleEvent
+	}
+}
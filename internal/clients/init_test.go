@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 )
 
@@ -40,3 +41,22 @@ func TestCheckServiceAvailableByPingWithTimeoutError(test *testing.T) {
 	}
 
 }
+
+func TestApplyOverrides(test *testing.T) {
+	defer Override(Overrides{})
+	device := &mock.DeviceClientMock{}
+	Override(Overrides{Device: device})
+	if overrides.complete() {
+		test.Fatal("Partial overrides taken as complete")
+	}
+
+	common.DeviceClient = nil
+	common.AddressableClient = nil
+	applyOverrides()
+	if common.DeviceClient != device {
+		test.Error("Device client not injected")
+	}
+	if common.AddressableClient != nil {
+		test.Error("Addressable client injected")
+	}
+}
@@ -0,0 +1,92 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clock estimates the drift between the clock of each Device (e.g.
+// the RTC of a meter) and the clock of the gateway, from the device-origin
+// timestamps supplied by the driver.
+package clock
+
+import (
+	"sync"
+)
+
+// window is the number of samples used to estimate the offset of a Device.
+const window = 16
+
+// Offset describes the measured clock offset of a Device.
+type Offset struct {
+	// Offset is the estimated device time minus gateway time, in milliseconds.
+	Offset int64 `json:"offset"`
+	// Samples is the number of samples the estimation is based on.
+	Samples int `json:"samples"`
+	// Updated is the gateway time (in milliseconds) of the last sample.
+	Updated int64 `json:"updated"`
+}
+
+type estimator struct {
+	samples []int64
+	next    int
+	updated int64
+}
+
+var (
+	mutex      sync.Mutex
+	estimators = make(map[string]*estimator) // key is Device name
+)
+
+// Observe records a device-origin timestamp of the named Device, together
+// with the gateway time it was received at, and returns the updated offset.
+func Observe(deviceName string, origin int64, now int64) Offset {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	e, ok := estimators[deviceName]
+	if !ok {
+		e = &estimator{}
+		estimators[deviceName] = e
+	}
+	if len(e.samples) < window {
+		e.samples = append(e.samples, origin-now)
+	} else {
+		e.samples[e.next] = origin - now
+		e.next = (e.next + 1) % window
+	}
+	e.updated = now
+	return e.offset()
+}
+
+// ForDevice returns the measured offset of the named Device, if any.
+func ForDevice(deviceName string) (Offset, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	e, ok := estimators[deviceName]
+	if !ok {
+		return Offset{}, false
+	}
+	return e.offset(), true
+}
+
+// Remove discards the samples of the named Device.
+func Remove(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(estimators, deviceName)
+}
+
+// offset returns the largest sample of the window. The transfer delay
+// always makes a sample smaller than the real offset, so the largest one
+// is the least affected by it.
+func (e *estimator) offset() Offset {
+	max := e.samples[0]
+	for _, s := range e.samples[1:] {
+		if s > max {
+			max = s
+		}
+	}
+	return Offset{Offset: max, Samples: len(e.samples), Updated: e.updated}
+}
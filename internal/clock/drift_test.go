@@ -0,0 +1,44 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clock
+
+import (
+	"testing"
+)
+
+func TestObserve(t *testing.T) {
+	defer Remove("meter")
+
+	// device clock is 5000ms ahead, with a transfer delay between 10 and 40ms
+	now := int64(1000000)
+	for i, delay := range []int64{40, 10, 25} {
+		off := Observe("meter", now+5000, now+delay)
+		if off.Samples != i+1 {
+			t.Errorf("Expected %d samples but got: %d", i+1, off.Samples)
+		}
+	}
+
+	off, ok := ForDevice("meter")
+	if !ok {
+		t.Fatal("Offset not found")
+	}
+	if off.Offset != 4990 {
+		t.Errorf("Expected offset 4990 but got: %d", off.Offset)
+	}
+
+	for i := 0; i < window; i++ {
+		off = Observe("meter", now-2000, now)
+	}
+	if off.Offset != -2000 || off.Samples != window {
+		t.Errorf("Expected offset -2000 of %d samples but got: %+v", window, off)
+	}
+
+	Remove("meter")
+	if _, ok = ForDevice("meter"); ok {
+		t.Error("Offset not removed")
+	}
+}
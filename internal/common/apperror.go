@@ -6,7 +6,11 @@
 
 package common
 
-import "net/http"
+import (
+	"net/http"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
 
 type AppError interface {
 	Error() error
@@ -14,6 +18,13 @@ type AppError interface {
 	Code() int
 }
 
+// RetryableError is an AppError telling the client when to retry, in
+// seconds.
+type RetryableError interface {
+	AppError
+	RetryAfter() int
+}
+
 type appError struct {
 	err  error
 	msg  string
@@ -32,6 +43,15 @@ func (a appError) Code() int {
 	return a.code
 }
 
+type busyError struct {
+	appError
+	retryAfter int
+}
+
+func (b busyError) RetryAfter() int {
+	return b.retryAfter
+}
+
 func NewNotFoundError(msg string, err error) AppError {
 	return appError{err: err, msg: msg, code: http.StatusNotFound}
 }
@@ -47,3 +67,40 @@ func NewBadRequestError(msg string, err error) AppError {
 func NewLockedError(msg string, err error) AppError {
 	return appError{err: err, msg: msg, code: http.StatusLocked}
 }
+
+func NewPreconditionFailedError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusPreconditionFailed}
+}
+
+func NewServiceUnavailableError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusServiceUnavailable}
+}
+
+func NewNotImplementedError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusNotImplemented}
+}
+
+// NewDriverError returns the error of a command failed by the driver, its
+// code mapped from the category of a DriverError, or a server error.
+func NewDriverError(msg string, err error) AppError {
+	code := http.StatusInternalServerError
+	if de, ok := err.(*ds_models.DriverError); ok {
+		switch de.Category {
+		case ds_models.ErrorTimeout:
+			code = http.StatusGatewayTimeout
+		case ds_models.ErrorProtocolException:
+			code = http.StatusBadGateway
+		case ds_models.ErrorNotSupported:
+			code = http.StatusNotImplemented
+		case ds_models.ErrorDeviceOffline:
+			code = http.StatusServiceUnavailable
+		}
+	}
+	return appError{err: err, msg: msg, code: code}
+}
+
+// NewBusyError returns the error of a command rejected as the DS is
+// saturated, to be retried after the given number of seconds.
+func NewBusyError(msg string, retryAfter int) AppError {
+	return busyError{appError: appError{msg: msg, code: http.StatusServiceUnavailable}, retryAfter: retryAfter}
+}
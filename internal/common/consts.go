@@ -23,6 +23,56 @@ const (
 	APIValueDescriptorRoute = APIv1Prefix + "/valuedescriptor"
 	APIDiscoveryRoute       = APIv1Prefix + "/discovery"
 	APIPingRoute            = APIv1Prefix + "/ping"
+	APIHealthRoute          = APIv1Prefix + "/health"
+	APIFederationRoute      = APIv1Prefix + "/federation"
 
 	SchedulerExecCMDPattern = APIv1Prefix + "/device/name/*/*"
+
+	StartModeCold = "cold"
+	StartModeWarm = "warm"
+	StartModeHot  = "hot"
+
+	TenantLabelPrefix = "tenant:"
+	TenantReadingName = "Tenant"
+
+	SnapshotReadingName = "Snapshot"
+
+	// FederationLabelPrefix prefixes the label naming the peer of a Device
+	// proxied by the DS
+	FederationLabelPrefix = "federation:"
+
+	// Precisions of the origins of the events pushed to Core Data
+	PrecisionMillis = "ms"
+	PrecisionMicros = "us"
+	PrecisionNanos  = "ns"
+
+	PrecisionReadingName = "OriginPrecision"
+	TimezoneReadingName  = "Timezone"
+
+	// Device resource (aka DeviceObject) attributes interpreted by the SDK
+	AttrSelectBeforeOperate  = "SelectBeforeOperate"
+	AttrSelectTimeout        = "SelectTimeout"
+	AttrAllowedValues        = "AllowedValues"
+	AttrCompression          = "Compression"
+	AttrCompressionThreshold = "CompressionThreshold"
+	AttrStatistics           = "Statistics"
+	AttrIntegrationMaxGap    = "IntegrationMaxGap"
+	AttrExpandNames          = "ExpandNames"
+	AttrExpandType           = "ExpandType"
+	AttrExpandStart          = "ExpandStart"
+	AttrAnomalyDetector      = "AnomalyDetector"
+	AttrAnomalyThreshold     = "AnomalyThreshold"
+	AttrAnomalyWindow        = "AnomalyWindow"
+	AttrAnomalyAction        = "AnomalyAction"
+	AttrInvalidValues        = "InvalidValues"
+	AttrMediaType            = "MediaType"
+	AttrTransformerRatio     = "TransformerRatio"
+	AttrAutoEventFrequency   = "AutoEventFrequency"
+	AttrAutoEventOnChange    = "AutoEventOnChange"
+
+	AnomalyReadingName = "Anomaly"
 )
+
+// DefaultShutdownTimeout is the time (in milliseconds) given to the DS to
+// stop, unless configured.
+const DefaultShutdownTimeout = 5000
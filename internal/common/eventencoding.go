@@ -0,0 +1,131 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/cbor"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// A binary reading carries its value as a data URL, e.g.
+// "data:application/octet-stream;base64,AAEC", which stays valid text for
+// the message queue and the dead letters, and is decoded in the CBOR events.
+const (
+	binaryValuePrefix = "data:"
+	binaryValueBase64 = ";base64,"
+)
+
+// CBOREventClient is implemented by the Core Data clients able to push CBOR
+// encoded events. The events with binary readings are pushed as JSON, their
+// values being data URLs, through the clients which aren't.
+type CBOREventClient interface {
+	AddCBOR(body []byte) (string, error)
+}
+
+var (
+	binaryMutex sync.Mutex
+	// binaryReadings are the media types of the binary readings, by Device
+	// and reading name.
+	binaryReadings = make(map[string]string)
+)
+
+func binaryReadingKey(deviceName string, readingName string) string {
+	return deviceName + "/" + readingName
+}
+
+// MarkBinaryReading records that the readings of the given name of a Device
+// are binary readings of the given media type, so that their values are
+// decoded in the CBOR events, whatever they look like otherwise.
+func MarkBinaryReading(deviceName string, readingName string, mediaType string) {
+	binaryMutex.Lock()
+	defer binaryMutex.Unlock()
+
+	binaryReadings[binaryReadingKey(deviceName, readingName)] = mediaType
+}
+
+// BinaryReadingValue returns the value of a binary reading of the given
+// media type.
+func BinaryReadingValue(mediaType string, data []byte) string {
+	return binaryValuePrefix + mediaType + binaryValueBase64 + base64.StdEncoding.EncodeToString(data)
+}
+
+// binaryReadingValue decodes the value of a reading marked as binary.
+func binaryReadingValue(r models.Reading) (string, []byte, bool) {
+	binaryMutex.Lock()
+	mediaType, ok := binaryReadings[binaryReadingKey(r.Device, r.Name)]
+	binaryMutex.Unlock()
+	if !ok {
+		return "", nil, false
+	}
+
+	prefix := binaryValuePrefix + mediaType + binaryValueBase64
+	if !strings.HasPrefix(r.Value, prefix) {
+		return "", nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(r.Value[len(prefix):])
+	if err != nil {
+		return "", nil, false
+	}
+	return mediaType, data, true
+}
+
+// hasBinaryReadings returns whether an event has binary readings, pushed
+// CBOR encoded.
+func hasBinaryReadings(event *models.Event) bool {
+	for _, r := range event.Readings {
+		if _, _, ok := binaryReadingValue(r); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeEventCBOR returns the CBOR encoding of an event, the values of its
+// binary readings being raw bytes.
+func EncodeEventCBOR(event *models.Event) ([]byte, error) {
+	readings := make([]interface{}, len(event.Readings))
+	for i, r := range event.Readings {
+		reading := map[string]interface{}{
+			"name":   r.Name,
+			"device": r.Device,
+			"origin": r.Origin,
+		}
+		if mediaType, data, ok := binaryReadingValue(r); ok {
+			reading["binaryValue"] = data
+			reading["mediaType"] = mediaType
+		} else {
+			reading["value"] = r.Value
+		}
+		readings[i] = reading
+	}
+	return cbor.Marshal(map[string]interface{}{
+		"device":   event.Device,
+		"origin":   event.Origin,
+		"readings": readings,
+	})
+}
+
+// addEvent pushes an event to Core Data, CBOR encoded if it has binary
+// readings and the client supports it.
+func addEvent(event *models.Event) error {
+	client, ok := EventClient.(CBOREventClient)
+	if !ok || !hasBinaryReadings(event) {
+		_, err := EventClient.Add(event)
+		return err
+	}
+
+	body, err := EncodeEventCBOR(event)
+	if err != nil {
+		return err
+	}
+	_, err = client.AddCBOR(body)
+	return err
+}
@@ -8,6 +8,8 @@ package common
 
 import (
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/publisher"
+	"github.com/edgexfoundry/device-sdk-go/pkg/signing"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/metadata"
@@ -17,6 +19,7 @@ import (
 var (
 	ServiceName           string
 	ServiceVersion        string
+	DriverAPIVersion      string
 	CurrentConfig         *Config
 	CurrentDeviceService  models.DeviceService
 	UseRegistry           bool
@@ -31,4 +34,6 @@ var (
 	ValueDescriptorClient coredata.ValueDescriptorClient
 	ScheduleClient        metadata.ScheduleClient
 	ScheduleEventClient   metadata.ScheduleEventClient
+	EventSigner           signing.Signer
+	EventPublisher        publisher.Publisher
 )
@@ -0,0 +1,78 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var leadership = struct {
+	sync.Mutex
+	enabled bool
+	led     map[string]bool
+}{led: make(map[string]bool)}
+
+// EnableLeaderElection makes the polling of the Devices conditional to the
+// leadership of their election group, initially not held.
+func EnableLeaderElection(enabled bool) {
+	leadership.Lock()
+	defer leadership.Unlock()
+
+	leadership.enabled = enabled
+	leadership.led = make(map[string]bool)
+}
+
+// SetLeader records whether the DS leads an election group.
+func SetLeader(group string, leader bool) {
+	leadership.Lock()
+	defer leadership.Unlock()
+
+	if leader {
+		leadership.led[group] = true
+	} else {
+		delete(leadership.led, group)
+	}
+}
+
+// ElectionGroup returns the election group of a Device: the first of its
+// labels listed in the LeaderElection configuration, shared by the Devices
+// with that label, or else the Device itself.
+func ElectionGroup(device models.Device) string {
+	if CurrentConfig != nil {
+		for _, l := range CurrentConfig.LeaderElection.Labels {
+			for _, dl := range device.Labels {
+				if l == dl {
+					return "label/" + l
+				}
+			}
+		}
+	}
+	return "device/" + device.Name
+}
+
+// IsLeader returns true if the DS may poll the Device, i.e. the leader
+// election is disabled or the DS leads the election group of the Device.
+func IsLeader(device models.Device) bool {
+	leadership.Lock()
+	defer leadership.Unlock()
+
+	return !leadership.enabled || leadership.led[ElectionGroup(device)]
+}
+
+// LeadElectionGroups returns the election groups led by the DS.
+func LeadElectionGroups() []string {
+	leadership.Lock()
+	defer leadership.Unlock()
+
+	groups := make([]string, 0, len(leadership.led))
+	for g := range leadership.led {
+		groups = append(groups, g)
+	}
+	return groups
+}
@@ -0,0 +1,47 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestLeadership(t *testing.T) {
+	saved := CurrentConfig
+	defer func() {
+		CurrentConfig = saved
+		EnableLeaderElection(false)
+	}()
+	CurrentConfig = &Config{LeaderElection: LeaderElectionInfo{Labels: []string{"line1"}}}
+
+	meter := models.Device{Name: "meter", Labels: []string{"energy", "line1"}}
+	pump := models.Device{Name: "pump", Labels: []string{"energy"}}
+	if g := ElectionGroup(meter); g != "label/line1" {
+		t.Errorf("Unexpected group %s", g)
+	}
+	if g := ElectionGroup(pump); g != "device/pump" {
+		t.Errorf("Unexpected group %s", g)
+	}
+
+	if !IsLeader(pump) {
+		t.Errorf("Expected all devices to be polled without leader election")
+	}
+	EnableLeaderElection(true)
+	if IsLeader(meter) || IsLeader(pump) {
+		t.Errorf("Expected no device to be polled before being elected")
+	}
+	SetLeader("label/line1", true)
+	if !IsLeader(meter) || IsLeader(pump) {
+		t.Errorf("Expected only the devices of line1 to be polled")
+	}
+	SetLeader("label/line1", false)
+	if IsLeader(meter) {
+		t.Errorf("Expected the leadership to be lost")
+	}
+}
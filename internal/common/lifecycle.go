@@ -0,0 +1,73 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// NotifyDeviceAdded calls AddDevice of the Driver, if it implements
+// DeviceLifecycleHandler.
+func NotifyDeviceAdded(device models.Device) error {
+	if IsSelfDevice(device.Name) {
+		return nil
+	}
+	if h, ok := Driver.(ds_models.DeviceLifecycleHandler); ok {
+		if err := h.AddDevice(device); err != nil {
+			return fmt.Errorf("device %s rejected by the driver: %v", device.Name, err)
+		}
+	}
+	return nil
+}
+
+// NotifyDeviceUpdated calls UpdateDevice of the Driver, if it implements
+// DeviceLifecycleHandler.
+func NotifyDeviceUpdated(device models.Device) error {
+	if IsSelfDevice(device.Name) {
+		return nil
+	}
+	if h, ok := Driver.(ds_models.DeviceLifecycleHandler); ok {
+		if err := h.UpdateDevice(device); err != nil {
+			return fmt.Errorf("update of device %s rejected by the driver: %v", device.Name, err)
+		}
+	}
+	return nil
+}
+
+// NotifyDeviceRemoved calls RemoveDevice of the Driver, if it implements
+// DeviceLifecycleHandler. Failures are only logged, as the Device is gone.
+func NotifyDeviceRemoved(device models.Device) {
+	if IsSelfDevice(device.Name) {
+		return
+	}
+	if h, ok := Driver.(ds_models.DeviceLifecycleHandler); ok {
+		if err := h.RemoveDevice(device); err != nil {
+			LoggingClient.Warn(fmt.Sprintf("Driver failed to remove device %s: %v", device.Name, err))
+		}
+	}
+}
+
+// selfDeviceSuffix is appended to the service name for the default name of
+// the Device representing the gateway.
+const selfDeviceSuffix = "-gateway"
+
+// SelfDeviceName returns the name of the Device representing the gateway.
+func SelfDeviceName() string {
+	if name := CurrentConfig.SelfDevice.Name; name != "" {
+		return name
+	}
+	return ServiceName + selfDeviceSuffix
+}
+
+// IsSelfDevice returns whether the named Device is the one representing the
+// gateway, which is read by the DS and unknown to the Driver.
+func IsSelfDevice(deviceName string) bool {
+	return CurrentConfig != nil && CurrentConfig.SelfDevice.Enabled && deviceName == SelfDeviceName()
+}
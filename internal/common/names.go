@@ -0,0 +1,110 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const (
+	// defaultAllowedNameChars are the unreserved URL characters.
+	defaultAllowedNameChars = `A-Za-z0-9._~-`
+	defaultNameReplacement  = "_"
+	// UniquenessReject and UniquenessSuffix are the DeviceNames uniqueness
+	// policies.
+	UniquenessReject = "reject"
+	UniquenessSuffix = "suffix"
+)
+
+var (
+	nameMutex      sync.Mutex
+	disallowedExpr string
+	disallowed     *regexp.Regexp
+)
+
+// disallowedNameChars returns the regular expression matching the runs of
+// characters disallowed in Device names, compiled once per configuration.
+func disallowedNameChars(allowed string) (*regexp.Regexp, error) {
+	if allowed == "" {
+		allowed = defaultAllowedNameChars
+	}
+	expr := "[^" + allowed + "]+"
+
+	nameMutex.Lock()
+	defer nameMutex.Unlock()
+	if disallowed != nil && disallowedExpr == expr {
+		return disallowed, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed device name characters %s: %v", allowed, err)
+	}
+	disallowedExpr, disallowed = expr, re
+	return re, nil
+}
+
+// SanitizeDeviceName returns the name of a Device created by discovery or
+// import, sanitized by the Driver, if it implements DeviceNameSanitizer,
+// then validated and sanitized as per the DeviceNames settings.
+func SanitizeDeviceName(name string) (string, error) {
+	if s, ok := Driver.(ds_models.DeviceNameSanitizer); ok {
+		sanitized, err := s.SanitizeDeviceName(name)
+		if err != nil {
+			return "", fmt.Errorf("device name %s rejected by the driver: %v", name, err)
+		}
+		name = sanitized
+	}
+
+	var info DeviceNamesInfo
+	if CurrentConfig != nil {
+		info = CurrentConfig.DeviceNames
+	}
+	re, err := disallowedNameChars(info.Allowed)
+	if err != nil {
+		return "", err
+	}
+	sanitized := strings.TrimSpace(name)
+	if re.MatchString(sanitized) {
+		if info.Strict {
+			return "", fmt.Errorf("device name %s has disallowed characters", name)
+		}
+		replacement := info.Replacement
+		if replacement == "" {
+			replacement = defaultNameReplacement
+		}
+		sanitized = re.ReplaceAllLiteralString(sanitized, replacement)
+	}
+	if info.MaxLength > 0 && len(sanitized) > info.MaxLength {
+		sanitized = sanitized[:info.MaxLength]
+	}
+	if sanitized == "" {
+		return "", fmt.Errorf("empty device name for %q", name)
+	}
+	return sanitized, nil
+}
+
+// ResolveDeviceName returns the current name of a Device renamed as per the
+// DeviceAliases settings, following the successive renames, or false if
+// the name isn't an alias.
+func ResolveDeviceName(name string) (string, bool) {
+	aliases := CurrentConfig.DeviceAliases
+	resolved := name
+	// the renames are bounded in case the aliases form a cycle
+	for i := 0; i < len(aliases); i++ {
+		next, ok := aliases[resolved]
+		if !ok || next == "" || next == resolved {
+			break
+		}
+		resolved = next
+	}
+	return resolved, resolved != name
+}
@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// engineeringExponents are the exponents of the engineering suffixes.
+var engineeringExponents = map[string]int{
+	"T": 12,
+	"G": 9,
+	"M": 6,
+	"k": 3,
+	"m": -3,
+	"u": -6,
+	"µ": -6,
+	"n": -9,
+	"p": -12,
+}
+
+// NormalizeNumber converts a number written in one of the common formats,
+// i.e. with a decimal comma (1.234,5), a hexadecimal prefix (0x1F) or an
+// engineering suffix (4.7k), to the format parsed by strconv. If integer is
+// set, the number must be integral and is returned without decimals or
+// exponent, e.g. "1.5k" as "1500".
+func NormalizeNumber(v string, integer bool) (string, error) {
+	s := strings.TrimSpace(v)
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if sign == "+" {
+		sign = ""
+	}
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		u, err := strconv.ParseUint(s[2:], 16, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid hexadecimal number %q", v)
+		}
+		return sign + strconv.FormatUint(u, 10), nil
+	}
+
+	// the last separator is the decimal one, the others group the thousands
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s[:i]) + "." + s[i+1:]
+	}
+
+	for suffix, exp := range engineeringExponents {
+		if strings.HasSuffix(s, suffix) {
+			s = strings.TrimSuffix(s, suffix) + "e" + strconv.Itoa(exp)
+			break
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(sign + s)
+	if !ok {
+		return v, fmt.Errorf("invalid number %q", v)
+	}
+	if integer {
+		if !r.IsInt() {
+			return v, fmt.Errorf("%q isn't an integer", v)
+		}
+		return r.Num().String(), nil
+	}
+	return sign + s, nil
+}
@@ -0,0 +1,50 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// LocationProperty is the property holding a Location given as a plain
+// string.
+const LocationProperty = "location"
+
+// Properties of a Device giving the ratios of its current (CT) and voltage
+// (PT) transformers, e.g. "200" or "1000:5".
+const (
+	CTRatioProperty = "ctRatio"
+	PTRatioProperty = "ptRatio"
+)
+
+// DeviceProperties returns the custom properties of a Device, e.g. its
+// phase or CT ratio, stored as a JSON object in its Location, as Core
+// Metadata has no other free-form field for the Devices. A Location given
+// as a plain string is the "location" property.
+func DeviceProperties(device models.Device) map[string]string {
+	switch l := device.Location.(type) {
+	case map[string]string:
+		props := make(map[string]string, len(l))
+		for k, v := range l {
+			props[k] = v
+		}
+		return props
+	case map[string]interface{}:
+		props := make(map[string]string, len(l))
+		for k, v := range l {
+			props[k] = fmt.Sprint(v)
+		}
+		return props
+	case string:
+		if l != "" {
+			return map[string]string{LocationProperty: l}
+		}
+	}
+	return nil
+}
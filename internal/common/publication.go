@@ -0,0 +1,148 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/deadletter"
+	"github.com/edgexfoundry/device-sdk-go/pkg/retry"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// PublicationStats counts the outcomes of the pushes of events to Core
+// Data.
+type PublicationStats struct {
+	// Delivered is the number of events pushed at the first attempt.
+	Delivered uint64 `json:"delivered"`
+	// Retried is the number of events pushed after retries.
+	Retried uint64 `json:"retried"`
+	// Uncertain is the number of events whose push timed out, which may
+	// have been received, and aren't retried not to duplicate them.
+	Uncertain uint64 `json:"uncertain"`
+	// DeadLettered is the number of events buffered after all the retries
+	// failed, to be replayed.
+	DeadLettered uint64 `json:"deadLettered"`
+	// Failed is the number of events lost, the buffer being disabled.
+	Failed uint64 `json:"failed"`
+}
+
+var (
+	publicationMutex sync.Mutex
+	publicationStats PublicationStats
+)
+
+// CurrentPublicationStats returns the counts of the outcomes of the pushes
+// of events to Core Data.
+func CurrentPublicationStats() PublicationStats {
+	publicationMutex.Lock()
+	defer publicationMutex.Unlock()
+	return publicationStats
+}
+
+func countPublication(count func(*PublicationStats)) {
+	publicationMutex.Lock()
+	defer publicationMutex.Unlock()
+	count(&publicationStats)
+}
+
+// publicationPolicy returns the retry policy of the pushes of events, from
+// the Publication settings, independent of the retries of the driver.
+func publicationPolicy() retry.Policy {
+	info := CurrentConfig.Publication
+	backoff := strings.ToLower(info.Backoff)
+	if backoff == "" {
+		backoff = retry.Fixed
+	}
+	return retry.Policy{
+		MaxRetries: info.MaxRetries,
+		Backoff:    backoff,
+		Delay:      time.Duration(info.RetryDelay) * time.Millisecond,
+		MaxDelay:   time.Duration(info.MaxRetryDelay) * time.Millisecond,
+	}
+}
+
+// pushEvent pushes an event to Core Data, retrying according to the
+// Publication settings, and buffers it as a dead letter if all the attempts
+// fail. It returns false if the event is lost.
+func pushEvent(event *models.Event) bool {
+	attempts := 0
+	err := publicationPolicy().Do(func() error {
+		attempts++
+		err := addEvent(event)
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			// the event may have been received, retrying could duplicate it
+			return retry.Permanent(err)
+		}
+		return err
+	})
+
+	switch ne, _ := err.(net.Error); {
+	case err == nil && attempts == 1:
+		countPublication(func(s *PublicationStats) { s.Delivered++ })
+		return true
+	case err == nil:
+		countPublication(func(s *PublicationStats) { s.Retried++ })
+		return true
+	case ne != nil && ne.Timeout():
+		LoggingClient.Warn(fmt.Sprintf("Push of event for device %s timed out: %v", event.Device, err))
+		countPublication(func(s *PublicationStats) { s.Uncertain++ })
+		return true
+	}
+
+	if deadletter.Add(event) {
+		LoggingClient.Warn(fmt.Sprintf("Failed to push event for device %s after %d attempts, buffered for replay: %v", event.Device, attempts, err))
+		countPublication(func(s *PublicationStats) { s.DeadLettered++ })
+		return true
+	}
+	LoggingClient.Error(fmt.Sprintf("Failed to push event for device %s: %v", event.Device, err))
+	countPublication(func(s *PublicationStats) { s.Failed++ })
+	return false
+}
+
+// ReplayEvent pushes a dead letter to Core Data, once.
+func ReplayEvent(event *models.Event) error {
+	return addEvent(event)
+}
+
+// ReadingExpired tells if a buffered reading of a Device with the given
+// labels is older than its max age, from the Publication settings, to be
+// discarded rather than replayed.
+func ReadingExpired(reading models.Reading, labels []string, now time.Time) bool {
+	maxAge := readingMaxAge(reading.Name, labels)
+	if maxAge <= 0 || reading.Origin <= 0 {
+		return false
+	}
+	timestampMutex.Lock()
+	scale := originScale
+	timestampMutex.Unlock()
+
+	// the origins of the buffered readings are in the configured precision
+	origin := time.Unix(0, reading.Origin/scale*int64(time.Millisecond))
+	return now.Sub(origin) > maxAge
+}
+
+func readingMaxAge(resource string, labels []string) time.Duration {
+	info := CurrentConfig.Publication
+	if age, ok := info.ResourceMaxAge[resource]; ok {
+		return time.Duration(age) * time.Millisecond
+	}
+	age, matched := 0, false
+	for _, l := range labels {
+		if a, ok := info.LabelMaxAge[l]; ok && (!matched || (age > 0 && (a <= 0 || a > age))) {
+			age, matched = a, true
+		}
+	}
+	if !matched {
+		age = info.MaxEventAge
+	}
+	return time.Duration(age) * time.Millisecond
+}
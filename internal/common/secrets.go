@@ -0,0 +1,101 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	secretFilePrefix = "file:"
+	secretEnvPrefix  = "env:"
+)
+
+// LookupSecret returns the secret referenced by ref, either "file:<path>"
+// for the contents of a file or "env:<name>" for an environment variable,
+// so that secrets themselves never appear in the configuration.
+func LookupSecret(ref string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(ref, secretFilePrefix):
+		return ioutil.ReadFile(strings.TrimPrefix(ref, secretFilePrefix))
+	case strings.HasPrefix(ref, secretEnvPrefix):
+		name := strings.TrimPrefix(ref, secretEnvPrefix)
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s not set", name)
+		}
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("invalid secret reference %q, expecting %s<path> or %s<name>", ref, secretFilePrefix, secretEnvPrefix)
+}
+
+// CredentialsProvider looks up the credentials of the Devices configured in
+// Credentials, resolving their secrets.
+type CredentialsProvider struct{}
+
+// Credentials returns the credentials of the named Device.
+func (CredentialsProvider) Credentials(deviceName string) (ds_models.Credentials, error) {
+	info, ok := CurrentConfig.Credentials[deviceName]
+	if !ok {
+		return ds_models.Credentials{}, ds_models.ErrNoCredentials
+	}
+
+	c := ds_models.Credentials{Username: info.Username}
+	if info.Password != "" {
+		password, err := LookupSecret(info.Password)
+		if err != nil {
+			return c, fmt.Errorf("password of Device %s: %v", deviceName, err)
+		}
+		c.Password = string(password)
+	}
+	if info.Key != "" {
+		key, err := LookupSecret(info.Key)
+		if err != nil {
+			return c, fmt.Errorf("key of Device %s: %v", deviceName, err)
+		}
+		c.Key = key
+	}
+	if len(info.Properties) > 0 {
+		c.Properties = make(map[string]string, len(info.Properties))
+		for name, ref := range info.Properties {
+			v, err := LookupSecret(ref)
+			if err != nil {
+				return c, fmt.Errorf("%s of Device %s: %v", name, deviceName, err)
+			}
+			c.Properties[name] = string(v)
+		}
+	}
+	return c, nil
+}
+
+// ResolveAddressable returns the Addressable of a Device as passed to the
+// driver, its User and Password being those of the credentials of the
+// Device if configured. Otherwise a Password which is a secret reference,
+// e.g. "env:METER_PASSWORD" stored in Core Metadata, is resolved.
+func ResolveAddressable(deviceName string, addr models.Addressable) (models.Addressable, error) {
+	c, err := CredentialsProvider{}.Credentials(deviceName)
+	switch {
+	case err == nil:
+		addr.User = c.Username
+		addr.Password = c.Password
+	case err != ds_models.ErrNoCredentials:
+		return addr, err
+	case strings.HasPrefix(addr.Password, secretFilePrefix) || strings.HasPrefix(addr.Password, secretEnvPrefix):
+		password, err := LookupSecret(addr.Password)
+		if err != nil {
+			return addr, fmt.Errorf("password of Device %s: %v", deviceName, err)
+		}
+		addr.Password = string(password)
+	}
+	return addr, nil
+}
@@ -0,0 +1,37 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"sync"
+)
+
+var (
+	standbyMutex sync.Mutex
+	standby      bool
+)
+
+// SetStandby enters or leaves the standby mode, in which the DS mirrors an
+// active gateway sharing the same Devices: it keeps its caches and health
+// checks up, but suppresses the writes to the Devices and the publication
+// of events until promoted. It returns true if the mode changed.
+func SetStandby(enabled bool) bool {
+	standbyMutex.Lock()
+	defer standbyMutex.Unlock()
+
+	changed := standby != enabled
+	standby = enabled
+	return changed
+}
+
+// InStandby returns true if the DS is in standby mode.
+func InStandby() bool {
+	standbyMutex.Lock()
+	defer standbyMutex.Unlock()
+
+	return standby
+}
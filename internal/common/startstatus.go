@@ -0,0 +1,59 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+// StartStatus describes how the DS was last (re)started.
+type StartStatus struct {
+	// Mode is the start mode which has actually been performed.
+	Mode string `json:"startMode"`
+	// Started is the time (in milliseconds) the start was completed.
+	Started int64 `json:"started"`
+	// Reconciled is true once the caches are known to be in sync with
+	// Core Metadata.
+	Reconciled bool `json:"reconciled"`
+	// Restarts is the number of hot restarts performed.
+	Restarts int `json:"restarts"`
+}
+
+var (
+	startMutex  sync.Mutex
+	startStatus StartStatus
+)
+
+// SetStarted records the completion of a start in the given mode.
+func SetStarted(mode string, reconciled bool) {
+	startMutex.Lock()
+	defer startMutex.Unlock()
+
+	if mode == StartModeHot {
+		startStatus.Restarts++
+	}
+	startStatus.Mode = mode
+	startStatus.Started = time.Now().UnixNano() / int64(time.Millisecond)
+	startStatus.Reconciled = reconciled
+}
+
+// SetReconciled records that the caches are in sync with Core Metadata.
+func SetReconciled() {
+	startMutex.Lock()
+	defer startMutex.Unlock()
+
+	startStatus.Reconciled = true
+}
+
+// CurrentStartStatus returns how the DS was last (re)started.
+func CurrentStartStatus() StartStatus {
+	startMutex.Lock()
+	defer startMutex.Unlock()
+
+	return startStatus
+}
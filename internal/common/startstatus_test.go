@@ -0,0 +1,41 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"testing"
+)
+
+func TestStartStatus(t *testing.T) {
+	startStatus = StartStatus{}
+	defer func() { startStatus = StartStatus{} }()
+
+	SetStarted(StartModeCold, true)
+	status := CurrentStartStatus()
+	if status.Mode != StartModeCold || !status.Reconciled || status.Restarts != 0 || status.Started == 0 {
+		t.Errorf("Status after a cold start: %+v", status)
+	}
+
+	// a warm start is reconciled once Core Metadata has been queried
+	SetStarted(StartModeWarm, false)
+	if status = CurrentStartStatus(); status.Mode != StartModeWarm || status.Reconciled {
+		t.Errorf("Status after a warm start: %+v", status)
+	}
+	SetReconciled()
+	if status = CurrentStartStatus(); !status.Reconciled {
+		t.Errorf("Status after the reconciliation: %+v", status)
+	}
+
+	// the hot restarts keep the reconciliation and are counted
+	for i := 1; i <= 2; i++ {
+		SetStarted(StartModeHot, CurrentStartStatus().Reconciled)
+		status = CurrentStartStatus()
+		if status.Mode != StartModeHot || !status.Reconciled || status.Restarts != i {
+			t.Errorf("Status after %d hot restarts: %+v", i, status)
+		}
+	}
+}
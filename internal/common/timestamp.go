@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var (
+	timestampMutex sync.Mutex
+	originScale    int64 = 1
+	reportLocation *time.Location
+)
+
+// InitTimestamps checks and applies the configured OriginPrecision and
+// Timezone of the events pushed to Core Data.
+func InitTimestamps(info ServiceInfo) error {
+	var scale int64
+	switch info.OriginPrecision {
+	case "", PrecisionMillis:
+		scale = 1
+	case PrecisionMicros:
+		scale = int64(time.Millisecond / time.Microsecond)
+	case PrecisionNanos:
+		scale = int64(time.Millisecond)
+	default:
+		return fmt.Errorf("invalid OriginPrecision %s, expected ms, us or ns", info.OriginPrecision)
+	}
+
+	var loc *time.Location
+	if info.Timezone != "" {
+		var err error
+		if loc, err = parseTimezone(info.Timezone); err != nil {
+			return fmt.Errorf("invalid Timezone %s: %v", info.Timezone, err)
+		}
+	}
+
+	timestampMutex.Lock()
+	defer timestampMutex.Unlock()
+
+	originScale = scale
+	reportLocation = loc
+	return nil
+}
+
+// parseTimezone accepts a zone name, e.g. "Europe/Madrid", or a fixed
+// offset, e.g. "+01:00".
+func parseTimezone(tz string) (*time.Location, error) {
+	if strings.HasPrefix(tz, "+") || strings.HasPrefix(tz, "-") {
+		t, err := time.Parse("-07:00", tz)
+		if err != nil {
+			return nil, err
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(tz, offset), nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// timestampEvent returns a copy of the event with its origins converted
+// from milliseconds to the configured precision and the configured
+// annotations as additional readings, or the event itself if none is
+// configured. The given event isn't modified, as it may be shared with the
+// caller.
+func timestampEvent(event *models.Event) *models.Event {
+	timestampMutex.Lock()
+	scale, loc := originScale, reportLocation
+	timestampMutex.Unlock()
+
+	if scale == 1 && loc == nil {
+		return event
+	}
+
+	e := *event
+	e.Readings = make([]models.Reading, len(event.Readings), len(event.Readings)+2)
+	copy(e.Readings, event.Readings)
+	e.Origin *= scale
+	for i := range e.Readings {
+		e.Readings[i].Origin *= scale
+	}
+	if scale != 1 {
+		precision := PrecisionMicros
+		if scale == int64(time.Millisecond) {
+			precision = PrecisionNanos
+		}
+		e.Readings = append(e.Readings, models.Reading{Name: PrecisionReadingName, Device: event.Device, Value: precision, Origin: e.Origin})
+	}
+	if loc != nil {
+		// the offset at the time of the event, as it changes with DST
+		t := time.Unix(0, event.Origin*int64(time.Millisecond)).In(loc)
+		e.Readings = append(e.Readings, models.Reading{Name: TimezoneReadingName, Device: event.Device, Value: t.Format("-07:00"), Origin: e.Origin})
+	}
+	return &e
+}
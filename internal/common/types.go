@@ -10,6 +10,9 @@ package common
 import (
 	"fmt"
 
+	"github.com/edgexfoundry/device-sdk-go/pkg/fault"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/publisher"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -39,6 +42,57 @@ type ServiceInfo struct {
 	EnableAsyncReadings bool
 	// AsyncBufferSize defines the size of asynchronous channel
 	AsyncBufferSize int
+	// AsyncBatchWindow is the time (in milliseconds) during which the
+	// asynchronous readings of a Device are accumulated into a single
+	// Event. Zero pushes them as they arrive.
+	AsyncBatchWindow int
+	// AsyncBatchMaxReadings is the number of readings after which a batch
+	// is pushed before the end of its window. Zero doesn't limit them.
+	AsyncBatchMaxReadings int
+	// Tenant identifies the customer or site served by the DS. If set, it is
+	// attached to the service registration and, as an additional reading,
+	// to all events pushed to Core Data.
+	Tenant string
+	// TenantPathPrefix specifies whether the REST API is served under a
+	// path prefixed with the Tenant, e.g. /<tenant>/api/v1/ping.
+	TenantPathPrefix bool
+	// StartMode is either "cold" (retrieve all objects from Core Metadata),
+	// "warm" (initialize the caches from CacheFile and reconcile them with
+	// Core Metadata in background) or "hot" (as warm, and additionally
+	// reload the configuration in-process on SIGHUP).
+	StartMode string
+	// CacheFile is the file the caches are saved to, for use by warm starts.
+	CacheFile string
+	// Locale selects the language of the messages returned by the REST API,
+	// e.g. "en", "es" or "fr".
+	Locale string
+	// MessageCatalog is an optional JSON file adding locales or overriding
+	// messages of the built-in catalogs.
+	MessageCatalog string
+	// StateDir is the directory of the files persisting the state of the DS,
+	// such as CacheFile and HistoryFile, when given as relative paths. The
+	// files are written atomically and checked for corruption.
+	StateDir string
+	// OriginPrecision is the unit of the origins of the events and readings
+	// pushed to Core Data: "ms" (the default), "us" or "ns". Other than
+	// milliseconds, it's attached to the events as an additional reading.
+	OriginPrecision string
+	// Timezone is the zone name, e.g. "Europe/Madrid", or fixed offset, e.g.
+	// "+01:00", of the devices. If set, the offset at the time of each event
+	// is attached to it as an additional reading.
+	Timezone string
+	// Standby starts the DS in standby mode, as the passive member of a
+	// redundant gateway pair: writes and event publication are suppressed
+	// until it's promoted through the standby endpoint or StandbyKey.
+	Standby bool
+	// StandbyKey is a key of the registry whose value, "true" or "false",
+	// sets the standby mode when it changes. Empty disables the watch.
+	StandbyKey string
+	// ShutdownTimeout is the time (in milliseconds) given to the DS to stop
+	// on SIGINT or SIGTERM, draining the asynchronous readings and the
+	// requests in progress before closing its listeners. Zero selects
+	// DefaultShutdownTimeout.
+	ShutdownTimeout int
 }
 
 type RegistryService struct {
@@ -82,6 +136,85 @@ type DeviceInfo struct {
 	// ProfilesDir specifies a directory which contains deviceprofile
 	// files which should be imported on startup.
 	ProfilesDir string
+	// SelectTimeout is the default time (in milliseconds) a command armed
+	// by a select request remains valid for the operate (PUT) request
+	// that follows, for device resources marked as SelectBeforeOperate.
+	SelectTimeout int
+	// ClampWriteValues specifies whether write values outside the Minimum and
+	// Maximum of a device resource are clamped to the limit instead of being
+	// rejected.
+	ClampWriteValues bool
+	// HistorySize is the number of recent commands kept in the history of
+	// each device. Zero disables the command history.
+	HistorySize int
+	// HistoryFile specifies a file used to persist the command history across
+	// restarts. If empty, the history is only kept in memory.
+	HistoryFile string
+	// DriftEstimation enables the estimation of the clock offset of each
+	// Device from the device-origin timestamps supplied by the driver.
+	DriftEstimation bool
+	// DriftCorrection specifies whether the device-origin timestamps of
+	// the readings are corrected by the estimated clock offset.
+	DriftCorrection bool
+	// DriftThreshold is the clock offset (in milliseconds) above which a
+	// warning is logged for the Device. Zero disables the warning.
+	DriftThreshold int64
+	// CaptureDir is the directory, relative to the state directory, storing
+	// the captures (e.g. waveforms) streamed by the driver.
+	CaptureDir string
+	// MaxCaptures is the number of completed captures retained, the oldest
+	// being removed first. Zero means unlimited.
+	MaxCaptures int
+	// JobDir is the directory, relative to the state directory, storing the
+	// checkpoints of the jobs submitted by the driver.
+	JobDir string
+	// DecommissionDir is the directory, relative to the state directory,
+	// the command history of the decommissioned Devices is exported to.
+	// Empty disables the export, the history being returned anyway.
+	DecommissionDir string
+	// DerivedFile specifies a file used to persist the energy accumulated
+	// from the readings of the device resources with an Energy statistic.
+	DerivedFile string
+	// TraceSize is the number of frames kept per bus by the protocol trace,
+	// which is enabled through the REST API. Zero selects the default.
+	TraceSize int
+	// DedupFile specifies a journal, relative to the state directory, of the
+	// events sent, used to suppress duplicates replayed by drivers after a
+	// reconnect. Only events whose readings all have an origin are checked.
+	DedupFile string
+	// DedupSize is the number of events remembered to detect duplicates.
+	// Zero selects the default.
+	DedupSize int
+	// CachedReads specifies whether read commands return the last values of
+	// the resources by default, instead of reading the Device, unless
+	// overridden by the "cached" query parameter.
+	CachedReads bool
+	// CacheMaxAge is the age (in milliseconds) beyond which a cached value
+	// is stale, and read from the Device instead. Zero never makes them
+	// stale.
+	CacheMaxAge int
+	// MaxParallelCommands bounds the number of transports commanded
+	// concurrently by the commands of all Devices, the Devices sharing a
+	// transport being commanded one after the other. Zero is unbounded.
+	MaxParallelCommands int
+	// MaxInFlightCommands bounds the number of driver calls in flight, the
+	// commands beyond it being rejected with 503 and a Retry-After of
+	// BusyRetryAfter seconds. Zero is unbounded. The calls of a Device are
+	// always serialized.
+	MaxInFlightCommands int
+	BusyRetryAfter      int
+	// DiscoveryInterval is the interval (in milliseconds) between the
+	// discoveries of the driver, which may also be triggered through the
+	// REST API. Zero disables the periodic discovery.
+	DiscoveryInterval int
+	// DiscoveryLogOnly specifies whether the Devices found by discovery
+	// are only logged and listed through the REST API, instead of being
+	// provisioned.
+	DiscoveryLogOnly bool
+	// LenientNumbers specifies whether the numeric write values may be
+	// written with a decimal comma, a 0x hexadecimal prefix or an
+	// engineering suffix (e.g. 4.7k), converted before being parsed.
+	LenientNumbers bool
 }
 
 // LoggingInfo is a struct which contains logging specific configuration settings.
@@ -94,6 +227,286 @@ type LoggingInfo struct {
 	Level string
 }
 
+// CacheInfo holds the maximum number of entries of each cache, zero
+// meaning unbounded.
+type CacheInfo struct {
+	// MaxDevices is the maximum number of Devices. Devices are never
+	// evicted, so Devices beyond the limit are rejected.
+	MaxDevices int
+	// MaxProfiles is the maximum number of Device Profiles, including their
+	// parsed commands. Profiles beyond the limit are rejected.
+	MaxProfiles int
+	// MaxValueDescriptors is the maximum number of Value Descriptors. The
+	// least recently used ones are evicted when the limit is hit.
+	MaxValueDescriptors int
+}
+
+// ThrottleInfo is a struct which contains the thresholds of the gateway
+// resource usage above which the DS throttles itself.
+type ThrottleInfo struct {
+	// CPUThreshold is the CPU usage (in percent) above which the DS is
+	// throttled. Zero disables it.
+	CPUThreshold float64
+	// MemoryThreshold is the memory usage (in percent) above which the DS is
+	// throttled. Zero disables it.
+	MemoryThreshold float64
+	// Interval is the time (in milliseconds) between samples of the
+	// resource usage.
+	Interval int
+}
+
+// SigningInfo is a struct which contains the settings of event signing.
+type SigningInfo struct {
+	// Algorithm is either "HMAC-SHA256" or "ECDSA-P256". If empty, events
+	// aren't signed.
+	Algorithm string
+	// Key references the shared secret (HMAC) or PEM encoded private key
+	// (ECDSA), as "file:<path>" or "env:<name>".
+	Key string
+}
+
+// AccessControlInfo is a struct which contains the role-based access control
+// settings of the REST API. The roles are "viewer" (read only), "operator"
+// (commands and discovery) and "admin" (management).
+type AccessControlInfo struct {
+	// Enabled specifies whether requests must present credentials.
+	Enabled bool
+	// Tokens maps bearer tokens, referenced as "file:<path>" or
+	// "env:<name>", to roles.
+	Tokens map[string]string
+	// Certificates maps client certificate common names to roles.
+	Certificates map[string]string
+	// CallbackAllowlist lists the CIDRs or IP addresses (e.g. of the Core
+	// Metadata hosts) allowed to call the callback route. If empty, any
+	// host is allowed.
+	CallbackAllowlist []string
+}
+
+// ProxyInfo is a struct which contains the settings of the proxy used for
+// all outbound HTTP requests. If no proxy is set, the HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY environment variables apply.
+type ProxyInfo struct {
+	// HTTPProxy and HTTPSProxy are the proxies of http and https requests.
+	HTTPProxy  string
+	HTTPSProxy string
+	// NoProxy is a comma separated list of hosts, domains, IP addresses or
+	// CIDRs which are reached directly.
+	NoProxy string
+	// Username and Password authenticate to the proxy. Password references
+	// a secret, as "file:<path>" or "env:<name>".
+	Username string
+	Password string
+}
+
+// FeatureInfo is a struct which contains the settings gating the optional
+// subsystems of the DS, such as the command history ("history").
+type FeatureInfo struct {
+	// Flags enables or disables features by name. Features without a flag
+	// are enabled, unless not granted by the license.
+	Flags map[string]bool
+	// LicenseFile is the signed license granting features. If empty, no
+	// license is required.
+	LicenseFile string
+	// LicenseKeyFile is the PEM encoded public key verifying the license.
+	LicenseKeyFile string
+}
+
+// MessageQueueInfo configures the publication of the events to a message
+// queue, e.g. an MQTT broker.
+type MessageQueueInfo struct {
+	// Config selects the message queue by Type, "mqtt" being built in, an
+	// empty Type disabling the publication. Password references a secret,
+	// as "file:<path>" or "env:<name>".
+	publisher.Config
+	// Exclusive publishes the events instead of pushing them to Core Data.
+	Exclusive bool
+}
+
+// PublicationInfo configures the retries of the pushes of the events to Core
+// Data, independent of the retries of the driver, and the local buffer of
+// the events failing after all the retries (the dead letters), which are
+// replayed periodically.
+type PublicationInfo struct {
+	// MaxRetries is the number of retries after the first attempt. Timed
+	// out pushes aren't retried, as the event may have been received.
+	MaxRetries int
+	// Backoff is "fixed" or "exponential", the delay doubling at each
+	// retry.
+	Backoff string
+	// RetryDelay is the delay (in milliseconds) before the first retry.
+	RetryDelay int
+	// MaxRetryDelay caps the exponential delay (in milliseconds). Zero
+	// means unlimited.
+	MaxRetryDelay int
+	// DeadLetterSize is the number of events buffered, the oldest being
+	// dropped. Zero disables the buffer, the failing events being lost.
+	DeadLetterSize int
+	// DeadLetterFile persists the buffer, relative to the state directory.
+	// If empty, the buffer is only kept in memory.
+	DeadLetterFile string
+	// ReplayInterval is the interval (in milliseconds) between the replays
+	// of the buffered events.
+	ReplayInterval int
+	// MaxEventAge is the age (in milliseconds) beyond which the buffered
+	// readings are discarded rather than replayed, bounding the replays
+	// after long outages. Zero keeps them indefinitely.
+	MaxEventAge int
+	// ResourceMaxAge overrides MaxEventAge per device resource, and
+	// LabelMaxAge per Device label, the longest age of the labels of the
+	// Device applying. The resource takes precedence, and zero keeps the
+	// readings indefinitely.
+	ResourceMaxAge map[string]int
+	LabelMaxAge    map[string]int
+}
+
+// RecoveryInfo configures the probes of the disabled Devices, i.e. a
+// lightweight read command issued periodically, re-enabling the Device when
+// it succeeds.
+type RecoveryInfo struct {
+	// Interval is the interval (in milliseconds) between the probes. Zero
+	// disables the probes.
+	Interval int
+	// Command is the read command of the probes.
+	Command string
+	// ProfileCommands overrides Command per device profile name. The
+	// Devices whose profile has no probe command aren't probed.
+	ProfileCommands map[string]string
+}
+
+// OperatingStateInfo configures the history of the changes of the
+// OperatingState of the Devices, and the flap detection holding back their
+// reports to Core Metadata.
+type OperatingStateInfo struct {
+	// HistorySize is the number of changes kept per Device. Zero disables
+	// the history.
+	HistorySize int
+	// HistoryFile persists the history, relative to the state directory.
+	// If empty, the history is only kept in memory.
+	HistoryFile string
+	// FlapThreshold is the number of changes within FlapWindow from which
+	// a Device is flapping, its changes not being reported to Core
+	// Metadata until it settles. Zero disables the flap detection.
+	FlapThreshold int
+	// FlapWindow is the window (in milliseconds) the changes are counted
+	// in.
+	FlapWindow int
+}
+
+// FaultInfo configures the fault injection used for resilience testing,
+// which is only available when the DS is built with the "faults" tag.
+type FaultInfo struct {
+	// Enabled starts injecting faults at startup.
+	Enabled bool
+	fault.Config
+}
+
+// LeaderElectionInfo configures the leader election through the registry,
+// for instances of a DS reaching the same Devices (e.g. Modbus TCP) of
+// which only one should poll at a time. Each Device, or group of Devices
+// sharing a label, has its own leader, so that the polling can be spread
+// over the instances; when a leader disappears, its session expires and
+// another instance takes over.
+type LeaderElectionInfo struct {
+	// Enabled turns the leader election on; it requires the registry.
+	Enabled bool
+	// KeyPrefix is the prefix of the registry keys locked by the leaders,
+	// defaulting to "leader/" followed by the service name.
+	KeyPrefix string
+	// SessionTTL is the time after which a disappeared leader loses its
+	// leadership, e.g. "15s".
+	SessionTTL string
+	// Labels are the Device labels electing a single leader for all their
+	// Devices. The other Devices have a leader each.
+	Labels []string
+	// Interval is the time (in milliseconds) between the updates of the
+	// election groups, as Devices are added and removed.
+	Interval int
+}
+
+// FederationInfo is a struct which contains the settings of the federation
+// of other DS instances, e.g. sub-gateways of a site concentrator, whose
+// Devices are re-exposed by the DS, the commands being forwarded to them.
+type FederationInfo struct {
+	// Interval is the time (in milliseconds) between the synchronizations
+	// of the Devices of the peers.
+	Interval int
+	// Timeout is the timeout (in milliseconds) of the requests to the peers.
+	Timeout int
+	// Peers are the federated instances, by name.
+	Peers map[string]FederationPeerInfo
+}
+
+// FederationPeerInfo is a struct which contains the settings of a federated
+// DS instance.
+type FederationPeerInfo struct {
+	// URL is the base URL of its REST API, e.g. "http://10.0.1.2:49990",
+	// followed by its tenant if its REST API is partitioned per tenant.
+	URL string
+	// Prefix is prepended to the names of its Devices, avoiding clashes
+	// with the Devices of the DS and the other peers.
+	Prefix string
+	// Token references the secret bearer token presented to the peer if it
+	// enforces access control, e.g. "env:SITE_A_TOKEN".
+	Token string
+	// Labels are added to its Devices.
+	Labels []string
+}
+
+// WritableInfo is a struct which contains the settings which can be changed
+// at runtime, without a restart, through the registry.
+type WritableInfo struct {
+	// LogLevel overrides the Level of the Logging section.
+	LogLevel string
+	// ScheduleFrequencies overrides the Frequency of Schedules, by name,
+	// e.g. "PT30S".
+	ScheduleFrequencies map[string]string
+	// Driver overrides settings of the Driver section, passed to the
+	// driver when changed if it implements ConfigUpdater.
+	Driver map[string]string
+	// Transforms enables or disables the transforms of the values defined
+	// by the profiles, by name: Mask, Shift, Base, Scale, Offset,
+	// Precision and Ratio. They're enabled unless set to false.
+	Transforms map[string]bool
+}
+
+// SelfDeviceInfo is a struct which contains the settings of the Device
+// representing the gateway itself, created with a standard profile and read
+// by the DS rather than the driver, so that every deployment reports the
+// health of its host the same way.
+type SelfDeviceInfo struct {
+	// Enabled creates the Device on startup.
+	Enabled bool
+	// Name is the Device name, defaulting to the service name followed by
+	// "-gateway".
+	Name string
+	// Labels are applied to the Device.
+	Labels []string
+	// DiskPath is the path of the file system whose usage is reported,
+	// defaulting to "/".
+	DiskPath string
+}
+
+// SnapshotInfo is a struct which contains the settings of a snapshot, i.e.
+// the daily capture of a set of resources of a Device (e.g. billing
+// registers) pushed as a single event tagged with the snapshot name.
+type SnapshotInfo struct {
+	// Name identifies the snapshot, and is the value of the Snapshot
+	// reading of the event.
+	Name string
+	// Device is the name of the Device the resources are read from.
+	Device string
+	// Resources are the names of the commands (or device resources) read.
+	Resources []string
+	// Time is the local time of day of the snapshot, as HH:MM.
+	Time string
+	// Window is the time (in seconds) after Time within which the resources
+	// failing to read are retried. Defaults to one hour.
+	Window int
+	// RetryInterval is the time (in seconds) between retries. Defaults to
+	// one minute.
+	RetryInterval int
+}
+
 // ScheduleEventInfo is a struct which contains event schedule specific
 // configuration settings.
 type ScheduleEventInfo struct {
@@ -106,11 +519,75 @@ type ScheduleEventInfo struct {
 	Service string
 }
 
+// DefaultAutoEventInfo defines an auto event, i.e. a Schedule Event running
+// a command of a Device, created for each Device of a profile or with a
+// label when it's added, so that e.g. newly discovered meters start
+// reporting without manual setup.
+type DefaultAutoEventInfo struct {
+	// Name identifies the auto event. The Schedule Event of a Device is
+	// named <device>-<name>, and removing it disables the auto event for
+	// that Device.
+	Name string
+	// Profile selects the Devices of the given profile.
+	Profile string
+	// Label selects the Devices with the given label.
+	Label string
+	// Schedule is the name of the Schedule running the command.
+	Schedule string
+	// Command is the name of the command read.
+	Command string
+}
+
+// CommandGroupInfo defines a read command grouping arbitrary resources of
+// the Devices of a profile, or of a single Device, read in a single call,
+// without editing the profile. The commands of the profile take precedence
+// over a group of the same name.
+type CommandGroupInfo struct {
+	// Name is the name of the command.
+	Name string
+	// Profile selects the Devices of the given profile.
+	Profile string
+	// Device selects a single Device, taking precedence over a group of
+	// its profile.
+	Device string
+	// Resources are the names of the device resources read.
+	Resources []string
+}
+
 // WatcherInfo is a struct which contains provisionwatcher configuration settings.
 type WatcherInfo struct {
 	Profile     string
 	Key         string
 	MatchString string
+	// Identifiers maps identifier names to regular expressions their
+	// discovered values must fully match, in addition to Key.
+	Identifiers map[string]string
+	// BlockingIdentifiers maps identifier names to values excluding the
+	// discovered Devices.
+	BlockingIdentifiers map[string][]string
+	// NameTemplate is a Go template naming the discovered Devices, given
+	// the Watcher, Profile, Identifiers and sorted identifier Values.
+	NameTemplate string
+}
+
+// DeviceNamesInfo configures the validation and sanitization of the names of
+// the Devices created by discovery or import, which must be usable in the
+// REST command URLs.
+type DeviceNamesInfo struct {
+	// MaxLength is the maximum length of a name, unlimited if 0. Longer
+	// names are truncated.
+	MaxLength int
+	// Allowed is the regular expression character class of the characters
+	// allowed in a name, by default the unreserved URL characters.
+	Allowed string
+	// Replacement replaces each run of disallowed characters, "_" if empty.
+	Replacement string
+	// Strict rejects the names with disallowed characters instead of
+	// replacing them.
+	Strict bool
+	// Uniqueness is the policy for a name already taken by another Device:
+	// "reject" (the default) or "suffix", appending "-2", "-3", etc.
+	Uniqueness string
 }
 
 // Config is a struct which contains all of a DS's configuration settings.
@@ -125,14 +602,117 @@ type Config struct {
 	Device DeviceInfo
 	// Logging contains logging-specific configuration settings.
 	Logging LoggingInfo
+	// Writable contains the settings applied at runtime when changed in
+	// the registry.
+	Writable WritableInfo
+	// Cache contains the limits of the caches.
+	Cache CacheInfo
+	// Throttle contains the resource usage self-limits.
+	Throttle ThrottleInfo
+	// Signing contains the settings of event signing.
+	Signing SigningInfo
+	// AccessControl contains the role-based access control settings.
+	AccessControl AccessControlInfo
+	// Proxy contains the settings of the proxy for outbound requests.
+	Proxy ProxyInfo
+	// Features contains the feature flags and license settings.
+	Features FeatureInfo
+	// MessageQueue configures the publication of the events to a message
+	// queue.
+	MessageQueue MessageQueueInfo
+	// Publication configures the retries of the pushes of the events to
+	// Core Data, and the buffering of the events still failing.
+	Publication PublicationInfo
+	// Recovery configures the probes re-enabling the disabled Devices.
+	Recovery RecoveryInfo
+	// OperatingState configures the history of the operating states and
+	// the flap detection.
+	OperatingState OperatingStateInfo
+	// Faults configures the fault injection for resilience testing.
+	Faults FaultInfo
+	// LeaderElection configures the election of the instance polling the
+	// Devices shared with other instances.
+	LeaderElection LeaderElectionInfo
+	// SelfDevice configures the Device representing the gateway.
+	SelfDevice SelfDeviceInfo
+	// Federation configures the proxying of the Devices of other instances.
+	Federation FederationInfo
+	// StartupProbes are waited for before the driver is initialized.
+	StartupProbes []ProbeInfo
+	// Snapshots are the daily snapshots run by the internal Scheduler.
+	Snapshots []SnapshotInfo
 	// Schedules is created on startup.
 	Schedules []models.Schedule
 	// SchedulesEvents is created on startup.
 	ScheduleEvents []models.ScheduleEvent
+	// DefaultAutoEvents are created for the matching Devices when added.
+	DefaultAutoEvents []DefaultAutoEventInfo
+	// CommandGroups are read commands grouping resources of Devices,
+	// additionally to the commands of their profiles.
+	CommandGroups []CommandGroupInfo
+	// Driver holds the settings of the driver, e.g. the retry policy of its
+	// communication, see pkg/retry.
+	Driver map[string]string
 	// Watchers is a map provisionwatchers to be created on startup.
 	Watchers map[string]WatcherInfo
+	// DeviceNames validates and sanitizes the names of the Devices created
+	// by discovery or import.
+	DeviceNames DeviceNamesInfo
 	// DeviceList is the list of pre-define Devices
 	DeviceList []DeviceConfig
+	// Keepalives are the keepalive reads, per Device name.
+	Keepalives map[string]KeepaliveInfo
+	// ReadingAliases maps, per Device name, the name of a resource
+	// operation parameter to the reading (and value descriptor) name
+	// used when the readings of that Device are pushed to Core Data.
+	ReadingAliases map[string]map[string]string
+	// DeviceAliases maps the former names of renamed Devices to their
+	// current names, accepted by the command API and for the readings
+	// pushed by the driver, while dashboards and schedules are migrated.
+	DeviceAliases map[string]string
+	// Credentials are the credentials of the Devices, by Device name.
+	Credentials map[string]CredentialsInfo
+}
+
+// CredentialsInfo configures the credentials of a Device. The secrets are
+// references resolved by LookupSecret, e.g. "env:METER_PASSWORD", so that
+// they never appear in the configuration.
+type CredentialsInfo struct {
+	Username string
+	// Password is the reference of the password.
+	Password string
+	// Key is the reference of the key, e.g. "file:/run/secrets/meter.key".
+	Key string
+	// Properties are the references of the other secrets of the protocol,
+	// by name.
+	Properties map[string]string
+}
+
+// ProbeInfo configures a probe of the readiness of the hardware of the
+// gateway, waited for before the driver is initialized, e.g. a serial port
+// created late by udev.
+type ProbeInfo struct {
+	// Type is "file" for a file to exist, "interface" for a network
+	// interface to be up, or "ntp" for the clock to be synchronized.
+	Type string
+	// Target is the path of the file or the name of the interface.
+	Target string
+	// Timeout is the time (in milliseconds) waited for the probe.
+	Timeout int
+	// Optional lets the DS start anyway if the probe times out.
+	Optional bool
+}
+
+// KeepaliveInfo configures the keepalive read of a Device, i.e. a harmless
+// read command issued when the Device has been idle for Interval, keeping
+// the session of a NAT or serial gateway in front of it alive between
+// sparse reads, so that the next one doesn't time out.
+type KeepaliveInfo struct {
+	// Command is the name of the read command.
+	Command string
+	// Interval is the idle time (in milliseconds) after which the command
+	// is read.
+	Interval int
 }
 
 // DeviceConfig is the definition of Devices which will be auto created when the Device Service starts up
@@ -147,6 +727,12 @@ type DeviceConfig struct {
 	Labels []string
 	// Addressable for the device - stores information about it's address
 	Addressable models.Addressable
+	// Properties are the custom properties of the Device, e.g. its phase or
+	// CT ratio, passed to the driver and available to the transforms.
+	Properties map[string]string
+	// AutoEvents are the periodic reads of the resources of the Device,
+	// run whether or not its Device is managed by Core Metadata.
+	AutoEvents []ds_models.AutoEvent
 }
 
 // ClientInfo provides the host and port of another service in the eco-system.
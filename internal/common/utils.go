@@ -10,9 +10,14 @@ package common
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/clock"
+	"github.com/edgexfoundry/device-sdk-go/internal/dedup"
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/publisher"
+	"github.com/edgexfoundry/device-sdk-go/pkg/signing"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"gopkg.in/mgo.v2/bson"
@@ -30,24 +35,152 @@ func BuildAddr(host string, port string) string {
 }
 
 func CommandValueToReading(cv *ds_models.CommandValue, devName string) *models.Reading {
-	reading := &models.Reading{Name: cv.RO.Parameter, Device: devName}
+	reading := &models.Reading{Name: ReadingName(devName, cv.RO.Parameter), Device: devName}
 	reading.Value = cv.ValueToString()
 
 	// if value has a non-zero Origin, use it
+	now := time.Now().UnixNano() / int64(time.Millisecond)
 	if cv.Origin > 0 {
-		reading.Origin = cv.Origin
+		reading.Origin = compensateDrift(devName, cv.Origin, now)
 	} else {
-		reading.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+		reading.Origin = now
 	}
 
 	return reading
 }
 
+// compensateDrift feeds a device-origin timestamp to the clock drift
+// estimator of the Device and returns it, corrected if so configured.
+func compensateDrift(devName string, origin int64, now int64) int64 {
+	if !CurrentConfig.Device.DriftEstimation {
+		return origin
+	}
+
+	off := clock.Observe(devName, origin, now)
+	threshold := CurrentConfig.Device.DriftThreshold
+	if threshold > 0 && (off.Offset > threshold || off.Offset < -threshold) {
+		LoggingClient.Warn(fmt.Sprintf("Clock of device %s is %d ms off the gateway clock", devName, off.Offset))
+	}
+	if CurrentConfig.Device.DriftCorrection {
+		return origin - off.Offset
+	}
+	return origin
+}
+
+// ReadingName returns the name of the readings generated for the given
+// parameter of a Device, taking any configured reading alias into account.
+func ReadingName(devName string, parameter string) string {
+	if aliases, ok := CurrentConfig.ReadingAliases[devName]; ok {
+		if alias, ok := aliases[parameter]; ok && alias != "" {
+			return alias
+		}
+	}
+	return parameter
+}
+
+// DriverConfig returns the settings of the driver, i.e. the Driver section
+// of the configuration overridden by the Driver subsection of Writable.
+func DriverConfig() map[string]string {
+	config := make(map[string]string, len(CurrentConfig.Driver)+len(CurrentConfig.Writable.Driver))
+	for name, value := range CurrentConfig.Driver {
+		config[name] = value
+	}
+	for name, value := range CurrentConfig.Writable.Driver {
+		config[name] = value
+	}
+	return config
+}
+
+// APIRoute returns the given REST API route, prefixed with the Tenant
+// when the REST API is partitioned per tenant.
+func APIRoute(route string) string {
+	return TenantRoute(CurrentConfig, route)
+}
+
+// TenantRoute is APIRoute for the given configuration, for use before
+// CurrentConfig has been set.
+func TenantRoute(config *Config, route string) string {
+	if config != nil && config.Service.TenantPathPrefix && config.Service.Tenant != "" {
+		return "/" + config.Service.Tenant + route
+	}
+	return route
+}
+
 func SendEvent(event *models.Event) {
-	_, err := EventClient.Add(event)
-	if err != nil {
-		LoggingClient.Error(fmt.Sprintf("Failed to push event for device %s: %v", event.Device, err))
+	if InStandby() {
+		LoggingClient.Debug(fmt.Sprintf("Suppressed event for device %s in standby mode", event.Device))
+		return
+	}
+	event = tenantEvent(event)
+	event = timestampEvent(event)
+	if !dedup.Mark(event) {
+		LoggingClient.Info(fmt.Sprintf("Suppressed duplicate event for device %s", event.Device))
+		return
 	}
+	marked := event
+	if EventSigner != nil {
+		signed, err := signEvent(event)
+		if err != nil {
+			LoggingClient.Error(fmt.Sprintf("Failed to sign event for device %s: %v", event.Device, err))
+			dedup.Unmark(marked)
+			return
+		}
+		event = signed
+	}
+	if !deliverEvent(event) {
+		dedup.Unmark(marked)
+	}
+}
+
+// deliverEvent publishes the event to the message queue, if configured,
+// and pushes it to Core Data, unless the message queue is exclusive. It
+// returns false if the event certainly hasn't been delivered.
+func deliverEvent(event *models.Event) bool {
+	delivered := false
+	if EventPublisher != nil {
+		mq := CurrentConfig.MessageQueue
+		topic := publisher.Topic(mq.Topic, ServiceName, CurrentConfig.Service.Tenant, event)
+		if err := EventPublisher.Publish(topic, event); err != nil {
+			LoggingClient.Error(fmt.Sprintf("Failed to publish event for device %s: %v", event.Device, err))
+		} else {
+			delivered = true
+		}
+		if mq.Exclusive {
+			return delivered
+		}
+	}
+
+	// on timeout the event may have been received, and a dead letter will
+	// be, so that a replay is still suppressed
+	return pushEvent(event) || delivered
+}
+
+// signEvent returns a signed copy of the event. As the origin is part of the
+// signature, it is set if missing.
+func signEvent(event *models.Event) (*models.Event, error) {
+	if event.Origin == 0 {
+		e := *event
+		e.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+		event = &e
+	}
+	return signing.Sign(event, EventSigner)
+}
+
+// tenantEvent returns a copy of the event with an additional reading
+// identifying the Tenant, or the event itself if no Tenant is configured.
+// The given event isn't modified, as it may be shared with the caller.
+func tenantEvent(event *models.Event) *models.Event {
+	if CurrentConfig == nil || CurrentConfig.Service.Tenant == "" {
+		return event
+	}
+
+	e := *event
+	e.Readings = make([]models.Reading, len(event.Readings), len(event.Readings)+1)
+	copy(e.Readings, event.Readings)
+	tenant := models.Reading{Name: TenantReadingName, Device: event.Device, Value: CurrentConfig.Service.Tenant}
+	tenant.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+	e.Readings = append(e.Readings, tenant)
+	return &e
 }
 
 func CompareCommands(a []models.Command, b []models.Command) bool {
@@ -73,7 +206,7 @@ func CompareDevices(a models.Device, b models.Device) bool {
 		a.AdminState == b.AdminState &&
 		a.Description == b.Description &&
 		a.Id == b.Id &&
-		a.Location == b.Location &&
+		reflect.DeepEqual(a.Location, b.Location) &&
 		a.Name == b.Name &&
 		a.OperatingState == b.OperatingState &&
 		labelsOk &&
@@ -239,6 +372,16 @@ func MakeAddressable(name string, addr *models.Addressable) (*models.Addressable
 	return &addressable, nil
 }
 
+// DeviceObjectAttribute returns the string form of the named attribute of
+// a device resource (aka DeviceObject), and whether it is defined.
+func DeviceObjectAttribute(do models.DeviceObject, name string) (string, bool) {
+	v, ok := do.Attributes[name]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%v", v), true
+}
+
 func VerifyIdFormat(id string, objName string) error {
 	if len(id) != 24 || !bson.IsObjectIdHex(id) {
 		errMsg := fmt.Sprintf("Add %s returned invalid Id: %s", objName, id)
@@ -8,7 +8,15 @@
 package common
 
 import (
+	"bytes"
+	"net/http"
+	"os"
 	"testing"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
 func TestBuildAddr(t *testing.T) {
@@ -76,3 +84,301 @@ func TestCompareStrStrMap(t *testing.T) {
 		t.Error("Maps with different content are OK!")
 	}
 }
+
+func TestTimestampEvent(t *testing.T) {
+	defer InitTimestamps(ServiceInfo{})
+
+	event := &models.Event{Device: "meter", Origin: 1530000000000, Readings: []models.Reading{{Name: "Energy", Origin: 1530000000000}}}
+	if e := timestampEvent(event); e != event {
+		t.Error("Event changed with the default settings")
+	}
+
+	if err := InitTimestamps(ServiceInfo{OriginPrecision: "us", Timezone: "+02:00"}); err != nil {
+		t.Fatal(err)
+	}
+	e := timestampEvent(event)
+	if e.Origin != 1530000000000000 || e.Readings[0].Origin != 1530000000000000 || event.Readings[0].Origin != 1530000000000 {
+		t.Errorf("Unexpected origins %d, %d", e.Origin, e.Readings[0].Origin)
+	}
+	if len(e.Readings) != 3 || e.Readings[1].Value != "us" || e.Readings[2].Value != "+02:00" {
+		t.Errorf("Unexpected annotations %v", e.Readings)
+	}
+
+	if err := InitTimestamps(ServiceInfo{OriginPrecision: "s"}); err == nil {
+		t.Error("Invalid precision accepted")
+	}
+	if err := InitTimestamps(ServiceInfo{Timezone: "Nowhere/Land"}); err == nil {
+		t.Error("Invalid timezone accepted")
+	}
+}
+
+func TestDriverConfig(t *testing.T) {
+	previous := CurrentConfig
+	defer func() { CurrentConfig = previous }()
+
+	CurrentConfig = &Config{
+		Driver:   map[string]string{"MaxRetries": "2", "RetryDelay": "100"},
+		Writable: WritableInfo{Driver: map[string]string{"MaxRetries": "5", "DialTimeout": "1000"}},
+	}
+	config := DriverConfig()
+	expected := map[string]string{"MaxRetries": "5", "RetryDelay": "100", "DialTimeout": "1000"}
+	if !CompareStrStrMap(config, expected) {
+		t.Errorf("Driver settings %v, expected %v", config, expected)
+	}
+	if CurrentConfig.Driver["MaxRetries"] != "2" {
+		t.Error("Driver section modified")
+	}
+}
+
+func TestReadingName(t *testing.T) {
+	previous := CurrentConfig
+	defer func() { CurrentConfig = previous }()
+
+	CurrentConfig = &Config{
+		ReadingAliases: map[string]map[string]string{
+			"meter": {"Voltage": "meter_voltage", "Current": ""},
+		},
+	}
+	tests := []struct {
+		device    string
+		parameter string
+		expected  string
+	}{
+		{"meter", "Voltage", "meter_voltage"},
+		{"meter", "Current", "Current"},
+		{"meter", "Power", "Power"},
+		{"switch", "Voltage", "Voltage"},
+	}
+	for _, tt := range tests {
+		if name := ReadingName(tt.device, tt.parameter); name != tt.expected {
+			t.Errorf("ReadingName(%s, %s) = %s, expected %s", tt.device, tt.parameter, name, tt.expected)
+		}
+	}
+}
+
+func TestTenantRoute(t *testing.T) {
+	config := &Config{Service: ServiceInfo{Tenant: "acme"}}
+	if route := TenantRoute(config, APIPingRoute); route != APIPingRoute {
+		t.Errorf("Route %s without TenantPathPrefix", route)
+	}
+	config.Service.TenantPathPrefix = true
+	if route := TenantRoute(config, APIPingRoute); route != "/acme"+APIPingRoute {
+		t.Errorf("Route %s with TenantPathPrefix", route)
+	}
+	if route := TenantRoute(nil, APIPingRoute); route != APIPingRoute {
+		t.Errorf("Route %s without configuration", route)
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		value    string
+		integer  bool
+		expected string
+		fails    bool
+	}{
+		{"12,5", false, "12.5", false},
+		{"1.234,5", false, "1234.5", false},
+		{"1,234.5", false, "1234.5", false},
+		{"0x1F", true, "31", false},
+		{"-0x10", true, "-16", false},
+		{"4.7k", false, "4.7e3", false},
+		{"1,5k", true, "1500", false},
+		{"220m", false, "220e-3", false},
+		{" 42 ", true, "42", false},
+		{"2.5", true, "", true},
+		{"0xZZ", true, "", true},
+		{"abc", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			s, err := NormalizeNumber(tt.value, tt.integer)
+			if tt.fails {
+				if err == nil {
+					t.Errorf("Expected %q to fail, got %q", tt.value, s)
+				}
+				return
+			}
+			if err != nil || s != tt.expected {
+				t.Errorf("Expected %q, got %q, %v", tt.expected, s, err)
+			}
+		})
+	}
+}
+
+func TestResolveDeviceName(t *testing.T) {
+	previous := CurrentConfig
+	defer func() { CurrentConfig = previous }()
+	CurrentConfig = &Config{DeviceAliases: map[string]string{
+		"Meter01":  "meter-01",
+		"meter-01": "site-a-meter-01",
+		"loop-a":   "loop-b",
+		"loop-b":   "loop-a",
+	}}
+
+	if name, ok := ResolveDeviceName("Meter01"); !ok || name != "site-a-meter-01" {
+		t.Errorf("Meter01 resolved to %q, %v", name, ok)
+	}
+	if name, ok := ResolveDeviceName("site-a-meter-01"); ok || name != "site-a-meter-01" {
+		t.Errorf("Current name resolved to %q, %v", name, ok)
+	}
+	// a cycle terminates
+	ResolveDeviceName("loop-a")
+}
+
+func TestResolveAddressable(t *testing.T) {
+	previous := CurrentConfig
+	defer func() { CurrentConfig = previous }()
+	CurrentConfig = &Config{Credentials: map[string]CredentialsInfo{
+		"meter":  {Username: "operator", Password: "env:RESOLVE_TEST_PASSWORD", Properties: map[string]string{"authKey": "env:RESOLVE_TEST_PASSWORD"}},
+		"broken": {Password: "env:RESOLVE_TEST_UNSET"},
+	}}
+	os.Setenv("RESOLVE_TEST_PASSWORD", "secret")
+	defer os.Unsetenv("RESOLVE_TEST_PASSWORD")
+
+	addr, err := ResolveAddressable("meter", models.Addressable{User: "admin", Password: "plain"})
+	if err != nil || addr.User != "operator" || addr.Password != "secret" {
+		t.Errorf("Unexpected credentials %s/%s, %v", addr.User, addr.Password, err)
+	}
+	c, _ := CredentialsProvider{}.Credentials("meter")
+	if c.Properties["authKey"] != "secret" {
+		t.Errorf("Unexpected properties %v", c.Properties)
+	}
+
+	addr, err = ResolveAddressable("plc", models.Addressable{Password: "env:RESOLVE_TEST_PASSWORD"})
+	if err != nil || addr.Password != "secret" {
+		t.Errorf("Password reference resolved to %s, %v", addr.Password, err)
+	}
+	addr, _ = ResolveAddressable("plc", models.Addressable{Password: "plain"})
+	if addr.Password != "plain" {
+		t.Errorf("Plain password resolved to %s", addr.Password)
+	}
+	if _, err = ResolveAddressable("broken", models.Addressable{}); err == nil {
+		t.Error("Missing secret resolved")
+	}
+}
+
+// eventClient records the events pushed to Core Data, as JSON or CBOR
+// encoded.
+type eventClient struct {
+	coredata.EventClient
+	added []*models.Event
+	cbor  [][]byte
+}
+
+func (c *eventClient) Add(event *models.Event) (string, error) {
+	c.added = append(c.added, event)
+	return "", nil
+}
+
+func (c *eventClient) AddCBOR(body []byte) (string, error) {
+	c.cbor = append(c.cbor, body)
+	return "", nil
+}
+
+func TestPushBinaryEvent(t *testing.T) {
+	previous := EventClient
+	defer func() { EventClient = previous }()
+	client := &eventClient{}
+	EventClient = client
+
+	value := BinaryReadingValue("application/octet-stream", []byte{0, 1, 2})
+	if value != "data:application/octet-stream;base64,AAEC" {
+		t.Errorf("Unexpected binary value %s", value)
+	}
+	// a String reading looking like a binary one isn't one
+	event := &models.Event{Device: "scope", Origin: 1, Readings: []models.Reading{
+		{Name: "Waveform", Device: "scope", Origin: 1, Value: value},
+		{Name: "Label", Device: "scope", Origin: 1, Value: value},
+	}}
+	if err := addEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.added) != 1 || len(client.cbor) != 0 {
+		t.Fatalf("Event without binary readings pushed CBOR encoded")
+	}
+
+	MarkBinaryReading("scope", "Waveform", "application/octet-stream")
+	defer func() {
+		binaryMutex.Lock()
+		delete(binaryReadings, binaryReadingKey("scope", "Waveform"))
+		binaryMutex.Unlock()
+	}()
+	if err := addEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.added) != 1 || len(client.cbor) != 1 {
+		t.Fatalf("Event with binary readings pushed as JSON")
+	}
+	// the raw bytes of the waveform, instead of their base64 encoding
+	body := client.cbor[0]
+	if !bytes.Contains(body, []byte{0x6b, 'b', 'i', 'n', 'a', 'r', 'y', 'V', 'a', 'l', 'u', 'e', 0x43, 0, 1, 2}) {
+		t.Errorf("Unexpected CBOR event % x", body)
+	}
+	if bytes.Count(body, []byte("binaryValue")) != 1 {
+		t.Errorf("String reading encoded as binary in % x", body)
+	}
+
+	// the clients without CBOR support push the data URLs
+	EventClient = struct{ coredata.EventClient }{client}
+	if err := addEvent(event); err != nil || len(client.added) != 2 {
+		t.Errorf("Event not pushed as JSON: %v", err)
+	}
+}
+
+func TestSendEventInStandby(t *testing.T) {
+	LoggingClient = logger.NewClient("common_test", false, "", "DEBUG")
+	previousConfig, previousClient := CurrentConfig, EventClient
+	defer func() { CurrentConfig, EventClient = previousConfig, previousClient }()
+	CurrentConfig = &Config{}
+	client := &eventClient{}
+	EventClient = client
+	defer SetStandby(false)
+
+	SetStandby(true)
+	SendEvent(&models.Event{Device: "meter", Readings: []models.Reading{{Name: "Voltage", Value: "230"}}})
+	if len(client.added) != 0 {
+		t.Fatalf("Event pushed in standby mode")
+	}
+
+	// the promoted DS pushes the following events
+	SetStandby(false)
+	SendEvent(&models.Event{Device: "meter", Readings: []models.Reading{{Name: "Voltage", Value: "231"}}})
+	if len(client.added) != 1 || client.added[0].Readings[0].Value != "231" {
+		t.Errorf("Events pushed after the promotion %v", client.added)
+	}
+}
+
+func TestNewDriverError(t *testing.T) {
+	err := ds_models.NewProtocolError("0x02", "Illegal Data Address", nil)
+	if appErr := NewDriverError("read failed", err); appErr.Code() != http.StatusBadGateway {
+		t.Errorf("Protocol exception mapped to %d", appErr.Code())
+	}
+	if err.Error() != "ProtocolException 0x02 (Illegal Data Address)" {
+		t.Errorf("Unexpected message %s", err.Error())
+	}
+	if appErr := NewDriverError("read failed", ds_models.NewDriverError(ds_models.ErrorTimeout, nil)); appErr.Code() != http.StatusGatewayTimeout {
+		t.Errorf("Timeout mapped to %d", appErr.Code())
+	}
+	if appErr := NewDriverError("read failed", os.ErrClosed); appErr.Code() != http.StatusInternalServerError {
+		t.Errorf("Other error mapped to %d", appErr.Code())
+	}
+}
+
+func TestDeviceProperties(t *testing.T) {
+	device := models.Device{Location: map[string]interface{}{"ctRatio": 200.0, "phase": "L1"}}
+	props := DeviceProperties(device)
+	if props["ctRatio"] != "200" || props["phase"] != "L1" {
+		t.Errorf("Properties %v", props)
+	}
+
+	device.Location = "Cabinet 3"
+	if props = DeviceProperties(device); props[LocationProperty] != "Cabinet 3" {
+		t.Errorf("Properties %v", props)
+	}
+
+	device.Location = nil
+	if props = DeviceProperties(device); len(props) != 0 {
+		t.Errorf("Properties %v without a Location", props)
+	}
+}
@@ -34,23 +34,14 @@ var (
 // specified parameters and returns a pointer to the global Config
 // struct which holds all of the local configuration settings for
 // the DS. The bool useRegisty indicates whether the registry
-// should be used to read initial config settings. This also controls
+// should be used to read initial config settings, overriding the ones of
+// the file, see loadFromRegistry. This also controls
 // whether the service registers itself the registry. The profile and confDir
 // are used to locate the local TOML config file.
 func LoadConfig(useRegistry bool, profile string, confDir string) (config *common.Config, err error) {
 	fmt.Fprintf(os.Stdout, "Init: useRegistry: %v profile: %s confDir: %s\n",
 		useRegistry, profile, confDir)
-	confName := "configuration.toml"
-
-	if len(confDir) == 0 {
-		confDir = "./res"
-	}
-
-	if len(profile) > 0 {
-		confDir = confDir + "/" + profile
-	}
-
-	path := confDir + "/" + confName
+	path := configPath(profile, confDir)
 
 	// As the toml package can panic if TOML is invalid,
 	// or elements are found that don't match members of
@@ -68,6 +59,16 @@ func LoadConfig(useRegistry bool, profile string, confDir string) (config *commo
 		return nil, fmt.Errorf("could not load configuration file (%s): %v", path, err.Error())
 	}
 
+	// Older layouts are migrated on the fly, the file itself is only
+	// migrated on request
+	if migrated, changes, err := Migrate(contents); err == nil && len(changes) > 0 {
+		fmt.Fprintf(os.Stdout, "Configuration file (%s) uses an older layout, run with -migrate to upgrade it:\n", path)
+		for _, c := range changes {
+			fmt.Fprintf(os.Stdout, "  %v\n", c)
+		}
+		contents = migrated
+	}
+
 	// Decode the configuration from TOML
 	//
 	// TODO: invalid input can cause a SIGSEGV fatal error (INVESTIGATE)!!!
@@ -84,6 +85,10 @@ func LoadConfig(useRegistry bool, profile string, confDir string) (config *commo
 		if err != nil {
 			return nil, err
 		}
+		// the settings held by the registry take precedence over the file
+		if err = loadFromRegistry(RegistryClient, KeyPrefix(common.ServiceName, profile), config); err != nil {
+			return nil, fmt.Errorf("could not load configuration from registry: %v", err)
+		}
 	} else {
 		registryMsg = "Bypassing registration in registry..."
 	}
@@ -92,6 +97,22 @@ func LoadConfig(useRegistry bool, profile string, confDir string) (config *commo
 	return config, nil
 }
 
+// configPath returns the path of the configuration file for the given
+// profile and configuration directory.
+func configPath(profile string, confDir string) string {
+	confName := "configuration.toml"
+
+	if len(confDir) == 0 {
+		confDir = "./res"
+	}
+
+	if len(profile) > 0 {
+		confDir = confDir + "/" + profile
+	}
+
+	return confDir + "/" + confName
+}
+
 func GetRegistryClient(serviceName string, config *common.Config) (*registry.ConsulClient, error) {
 	err := checkRegistryUp(config)
 	if err != nil {
@@ -141,9 +162,12 @@ func newConsulClient(serviceName string, config *common.Config) (*registry.Consu
 		ServiceName:    serviceName,
 		ServiceAddress: config.Service.Host,
 		ServicePort:    config.Service.Port,
-		CheckAddress:   fmt.Sprintf("http://%v:%v%v", config.Service.Host, config.Service.Port, common.APIPingRoute),
+		CheckAddress:   fmt.Sprintf("http://%v:%v%v", config.Service.Host, config.Service.Port, common.TenantRoute(config, common.APIPingRoute)),
 		CheckInterval:  config.Registry.CheckInterval,
 	}
+	if config.Service.Tenant != "" {
+		registryConfig.Tags = []string{common.TenantLabelPrefix + config.Service.Tenant}
+	}
 	err := consulClient.Init(registryConfig)
 	return consulClient, err
 }
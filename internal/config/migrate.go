@@ -0,0 +1,206 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Change describes a configuration key changed by Migrate. To is empty if
+// the key has been removed.
+type Change struct {
+	From string
+	To   string
+	Note string
+}
+
+func (c Change) String() string {
+	s := c.From + " -> " + c.To
+	if c.To == "" {
+		s = c.From + " removed"
+	}
+	if c.Note != "" {
+		s += " (" + c.Note + ")"
+	}
+	return s
+}
+
+// renamedSections maps the top level tables of older layouts to the
+// current ones.
+var renamedSections = map[string]string{
+	"Consul": "Registry",
+}
+
+// renamedClients maps the client names of older layouts to the current ones.
+var renamedClients = map[string]string{
+	"Core":           "Data",
+	"CoreData":       "Data",
+	"Meta":           "Metadata",
+	"MetaData":       "Metadata",
+	"Log":            "Logging",
+	"SupportLogging": "Logging",
+}
+
+// removedKeys lists keys of older layouts which are no longer used.
+var removedKeys = map[string][]string{
+	"Service": {"Name", "HealthCheck"},
+	"Device":  {"Discovery"},
+}
+
+// addressableKeys are the Addressable fields found directly in device and
+// schedule event entries of older layouts.
+var addressableKeys = []string{"Name", "Protocol", "HTTPMethod", "Address", "Port", "Path", "Publisher", "User", "Password", "Topic"}
+
+// Migrate upgrades a configuration in an older layout to the current one,
+// returning the migrated configuration and the changed keys. Comments aren't
+// preserved, so no changes means the given contents should be kept.
+func Migrate(contents []byte) ([]byte, []Change, error) {
+	conf := map[string]interface{}{}
+	if _, err := toml.Decode(string(contents), &conf); err != nil {
+		return nil, nil, err
+	}
+
+	var changes []Change
+	for from, to := range renamedSections {
+		if section, ok := conf[from]; ok {
+			if _, ok = conf[to]; !ok {
+				conf[to] = section
+				delete(conf, from)
+				changes = append(changes, Change{From: from, To: to})
+			}
+		}
+	}
+
+	for section, keys := range removedKeys {
+		table, _ := conf[section].(map[string]interface{})
+		for _, key := range keys {
+			if _, ok := table[key]; ok {
+				delete(table, key)
+				changes = append(changes, Change{From: section + "." + key})
+			}
+		}
+	}
+
+	if clients, ok := conf["Clients"].(map[string]interface{}); ok {
+		for from, to := range renamedClients {
+			if client, ok := clients[from]; ok {
+				if _, ok = clients[to]; !ok {
+					clients[to] = client
+					delete(clients, from)
+					changes = append(changes, Change{From: "Clients." + from, To: "Clients." + to})
+				}
+			}
+		}
+	}
+
+	if logging, ok := conf["Logging"].(map[string]interface{}); ok {
+		if url, ok := logging["RemoteURL"].(string); ok {
+			delete(logging, "RemoteURL")
+			if _, ok = logging["EnableRemote"]; !ok {
+				logging["EnableRemote"] = url != ""
+			}
+			changes = append(changes, Change{From: "Logging.RemoteURL", To: "Logging.EnableRemote", Note: "the remote service is configured in Clients.Logging"})
+		}
+	}
+
+	if registry, ok := conf["Registry"].(map[string]interface{}); ok {
+		if interval, ok := registry["CheckInterval"].(int64); ok {
+			registry["CheckInterval"] = fmt.Sprintf("%ds", interval)
+			changes = append(changes, Change{From: "Registry.CheckInterval", To: "Registry.CheckInterval", Note: "seconds as a duration"})
+		}
+	}
+
+	changes = append(changes, migrateAddressables(conf, "DeviceList")...)
+	changes = append(changes, migrateAddressables(conf, "ScheduleEvents")...)
+
+	if len(changes) == 0 {
+		return contents, nil, nil
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].From < changes[j].From })
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(conf); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// migrateAddressables moves the Addressable fields of the entries of the
+// given array of tables into their Addressable table, and capitalizes the
+// keys of the latter.
+func migrateAddressables(conf map[string]interface{}, array string) []Change {
+	entries, _ := conf[array].([]map[string]interface{})
+	var changes []Change
+	for i, entry := range entries {
+		prefix := fmt.Sprintf("%s[%d].", array, i)
+		addr, ok := entry["Addressable"].(map[string]interface{})
+		if !ok {
+			addr = map[string]interface{}{}
+		}
+		for key, value := range addr {
+			for _, k := range addressableKeys {
+				if key != k && strings.EqualFold(key, k) {
+					delete(addr, key)
+					addr[k] = value
+					changes = append(changes, Change{From: prefix + "Addressable." + key, To: prefix + "Addressable." + k})
+				}
+			}
+		}
+		for _, key := range addressableKeys {
+			// devices keep their own name
+			if key == "Name" {
+				continue
+			}
+			value, ok := entry[key]
+			if !ok {
+				continue
+			}
+			delete(entry, key)
+			if _, ok = addr[key]; !ok {
+				addr[key] = value
+			}
+			changes = append(changes, Change{From: prefix + key, To: prefix + "Addressable." + key})
+		}
+		if len(addr) > 0 {
+			entry["Addressable"] = addr
+		}
+	}
+	return changes
+}
+
+// MigrateFile migrates the configuration file selected by the given profile
+// and configuration directory, as in LoadConfig. The original file is kept
+// with an .orig suffix.
+func MigrateFile(profile string, confDir string) ([]Change, error) {
+	path := configPath(profile, confDir)
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load configuration file (%s): %v", path, err)
+	}
+	migrated, changes, err := Migrate(contents)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file (%s): %v", path, err)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(path+".orig", contents, info.Mode()); err != nil {
+		return nil, err
+	}
+	return changes, ioutil.WriteFile(path, migrated, info.Mode())
+}
@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+const legacyConfig = `
+[Service]
+Name = "device-modbus"
+Host = "localhost"
+Port = 49991
+HealthCheck = "/api/v1/ping"
+
+[Consul]
+Host = "localhost"
+Port = 8500
+CheckInterval = 10
+
+[Clients]
+  [Clients.CoreData]
+  Host = "localhost"
+  Port = 48080
+
+  [Clients.MetaData]
+  Host = "localhost"
+  Port = 48081
+
+[Device]
+DataTransform = true
+Discovery = false
+
+[Logging]
+RemoteURL = "http://localhost:48061/api/v1/logs"
+File = "./device-modbus.log"
+
+[[DeviceList]]
+  Name = "Modbus-TCP-Device"
+  Profile = "Network Power Meter"
+  Protocol = "TCP"
+  Address = "10.0.0.1"
+  Port = 502
+  [DeviceList.Addressable]
+    name = "Gateway"
+    path = "/1"
+`
+
+func TestMigrate(t *testing.T) {
+	migrated, changes, err := Migrate([]byte(legacyConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 13 {
+		t.Errorf("Unexpected changes: %v", changes)
+	}
+
+	config := &common.Config{}
+	if err = toml.Unmarshal(migrated, config); err != nil {
+		t.Fatalf("Migrated configuration can't be loaded: %v\n%s", err, migrated)
+	}
+	if config.Registry.Port != 8500 || config.Registry.CheckInterval != "10s" {
+		t.Errorf("Unexpected Registry %v", config.Registry)
+	}
+	if config.Clients["Data"].Port != 48080 || config.Clients["Metadata"].Port != 48081 {
+		t.Errorf("Unexpected Clients %v", config.Clients)
+	}
+	if !config.Logging.EnableRemote {
+		t.Error("Remote logging not enabled")
+	}
+	addr := config.DeviceList[0].Addressable
+	if config.DeviceList[0].Name != "Modbus-TCP-Device" || addr.Name != "Gateway" || addr.Address != "10.0.0.1" || addr.Port != 502 || addr.Path != "/1" || addr.Protocol != "TCP" {
+		t.Errorf("Unexpected device %v", config.DeviceList[0])
+	}
+}
+
+func TestMigrateCurrent(t *testing.T) {
+	current := "[Service]\nHost = \"localhost\"\n"
+	migrated, changes, err := Migrate([]byte(current))
+	if err != nil || len(changes) != 0 || string(migrated) != current {
+		t.Errorf("Current configuration changed: %v, %v", changes, err)
+	}
+}
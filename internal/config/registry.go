@@ -0,0 +1,249 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/registry"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+const (
+	configKeyRoot = "config/"
+	writableKey   = "Writable"
+)
+
+// KeyPrefix returns the registry prefix of the configuration of a service,
+// "config/<service>" followed by ";<profile>" for a profile.
+func KeyPrefix(serviceName string, profile string) string {
+	if profile == "" {
+		return configKeyRoot + serviceName
+	}
+	return configKeyRoot + serviceName + ";" + profile
+}
+
+// loadFromRegistry overrides the configuration with the settings held by the
+// registry, under prefix, and adds the ones missing from it. The settings
+// are the fields of the sections and the entries of the maps, e.g.
+// "Service/Port" or "Driver/MaxRetries"; lists of strings are
+// comma-separated, other lists, e.g. DeviceList, are only configured by the
+// file.
+func loadFromRegistry(client registry.Client, prefix string, config *common.Config) error {
+	values, err := client.GetKeyValues(prefix)
+	if err != nil {
+		return err
+	}
+	if err = applyValues(config, values); err != nil {
+		return err
+	}
+
+	missing := make(map[string]string)
+	for key, value := range flatten(reflect.ValueOf(config).Elem(), "") {
+		if _, ok := values[key]; !ok {
+			missing[key] = value
+		}
+	}
+	return client.PutKeyValues(prefix, missing)
+}
+
+// SetRegistryLogger sets the logging client reporting the failures of the
+// watches and campaigns of the registry, if used.
+func SetRegistryLogger(lc logger.LoggingClient) {
+	if c, ok := RegistryClient.(*registry.ConsulClient); ok {
+		c.Logger = lc
+	}
+}
+
+// WatchWritable calls onChange with the Writable section of the configuration
+// held by the registry whenever it differs from the current one, until stop
+// is closed. It returns immediately if the registry isn't used.
+func WatchWritable(profile string, stop <-chan struct{}, onChange func(writable common.WritableInfo)) {
+	if RegistryClient == nil {
+		return
+	}
+	watchWritable(RegistryClient, KeyPrefix(common.ServiceName, profile), common.CurrentConfig.Writable, stop, onChange)
+}
+
+func watchWritable(client registry.Client, prefix string, current common.WritableInfo, stop <-chan struct{}, onChange func(writable common.WritableInfo)) {
+	last := current
+	client.WatchPrefix(prefix, stop, func(values map[string]string) {
+		writable := make(map[string]string)
+		for key, value := range values {
+			if strings.HasPrefix(key, writableKey+"/") {
+				writable[key] = value
+			}
+		}
+		var config common.Config
+		if err := applyValues(&config, writable); err != nil {
+			fmt.Println("Invalid Writable configuration in registry:", err)
+			return
+		}
+		if !reflect.DeepEqual(last, config.Writable) {
+			last = config.Writable
+			onChange(config.Writable)
+		}
+	})
+}
+
+// applyValues sets the settings of the configuration given by their keys.
+func applyValues(config *common.Config, values map[string]string) error {
+	for key, value := range values {
+		if err := setPath(reflect.ValueOf(config).Elem(), strings.Split(key, "/"), value); err != nil {
+			return fmt.Errorf("key %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// flatten returns the settings of v by their keys, prefixed with path.
+func flatten(v reflect.Value, path string) map[string]string {
+	values := make(map[string]string)
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "/" + name
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			for key, value := range flatten(v.Field(i), join(v.Type().Field(i).Name)) {
+				values[key] = value
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		for _, k := range v.MapKeys() {
+			for key, value := range flatten(v.MapIndex(k), join(k.String())) {
+				values[key] = value
+			}
+		}
+	default:
+		if s, ok := formatValue(v); ok {
+			values[path] = s
+		}
+	}
+	return values
+}
+
+// setPath sets the setting of v at path.
+func setPath(v reflect.Value, path []string, value string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		if len(path) == 0 {
+			return fmt.Errorf("not a setting")
+		}
+		f := v.FieldByName(path[0])
+		if !f.IsValid() {
+			return fmt.Errorf("unknown setting %s", path[0])
+		}
+		return setPath(f, path[1:], value)
+	case reflect.Map:
+		if len(path) == 0 || v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("not a setting")
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setPath(elem, path[1:], value); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	}
+	if len(path) != 0 {
+		return fmt.Errorf("unknown setting %s", path[0])
+	}
+	return parseValue(v, value)
+}
+
+// formatValue returns the string form of a setting, or false if it isn't
+// configurable through the registry.
+func formatValue(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return "", false
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+		return strings.Join(items, ","), true
+	}
+	return "", false
+}
+
+// parseValue sets a setting from its string form.
+func parseValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("not a setting")
+		}
+		var items []string
+		if s != "" {
+			items = strings.Split(s, ",")
+			for i := range items {
+				items[i] = strings.TrimSpace(items[i])
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	default:
+		return fmt.Errorf("not a setting")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/registry"
+)
+
+// fakeRegistry holds key/value pairs, calling the watchers on each update.
+type fakeRegistry struct {
+	values   map[string]string
+	watchers []func(map[string]string)
+}
+
+func (r *fakeRegistry) Init(config registry.RegistryConfig) error { return nil }
+
+func (r *fakeRegistry) GetServiceEndpoint(serviceKey string) (registry.ServiceEndpoint, error) {
+	return registry.ServiceEndpoint{}, nil
+}
+
+func (r *fakeRegistry) GetKeyValues(prefix string) (map[string]string, error) {
+	values := make(map[string]string)
+	for k, v := range r.values {
+		values[k] = v
+	}
+	return values, nil
+}
+
+func (r *fakeRegistry) PutKeyValues(prefix string, values map[string]string) error {
+	for k, v := range values {
+		r.values[k] = v
+	}
+	for _, w := range r.watchers {
+		current, _ := r.GetKeyValues(prefix)
+		w(current)
+	}
+	return nil
+}
+
+func (r *fakeRegistry) WatchPrefix(prefix string, stop <-chan struct{}, onChange func(values map[string]string)) {
+	r.watchers = append(r.watchers, onChange)
+	current, _ := r.GetKeyValues(prefix)
+	onChange(current)
+}
+
+func (r *fakeRegistry) WatchKey(key string, stop <-chan struct{}, onChange func(value string)) {}
+
+func (r *fakeRegistry) Campaign(key string, sessionTTL string, stop <-chan struct{}, onChange func(leader bool)) {
+}
+
+func (r *fakeRegistry) Deregister(serviceName string) error { return nil }
+
+func TestKeyPrefix(t *testing.T) {
+	if p := KeyPrefix("device-simple", ""); p != "config/device-simple" {
+		t.Errorf("unexpected prefix %s", p)
+	}
+	if p := KeyPrefix("device-simple", "docker"); p != "config/device-simple;docker" {
+		t.Errorf("unexpected prefix %s", p)
+	}
+}
+
+func TestLoadFromRegistry(t *testing.T) {
+	r := &fakeRegistry{values: map[string]string{
+		"Service/Port":             "50000",
+		"Service/Labels":           "a, b",
+		"Driver/MaxRetries":        "5",
+		"Clients/Data/Host":        "core-data",
+		"Writable/LogLevel":        "INFO",
+		"Throttle/CPUThreshold":    "80.5",
+		"AccessControl/Enabled":    "true",
+		"Clients/Metadata/Timeout": "1000",
+	}}
+	config := &common.Config{
+		Service: common.ServiceInfo{Host: "localhost", Port: 49990},
+		Driver:  map[string]string{"MaxRetries": "2", "RetryDelay": "100"},
+		Clients: map[string]common.ClientInfo{"Data": {Host: "localhost", Port: 48080}},
+	}
+
+	if err := loadFromRegistry(r, "config/test", config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Service.Port != 50000 || config.Service.Host != "localhost" || !reflect.DeepEqual(config.Service.Labels, []string{"a", "b"}) {
+		t.Errorf("unexpected Service %v", config.Service)
+	}
+	if config.Driver["MaxRetries"] != "5" || config.Driver["RetryDelay"] != "100" {
+		t.Errorf("unexpected Driver %v", config.Driver)
+	}
+	if c := config.Clients["Data"]; c.Host != "core-data" || c.Port != 48080 {
+		t.Errorf("unexpected Data client %v", c)
+	}
+	if config.Clients["Metadata"].Timeout != 1000 || config.Throttle.CPUThreshold != 80.5 || !config.AccessControl.Enabled || config.Writable.LogLevel != "INFO" {
+		t.Errorf("unexpected config %v", config)
+	}
+
+	// the missing settings are added to the registry
+	if r.values["Service/Host"] != "localhost" || r.values["Driver/RetryDelay"] != "100" || r.values["Clients/Data/Port"] != "48080" {
+		t.Errorf("unexpected registry %v", r.values)
+	}
+	if r.values["Service/Port"] != "50000" {
+		t.Errorf("registry setting overwritten")
+	}
+
+	r.values["Service/Port"] = "abc"
+	if err := loadFromRegistry(r, "config/test", config); err == nil {
+		t.Errorf("no error for an invalid setting")
+	}
+}
+
+func TestWatchWritable(t *testing.T) {
+	r := &fakeRegistry{values: map[string]string{"Writable/LogLevel": "INFO", "Service/Port": "49990"}}
+
+	var changes []common.WritableInfo
+	watchWritable(r, "config/test", common.WritableInfo{LogLevel: "INFO"}, nil, func(w common.WritableInfo) {
+		changes = append(changes, w)
+	})
+	if len(changes) != 0 {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+
+	r.PutKeyValues("config/test", map[string]string{"Service/Port": "50000"})
+	r.PutKeyValues("config/test", map[string]string{"Writable/LogLevel": "DEBUG", "Writable/ScheduleFrequencies/10sec-schedule": "PT30S"})
+	if len(changes) != 1 || changes[0].LogLevel != "DEBUG" || changes[0].ScheduleFrequencies["10sec-schedule"] != "PT30S" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
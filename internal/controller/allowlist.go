@@ -0,0 +1,70 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+)
+
+// parseAllowlist parses a list of CIDRs or single IP addresses.
+func parseAllowlist(entries []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Invalid allowlist entry %s: %v", entry, err))
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// allowFrom wraps the handler so that it only serves requests coming from
+// the given networks. It returns the handler as is if the list is empty.
+// The address of the peer is used, as forwarding headers can be spoofed.
+func allowFrom(nets []*net.IPNet, next http.HandlerFunc) http.HandlerFunc {
+	if len(nets) == 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			host = req.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		for _, n := range nets {
+			if ip != nil && n.Contains(ip) {
+				next(w, req)
+				return
+			}
+		}
+		common.LoggingClient.Warn(fmt.Sprintf("Request to %s from %s rejected, not in allowlist", req.URL.Path, req.RemoteAddr))
+		http.Error(w, i18n.T(i18n.Forbidden, req.URL.Path), http.StatusForbidden)
+	}
+}
+
+// callbackAllowlist returns the networks allowed to call the callback route.
+func callbackAllowlist() []*net.IPNet {
+	if common.CurrentConfig == nil {
+		return nil
+	}
+	return parseAllowlist(common.CurrentConfig.AccessControl.CallbackAllowlist)
+}
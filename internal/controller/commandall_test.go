@@ -0,0 +1,141 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+	logger "github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// feederDriver reads the Voltage of the feeders, failing for those at the
+// address "broken".
+type feederDriver struct{}
+
+func (feederDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+func (feederDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return nil
+}
+
+func (feederDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	if addr.Address == "broken" {
+		return nil, errors.New("no response")
+	}
+	cv, _ := ds_models.NewInt32Value(&reqs[0].RO, 0, 230)
+	return []*ds_models.CommandValue{cv}, nil
+}
+
+func (feederDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	return nil
+}
+
+func (feederDriver) Stop(force bool) error {
+	return nil
+}
+
+// eventClient passes the events pushed to Core Data to events.
+type eventClient struct {
+	coredata.EventClient
+	events chan *models.Event
+}
+
+func (c *eventClient) Add(event *models.Event) (string, error) {
+	c.events <- event
+	return "", nil
+}
+
+var cacheOnce sync.Once
+
+// initCache caches the feeders, feeder2 failing to respond, whose profile
+// has a Voltage command.
+func initCache(t *testing.T) {
+	cacheOnce.Do(func() {
+		dir, err := ioutil.TempDir("", "controller")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		profile := models.DeviceProfile{Name: "Feeder"}
+		profile.DeviceResources = []models.DeviceObject{{Name: "Voltage"}}
+		profile.Commands = []models.Command{{Name: "Voltage", Get: &models.Get{}}}
+		feeder := func(name string, address string) models.Device {
+			return models.Device{Id: bson.NewObjectId(), Name: name, Profile: profile, Labels: []string{"feeders"}, AdminState: models.Unlocked, OperatingState: models.Enabled, Addressable: models.Addressable{Address: address}}
+		}
+		devices := []models.Device{feeder("feeder1", "10.0.0.1"), feeder("feeder2", "broken")}
+		snap, _ := json.Marshal(cache.Snapshot{Devices: devices, Profiles: []models.DeviceProfile{profile}})
+		file := filepath.Join(dir, "cache.json")
+		if err = statedir.WriteFile(file, snap); err != nil {
+			t.Fatal(err)
+		}
+		if err = cache.InitCacheFromFile(file); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestCommandAllMultiStatus(t *testing.T) {
+	common.LoggingClient = logger.NewClient("commandall_test", false, "", "DEBUG")
+	previousDriver, previousClient := common.Driver, common.EventClient
+	defer func() {
+		common.CurrentConfig, common.Driver, common.EventClient = nil, previousDriver, previousClient
+	}()
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	common.Driver = feederDriver{}
+	client := &eventClient{events: make(chan *models.Event, 2)}
+	common.EventClient = client
+
+	initCache(t)
+
+	req := httptest.NewRequest(http.MethodGet, common.APIv1Prefix+"/device/all/Voltage?label=feeders", nil)
+	rr := httptest.NewRecorder()
+	InitRestRoutes().ServeHTTP(rr, req)
+	// the event of the successful read is pushed concurrently
+	select {
+	case <-client.events:
+	case <-time.After(5 * time.Second):
+		t.Error("Event of the successful read not pushed")
+	}
+
+	if rr.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusMultiStatus, rr.Code, rr.Body.String())
+	}
+	var results []handler.CommandResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Results %v", results)
+	}
+	ok, failed := results[0], results[1]
+	if ok.Device != "feeder1" || ok.Code != http.StatusOK || ok.Error != "" || ok.Event == nil || len(ok.Event.Readings) != 1 || ok.Event.Readings[0].Value != "230" {
+		t.Errorf("Result of the successful read %+v", ok)
+	}
+	if failed.Device != "feeder2" || failed.Code == http.StatusOK || failed.Error == "" || failed.Event != nil {
+		t.Errorf("Result of the failed read %+v", failed)
+	}
+}
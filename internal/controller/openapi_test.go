@@ -0,0 +1,96 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	logger "github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/gorilla/mux"
+)
+
+func TestOpenAPI(t *testing.T) {
+	common.LoggingClient = logger.NewClient("openapi_test", false, "", "DEBUG")
+	common.CurrentConfig = &common.Config{}
+	defer func() { common.CurrentConfig = nil }()
+	initCache(t)
+	r := InitRestRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, common.APIv1Prefix+"/openapi", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var doc handler.OpenAPI
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Document not parsed: %v", err)
+	}
+	if doc.OpenAPI == "" || len(doc.Servers) != 1 || !strings.HasSuffix(doc.Servers[0].URL, common.APIv1Prefix) {
+		t.Errorf("Unexpected document header %+v %+v", doc.OpenAPI, doc.Servers)
+	}
+
+	// every registered route is documented
+	r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || route.GetHandler() == nil {
+			return nil
+		}
+		path := routePattern.ReplaceAllString(strings.TrimPrefix(template, common.APIv1Prefix), "{$1}")
+		ops, ok := doc.Paths[path]
+		if !ok {
+			t.Errorf("Route %s not documented", path)
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		for _, m := range methods {
+			if ops[strings.ToLower(m)] == nil {
+				t.Errorf("Method %s of route %s not documented", m, path)
+			}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		path       string
+		method     string
+		parameters []string
+	}{
+		{"/device/name/{name}/history", "get", []string{"name"}},
+		{"/device/name/{name}/_sdk/history", "get", []string{"name"}},
+		{"/device/all/{command}", "put", []string{"command"}},
+		{"/trace/{bus}", "get", []string{"bus"}},
+		{"/ping", "get", nil},
+		{"/device/name/feeder1/Voltage", "get", nil},
+	}
+	for _, tt := range tests {
+		op := doc.Paths[tt.path][tt.method]
+		if op == nil {
+			t.Errorf("%s %s not documented", tt.method, tt.path)
+			continue
+		}
+		var parameters []string
+		for _, p := range op.Parameters {
+			if p.In != "path" || !p.Required {
+				t.Errorf("Parameter %+v of %s", p, tt.path)
+			}
+			parameters = append(parameters, p.Name)
+		}
+		if strings.Join(parameters, ",") != strings.Join(tt.parameters, ",") {
+			t.Errorf("Parameters %v of %s, expected %v", parameters, tt.path, tt.parameters)
+		}
+	}
+	if _, ok := doc.Paths["/device/name/{name}/_sdk"]; ok {
+		t.Error("Path prefix of a subrouter documented")
+	}
+}
@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+)
+
+const (
+	roleViewer   = "viewer"
+	roleOperator = "operator"
+	roleAdmin    = "admin"
+
+	bearerPrefix = "Bearer "
+)
+
+// roleRanks orders the roles, each role being granted the rights of the
+// roles of lower rank.
+var roleRanks = map[string]int{roleViewer: 1, roleOperator: 2, roleAdmin: 3}
+
+// accessControl maps the credentials of the clients to their roles.
+type accessControl struct {
+	tokens       map[string]string // key is bearer token
+	certificates map[string]string // key is client certificate common name
+}
+
+// newAccessControl returns the access control given by the configuration,
+// or nil if it is disabled.
+func newAccessControl() *accessControl {
+	if common.CurrentConfig == nil || !common.CurrentConfig.AccessControl.Enabled {
+		return nil
+	}
+
+	ac := &accessControl{tokens: make(map[string]string), certificates: make(map[string]string)}
+	for ref, role := range common.CurrentConfig.AccessControl.Tokens {
+		if _, ok := roleRanks[role]; !ok {
+			common.LoggingClient.Error(fmt.Sprintf("Invalid role %s for token %s", role, ref))
+			continue
+		}
+		token, err := common.LookupSecret(ref)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Couldn't look up token %s: %v", ref, err))
+			continue
+		}
+		ac.tokens[strings.TrimSpace(string(token))] = role
+	}
+	for cn, role := range common.CurrentConfig.AccessControl.Certificates {
+		if _, ok := roleRanks[role]; !ok {
+			common.LoggingClient.Error(fmt.Sprintf("Invalid role %s for certificate %s", role, cn))
+			continue
+		}
+		ac.certificates[cn] = role
+	}
+	return ac
+}
+
+// role returns the role of the client of the request, if it has presented
+// a known bearer token or client certificate (when served over TLS).
+func (ac *accessControl) role(req *http.Request) (string, bool) {
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, bearerPrefix) {
+		role, ok := ac.tokens[strings.TrimPrefix(auth, bearerPrefix)]
+		return role, ok
+	}
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		role, ok := ac.certificates[req.TLS.PeerCertificates[0].Subject.CommonName]
+		return role, ok
+	}
+	return "", false
+}
+
+// restrict wraps the handler so that it requires readRole for GET requests
+// and writeRole for any other method. It returns the handler as is if the
+// access control is disabled.
+func (ac *accessControl) restrict(next http.HandlerFunc, readRole string, writeRole string) http.HandlerFunc {
+	if ac == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		required := writeRole
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			required = readRole
+		}
+
+		role, ok := ac.role(req)
+		if !ok {
+			http.Error(w, i18n.T(i18n.Unauthorized, req.URL.Path), http.StatusUnauthorized)
+			return
+		}
+		if roleRanks[role] < roleRanks[required] {
+			common.LoggingClient.Warn(fmt.Sprintf("Role %s not allowed to %s %s", role, req.Method, req.URL.Path))
+			http.Error(w, i18n.T(i18n.Forbidden, req.URL.Path), http.StatusForbidden)
+			return
+		}
+		next(w, req)
+	}
+}
@@ -13,9 +13,12 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/gorilla/mux"
 )
@@ -24,6 +27,8 @@ const (
 	statusOK          string = "OK"
 	headerContentType string = "Content-Type"
 	contentTypeJson   string = "application/json"
+
+	contentTypeOctetStream string = "application/octet-stream"
 )
 
 func statusFunc(w http.ResponseWriter, req *http.Request) {
@@ -31,15 +36,80 @@ func statusFunc(w http.ResponseWriter, req *http.Request) {
 	io.WriteString(w, result)
 }
 
+func healthFunc(w http.ResponseWriter, req *http.Request) {
+	health := handler.HealthHandler()
+	w.Header().Set(headerContentType, contentTypeJson)
+	if !health.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(health)
+}
+
+func versionFunc(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(handler.VersionHandler())
+}
+
+func federationFunc(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(handler.FederationHandler())
+}
+
+func openAPIFunc(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(handler.OpenAPIHandler(common.HttpScheme+req.Host, apiRoutes))
+}
+
+func drainFunc(w http.ResponseWriter, req *http.Request) {
+	var status handler.DrainStatus
+	switch req.Method {
+	case http.MethodPut:
+		status = handler.DrainHandler()
+	case http.MethodDelete:
+		status = handler.ResumeHandler()
+	default:
+		status = handler.DrainStatusHandler()
+	}
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(status)
+}
+
+func standbyFunc(w http.ResponseWriter, req *http.Request) {
+	var status handler.StandbyStatus
+	switch req.Method {
+	case http.MethodPut:
+		status = handler.StandbyHandler(true)
+	case http.MethodDelete:
+		status = handler.StandbyHandler(false)
+	default:
+		status = handler.StandbyStatusHandler()
+	}
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(status)
+}
+
 func discoveryFunc(w http.ResponseWriter, req *http.Request) {
 	if checkServiceLocked(w, req) {
 		return
 	}
+	if req.Method == http.MethodGet {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(handler.DiscoveredDevicesHandler())
+		return
+	}
 
 	vars := mux.Vars(req)
-	go handler.DiscoveryHandler(vars)
-	io.WriteString(w, statusOK)
+	if appErr := handler.DiscoveryHandler(vars); appErr != nil {
+		http.Error(w, appErr.Message(), appErr.Code())
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
+	io.WriteString(w, statusOK)
+}
+
+func selfTestFunc(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(handler.SelfTestHandler())
 }
 
 func transformFunc(w http.ResponseWriter, req *http.Request) {
@@ -85,6 +155,7 @@ func commandFunc(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	vars := mux.Vars(req)
+	vars["cached"] = req.URL.Query().Get("cached")
 
 	body, ok := readBodyAsString(w, req)
 	if !ok {
@@ -94,13 +165,224 @@ func commandFunc(w http.ResponseWriter, req *http.Request) {
 	event, appErr := handler.CommandHandler(vars, body, req.Method)
 
 	if appErr != nil {
-		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+		commandError(w, req, appErr)
 	} else if event != nil {
 		w.Header().Set(headerContentType, contentTypeJson)
 		json.NewEncoder(w).Encode(event)
 	}
 }
 
+// errorResponse is the body of the errors of the commands failed by the
+// driver with a DriverError.
+type errorResponse struct {
+	Message     string `json:"message"`
+	Path        string `json:"path"`
+	Category    string `json:"category"`
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// commandError responds with the error of a command, as JSON for the
+// DriverErrors, with a Retry-After header for the rejected ones.
+func commandError(w http.ResponseWriter, req *http.Request, appErr common.AppError) {
+	if re, ok := appErr.(common.RetryableError); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(re.RetryAfter()))
+	}
+	de, ok := appErr.Error().(*ds_models.DriverError)
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJson)
+	w.WriteHeader(appErr.Code())
+	json.NewEncoder(w).Encode(errorResponse{
+		Message:     appErr.Message(),
+		Path:        req.URL.Path,
+		Category:    string(de.Category),
+		Code:        de.Code,
+		Description: de.Description,
+	})
+}
+
+func selectFunc(w http.ResponseWriter, req *http.Request) {
+	if checkServiceLocked(w, req) {
+		return
+	}
+	vars := mux.Vars(req)
+
+	appErr := handler.SelectHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		io.WriteString(w, statusOK)
+	}
+}
+
+func historyFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	records, appErr := handler.HistoryHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(records)
+	}
+}
+
+// historyOrCommandFunc serves the history of a Device at the path requested
+// for it, /device/name/{name}/history, unless the Device has a command named
+// history.
+func historyOrCommandFunc(w http.ResponseWriter, req *http.Request) {
+	if handler.HistoryShadowed(mux.Vars(req)) {
+		commandFunc(w, req)
+		return
+	}
+	historyFunc(w, req)
+}
+
+func clockOffsetFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	offset, appErr := handler.ClockOffsetHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(offset)
+	}
+}
+
+func deviceStatsFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	stats, appErr := handler.DeviceStatsHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(stats)
+	}
+}
+
+func opStateFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	report, appErr := handler.OpStateHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+func deviceCapturesFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	statuses, appErr := handler.DeviceCapturesHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(statuses)
+	}
+}
+
+func captureFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	status, appErr := handler.CaptureHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+func captureDataFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	data, appErr := handler.CaptureDataHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeOctetStream)
+		w.Write(data)
+	}
+}
+
+func deviceJobsFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	statuses, appErr := handler.DeviceJobsHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(statuses)
+	}
+}
+
+func adminStateFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	body, ok := readBodyAsString(w, req)
+	if !ok {
+		return
+	}
+
+	state, appErr := handler.AdminStateHandler(vars, body)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(state)
+	}
+}
+
+func decommissionFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	report, appErr := handler.DecommissionHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+func jobFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	status, appErr := handler.JobHandler(vars, req.Method)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+func traceFunc(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(headerContentType, contentTypeJson)
+	json.NewEncoder(w).Encode(handler.TraceHandler(req.Method))
+}
+
+func traceFramesFunc(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	frames, appErr := handler.TraceFramesHandler(vars)
+	if appErr != nil {
+		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+	} else {
+		w.Header().Set(headerContentType, contentTypeJson)
+		json.NewEncoder(w).Encode(frames)
+	}
+}
+
 func commandAllFunc(w http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
 	common.LoggingClient.Debug(fmt.Sprintf("Controller - Command: execute the Get command %s from all operational devices", vars["command"]))
@@ -114,10 +396,26 @@ func commandAllFunc(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	events, appErr := handler.CommandAllHandler(vars["command"], body, req.Method)
+	results, appErr := handler.CommandAllHandler(vars["command"], body, req.Method, req.URL.Query().Get("label"))
 	if appErr != nil {
-		http.Error(w, appErr.Message(), appErr.Code())
-	} else if len(events) > 0 {
+		commandError(w, req, appErr)
+		return
+	}
+
+	// partial failures are reported per Device, with a multi-status
+	events := make([]*models.Event, 0, len(results))
+	for _, r := range results {
+		if r.Error != "" {
+			w.Header().Set(headerContentType, contentTypeJson)
+			w.WriteHeader(http.StatusMultiStatus)
+			json.NewEncoder(w).Encode(results)
+			return
+		}
+		if r.Event != nil {
+			events = append(events, r.Event)
+		}
+	}
+	if len(events) > 0 {
 		w.Header().Set(headerContentType, contentTypeJson)
 		json.NewEncoder(w).Encode(events)
 	}
@@ -125,7 +423,7 @@ func commandAllFunc(w http.ResponseWriter, req *http.Request) {
 
 func checkServiceLocked(w http.ResponseWriter, req *http.Request) bool {
 	if common.ServiceLocked {
-		msg := fmt.Sprintf("%s is locked; %s %s", common.ServiceName, req.Method, req.URL)
+		msg := i18n.T(i18n.ServiceLocked, common.ServiceName, req.Method, req.URL)
 		common.LoggingClient.Error(msg)
 		http.Error(w, msg, http.StatusLocked) // status=423
 		return true
@@ -143,7 +441,7 @@ func readBodyAsString(w http.ResponseWriter, req *http.Request) (string, bool) {
 	}
 
 	if len(body) == 0 && req.Method == http.MethodPut {
-		msg := fmt.Sprintf("no request body provided; %s %s", req.Method, req.URL)
+		msg := i18n.T(i18n.NoRequestBody, req.Method, req.URL)
 		common.LoggingClient.Error(msg)
 		http.Error(w, msg, http.StatusBadRequest) // status=400
 		return "", false
@@ -13,6 +13,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
@@ -174,3 +175,107 @@ if body != expected {
 }
 */
 //}
+
+func TestAccessControl(t *testing.T) {
+	common.LoggingClient = logger.NewClient("rbac_test", false, "", "DEBUG")
+	os.Setenv("RBAC_TEST_VIEWER", "viewer-token")
+	defer os.Unsetenv("RBAC_TEST_VIEWER")
+	common.CurrentConfig = &common.Config{AccessControl: common.AccessControlInfo{
+		Enabled: true,
+		Tokens:  map[string]string{"env:RBAC_TEST_VIEWER": "viewer"},
+	}}
+	defer func() { common.CurrentConfig = nil }()
+
+	ac := newAccessControl()
+	next := func(w http.ResponseWriter, req *http.Request) {}
+	h := ac.restrict(next, roleViewer, roleOperator)
+
+	var tests = []struct {
+		name   string
+		method string
+		token  string
+		code   int
+	}{
+		{"No token", http.MethodGet, "", http.StatusUnauthorized},
+		{"Unknown token", http.MethodGet, "other", http.StatusUnauthorized},
+		{"Viewer read", http.MethodGet, "viewer-token", http.StatusOK},
+		{"Viewer write", http.MethodPut, "viewer-token", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, common.APIv1Prefix+"/device/all/"+testCmd, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+			h(rr, req)
+			if rr.Code != tt.code {
+				t.Errorf("%s: expected status %d but got: %d", tt.name, tt.code, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCallbackAllowlist(t *testing.T) {
+	common.LoggingClient = logger.NewClient("allowlist_test", false, "", "DEBUG")
+	next := func(w http.ResponseWriter, req *http.Request) {}
+	h := allowFrom(parseAllowlist([]string{"10.0.0.0/24", "192.168.1.5"}), next)
+
+	var tests = []struct {
+		remoteAddr string
+		code       int
+	}{
+		{"10.0.0.17:48000", http.StatusOK},
+		{"192.168.1.5:48000", http.StatusOK},
+		{"192.168.1.6:48000", http.StatusForbidden},
+		{"[::1]:48000", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, common.APICallbackRoute, nil)
+		req.RemoteAddr = tt.remoteAddr
+		rr := httptest.NewRecorder()
+		h(rr, req)
+		if rr.Code != tt.code {
+			t.Errorf("%s: expected status %d but got: %d", tt.remoteAddr, tt.code, rr.Code)
+		}
+	}
+}
+
+func TestDeviceRoutes(t *testing.T) {
+	common.LoggingClient = logger.NewClient("routes_test", false, "", "DEBUG")
+	common.CurrentConfig = &common.Config{}
+	defer func() { common.CurrentConfig = nil }()
+	r := InitRestRoutes()
+
+	var tests = []struct {
+		method   string
+		path     string
+		template string
+	}{
+		{http.MethodGet, "/device/name/meter/history", "/device/name/{name}/history"},
+		{http.MethodPut, "/device/name/meter/history", "/device/name/{name}/{command}"},
+		{http.MethodGet, "/device/name/meter/_sdk/history", "/device/name/{name}/_sdk/history"},
+		{http.MethodGet, "/device/name/meter/_sdk/jobs", "/device/name/{name}/_sdk/jobs"},
+		{http.MethodGet, "/device/name/meter/_sdk/captures", "/device/name/{name}/_sdk/captures"},
+		{http.MethodGet, "/device/name/meter/_sdk/clockoffset", "/device/name/{name}/_sdk/clockoffset"},
+		{http.MethodGet, "/device/name/meter/_sdk/opstate", "/device/name/{name}/_sdk/opstate"},
+		{http.MethodGet, "/device/name/meter/_sdk/stats", "/device/name/{name}/_sdk/stats"},
+		{http.MethodPut, "/device/name/meter/_sdk/adminstate", "/device/name/{name}/_sdk/adminstate"},
+		{http.MethodPut, "/device/name/meter/select", "/device/name/{name}/{command}"},
+		{http.MethodPut, "/device/name/meter/_sdk/select/Breaker", "/device/name/{name}/_sdk/select/{command}"},
+		{http.MethodPut, "/device/5b9a4f9a64562a2f966fdb0b/_sdk/select/Breaker", "/device/{id}/_sdk/select/{command}"},
+		{http.MethodGet, "/device/all/Voltage", "/device/all/{command}"},
+		{http.MethodGet, "/device/5b9a4f9a64562a2f966fdb0b/Voltage", "/device/{id}/{command}"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, common.APIv1Prefix+tt.path, nil)
+		var match mux.RouteMatch
+		if !r.Match(req, &match) {
+			t.Errorf("%s %s not routed", tt.method, tt.path)
+			continue
+		}
+		if template, _ := match.Route.GetPathTemplate(); template != common.APIv1Prefix+tt.template {
+			t.Errorf("%s %s routed to %s", tt.method, tt.path, template)
+		}
+	}
+}
@@ -9,29 +9,95 @@ package controller
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	"github.com/gorilla/mux"
 )
 
 func InitRestRoutes() *mux.Router {
-	r := mux.NewRouter().PathPrefix(common.APIv1Prefix).Subrouter()
+	r := mux.NewRouter().PathPrefix(common.APIRoute(common.APIv1Prefix)).Subrouter()
+
+	// ping and callback aren't subject to roles, as used by the registry and
+	// Core Metadata, the latter being restricted by the callback allowlist
+	ac := newAccessControl()
 
 	common.LoggingClient.Debug("init status rest controller")
 	r.HandleFunc("/ping", statusFunc)
+	r.HandleFunc("/health", ac.restrict(healthFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	r.HandleFunc("/version", ac.restrict(versionFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	r.HandleFunc("/openapi", ac.restrict(openAPIFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	r.HandleFunc("/federation", ac.restrict(federationFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	r.HandleFunc("/drain", ac.restrict(drainFunc, roleViewer, roleAdmin)).Methods(http.MethodGet, http.MethodPut, http.MethodDelete)
+	r.HandleFunc("/standby", ac.restrict(standbyFunc, roleViewer, roleAdmin)).Methods(http.MethodGet, http.MethodPut, http.MethodDelete)
 
 	common.LoggingClient.Debug("init command rest controller")
 	sr := r.PathPrefix("/device").Subrouter()
-	sr.HandleFunc("/{id}/{command}", commandFunc).Methods(http.MethodGet, http.MethodPut)
-	sr.HandleFunc("/name/{name}/{command}", commandFunc).Methods(http.MethodGet, http.MethodPut)
-	sr.HandleFunc("/all/{command}", commandAllFunc).Methods(http.MethodGet, http.MethodPut)
+	// the resources of the SDK about a Device are under _sdk, so that they
+	// can't shadow the commands of the Device
+	sr.HandleFunc("/{id}/_sdk/select/{command}", ac.restrict(selectFunc, roleOperator, roleOperator)).Methods(http.MethodPut)
+	ds := sr.PathPrefix("/name/{name}/_sdk").Subrouter()
+	ds.HandleFunc("/select/{command}", ac.restrict(selectFunc, roleOperator, roleOperator)).Methods(http.MethodPut)
+	ds.HandleFunc("/history", ac.restrict(historyFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/jobs", ac.restrict(deviceJobsFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/captures", ac.restrict(deviceCapturesFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/clockoffset", ac.restrict(clockOffsetFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/opstate", ac.restrict(opStateFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/stats", ac.restrict(deviceStatsFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	ds.HandleFunc("/adminstate", ac.restrict(adminStateFunc, roleOperator, roleOperator)).Methods(http.MethodPut)
+	sr.HandleFunc("/name/{name}/history", ac.restrict(historyOrCommandFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	sr.HandleFunc("/name/{name}/decommission", ac.restrict(decommissionFunc, roleAdmin, roleAdmin)).Methods(http.MethodPost)
+	// all isn't a Device id, its route must come first
+	sr.HandleFunc("/all/{command}", ac.restrict(commandAllFunc, roleViewer, roleOperator)).Methods(http.MethodGet, http.MethodPut)
+	sr.HandleFunc("/{id}/{command}", ac.restrict(commandFunc, roleViewer, roleOperator)).Methods(http.MethodGet, http.MethodPut)
+	sr.HandleFunc("/name/{name}/{command}", ac.restrict(commandFunc, roleViewer, roleOperator)).Methods(http.MethodGet, http.MethodPut)
+
+	common.LoggingClient.Debug("init capture rest controller")
+	r.HandleFunc("/capture/{captureid}", ac.restrict(captureFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+	r.HandleFunc("/capture/{captureid}/data", ac.restrict(captureDataFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
+
+	common.LoggingClient.Debug("init job rest controller")
+	r.HandleFunc("/job/{jobid}", ac.restrict(jobFunc, roleViewer, roleOperator)).Methods(http.MethodGet, http.MethodDelete)
+
+	common.LoggingClient.Debug("init trace rest controller")
+	r.HandleFunc("/trace", ac.restrict(traceFunc, roleViewer, roleAdmin)).Methods(http.MethodGet, http.MethodPut, http.MethodDelete)
+	// bus names are serial ports or TCP peers, which may contain slashes
+	r.HandleFunc("/trace/{bus:.+}", ac.restrict(traceFramesFunc, roleViewer, roleViewer)).Methods(http.MethodGet)
 
 	common.LoggingClient.Debug("init callback rest controller")
-	r.HandleFunc("/callback", callbackFunc)
+	r.HandleFunc("/callback", allowFrom(callbackAllowlist(), callbackFunc))
 
 	common.LoggingClient.Debug("init other rest controller")
-	r.HandleFunc("/discovery", discoveryFunc).Methods("POST")
-	r.HandleFunc("/debug/transformData/{transformData}", transformFunc).Methods("GET")
+	r.HandleFunc("/discovery", ac.restrict(discoveryFunc, roleViewer, roleOperator)).Methods(http.MethodGet, http.MethodPost)
+	r.HandleFunc("/selftest", ac.restrict(selfTestFunc, roleOperator, roleOperator)).Methods(http.MethodPost)
+	r.HandleFunc("/debug/transformData/{transformData}", ac.restrict(transformFunc, roleAdmin, roleAdmin)).Methods("GET")
 
+	apiRoutes = openAPIRoutes(r, common.APIRoute(common.APIv1Prefix))
 	return r
 }
+
+// apiRoutes are the routes of the REST API, documented by the OpenAPI
+// document.
+var apiRoutes []handler.OpenAPIRoute
+
+// routePattern matches the regular expression of a path variable.
+var routePattern = regexp.MustCompile(`\{(\w+):[^}]*\}`)
+
+// openAPIRoutes returns the routes of a router, relative to its prefix.
+func openAPIRoutes(r *mux.Router, prefix string) []handler.OpenAPIRoute {
+	var routes []handler.OpenAPIRoute
+	r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		// the path prefixes of the subrouters have no handler
+		if err != nil || route.GetHandler() == nil {
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		path := routePattern.ReplaceAllString(strings.TrimPrefix(template, prefix), "{$1}")
+		routes = append(routes, handler.OpenAPIRoute{Path: path, Methods: methods})
+		return nil
+	})
+	return routes
+}
@@ -0,0 +1,231 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deadletter buffers the events which couldn't be pushed to Core
+// Data after all the retries of the publication, and replays them, oldest
+// first, once Core Data is back. The buffer is bounded, the oldest events
+// being dropped, and persisted in the state directory. The readings expired
+// by the max age policy are discarded before each replay.
+package deadletter
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Stats counts the dead letters.
+type Stats struct {
+	// Buffered is the number of events waiting to be replayed.
+	Buffered int    `json:"buffered"`
+	Replayed uint64 `json:"replayed"`
+	// Dropped is the number of events dropped as the buffer was full.
+	Dropped uint64 `json:"dropped"`
+	// Expired is the number of readings discarded as older than their max
+	// age.
+	Expired uint64 `json:"expired"`
+}
+
+var (
+	mutex  sync.Mutex
+	file   string
+	size   int
+	events []*models.Event
+	stats  Stats
+	done   chan struct{}
+	expiry Expiry
+)
+
+// deadLetterSchema versions the format of the buffer file.
+var deadLetterSchema = statedir.Schema{Version: 1, Migrations: []statedir.Migration{statedir.Unversioned}}
+
+// Expiry tells if a buffered reading of an event is expired, to be
+// discarded rather than replayed.
+type Expiry func(event *models.Event, reading models.Reading, now time.Time) bool
+
+// SetExpiry sets the max age policy of the buffered readings. A nil expiry
+// keeps them indefinitely.
+func SetExpiry(e Expiry) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	expiry = e
+}
+
+// Init loads the buffer persisted in the named file, relative to the state
+// directory, keeping up to max events. An empty name keeps the events in
+// memory only, and a zero max disables the buffer.
+func Init(name string, max int) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	file = name
+	size = max
+	events = nil
+	if file == "" || size <= 0 {
+		return nil
+	}
+	data, err := statedir.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = deadLetterSchema.Unmarshal(data, &events); err != nil {
+		return err
+	}
+	trim()
+	return nil
+}
+
+// Add buffers an event, dropping the oldest one if the buffer is full. It
+// returns false if the buffer is disabled.
+func Add(event *models.Event) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if size <= 0 {
+		return false
+	}
+	events = append(events, event)
+	trim()
+	persist()
+	return true
+}
+
+// trim drops the oldest events beyond the size of the buffer.
+func trim() {
+	if n := len(events) - size; n > 0 {
+		stats.Dropped += uint64(n)
+		events = append([]*models.Event(nil), events[n:]...)
+	}
+}
+
+// expire discards the expired readings, and the events left without
+// readings. The events are replaced rather than modified, as the replays
+// don't lock the buffer.
+func expire(now time.Time) {
+	if expiry == nil {
+		return
+	}
+	kept := make([]*models.Event, 0, len(events))
+	changed := false
+	for _, e := range events {
+		readings := make([]models.Reading, 0, len(e.Readings))
+		for _, r := range e.Readings {
+			if !expiry(e, r, now) {
+				readings = append(readings, r)
+			}
+		}
+		if n := len(e.Readings) - len(readings); n > 0 {
+			stats.Expired += uint64(n)
+			changed = true
+			if len(readings) == 0 {
+				continue
+			}
+			copied := *e
+			copied.Readings = readings
+			e = &copied
+		}
+		kept = append(kept, e)
+	}
+	events = kept
+	if changed {
+		persist()
+	}
+}
+
+// persist saves the buffer, if a file is set, failures only costing the
+// events on a restart.
+func persist() error {
+	if file == "" {
+		return nil
+	}
+	data, err := deadLetterSchema.Marshal(events)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(file, data)
+}
+
+// CurrentStats returns the counts of the dead letters.
+func CurrentStats() Stats {
+	mutex.Lock()
+	defer mutex.Unlock()
+	s := stats
+	s.Buffered = len(events)
+	return s
+}
+
+// Replay sends the buffered events, oldest first, until send fails, and
+// returns the number of events sent. The buffer isn't locked while sending.
+func Replay(send func(*models.Event) error) int {
+	mutex.Lock()
+	expire(time.Now())
+	pending := append([]*models.Event(nil), events...)
+	mutex.Unlock()
+
+	sent := make(map[*models.Event]bool)
+	for _, e := range pending {
+		if send(e) != nil {
+			break
+		}
+		sent[e] = true
+	}
+	if len(sent) == 0 {
+		return 0
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	kept := make([]*models.Event, 0, len(events))
+	for _, e := range events {
+		if !sent[e] {
+			kept = append(kept, e)
+		}
+	}
+	events = kept
+	stats.Replayed += uint64(len(sent))
+	persist()
+	return len(sent)
+}
+
+// Start replays the buffered events periodically.
+func Start(interval time.Duration, send func(*models.Event) error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil || interval <= 0 {
+		return
+	}
+	done = make(chan struct{})
+	go run(done, interval, send)
+}
+
+// Stop stops replaying the buffered events.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		close(done)
+		done = nil
+	}
+}
+
+func run(done <-chan struct{}, interval time.Duration, send func(*models.Event) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			Replay(send)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestDeadLetters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	statedir.Init(dir)
+	defer statedir.Init("")
+
+	if err = Init("deadletters.json", 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{"meter1", "meter2", "meter3"} {
+		if !Add(&models.Event{Device: d}) {
+			t.Fatalf("Event of %s not buffered", d)
+		}
+	}
+	if s := CurrentStats(); s.Buffered != 2 || s.Dropped != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+
+	// the buffer survives a restart
+	if err = Init("deadletters.json", 2); err != nil {
+		t.Fatal(err)
+	}
+	var sent []string
+	failing := func(e *models.Event) error {
+		if len(sent) == 1 {
+			return errors.New("Core Data unreachable")
+		}
+		sent = append(sent, e.Device)
+		return nil
+	}
+	if n := Replay(failing); n != 1 || sent[0] != "meter2" {
+		t.Errorf("Unexpected replay of %d events: %v", n, sent)
+	}
+	if s := CurrentStats(); s.Buffered != 1 || s.Replayed != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+
+	sent = nil
+	Replay(func(e *models.Event) error {
+		sent = append(sent, e.Device)
+		return nil
+	})
+	if len(sent) != 1 || sent[0] != "meter3" || CurrentStats().Buffered != 0 {
+		t.Errorf("Unexpected replay %v", sent)
+	}
+
+	// a disabled buffer refuses the events
+	Init("", 0)
+	if Add(&models.Event{Device: "meter1"}) {
+		t.Errorf("Event buffered while disabled")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	Init("", 10)
+	defer SetExpiry(nil)
+	SetExpiry(func(e *models.Event, r models.Reading, now time.Time) bool {
+		return r.Name == "Diagnostics" && now.Sub(time.Unix(0, r.Origin*int64(time.Millisecond))) > time.Hour
+	})
+
+	old := time.Now().Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)
+	Add(&models.Event{Device: "meter1", Readings: []models.Reading{{Name: "Diagnostics", Origin: old}}})
+	Add(&models.Event{Device: "meter2", Readings: []models.Reading{{Name: "Diagnostics", Origin: old}, {Name: "Energy", Origin: old}}})
+
+	var sent []*models.Event
+	Replay(func(e *models.Event) error {
+		sent = append(sent, e)
+		return nil
+	})
+	if len(sent) != 1 || sent[0].Device != "meter2" || len(sent[0].Readings) != 1 || sent[0].Readings[0].Name != "Energy" {
+		t.Errorf("Unexpected replay %+v", sent)
+	}
+	if s := CurrentStats(); s.Expired != 2 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
@@ -0,0 +1,195 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dedup suppresses duplicate events, e.g. the readings buffered by a
+// device and replayed by its driver after a reconnect, which would otherwise
+// be counted twice by Core Data consumers. Events are identified by their
+// Device and the origins and values of their readings, and the identifiers
+// are persisted in a journal before the events are sent.
+package dedup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultSize is the number of events remembered, unless set by Init.
+const DefaultSize = 10000
+
+// removedPrefix marks a journal line removing an identifier.
+const removedPrefix = "-"
+
+var (
+	mutex   sync.Mutex
+	ids     map[string]bool
+	order   []string
+	next    int
+	path    string
+	journal *os.File
+	written int
+)
+
+// Init loads the journal with the given name, relative to the state
+// directory, remembering up to size events. An empty name disables the
+// suppression of duplicates.
+func Init(file string, size int) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	closeJournal()
+	ids = nil
+	if file == "" {
+		return nil
+	}
+	if size <= 0 {
+		size = DefaultSize
+	}
+	ids = make(map[string]bool)
+	order = make([]string, size)
+	next = 0
+	path = statedir.Path(file)
+
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, removedPrefix) {
+				delete(ids, strings.TrimPrefix(line, removedPrefix))
+			} else if line != "" {
+				remember(line)
+			}
+		}
+		f.Close()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return compact()
+}
+
+// remember adds an identifier, forgetting the oldest one if full.
+func remember(id string) {
+	if old := order[next]; old != "" {
+		delete(ids, old)
+	}
+	order[next] = id
+	next = (next + 1) % len(order)
+	ids[id] = true
+}
+
+// compact rewrites the journal with the remembered identifiers.
+func compact() error {
+	closeJournal()
+	var b strings.Builder
+	for i := range order {
+		if id := order[(next+i)%len(order)]; id != "" && ids[id] {
+			b.WriteString(id + "\n")
+		}
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	journal = f
+	written = 0
+	return nil
+}
+
+func closeJournal() {
+	if journal != nil {
+		journal.Close()
+		journal = nil
+	}
+}
+
+// appendLine appends a line to the journal, and compacts it once it holds
+// twice the remembered identifiers.
+func appendLine(line string) error {
+	if journal == nil {
+		return fmt.Errorf("journal %s not open", path)
+	}
+	if _, err := journal.WriteString(line + "\n"); err != nil {
+		return err
+	}
+	if err := journal.Sync(); err != nil {
+		return err
+	}
+	written++
+	if written > len(order) {
+		return compact()
+	}
+	return nil
+}
+
+// ID returns the identifier of an event, or an empty string if the event
+// can't be identified, i.e. if some reading has no origin.
+func ID(event *models.Event) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", event.Device)
+	for _, r := range event.Readings {
+		if r.Origin == 0 {
+			return ""
+		}
+		fmt.Fprintf(h, "%s\n%d\n%s\n", r.Name, r.Origin, r.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Mark records an event about to be sent, returning false if it's a
+// duplicate which shouldn't be sent. It always returns true if disabled.
+func Mark(event *models.Event) bool {
+	id := ID(event)
+	if id == "" {
+		return true
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if ids == nil {
+		return true
+	}
+	if ids[id] {
+		return false
+	}
+	remember(id)
+	// a journal failure only weakens the suppression after a restart
+	appendLine(id)
+	return true
+}
+
+// Unmark forgets an event which couldn't be sent, so that it's sent again
+// if replayed.
+func Unmark(event *models.Event) {
+	id := ID(event)
+	if id == "" {
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if ids == nil || !ids[id] {
+		return
+	}
+	delete(ids, id)
+	appendLine(removedPrefix + id)
+}
@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dedup
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func event(origin int64, value string) *models.Event {
+	return &models.Event{Device: "meter", Readings: []models.Reading{{Name: "Energy", Origin: origin, Value: value}}}
+}
+
+func TestMark(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "dedup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	if err = statedir.Init(tmp); err != nil {
+		t.Fatal(err)
+	}
+	defer statedir.Init("")
+
+	if err = Init("events.journal", 2); err != nil {
+		t.Fatal(err)
+	}
+	defer Init("", 0)
+
+	if !Mark(event(1, "10")) || !Mark(event(2, "11")) {
+		t.Fatal("New events suppressed")
+	}
+	if Mark(event(1, "10")) {
+		t.Error("Duplicate event not suppressed")
+	}
+	if !Mark(event(0, "10")) || !Mark(event(0, "10")) {
+		t.Error("Events without origin suppressed")
+	}
+
+	Unmark(event(2, "11"))
+	if err = Init("events.journal", 2); err != nil {
+		t.Fatal(err)
+	}
+	if Mark(event(1, "10")) {
+		t.Error("Duplicate event not suppressed after reload")
+	}
+	if !Mark(event(2, "11")) {
+		t.Error("Unmarked event suppressed after reload")
+	}
+
+	// the oldest event is forgotten
+	Mark(event(3, "12"))
+	if !Mark(event(1, "10")) {
+		t.Error("Oldest event still remembered")
+	}
+}
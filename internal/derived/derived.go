@@ -0,0 +1,236 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package derived computes statistics of the numeric readings of a device
+// resource, as selected by its Statistics attribute, e.g. "RateOfChange,Max".
+// Each statistic is pushed as an additional (virtual) reading named after
+// the reading, e.g. "Power_Max". The minimum, maximum and average are rolling
+// values since local midnight; the rate of change is per minute. The energy is
+// the trapezoidal integration of the readings over time (in hours), e.g. in Wh
+// for a power read in W, and persists across restarts.
+package derived
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Statistics, as named in the Statistics attribute.
+const (
+	RateOfChange = "RateOfChange"
+	Min          = "Min"
+	Max          = "Max"
+	Avg          = "Avg"
+	Energy       = "Energy"
+)
+
+var known = []string{RateOfChange, Min, Max, Avg, Energy}
+
+type series struct {
+	day      int // yyyymmdd, in local time
+	count    int
+	min, max float64
+	sum      float64
+	last     float64
+	lastTime int64
+	hasLast  bool
+	energy   float64
+}
+
+var (
+	mutex sync.Mutex
+	all   = make(map[string]map[string]*series) // keys are Device and reading names
+)
+
+// Statistics returns the statistics selected for a device resource.
+func Statistics(do models.DeviceObject) []string {
+	v, ok := common.DeviceObjectAttribute(do, common.AttrStatistics)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		for _, k := range known {
+			if strings.EqualFold(s, k) {
+				result = append(result, k)
+			}
+		}
+	}
+	return result
+}
+
+// ReadingName returns the name of the reading of a statistic.
+func ReadingName(name string, statistic string) string {
+	return name + "_" + statistic
+}
+
+// Readings updates the statistics of a reading and returns the readings of
+// the statistics selected for its device resource. Readings of non-numeric
+// values are ignored.
+func Readings(do models.DeviceObject, cv *ds_models.CommandValue, reading *models.Reading) []models.Reading {
+	stats := Statistics(do)
+	if len(stats) == 0 {
+		return nil
+	}
+	value, ok := numericValue(cv)
+	if !ok {
+		return nil
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	s := seriesFor(reading.Device, reading.Name)
+	rate, rated := s.add(value, reading.Origin, maxGap(do))
+	result := make([]models.Reading, 0, len(stats))
+	dirty := false
+	for _, stat := range stats {
+		var v float64
+		switch stat {
+		case RateOfChange:
+			if !rated {
+				continue
+			}
+			v = rate
+		case Min:
+			v = s.min
+		case Max:
+			v = s.max
+		case Avg:
+			v = s.sum / float64(s.count)
+		case Energy:
+			v = s.energy
+			dirty = true
+		}
+		statCV, err := ds_models.NewFloat64Value(cv.RO, reading.Origin, v)
+		if err != nil {
+			continue
+		}
+		r := models.Reading{Name: ReadingName(reading.Name, stat), Device: reading.Device, Origin: reading.Origin}
+		r.Value = statCV.ValueToString()
+		result = append(result, r)
+	}
+	if dirty {
+		scheduleSave()
+	}
+	return result
+}
+
+// Remove discards the statistics of the named Device.
+func Remove(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(all, deviceName)
+}
+
+func seriesFor(deviceName string, name string) *series {
+	readings, ok := all[deviceName]
+	if !ok {
+		readings = make(map[string]*series)
+		all[deviceName] = readings
+	}
+	s, ok := readings[name]
+	if !ok {
+		s = &series{}
+		readings[name] = s
+	}
+	return s
+}
+
+// add adds a value read at origin (in milliseconds) to the series, and
+// returns its rate of change per minute since the previous value, if any.
+// The energy isn't integrated over intervals longer than maxGap.
+func (s *series) add(value float64, origin int64, maxGap time.Duration) (float64, bool) {
+	t := time.Unix(0, origin*int64(time.Millisecond))
+	day := t.Year()*10000 + int(t.Month())*100 + t.Day()
+	if day != s.day || s.count == 0 {
+		s.day = day
+		s.count = 0
+		s.min, s.max, s.sum = value, value, 0
+	}
+	s.count++
+	s.sum += value
+	if value < s.min {
+		s.min = value
+	}
+	if value > s.max {
+		s.max = value
+	}
+
+	var rate float64
+	rated := s.hasLast && origin > s.lastTime
+	if rated {
+		elapsed := time.Duration(origin-s.lastTime) * time.Millisecond
+		rate = (value - s.last) / elapsed.Minutes()
+		if elapsed <= maxGap {
+			s.energy += (s.last + value) / 2 * elapsed.Hours()
+		}
+	}
+	s.last, s.lastTime, s.hasLast = value, origin, true
+	return rate, rated
+}
+
+func numericValue(cv *ds_models.CommandValue) (float64, bool) {
+	var v interface{}
+	var err error
+	switch cv.Type {
+	case ds_models.Uint8:
+		v, err = cv.Uint8Value()
+	case ds_models.Uint16:
+		v, err = cv.Uint16Value()
+	case ds_models.Uint32:
+		v, err = cv.Uint32Value()
+	case ds_models.Uint64:
+		v, err = cv.Uint64Value()
+	case ds_models.Int8:
+		v, err = cv.Int8Value()
+	case ds_models.Int16:
+		v, err = cv.Int16Value()
+	case ds_models.Int32:
+		v, err = cv.Int32Value()
+	case ds_models.Int64:
+		v, err = cv.Int64Value()
+	case ds_models.Float32:
+		v, err = cv.Float32Value()
+	case ds_models.Float64:
+		v, err = cv.Float64Value()
+	default:
+		return 0, false
+	}
+	if err != nil {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
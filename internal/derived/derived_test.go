@@ -0,0 +1,120 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derived
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var ro = &models.ResourceOperation{Object: "Power", Parameter: "Power"}
+
+func float64String(v float64) string {
+	cv, _ := ds_models.NewFloat64Value(ro, 0, v)
+	return cv.ValueToString()
+}
+
+func TestReadings(t *testing.T) {
+	defer Remove("meter")
+
+	do := models.DeviceObject{Name: "Power", Attributes: map[string]interface{}{"Statistics": "RateOfChange, Min,max,Avg"}}
+	midnight := time.Date(2018, 10, 15, 0, 0, 0, 0, time.Local)
+	origin := midnight.Add(8*time.Hour).UnixNano() / int64(time.Millisecond)
+
+	read := func(value int32, origin int64) map[string]string {
+		cv, _ := ds_models.NewInt32Value(ro, origin, value)
+		reading := &models.Reading{Name: "Power", Device: "meter", Value: cv.ValueToString(), Origin: origin}
+		result := make(map[string]string)
+		for _, r := range Readings(do, cv, reading) {
+			result[r.Name] = r.Value
+		}
+		return result
+	}
+
+	rs := read(10, origin)
+	if _, ok := rs["Power_RateOfChange"]; ok || rs["Power_Min"] != float64String(10) || len(rs) != 3 {
+		t.Errorf("Unexpected first readings %v", rs)
+	}
+
+	rs = read(40, origin+30000) // 30 s later
+	expected := map[string]float64{"Power_RateOfChange": 60, "Power_Min": 10, "Power_Max": 40, "Power_Avg": 25}
+	for name, value := range expected {
+		if rs[name] != float64String(value) {
+			t.Errorf("%s: expected %v, got %s", name, value, rs[name])
+		}
+	}
+
+	// the rolling values are reset at midnight
+	nextDay := midnight.Add(24*time.Hour).UnixNano() / int64(time.Millisecond)
+	rs = read(5, nextDay)
+	if rs["Power_Max"] != float64String(5) || rs["Power_Avg"] != float64String(5) {
+		t.Errorf("Statistics not reset at midnight: %v", rs)
+	}
+}
+
+func TestNonNumeric(t *testing.T) {
+	do := models.DeviceObject{Name: "Status", Attributes: map[string]interface{}{"Statistics": "Max"}}
+	cv := ds_models.NewStringValue(ro, 0, "ok")
+	if rs := Readings(do, cv, &models.Reading{Name: "Status", Device: "meter", Value: "ok"}); len(rs) != 0 {
+		t.Errorf("Statistics of String reading: %v", rs)
+	}
+}
+
+func TestEnergy(t *testing.T) {
+	defer Remove("meter")
+
+	tmp, err := ioutil.TempDir("", "derived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	file := filepath.Join(tmp, "energy.json")
+	if err = Init(file); err != nil {
+		t.Fatal(err)
+	}
+	defer Init("")
+
+	do := models.DeviceObject{Name: "Power", Attributes: map[string]interface{}{"Statistics": "Energy", "IntegrationMaxGap": "3600"}}
+	origin := time.Date(2018, 10, 15, 8, 0, 0, 0, time.Local).UnixNano() / int64(time.Millisecond)
+	read := func(value int32, origin int64) string {
+		cv, _ := ds_models.NewInt32Value(ro, origin, value)
+		reading := &models.Reading{Name: "Power", Device: "meter", Value: cv.ValueToString(), Origin: origin}
+		rs := Readings(do, cv, reading)
+		if len(rs) != 1 || rs[0].Name != "Power_Energy" {
+			t.Fatalf("Unexpected readings %v", rs)
+		}
+		return rs[0].Value
+	}
+
+	hour := int64(time.Hour / time.Millisecond)
+	read(1000, origin)
+	if e := read(2000, origin+hour/2); e != float64String(750) {
+		t.Errorf("Unexpected energy %s after half an hour", e)
+	}
+	// the interval exceeds the maximum gap, so isn't integrated
+	if e := read(2000, origin+3*hour); e != float64String(750) {
+		t.Errorf("Energy integrated over gap: %s", e)
+	}
+
+	// the energy persists across restarts
+	if err = Save(); err != nil {
+		t.Fatal(err)
+	}
+	Remove("meter")
+	if err = Init(file); err != nil {
+		t.Fatal(err)
+	}
+	if e := read(2000, origin+4*hour); e != float64String(2750) {
+		t.Errorf("Unexpected energy %s after restart", e)
+	}
+}
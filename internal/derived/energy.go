@@ -0,0 +1,132 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derived
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	defaultMaxGap = 15 * time.Minute
+	saveInterval  = time.Minute
+)
+
+// accumulator is the persisted state of the energy integration of a reading.
+type accumulator struct {
+	Energy   float64 `json:"energy"`
+	Last     float64 `json:"last"`
+	LastTime int64   `json:"lastTime"`
+}
+
+var (
+	path     string
+	lastSave time.Time
+	saving   bool
+)
+
+// energySchema versions the format of the energy file.
+var energySchema = statedir.Schema{Version: 1, Migrations: []statedir.Migration{statedir.Unversioned}}
+
+// Init sets the file the accumulated energies are persisted to, and loads
+// them if the file exists.
+func Init(file string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	path = file
+	if path == "" {
+		return nil
+	}
+	contents, err := statedir.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := make(map[string]map[string]accumulator)
+	if err = energySchema.Unmarshal(contents, &loaded); err != nil {
+		return err
+	}
+	for deviceName, readings := range loaded {
+		for name, acc := range readings {
+			s := seriesFor(deviceName, name)
+			s.energy = acc.Energy
+			s.last, s.lastTime, s.hasLast = acc.Last, acc.LastTime, true
+		}
+	}
+	return nil
+}
+
+// Save writes the accumulated energies to the configured file, if any.
+func Save() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return save()
+}
+
+func save() error {
+	saving = false
+	lastSave = time.Now()
+	if path == "" {
+		return nil
+	}
+
+	accs := make(map[string]map[string]accumulator)
+	for deviceName, readings := range all {
+		for name, s := range readings {
+			if s.energy == 0 {
+				continue
+			}
+			if accs[deviceName] == nil {
+				accs[deviceName] = make(map[string]accumulator)
+			}
+			accs[deviceName][name] = accumulator{Energy: s.energy, Last: s.last, LastTime: s.lastTime}
+		}
+	}
+	contents, err := energySchema.Marshal(accs)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(path, contents)
+}
+
+// scheduleSave saves the accumulated energies in background, at most once
+// per saveInterval. It must be called with the mutex held.
+func scheduleSave() {
+	if path == "" || saving {
+		return
+	}
+	saving = true
+	go func() {
+		if wait := saveInterval - time.Since(lastSave); wait > 0 {
+			time.Sleep(wait)
+		}
+		if err := Save(); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Couldn't save the accumulated energies: %v", err))
+		}
+	}()
+}
+
+// maxGap returns the longest interval between two readings the energy is
+// integrated over, as given by the IntegrationMaxGap attribute (in seconds).
+func maxGap(do models.DeviceObject) time.Duration {
+	if v, ok := common.DeviceObjectAttribute(do, common.AttrIntegrationMaxGap); ok {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultMaxGap
+}
@@ -0,0 +1,247 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package discovery runs the discovery of the driver, on demand or
+// periodically, and provisions the Devices it finds which match the
+// provision watchers of the configuration.
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// resultsSize is the number of batches of discovered Devices queued before
+// the driver blocks.
+const resultsSize = 16
+
+// ErrNotSupported is returned by Trigger if the driver can't discover
+// Devices.
+var ErrNotSupported = errors.New("the driver doesn't support discovery")
+
+// Status describes the discoveries.
+type Status struct {
+	Running bool `json:"running"`
+	// LastRun is the time (in milliseconds) the last discovery started.
+	LastRun int64  `json:"lastRun,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// Found and Provisioned count the Devices found, and those created.
+	Found       int `json:"found"`
+	Provisioned int `json:"provisioned"`
+}
+
+var (
+	mutex   sync.Mutex
+	status  Status
+	found   []ds_models.DiscoveredDevice
+	matcher *provision.Matcher
+	done    chan struct{}
+	results = make(chan []ds_models.DiscoveredDevice, resultsSize)
+)
+
+// Results returns the channel the driver sends the Devices it finds on.
+func Results() chan<- []ds_models.DiscoveredDevice {
+	return results
+}
+
+// Start provisions the Devices sent on the Results channel, and triggers a
+// discovery every interval, unless zero.
+func Start(interval time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		return
+	}
+	matcher = provision.LoadWatchers(common.CurrentConfig.Watchers)
+	done = make(chan struct{})
+	go receive(done)
+	if interval > 0 {
+		go run(done, interval)
+	}
+}
+
+// Stop stops the periodic discovery and the provisioning of the Devices
+// found.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		close(done)
+		done = nil
+	}
+}
+
+// CurrentStatus returns the status of the discoveries.
+func CurrentStatus() Status {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return status
+}
+
+// Devices returns the Devices found by the last discovery, and those sent
+// on the Results channel since.
+func Devices() []ds_models.DiscoveredDevice {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return append([]ds_models.DiscoveredDevice(nil), found...)
+}
+
+// Trigger runs the discovery of the driver asynchronously, unless already
+// running. The Devices found are returned by DiscoverDevices, or on the
+// Results channel for the drivers only implementing Discover.
+func Trigger() error {
+	var scan func() ([]ds_models.DiscoveredDevice, error)
+	switch d := common.Driver.(type) {
+	case ds_models.DeviceDiscovery:
+		scan = d.DiscoverDevices
+	case ds_models.ProtocolDiscovery:
+		scan = func() ([]ds_models.DiscoveredDevice, error) {
+			_, err := d.Discover()
+			return nil, err
+		}
+	default:
+		return ErrNotSupported
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if status.Running {
+		common.LoggingClient.Debug("Discovery already running")
+		return nil
+	}
+	status.Running = true
+	status.LastRun = time.Now().UnixNano() / int64(time.Millisecond)
+	status.Error = ""
+	found = nil
+	go discover(scan)
+	return nil
+}
+
+func discover(scan func() ([]ds_models.DiscoveredDevice, error)) {
+	common.LoggingClient.Info("Discovery started")
+	devices, err := scan()
+	if err == nil {
+		handle(devices)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	status.Running = false
+	if err != nil {
+		status.Error = err.Error()
+		common.LoggingClient.Error(fmt.Sprintf("Discovery failed: %v", err))
+		return
+	}
+	common.LoggingClient.Info("Discovery completed")
+}
+
+// handle records the Devices found, and provisions them unless only
+// logged as per the DiscoveryLogOnly setting.
+func handle(devices []ds_models.DiscoveredDevice) {
+	if len(devices) == 0 {
+		return
+	}
+	mutex.Lock()
+	found = append(found, devices...)
+	mutex.Unlock()
+
+	if common.CurrentConfig.Device.DiscoveryLogOnly {
+		mutex.Lock()
+		status.Found += len(devices)
+		mutex.Unlock()
+		for _, d := range devices {
+			common.LoggingClient.Info(fmt.Sprintf("Discovery: found Device %s %v", d.Name, d.Identifiers))
+		}
+		return
+	}
+	Provision(devices)
+}
+
+func run(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if common.InStandby() {
+				continue
+			}
+			if err := Trigger(); err != nil {
+				common.LoggingClient.Warn(fmt.Sprintf("Periodic discovery: %v", err))
+			}
+		}
+	}
+}
+
+func receive(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case devices := <-results:
+			handle(devices)
+		}
+	}
+}
+
+// Provision creates the discovered Devices matching a provision watcher,
+// unless they exist, and returns the number of Devices created.
+func Provision(devices []ds_models.DiscoveredDevice) int {
+	mutex.Lock()
+	m := matcher
+	mutex.Unlock()
+	if m == nil {
+		m = provision.LoadWatchers(common.CurrentConfig.Watchers)
+	}
+
+	created := 0
+	for _, d := range devices {
+		match, ok, err := m.Match(d.Identifiers)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Discovery: naming Device %v failed: %v", d.Identifiers, err))
+			continue
+		}
+		if !ok {
+			common.LoggingClient.Debug(fmt.Sprintf("Discovery: Device %v matches no provision watcher", d.Identifiers))
+			continue
+		}
+
+		addr := d.Addressable
+		if addr.Name == "" {
+			addr.Name = match.DeviceName
+		}
+		dc := common.DeviceConfig{
+			Name:        match.DeviceName,
+			Profile:     match.Profile,
+			Description: d.Description,
+			Labels:      d.Labels,
+			Addressable: addr,
+		}
+		ok, err = provision.LoadDiscoveredDevice(dc)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Discovery: provisioning Device %s failed: %v", match.DeviceName, err))
+			continue
+		}
+		if ok {
+			common.LoggingClient.Info(fmt.Sprintf("Discovery: Device %s provisioned by watcher %s", match.DeviceName, match.Watcher))
+			created++
+		}
+	}
+
+	mutex.Lock()
+	status.Found += len(devices)
+	status.Provisioned += created
+	mutex.Unlock()
+	return created
+}
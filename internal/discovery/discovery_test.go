@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+// scanner is a driver whose discovery fails after release is closed.
+type scanner struct {
+	ds_models.ProtocolDriver
+	release chan struct{}
+}
+
+func (s scanner) Discover() (*interface{}, error) {
+	<-s.release
+	return nil, errors.New("bus busy")
+}
+
+type nonDiscovering struct {
+	ds_models.ProtocolDriver
+}
+
+func TestTrigger(t *testing.T) {
+	common.LoggingClient = logger.NewClient("discovery_test", false, "", "DEBUG")
+	defer func() { common.Driver = nil }()
+
+	common.Driver = nonDiscovering{}
+	if err := Trigger(); err != ErrNotSupported {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+
+	s := scanner{release: make(chan struct{})}
+	common.Driver = s
+	if err := Trigger(); err != nil {
+		t.Fatal(err)
+	}
+	// a discovery already running isn't started again
+	if err := Trigger(); err != nil || !CurrentStatus().Running {
+		t.Errorf("Unexpected status %+v, %v", CurrentStatus(), err)
+	}
+	close(s.release)
+
+	deadline := time.Now().Add(time.Second)
+	for CurrentStatus().Running && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if st := CurrentStatus(); st.Running || st.Error != "bus busy" || st.LastRun == 0 {
+		t.Errorf("Unexpected status %+v", st)
+	}
+}
+
+func TestProvisionUnmatched(t *testing.T) {
+	common.LoggingClient = logger.NewClient("discovery_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{Watchers: map[string]common.WatcherInfo{
+		"meters": {Profile: "Meter", Key: "model", MatchString: "PM[0-9]+"},
+	}}
+
+	found := CurrentStatus().Found
+	n := Provision([]ds_models.DiscoveredDevice{{Identifiers: map[string]string{"model": "XYZ"}}})
+	if n != 0 || CurrentStatus().Found != found+1 {
+		t.Errorf("Unexpected provisioning of %d Devices, status %+v", n, CurrentStatus())
+	}
+}
+
+// lister is a driver returning the Devices it finds.
+type lister struct {
+	ds_models.ProtocolDriver
+}
+
+func (lister) DiscoverDevices() ([]ds_models.DiscoveredDevice, error) {
+	return []ds_models.DiscoveredDevice{
+		{Name: "PM5560", Identifiers: map[string]string{"model": "PM5560", "serial": "1"}},
+		{Name: "PM5560", Identifiers: map[string]string{"model": "PM5560", "serial": "2"}},
+	}, nil
+}
+
+func TestDiscoverDevicesLogOnly(t *testing.T) {
+	common.LoggingClient = logger.NewClient("discovery_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() {
+		common.CurrentConfig = previous
+		common.Driver = nil
+	}()
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{DiscoveryLogOnly: true}}
+	common.Driver = lister{}
+
+	found := CurrentStatus().Found
+	if err := Trigger(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for CurrentStatus().Running && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	st := CurrentStatus()
+	if st.Error != "" || st.Found != found+2 {
+		t.Errorf("Unexpected status %+v", st)
+	}
+	if devices := Devices(); len(devices) != 2 || devices[1].Identifiers["serial"] != "2" {
+		t.Errorf("Unexpected Devices found %v", devices)
+	}
+}
@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package feature gates the optional subsystems of the DS, so that one
+// binary can be shipped with features enabled per product tier. Features are
+// switched by flags in the configuration and, if a license file is
+// configured, must also be granted by that signed license.
+package feature
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/pkg/signing"
+)
+
+// Features gated by this package.
+const (
+	History = "history"
+)
+
+// License grants features to a customer until it expires.
+type License struct {
+	Customer string   `json:"customer"`
+	Features []string `json:"features"`
+	// Expires is the expiry date (YYYY-MM-DD), empty if it never expires.
+	Expires string `json:"expires"`
+}
+
+// licenseFile is the format of the license file: the license and the
+// ECDSA-P256 signature of its exact JSON encoding.
+type licenseFile struct {
+	License   json.RawMessage `json:"license"`
+	Signature string          `json:"signature"`
+}
+
+var (
+	mutex    sync.Mutex
+	flags    map[string]bool
+	licensed map[string]bool // nil if no license is required
+)
+
+// Init sets the feature flags and verifies the license file, if any. If the
+// license is invalid or expired, no feature requiring it is enabled.
+func Init(info common.FeatureInfo) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	flags = info.Flags
+	licensed = nil
+	if info.LicenseFile == "" {
+		return nil
+	}
+
+	licensed = make(map[string]bool)
+	license, err := loadLicense(info.LicenseFile, info.LicenseKeyFile, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, f := range license.Features {
+		licensed[f] = true
+	}
+	common.LoggingClient.Info(fmt.Sprintf("License of %s grants features %v", license.Customer, license.Features))
+	return nil
+}
+
+// EnabledFeatures returns the names of the enabled features among those
+// gated by this package or flagged, sorted.
+func EnabledFeatures() []string {
+	mutex.Lock()
+	names := map[string]bool{History: true}
+	for name := range flags {
+		names[name] = true
+	}
+	mutex.Unlock()
+
+	var enabled []string
+	for name := range names {
+		if Enabled(name) {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
+// Enabled returns true if the named feature is enabled. Features without a
+// flag are enabled, unless a license is required and doesn't grant them.
+func Enabled(name string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if enabled, ok := flags[name]; ok && !enabled {
+		return false
+	}
+	return licensed == nil || licensed[name]
+}
+
+func loadLicense(file string, keyFile string, now time.Time) (License, error) {
+	var license License
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return license, err
+	}
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return license, fmt.Errorf("couldn't read the license key: %v", err)
+	}
+	return verifyLicense(contents, key, now)
+}
+
+// verifyLicense checks the signature and expiry of the license file
+// contents, using the PEM encoded public key.
+func verifyLicense(contents []byte, key []byte, now time.Time) (License, error) {
+	var license License
+	verifier, err := signing.NewECDSAVerifier(key)
+	if err != nil {
+		return license, err
+	}
+
+	var lf licenseFile
+	if err = json.Unmarshal(contents, &lf); err != nil {
+		return license, fmt.Errorf("invalid license file: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(lf.Signature)
+	if err != nil {
+		return license, fmt.Errorf("invalid license signature: %v", err)
+	}
+	if err = verifier.Verify(lf.License, sig); err != nil {
+		return license, fmt.Errorf("invalid license signature: %v", err)
+	}
+
+	if err = json.Unmarshal(lf.License, &license); err != nil {
+		return license, fmt.Errorf("invalid license: %v", err)
+	}
+	if license.Expires != "" {
+		expires, err := time.Parse("2006-01-02", license.Expires)
+		if err != nil {
+			return license, fmt.Errorf("invalid license expiry date: %v", err)
+		}
+		if now.After(expires.Add(24 * time.Hour)) {
+			return license, fmt.Errorf("license expired on %s", license.Expires)
+		}
+	}
+	return license, nil
+}
@@ -0,0 +1,66 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package feature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/pkg/signing"
+)
+
+func TestVerifyLicense(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalECPrivateKey(key)
+	signer, _ := signing.NewECDSASigner(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	der, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	license := []byte(`{"customer":"ACME","features":["history"],"expires":"2030-06-30"}`)
+	sig, _ := signer.Sign(license)
+	contents, _ := json.Marshal(licenseFile{License: license, Signature: base64.StdEncoding.EncodeToString(sig)})
+
+	l, err := verifyLicense(contents, pub, time.Date(2030, 6, 30, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Valid license rejected: %v", err)
+	}
+	if l.Customer != "ACME" || len(l.Features) != 1 {
+		t.Errorf("Unexpected license: %+v", l)
+	}
+
+	if _, err = verifyLicense(contents, pub, time.Date(2030, 7, 2, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("Expired license accepted")
+	}
+
+	tampered, _ := json.Marshal(licenseFile{License: []byte(`{"customer":"ACME","features":["history","webui"]}`), Signature: base64.StdEncoding.EncodeToString(sig)})
+	if _, err = verifyLicense(tampered, pub, time.Now()); err == nil {
+		t.Error("Tampered license accepted")
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	defer Init(common.FeatureInfo{})
+
+	Init(common.FeatureInfo{Flags: map[string]bool{"webui": false}})
+	if !Enabled(History) {
+		t.Error("Feature without flag disabled")
+	}
+	if Enabled("webui") {
+		t.Error("Feature disabled by flag enabled")
+	}
+}
@@ -0,0 +1,313 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package federation proxies the Devices of other DS instances, e.g. the
+// sub-gateways of a site concentrator. The Devices of the peers, listed by
+// their federation endpoint, are created with the same profiles, their
+// names prefixed, and their commands are forwarded to the peers.
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/edgex-go/pkg/clients"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	defaultInterval = time.Minute
+	defaultTimeout  = 5 * time.Second
+	// maxErrorLength bounds the error message of a peer kept in a PeerError.
+	maxErrorLength = 1024
+)
+
+// Inventory describes the Devices of a DS and their profiles, as fetched by
+// the instances federating it.
+type Inventory struct {
+	Devices  []models.Device        `json:"devices"`
+	Profiles []models.DeviceProfile `json:"profiles"`
+}
+
+// PeerStatus describes the last synchronization with a peer.
+type PeerStatus struct {
+	Reachable bool `json:"reachable"`
+	// Devices is the number of Devices of the peer.
+	Devices  int    `json:"devices"`
+	LastSync int64  `json:"lastSync,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PeerError is returned for a request rejected by a peer.
+type PeerError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e PeerError) Error() string {
+	return fmt.Sprintf("peer responded %d: %s", e.StatusCode, e.Message)
+}
+
+var (
+	mutex  sync.Mutex
+	status = make(map[string]PeerStatus)
+	done   chan struct{}
+)
+
+// LocalInventory returns the Inventory of the Devices of the DS.
+func LocalInventory() Inventory {
+	devices := cache.Devices().All()
+	inv := Inventory{Devices: devices, Profiles: make([]models.DeviceProfile, 0)}
+	seen := make(map[string]bool)
+	for _, d := range devices {
+		if seen[d.Profile.Name] {
+			continue
+		}
+		seen[d.Profile.Name] = true
+		if p, ok := cache.Profiles().ForName(d.Profile.Name); ok {
+			inv.Profiles = append(inv.Profiles, p)
+		}
+	}
+	return inv
+}
+
+// Start synchronizes the Devices of the peers periodically, if any peer is
+// configured.
+func Start() {
+	info := common.CurrentConfig.Federation
+	if len(info.Peers) == 0 {
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		return
+	}
+	interval := time.Duration(info.Interval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	done = make(chan struct{})
+	go run(done, interval)
+}
+
+// Stop stops the synchronization.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		close(done)
+		done = nil
+	}
+}
+
+// Status returns the status of the synchronization with the peers, by name.
+func Status() map[string]PeerStatus {
+	mutex.Lock()
+	defer mutex.Unlock()
+	result := make(map[string]PeerStatus, len(status))
+	for name, s := range status {
+		result[name] = s
+	}
+	return result
+}
+
+// IsProxied returns whether a Device is the proxy of a Device of a peer.
+func IsProxied(device models.Device) bool {
+	_, _, _, ok := peerOf(device)
+	return ok
+}
+
+// Read forwards a read command to the peer of a proxied Device, and returns
+// the resulting readings, as pushed by the DS.
+func Read(device models.Device, cmd string) ([]models.Reading, error) {
+	_, peer, remote, ok := peerOf(device)
+	if !ok {
+		return nil, fmt.Errorf("Device %s isn't proxied", device.Name)
+	}
+	var event models.Event
+	if err := request(peer, http.MethodGet, commandRoute(remote, cmd), nil, &event); err != nil {
+		return nil, err
+	}
+	readings := make([]models.Reading, len(event.Readings))
+	for i, r := range event.Readings {
+		r.Id = ""
+		r.Device = device.Name
+		readings[i] = r
+	}
+	return readings, nil
+}
+
+// Write forwards a write command, and its JSON parameters, to the peer of a
+// proxied Device.
+func Write(device models.Device, cmd string, params string) error {
+	_, peer, remote, ok := peerOf(device)
+	if !ok {
+		return fmt.Errorf("Device %s isn't proxied", device.Name)
+	}
+	return request(peer, http.MethodPut, commandRoute(remote, cmd), strings.NewReader(params), nil)
+}
+
+// peerOf returns the peer of a proxied Device, given by its label, and the
+// name of the Device on the peer.
+func peerOf(device models.Device) (string, common.FederationPeerInfo, string, bool) {
+	for _, l := range device.Labels {
+		if !strings.HasPrefix(l, common.FederationLabelPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(l, common.FederationLabelPrefix)
+		peer, ok := common.CurrentConfig.Federation.Peers[name]
+		if !ok || !strings.HasPrefix(device.Name, peer.Prefix) {
+			return "", peer, "", false
+		}
+		return name, peer, strings.TrimPrefix(device.Name, peer.Prefix), true
+	}
+	return "", common.FederationPeerInfo{}, "", false
+}
+
+func commandRoute(deviceName string, cmd string) string {
+	return clients.ApiDeviceRoute + "/name/" + url.PathEscape(deviceName) + "/" + url.PathEscape(cmd)
+}
+
+func run(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for name, peer := range common.CurrentConfig.Federation.Peers {
+			s := syncPeer(name, peer)
+			mutex.Lock()
+			status[name] = s
+			mutex.Unlock()
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncPeer creates the proxies of the Devices of a peer missing from the
+// DS, and updates the operating state of its proxies: a proxy is disabled
+// if its Device is, or is gone, or if the peer is unreachable.
+func syncPeer(name string, peer common.FederationPeerInfo) PeerStatus {
+	var inv Inventory
+	if err := request(peer, http.MethodGet, common.APIFederationRoute, nil, &inv); err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Federation: synchronizing the Devices of peer %s failed: %v", name, err))
+		updateOpStates(name, nil)
+		return PeerStatus{Error: err.Error()}
+	}
+
+	profiles := make(map[string]models.DeviceProfile, len(inv.Profiles))
+	for _, p := range inv.Profiles {
+		profiles[p.Name] = p
+	}
+	enabled := make(map[string]bool, len(inv.Devices))
+	for _, d := range inv.Devices {
+		localName := peer.Prefix + d.Name
+		enabled[localName] = d.OperatingState == models.Enabled
+		if _, ok := cache.Devices().ForName(localName); ok {
+			continue
+		}
+
+		profile, ok := profiles[d.Profile.Name]
+		if !ok {
+			profile = d.Profile
+		}
+		labels := append([]string{common.FederationLabelPrefix + name}, peer.Labels...)
+		dc := common.DeviceConfig{
+			Name:        localName,
+			Profile:     profile.Name,
+			Description: d.Description,
+			Labels:      append(labels, d.Labels...),
+			Addressable: models.Addressable{
+				Name:     localName,
+				Protocol: common.HttpProto,
+				Address:  peer.URL,
+				Path:     clients.ApiDeviceRoute + "/name/" + url.PathEscape(d.Name),
+			},
+		}
+		if err := provision.LoadFederatedDevice(dc, profile); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Federation: creating the proxy of Device %s of peer %s failed: %v", d.Name, name, err))
+			continue
+		}
+		common.LoggingClient.Info(fmt.Sprintf("Federation: Device %s of peer %s proxied as %s", d.Name, name, localName))
+	}
+	updateOpStates(name, enabled)
+
+	return PeerStatus{
+		Reachable: true,
+		Devices:   len(inv.Devices),
+		LastSync:  time.Now().UnixNano() / int64(time.Millisecond),
+	}
+}
+
+// updateOpStates enables the proxies of the Devices of a peer which are
+// enabled, and disables the others.
+func updateOpStates(peerName string, enabled map[string]bool) {
+	for _, d := range cache.Devices().All() {
+		if name, _, _, ok := peerOf(d); !ok || name != peerName {
+			continue
+		}
+		state := models.OperatingState(models.Disabled)
+		if enabled[d.Name] {
+			state = models.Enabled
+		}
+		opstate.Set(d, state, "federation")
+	}
+}
+
+// request sends a request to a peer and decodes its JSON response into
+// result, unless nil.
+func request(peer common.FederationPeerInfo, method string, path string, body io.Reader, result interface{}) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(peer.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if peer.Token != "" {
+		token, err := common.LookupSecret(peer.Token)
+		if err != nil {
+			return fmt.Errorf("token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	timeout := time.Duration(common.CurrentConfig.Federation.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	client := http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return PeerError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func setPeer(t *testing.T, url string) func() {
+	previous := common.CurrentConfig
+	common.CurrentConfig = &common.Config{Federation: common.FederationInfo{
+		Peers: map[string]common.FederationPeerInfo{
+			"site-a": {URL: url, Prefix: "a-", Token: "env:FEDERATION_TEST_TOKEN"},
+		},
+	}}
+	os.Setenv("FEDERATION_TEST_TOKEN", "secret")
+	return func() {
+		common.CurrentConfig = previous
+		os.Unsetenv("FEDERATION_TEST_TOKEN")
+	}
+}
+
+func TestPeerOf(t *testing.T) {
+	defer setPeer(t, "http://localhost")()
+
+	tests := []struct {
+		name    string
+		device  models.Device
+		proxied bool
+		remote  string
+	}{
+		{"Proxied", models.Device{Name: "a-meter", Labels: []string{"federation:site-a", "federation:sub"}}, true, "meter"},
+		{"Local", models.Device{Name: "meter", Labels: []string{"industrial"}}, false, ""},
+		{"UnknownPeer", models.Device{Name: "b-meter", Labels: []string{"federation:site-b"}}, false, ""},
+		{"PrefixMismatch", models.Device{Name: "meter", Labels: []string{"federation:site-a"}}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peer, _, remote, ok := peerOf(tt.device)
+			if ok != tt.proxied || remote != tt.remote {
+				t.Errorf("peerOf: %q, %q, %v, expected %q, %v", peer, remote, ok, tt.remote, tt.proxied)
+			}
+		})
+	}
+}
+
+func TestForwarding(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch req.URL.Path {
+		case "/api/v1/device/name/meter/Energy":
+			if req.Method == http.MethodPut {
+				b, _ := ioutil.ReadAll(req.Body)
+				body = string(b)
+				return
+			}
+			json.NewEncoder(w).Encode(models.Event{Device: "meter", Readings: []models.Reading{{Device: "meter", Name: "Energy", Value: "42"}}})
+		default:
+			http.Error(w, "command not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer setPeer(t, server.URL)()
+
+	device := models.Device{Name: "a-meter", Labels: []string{"federation:site-a"}}
+	readings, err := Read(device, "Energy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 || readings[0].Device != "a-meter" || readings[0].Value != "42" {
+		t.Errorf("Unexpected readings %v", readings)
+	}
+
+	if err = Write(device, "Energy", `{"Energy":"0"}`); err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"Energy":"0"}` {
+		t.Errorf("Parameters %q not forwarded", body)
+	}
+
+	_, err = Read(device, "Power")
+	if pe, ok := err.(PeerError); !ok || pe.StatusCode != http.StatusNotFound || pe.Message != "command not found" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	if _, err = Read(models.Device{Name: "meter"}, "Energy"); err == nil {
+		t.Error("Local Device read from a peer")
+	}
+}
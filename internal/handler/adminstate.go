@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// AdminStateRequest is the body of an admin state request.
+type AdminStateRequest struct {
+	AdminState models.AdminState `json:"adminState"`
+}
+
+// AdminStateHandler locks or unlocks the Device specified by name, the
+// commands of a locked Device being rejected without invoking the driver.
+// The admin state is updated in Core Metadata first, then in cache.
+func AdminStateHandler(vars map[string]string, body string) (AdminStateRequest, common.AppError) {
+	device, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return AdminStateRequest{}, appErr
+	}
+
+	var req AdminStateRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		msg := fmt.Sprintf("Handler - AdminState: parsing the request for Device %s failed: %v", device.Name, err)
+		common.LoggingClient.Error(msg)
+		return AdminStateRequest{}, common.NewBadRequestError(msg, err)
+	}
+	state := models.AdminState(strings.ToUpper(string(req.AdminState)))
+	if state != models.Locked && state != models.Unlocked {
+		msg := fmt.Sprintf("Handler - AdminState: invalid admin state %q for Device %s", req.AdminState, device.Name)
+		common.LoggingClient.Error(msg)
+		return AdminStateRequest{}, common.NewBadRequestError(msg, nil)
+	}
+
+	if device.AdminState != state {
+		err := common.DeviceClient.UpdateAdminStateByName(device.Name, string(state))
+		if err != nil {
+			msg := fmt.Sprintf("Handler - AdminState: updating Device %s in Core Metadata failed: %v", device.Name, err)
+			common.LoggingClient.Error(msg)
+			return AdminStateRequest{}, common.NewServerError(msg, err)
+		}
+		device.AdminState = state
+		cache.Devices().Update(device)
+		common.LoggingClient.Info(fmt.Sprintf("Handler - AdminState: Device %s %s", device.Name, strings.ToLower(string(state))))
+	}
+	return AdminStateRequest{AdminState: state}, nil
+}
@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// adminStateClient records the admin states updated in Core Metadata,
+// failing the updates if err is set.
+type adminStateClient struct {
+	mock.DeviceClientMock
+	updates []string
+	err     error
+}
+
+func (c *adminStateClient) UpdateAdminStateByName(name string, adminState string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.updates = append(c.updates, adminState)
+	return nil
+}
+
+func TestAdminStateHandler(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousClient := common.DeviceClient
+	defer func() { common.DeviceClient = previousClient }()
+	client := &adminStateClient{}
+	common.DeviceClient = client
+
+	initCache(t)
+	meter, _ := cache.Devices().ForName("meter")
+	meter.AdminState = models.Unlocked
+	cache.Devices().Update(meter)
+
+	vars := map[string]string{"name": "meter"}
+	adminState := func() models.AdminState {
+		d, _ := cache.Devices().ForName("meter")
+		return d.AdminState
+	}
+
+	req, appErr := AdminStateHandler(vars, `{"adminState": "locked"}`)
+	if appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	if req.AdminState != models.Locked || adminState() != models.Locked || len(client.updates) != 1 || client.updates[0] != "LOCKED" {
+		t.Errorf("Lowercase state: %v, cached %s, updated %v", req.AdminState, adminState(), client.updates)
+	}
+
+	for _, body := range []string{`{"adminState": "frozen"}`, `{"adminState": ""}`, `locked`} {
+		if _, appErr = AdminStateHandler(vars, body); appErr == nil || appErr.Code() != http.StatusBadRequest {
+			t.Errorf("Request %s: expected status %d, got %v", body, http.StatusBadRequest, appErr)
+		}
+	}
+	if adminState() != models.Locked || len(client.updates) != 1 {
+		t.Errorf("Invalid requests changed the state to %s, updated %v", adminState(), client.updates)
+	}
+
+	client.err = errors.New("metadata unavailable")
+	if _, appErr = AdminStateHandler(vars, `{"adminState": "UNLOCKED"}`); appErr == nil || appErr.Code() != http.StatusInternalServerError {
+		t.Errorf("Metadata failure: expected status %d, got %v", http.StatusInternalServerError, appErr)
+	}
+	if adminState() != models.Locked {
+		t.Errorf("Metadata failure changed the cached state to %s", adminState())
+	}
+}
@@ -0,0 +1,55 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// StartAutoEvents, StopAutoEvents and RestartAutoEvents start, stop and
+// restart the AutoEvents of a Device as it's added, removed or updated.
+// They're set by the DS, as the autoevent package depends on this one.
+var (
+	StartAutoEvents   = func(deviceName string) {}
+	StopAutoEvents    = func(deviceName string) {}
+	RestartAutoEvents = func(deviceName string) {}
+)
+
+// AutoEventReadHandler reads a resource of a Device for an AutoEvent, as a
+// GET command, but returns the event without pushing it to Core Data, so
+// that the unchanged readings can be dropped.
+func AutoEventReadHandler(deviceName string, resource string) (*models.Event, common.AppError) {
+	if appErr := beginCommand(); appErr != nil {
+		return nil, appErr
+	}
+	defer endCommand()
+
+	d, ok := deviceForName(deviceName)
+	if !ok {
+		msg := i18n.T(i18n.DeviceNotFoundMethod, deviceName, "GET")
+		return nil, common.NewNotFoundError(msg, nil)
+	}
+	if d.AdminState == models.Locked {
+		msg := i18n.T(i18n.DeviceLocked, d.Name, "GET")
+		return nil, common.NewLockedError(msg, nil)
+	}
+
+	start := time.Now()
+	markActive(d.Name)
+	readings, appErr := readCmd(&d, resource)
+	recordHistory(d.Name, "get", resource, start, appErr)
+	if appErr != nil {
+		return nil, appErr
+	}
+	common.LoggingClient.Debug(fmt.Sprintf("Handler - AutoEvent: %d readings of %s for Device %s", len(readings), resource, d.Name))
+	return &models.Event{Device: d.Name, Origin: time.Now().UnixNano() / int64(time.Millisecond), Readings: readings}, nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
 	"github.com/edgexfoundry/device-sdk-go/internal/provision"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
@@ -63,9 +64,20 @@ func handleDevice(method string, id string) common.AppError {
 			}
 		}
 
+		// Devices added by the DS itself are already known by the Driver
+		if _, cached := cache.Devices().ForId(id); !cached {
+			if err = common.NotifyDeviceAdded(device); err != nil {
+				common.LoggingClient.Error(err.Error())
+				return common.NewBadRequestError(err.Error(), err)
+			}
+		}
+
 		err = cache.Devices().Add(device)
 		if err == nil {
 			common.LoggingClient.Info(fmt.Sprintf("Added device %s", id))
+			provision.CreateAliasDescriptors(device)
+			provision.CreateDefaultAutoEvents(device)
+			StartAutoEvents(device.Name)
 		} else {
 			appErr := common.NewServerError(err.Error(), err)
 			common.LoggingClient.Error(fmt.Sprintf("Couldn't add device %s: %v", id, err.Error()))
@@ -79,22 +91,39 @@ func handleDevice(method string, id string) common.AppError {
 			return appErr
 		}
 
+		if err = common.NotifyDeviceUpdated(dev); err != nil {
+			common.LoggingClient.Error(err.Error())
+			return common.NewBadRequestError(err.Error(), err)
+		}
+
+		old, cached := cache.Devices().ForId(id)
+		if cached && old.OperatingState != dev.OperatingState {
+			opstate.Observe(dev.Name, dev.OperatingState, "metadata")
+		}
 		err = cache.Devices().Update(dev)
 		if err == nil {
 			common.LoggingClient.Info(fmt.Sprintf("Updated device %s", id))
+			if cached && old.Name != dev.Name {
+				StopAutoEvents(old.Name)
+				forgetActivity(old.Name)
+			}
+			provision.CreateAliasDescriptors(dev)
+			RestartAutoEvents(dev.Name)
 		} else {
 			appErr := common.NewServerError(err.Error(), err)
 			common.LoggingClient.Error(fmt.Sprintf("Couldn't update device %s: %v", id, err.Error()))
 			return appErr
 		}
 	} else if method == http.MethodDelete {
-		err := cache.Devices().Remove(id)
-		if err == nil {
-			common.LoggingClient.Info(fmt.Sprintf("Removed device %s", id))
+		// the Devices removed by the DS itself are already gone
+		if device, ok := cache.Devices().ForId(id); ok {
+			if _, removed := RemoveCachedDevice(device.Name); removed {
+				common.LoggingClient.Info(fmt.Sprintf("Removed device %s", id))
+			} else {
+				common.LoggingClient.Debug(fmt.Sprintf("Device %s removed by its decommissioning", id))
+			}
 		} else {
-			appErr := common.NewServerError(err.Error(), err)
-			common.LoggingClient.Error(fmt.Sprintf("Couldn't remove device %s: %v", id, err.Error()))
-			return appErr
+			common.LoggingClient.Debug(fmt.Sprintf("Device %s already removed", id))
 		}
 	} else {
 		common.LoggingClient.Error(fmt.Sprintf("Invalid device method type: %s", method))
@@ -144,6 +173,12 @@ func handleProfile(method string, id string) common.AppError {
 		if err == nil {
 			provision.CreateDescriptorsFromProfile(&profile)
 			common.LoggingClient.Info(fmt.Sprintf("Updated device profile %s", id))
+			// the AutoEvents are defined by the profiles
+			for _, d := range cache.Devices().All() {
+				if d.Profile.Name == profile.Name {
+					RestartAutoEvents(d.Name)
+				}
+			}
 		} else {
 			appErr := common.NewServerError(err.Error(), err)
 			common.LoggingClient.Error(fmt.Sprintf("Couldn't update device profile %s: %v", id, err.Error()))
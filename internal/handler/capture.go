@@ -0,0 +1,56 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/capture"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// DeviceCapturesHandler returns the status of the captures of the Device
+// specified by name.
+func DeviceCapturesHandler(vars map[string]string) ([]capture.Status, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return capture.ForDevice(d.Name), nil
+}
+
+// CaptureHandler returns the status of the capture specified by id.
+func CaptureHandler(vars map[string]string) (capture.Status, common.AppError) {
+	id := vars["captureid"]
+	s, ok := capture.ForID(id)
+	if !ok {
+		msg := fmt.Sprintf("Capture: %s not found", id)
+		common.LoggingClient.Debug(msg)
+		return s, common.NewNotFoundError(msg, nil)
+	}
+	return s, nil
+}
+
+// CaptureDataHandler returns the contents of the completed capture
+// specified by id.
+func CaptureDataHandler(vars map[string]string) ([]byte, common.AppError) {
+	s, appErr := CaptureHandler(vars)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if s.State != capture.StateComplete {
+		msg := fmt.Sprintf("Capture: %s is %s", s.ID, s.State)
+		return nil, common.NewPreconditionFailedError(msg, nil)
+	}
+	data, err := capture.Data(s.ID)
+	if err != nil {
+		msg := fmt.Sprintf("Capture: %s can't be read", s.ID)
+		common.LoggingClient.Error(fmt.Sprintf("%s: %v", msg, err))
+		return nil, common.NewServerError(msg, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,29 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/clock"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+)
+
+// ClockOffsetHandler returns the measured clock offset of the Device
+// specified by name.
+func ClockOffsetHandler(vars map[string]string) (clock.Offset, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return clock.Offset{}, appErr
+	}
+	off, ok := clock.ForDevice(d.Name)
+	if !ok {
+		msg := i18n.T(i18n.ClockOffsetNotMeasured, d.Name)
+		common.LoggingClient.Debug(msg)
+		return off, common.NewNotFoundError(msg, nil)
+	}
+	return off, nil
+}
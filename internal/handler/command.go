@@ -10,13 +10,22 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/anomaly"
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/federation"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	"github.com/edgexfoundry/device-sdk-go/internal/metrics"
+	"github.com/edgexfoundry/device-sdk-go/internal/selfdevice"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
 	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
@@ -25,6 +34,11 @@ import (
 // Note, every HTTP request to ServeHTTP is made in a separate goroutine, which
 // means care needs to be taken with respect to shared data accessed through *Server.
 func CommandHandler(vars map[string]string, body string, method string) (*models.Event, common.AppError) {
+	if appErr := beginCommand(); appErr != nil {
+		return nil, appErr
+	}
+	defer endCommand()
+
 	dKey := vars["id"]
 	cmd := vars["command"]
 
@@ -34,25 +48,24 @@ func CommandHandler(vars map[string]string, body string, method string) (*models
 		d, ok = cache.Devices().ForId(dKey)
 	} else {
 		dKey = vars["name"]
-		d, ok = cache.Devices().ForName(dKey)
+		d, ok = deviceForName(dKey)
 	}
 	if !ok {
-		msg := fmt.Sprintf("Device: %s not found; %s", dKey, method)
+		msg := i18n.T(i18n.DeviceNotFoundMethod, dKey, method)
 		common.LoggingClient.Error(msg)
 		return nil, common.NewNotFoundError(msg, nil)
 	}
 
 	if d.AdminState == "LOCKED" {
-		msg := fmt.Sprintf("%s is locked; %s", d.Name, method)
+		msg := i18n.T(i18n.DeviceLocked, d.Name, method)
 		common.LoggingClient.Error(msg)
 		return nil, common.NewLockedError(msg, nil)
 	}
 
 	// TODO: need to mark device when operation in progress, so it can't be removed till completed
 
-	// NOTE: as currently implemented, CommandExists checks the existence of a deviceprofile
-	// *resource* name, not a *command* name! A deviceprofile's command section is only used
-	// to trigger valuedescriptor creation.
+	// the command may be a core command or a device command of the profile,
+	// whose resource operations are executed in a single driver call
 	exists, err := cache.Profiles().CommandExists(d.Profile.Name, cmd)
 
 	// TODO: once cache locking has been implemented, this should never happen
@@ -63,24 +76,130 @@ func CommandHandler(vars map[string]string, body string, method string) (*models
 	}
 
 	if !exists {
-		msg := fmt.Sprintf("%s for Device: %s not found; %s", cmd, d.Name, method)
+		if _, ok := commandGroup(&d, cmd); ok {
+			exists = true
+			if strings.ToLower(method) != "get" {
+				msg := fmt.Sprintf("Handler - CommandHandler: command group %s of Device %s can only be read", cmd, d.Name)
+				common.LoggingClient.Error(msg)
+				return nil, common.NewBadRequestError(msg, nil)
+			}
+		}
+	}
+	if !exists {
+		msg := i18n.T(i18n.CommandNotFound, cmd, d.Name, method)
 		common.LoggingClient.Error(msg)
 		return nil, common.NewNotFoundError(msg, nil)
 	}
 
+	start := time.Now()
 	if strings.ToLower(method) == "get" {
-		return execReadCmd(&d, cmd)
+		cached := common.CurrentConfig.Device.CachedReads
+		if v := vars["cached"]; v != "" {
+			cached, err = strconv.ParseBool(v)
+			if err != nil {
+				msg := fmt.Sprintf("Handler - CommandHandler: invalid cached parameter %q", v)
+				common.LoggingClient.Error(msg)
+				return nil, common.NewBadRequestError(msg, err)
+			}
+		}
+		if cached {
+			if evt, ok := cachedReadCmd(&d, cmd); ok {
+				return evt, nil
+			}
+		}
+
+		markActive(d.Name)
+		evt, appErr := execReadCmd(&d, cmd)
+		recordHistory(d.Name, "get", cmd, start, appErr)
+		return evt, appErr
 	} else {
+		markActive(d.Name)
 		appErr := execWriteCmd(&d, cmd, body)
+		recordHistory(d.Name, "set", cmd, start, appErr)
 		return nil, appErr
 	}
 }
 
+func recordHistory(deviceName string, method string, cmd string, start time.Time, appErr common.AppError) {
+	r := history.Record{
+		Timestamp: start.UnixNano() / int64(time.Millisecond),
+		Method:    method,
+		Command:   cmd,
+		Result:    "OK",
+		Latency:   int64(time.Since(start) / time.Millisecond),
+	}
+	if appErr != nil {
+		r.Result = appErr.Message()
+	}
+	history.Add(deviceName, r)
+}
+
 func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppError) {
+	readings, appErr := readCmd(device, cmd)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// push to Core Data
+	event := &models.Event{Device: device.Name, Readings: readings}
+	event.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+	go common.SendEvent(event)
+
+	// TODO: enforce config.MaxCmdValueLen; need to include overhead for
+	// the rest of the reading JSON + Event JSON length?  Should there be
+	// a separate JSON body max limit for retvals & command parameters?
+
+	return event, nil
+}
+
+// cachedReadCmd returns an event holding the cached values of the resources
+// of a read command, unless any is missing or stale. The event isn't pushed
+// to Core Data again.
+func cachedReadCmd(device *models.Device, cmd string) (*models.Event, bool) {
+	ros, err := readOperations(device, cmd)
+	if err != nil || len(ros) == 0 {
+		return nil, false
+	}
+
+	maxAge := time.Duration(common.CurrentConfig.Device.CacheMaxAge) * time.Millisecond
+	readings := make([]models.Reading, 0, len(ros))
+	var origin int64
+	for _, ro := range ros {
+		v, ok := cache.Readings().ForResource(device.Name, ro.Object)
+		if !ok || v.Stale(maxAge) {
+			common.LoggingClient.Debug(fmt.Sprintf("Handler - cachedReadCmd: no fresh value of %s for dev: %s, reading it", ro.Object, device.Name))
+			return nil, false
+		}
+		do, ok := cache.Profiles().DeviceObject(device.Profile.Name, ro.Object)
+		if !ok {
+			return nil, false
+		}
+		readings = append(readings, transformer.CommandValueToReadings(v.Value, device.Name, do)...)
+		if ts := v.Timestamp.UnixNano() / int64(time.Millisecond); ts > origin {
+			origin = ts
+		}
+	}
+
+	return &models.Event{Device: device.Name, Origin: origin, Readings: readings}, true
+}
+
+// readCmd executes a read command and returns the resulting readings.
+func readCmd(device *models.Device, cmd string) ([]models.Reading, common.AppError) {
+	if federation.IsProxied(*device) {
+		// the peer has already transformed the values
+		readings, err := federation.Read(*device, cmd)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execReadCmd: forwarding cmd: %s of dev: %s to its peer failed: %v", cmd, device.Name, err)
+			common.LoggingClient.Error(msg)
+			return nil, peerError(msg, err)
+		}
+		return readings, nil
+	}
+
 	readings := make([]models.Reading, 0, common.CurrentConfig.Device.MaxCmdOps)
 
 	// make ResourceOperations
-	ros, err := cache.Profiles().ResourceOperations(device.Profile.Name, cmd, "get")
+	ros, err := readOperations(device, cmd)
 	if err != nil {
 		common.LoggingClient.Error(err.Error())
 		return nil, common.NewNotFoundError(err.Error(), err)
@@ -93,16 +212,13 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 		return nil, common.NewServerError(msg, nil)
 	}
 
+	props := common.DeviceProperties(*device)
 	reqs := make([]ds_models.CommandRequest, len(ros))
 
 	for i, op := range ros {
 		objName := op.Object
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: deviceObject: %s", objName))
 
-		// TODO: add recursive support for resource command chaining. This occurs when a
-		// deviceprofile resource command operation references another resource command
-		// instead of a device resource (see BoschXDK for reference).
-
 		devObj, ok := cache.Profiles().DeviceObject(device.Profile.Name, objName)
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: deviceObject: %v", devObj))
 		if !ok {
@@ -113,12 +229,33 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 
 		reqs[i].RO = op
 		reqs[i].DeviceObject = devObj
+		reqs[i].Properties = props
 	}
 
-	results, err := common.Driver.HandleReadCommands(&device.Addressable, reqs)
+	var results []*ds_models.CommandValue
+	start := time.Now()
+	if common.IsSelfDevice(device.Name) {
+		// the gateway Device is read by the DS, whatever the driver
+		results, err = selfdevice.Read(reqs)
+	} else {
+		var addr models.Addressable
+		addr, err = common.ResolveAddressable(device.Name, device.Addressable)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execReadCmd: credentials of Device: %s, %v", device.Name, err)
+			common.LoggingClient.Error(msg)
+			return nil, common.NewServerError(msg, err)
+		}
+		release, appErr := acquireDriver(device.Name)
+		if appErr != nil {
+			return nil, appErr
+		}
+		results, err = common.Driver.HandleReadCommands(&addr, reqs)
+		release()
+	}
+	metrics.RecordRead(device.Name, requestedResources(reqs), time.Since(start), err)
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execReadCmd: error for Device: %s cmd: %s, %v", device.Name, cmd, err)
-		return nil, common.NewServerError(msg, err)
+		return nil, common.NewDriverError(msg, err)
 	}
 
 	var transformsOK bool = true
@@ -132,8 +269,15 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 			return nil, common.NewServerError(msg, nil)
 		}
 
+		if anomaly.Invalid(device.Name, cv, do) {
+			continue
+		}
+
 		if common.CurrentConfig.Device.DataTransform {
-			err = transformer.TransformReadResult(cv, do.Properties.Value)
+			err = transformer.TransformReadResult(cv, transformer.ResolveProperties(do.Properties.Value, props))
+			if err == nil {
+				err = transformer.ApplyRatio(cv, do, props)
+			}
 			if err != nil {
 				common.LoggingClient.Error(fmt.Sprintf("Handler - execReadCmd: CommandValue (%s) transformed failed: %v", cv.String(), err))
 				transformsOK = false
@@ -142,8 +286,9 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 
 		err = transformer.CheckAssertion(cv, do.Properties.Value.Assertion, device)
 		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Handler - execReadCmd: Assertion failed for device resource: %s, with value: %v", cv.String(), err))
-			cv = ds_models.NewStringValue(cv.RO, cv.Origin, fmt.Sprintf("Assertion failed for device resource, with value: %s and assertion: %s", cv.String(), do.Properties.Value.Assertion))
+			msg := fmt.Sprintf("Handler - execReadCmd: Assertion failed for device resource: %s of dev: %s, Device disabled: %v", cv.RO.Object, device.Name, err)
+			common.LoggingClient.Error(msg)
+			return nil, common.NewServerError(msg, err)
 		}
 
 		if len(cv.RO.Mappings) > 0 {
@@ -163,10 +308,19 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 		// been implemened in gxds. TBD at the devices f2f whether this
 		// be killed completely.
 
-		reading := common.CommandValueToReading(cv, device.Name)
-		readings = append(readings, *reading)
+		a := anomaly.Detect(device.Name, cv, do)
+		if a != nil && a.Action == ds_models.AnomalySuppress {
+			continue
+		}
+
+		cache.Readings().Add(device.Name, cv)
+		rs := transformer.CommandValueToReadings(cv, device.Name, do)
+		if a != nil {
+			rs = append(rs, anomaly.Reading(device.Name, cv, a))
+		}
+		readings = append(readings, rs...)
 
-		common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: device: %s RO: %v reading: %v", device.Name, cv.RO, reading))
+		common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: device: %s RO: %v readings: %v", device.Name, cv.RO, rs))
 	}
 
 	if !transformsOK {
@@ -176,19 +330,25 @@ func execReadCmd(device *models.Device, cmd string) (*models.Event, common.AppEr
 		return nil, common.NewServerError(msg, nil)
 	}
 
-	// push to Core Data
-	event := &models.Event{Device: device.Name, Readings: readings}
-	event.Origin = time.Now().UnixNano() / int64(time.Millisecond)
-	go common.SendEvent(event)
-
-	// TODO: enforce config.MaxCmdValueLen; need to include overhead for
-	// the rest of the reading JSON + Event JSON length?  Should there be
-	// a separate JSON body max limit for retvals & command parameters?
-
-	return event, nil
+	return readings, nil
 }
 
 func execWriteCmd(device *models.Device, cmd string, params string) common.AppError {
+	if common.InStandby() {
+		msg := i18n.T(i18n.ServiceStandby, device.Name)
+		common.LoggingClient.Warn(msg)
+		return common.NewServiceUnavailableError(msg, nil)
+	}
+
+	if federation.IsProxied(*device) {
+		if err := federation.Write(*device, cmd, params); err != nil {
+			msg := fmt.Sprintf("Handler - execWriteCmd: forwarding cmd: %s of dev: %s to its peer failed: %v", cmd, device.Name, err)
+			common.LoggingClient.Error(msg)
+			return peerError(msg, err)
+		}
+		return nil
+	}
+
 	ros, err := cache.Profiles().ResourceOperations(device.Profile.Name, cmd, "set")
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execWriteCmd: can't find ResrouceOperations in Profile(%s) and Command(%s), %v", device.Profile.Name, cmd, err)
@@ -212,15 +372,12 @@ func execWriteCmd(device *models.Device, cmd string, params string) common.AppEr
 		return common.NewBadRequestError(msg, err)
 	}
 
+	props := common.DeviceProperties(*device)
 	reqs := make([]ds_models.CommandRequest, len(cvs))
 	for i, cv := range cvs {
 		objName := cv.RO.Object
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execWriteCmd: putting deviceObject: %s", objName))
 
-		// TODO: add recursive support for resource command chaining. This occurs when a
-		// deviceprofile resource command operation references another resource command
-		// instead of a device resource (see BoschXDK for reference).
-
 		devObj, ok := cache.Profiles().DeviceObject(device.Profile.Name, objName)
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execWriteCmd: putting deviceObject: %v", devObj))
 		if !ok {
@@ -231,9 +388,20 @@ func execWriteCmd(device *models.Device, cmd string, params string) common.AppEr
 
 		reqs[i].RO = *cv.RO
 		reqs[i].DeviceObject = devObj
+		reqs[i].Properties = props
+
+		err = transformer.CheckWriteConstraints(cv, devObj, common.CurrentConfig.Device.ClampWriteValues)
+		if err != nil {
+			msg := i18n.T(i18n.ValueRejected, cv.String(), err)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
 
 		if common.CurrentConfig.Device.DataTransform {
-			err = transformer.TransformWriteParameter(cv, devObj.Properties.Value)
+			err = transformer.InvertRatio(cv, devObj, props)
+			if err == nil {
+				err = transformer.TransformWriteParameter(cv, transformer.ResolveProperties(devObj.Properties.Value, props))
+			}
 			if err != nil {
 				msg := fmt.Sprintf("Handler - execWriteCmd: CommandValue (%s) transformed failed: %v", cv.String(), err)
 				common.LoggingClient.Error(msg)
@@ -242,15 +410,45 @@ func execWriteCmd(device *models.Device, cmd string, params string) common.AppEr
 		}
 	}
 
-	err = common.Driver.HandleWriteCommands(&device.Addressable, reqs, cvs)
+	if requiresSelect(reqs) && !consumeSelection(device.Name, cmd) {
+		msg := i18n.T(i18n.SelectRequired, device.Name, cmd)
+		common.LoggingClient.Error(msg)
+		return common.NewPreconditionFailedError(msg, nil)
+	}
+
+	addr, err := common.ResolveAddressable(device.Name, device.Addressable)
 	if err != nil {
-		msg := fmt.Sprintf("Handler - execWriteCmd: error for Device: %s cmd: %s, %v", device.Name, cmd, err)
+		msg := fmt.Sprintf("Handler - execWriteCmd: credentials of Device: %s, %v", device.Name, err)
+		common.LoggingClient.Error(msg)
 		return common.NewServerError(msg, err)
 	}
 
+	release, appErr := acquireDriver(device.Name)
+	if appErr != nil {
+		return appErr
+	}
+	start := time.Now()
+	err = common.Driver.HandleWriteCommands(&addr, reqs, cvs)
+	release()
+	metrics.RecordWrite(device.Name, requestedResources(reqs), time.Since(start), err)
+	if err != nil {
+		msg := fmt.Sprintf("Handler - execWriteCmd: error for Device: %s cmd: %s, %v", device.Name, cmd, err)
+		return common.NewDriverError(msg, err)
+	}
+
 	return nil
 }
 
+// requestedResources returns the names of the device resources of the
+// requests.
+func requestedResources(reqs []ds_models.CommandRequest) []string {
+	names := make([]string, len(reqs))
+	for i, req := range reqs {
+		names[i] = req.DeviceObject.Name
+	}
+	return names
+}
+
 func parseWriteParams(roMap map[string]*models.ResourceOperation, params string) ([]*ds_models.CommandValue, error) {
 	var paramMaps []map[string]string
 	err := json.Unmarshal([]byte(params), &paramMaps)
@@ -311,7 +509,16 @@ func createCommandValueForParam(ro *models.ResourceOperation, v string) (*ds_mod
 
 	origin := time.Now().UnixNano() / int64(time.Millisecond)
 
-	switch strings.ToLower(vd.Type) {
+	typeName := strings.ToLower(vd.Type)
+	if common.CurrentConfig.Device.LenientNumbers && typeName != "bool" && typeName != "string" {
+		v, err = common.NormalizeNumber(v, !strings.HasPrefix(typeName, "float"))
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Handler - Command: Parsing parameter value to %s failed: %v", vd.Type, err))
+			return result, err
+		}
+	}
+
+	switch typeName {
 	case "bool":
 		value, err = strconv.ParseBool(v)
 		t = ds_models.Bool
@@ -360,57 +567,135 @@ func createCommandValueForParam(ro *models.ResourceOperation, v string) (*ds_mod
 	return result, err
 }
 
-func CommandAllHandler(cmd string, body string, method string) ([]*models.Event, common.AppError) {
+// CommandResult is the result of a command executed on several Devices, for
+// one of them.
+type CommandResult struct {
+	Device string        `json:"device"`
+	Code   int           `json:"code"`
+	Event  *models.Event `json:"event,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// CommandAllHandler executes a command on all the operational Devices, or
+// those with the given label if not empty. The Devices on independent
+// transports are commanded concurrently, up to MaxParallelCommands, and the
+// Devices sharing a transport one after the other. The results are sorted by
+// Device name; an error is returned only if the command failed for all the
+// Devices.
+func CommandAllHandler(cmd string, body string, method string, label string) ([]CommandResult, common.AppError) {
 	common.LoggingClient.Debug(fmt.Sprintf("Handler - CommandAll: execute the %s command %s from all operational devices", method, cmd))
+	if appErr := beginCommand(); appErr != nil {
+		return nil, appErr
+	}
+	defer endCommand()
+
 	devices := filterOperationalDevices(cache.Devices().All())
+	if label != "" {
+		devices = filterLabeledDevices(devices, label)
+	}
+	transports := groupByTransport(devices)
 
-	devCount := len(devices)
+	parallel := len(transports)
+	if max := common.CurrentConfig.Device.MaxParallelCommands; max > 0 && max < parallel {
+		parallel = max
+	}
+	// the number of concurrent commands is reduced when the DS is throttled
+	sem := make(chan struct{}, throttle.Parallelism(parallel))
+
+	results := make([]CommandResult, 0, len(devices))
+	var mutex sync.Mutex
 	var waitGroup sync.WaitGroup
-	waitGroup.Add(devCount)
-	cmdResults := make(chan struct {
-		event  *models.Event
-		appErr common.AppError
-	}, devCount)
-
-	for i, _ := range devices {
-		go func(device *models.Device) {
+	waitGroup.Add(len(transports))
+	for i := range transports {
+		go func(devices []*models.Device) {
 			defer waitGroup.Done()
-			var event *models.Event = nil
-			var appErr common.AppError = nil
-			if strings.ToLower(method) == "get" {
-				event, appErr = execReadCmd(device, cmd)
-			} else {
-				appErr = execWriteCmd(device, cmd, body)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			for _, device := range devices {
+				r := execCommand(device, cmd, body, method)
+				mutex.Lock()
+				results = append(results, r)
+				mutex.Unlock()
 			}
-			cmdResults <- struct {
-				event  *models.Event
-				appErr common.AppError
-			}{event, appErr}
-		}(devices[i])
+		}(transports[i])
 	}
 	waitGroup.Wait()
-	close(cmdResults)
+	sort.Slice(results, func(i, j int) bool { return results[i].Device < results[j].Device })
 
-	errCount := 0
-	getResults := make([]*models.Event, 0, devCount)
 	var appErr common.AppError
-	for r := range cmdResults {
-		if r.appErr != nil {
+	errCount := 0
+	for _, r := range results {
+		if r.Error != "" {
 			errCount++
-			common.LoggingClient.Error("Handler - CommandAll: " + r.appErr.Message())
-			appErr = r.appErr // only the last error will be returned
-		} else if r.event != nil {
-			getResults = append(getResults, r.event)
+			common.LoggingClient.Error("Handler - CommandAll: " + r.Error)
+			if appErr == nil {
+				appErr = common.NewServerError(r.Error, nil)
+			}
 		}
 	}
+	if errCount > 0 && errCount == len(results) {
+		return nil, appErr
+	}
+	if errCount > 0 {
+		common.LoggingClient.Info("Handler - CommandAll: part of commands executed successfully")
+	}
+	return results, nil
+}
 
-	if errCount < devCount {
-		common.LoggingClient.Info("Handler - CommandAll: part of commands executed successfully, returning 200 OK")
-		appErr = nil
+// execCommand executes a command on a Device for CommandAllHandler.
+func execCommand(device *models.Device, cmd string, body string, method string) CommandResult {
+	r := CommandResult{Device: device.Name, Code: http.StatusOK}
+	var appErr common.AppError
+	start := time.Now()
+	if strings.ToLower(method) == "get" {
+		r.Event, appErr = execReadCmd(device, cmd)
+		recordHistory(device.Name, "get", cmd, start, appErr)
+	} else {
+		appErr = execWriteCmd(device, cmd, body)
+		recordHistory(device.Name, "set", cmd, start, appErr)
+	}
+	if appErr != nil {
+		r.Code = appErr.Code()
+		r.Error = appErr.Message()
 	}
+	return r
+}
 
-	return getResults, appErr
+// groupByTransport groups the Devices by transport, e.g. a serial port or a
+// gateway, given by the protocol, address and port of their Addressable, as
+// a transport carries a command at a time. The Devices without address are
+// on their own.
+func groupByTransport(devices []*models.Device) [][]*models.Device {
+	groups := make([][]*models.Device, 0, len(devices))
+	index := make(map[string]int)
+	for _, d := range devices {
+		a := d.Addressable
+		if a.Address == "" {
+			groups = append(groups, []*models.Device{d})
+			continue
+		}
+		key := fmt.Sprintf("%s://%s:%d", strings.ToLower(a.Protocol), a.Address, a.Port)
+		if i, ok := index[key]; ok {
+			groups[i] = append(groups[i], d)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, []*models.Device{d})
+	}
+	return groups
+}
 
+func filterLabeledDevices(devices []*models.Device, label string) []*models.Device {
+	result := make([]*models.Device, 0, len(devices))
+	for _, d := range devices {
+		for _, l := range d.Labels {
+			if l == label {
+				result = append(result, d)
+				break
+			}
+		}
+	}
+	return result
 }
 
 func filterOperationalDevices(devices []models.Device) []*models.Device {
@@ -0,0 +1,67 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+var (
+	concurrencyMutex sync.Mutex
+	deviceLocks      = make(map[string]*deviceLock)
+	driverCalls      int
+)
+
+// deviceLock serializes the driver calls of a Device. It's discarded once
+// no driver call holds it or waits for it.
+type deviceLock struct {
+	sync.Mutex
+	refs int
+}
+
+// acquireDriver registers a driver call, unless MaxInFlightCommands are
+// already in flight, then waits for the driver calls of the Device in
+// progress, so that its requests don't interleave. The calls waiting for a
+// Device count as in flight, so that a slow Device can't queue requests
+// without bound. The returned function releases the call.
+func acquireDriver(deviceName string) (func(), common.AppError) {
+	concurrencyMutex.Lock()
+	info := common.CurrentConfig.Device
+	if info.MaxInFlightCommands > 0 && driverCalls >= info.MaxInFlightCommands {
+		concurrencyMutex.Unlock()
+		msg := fmt.Sprintf("Handler - command: %d driver calls in flight, Device %s busy", driverCalls, deviceName)
+		common.LoggingClient.Warn(msg)
+		retryAfter := info.BusyRetryAfter
+		if retryAfter <= 0 {
+			retryAfter = 1
+		}
+		return nil, common.NewBusyError(msg, retryAfter)
+	}
+	driverCalls++
+	lock, ok := deviceLocks[deviceName]
+	if !ok {
+		lock = &deviceLock{}
+		deviceLocks[deviceName] = lock
+	}
+	lock.refs++
+	concurrencyMutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		concurrencyMutex.Lock()
+		defer concurrencyMutex.Unlock()
+
+		driverCalls--
+		if lock.refs--; lock.refs == 0 {
+			delete(deviceLocks, deviceName)
+		}
+	}, nil
+}
@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func setMaxInFlight(max int, retryAfter int) func() {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.MaxInFlightCommands = max
+	common.CurrentConfig.Device.BusyRetryAfter = retryAfter
+	return func() { common.CurrentConfig = previous }
+}
+
+// acquireAsync acquires the driver for a Device in background, sending the
+// release function once acquired.
+func acquireAsync(t *testing.T, deviceName string) chan func() {
+	acquired := make(chan func(), 1)
+	go func() {
+		release, appErr := acquireDriver(deviceName)
+		if appErr != nil {
+			t.Error(appErr.Message())
+			close(acquired)
+			return
+		}
+		acquired <- release
+	}()
+	return acquired
+}
+
+func TestDriverCallsSerializedPerDevice(t *testing.T) {
+	defer setMaxInFlight(0, 0)()
+
+	release, appErr := acquireDriver("meter")
+	if appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	waiting := acquireAsync(t, "meter")
+	other := acquireAsync(t, "switch")
+
+	select {
+	case releaseOther := <-other:
+		releaseOther()
+	case <-time.After(time.Second):
+		t.Fatal("A call for another Device waited")
+	}
+	select {
+	case <-waiting:
+		t.Fatal("Two driver calls for the same Device at once")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case releaseWaiting := <-waiting:
+		releaseWaiting()
+	case <-time.After(time.Second):
+		t.Fatal("The waiting call wasn't run after the release")
+	}
+
+	concurrencyMutex.Lock()
+	defer concurrencyMutex.Unlock()
+	if len(deviceLocks) != 0 || driverCalls != 0 {
+		t.Errorf("%d locks and %d calls left after the releases", len(deviceLocks), driverCalls)
+	}
+}
+
+func TestDriverBusy(t *testing.T) {
+	defer setMaxInFlight(2, 7)()
+
+	release, appErr := acquireDriver("meter")
+	if appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	// a call waiting for the Device counts as in flight
+	waiting := acquireAsync(t, "meter")
+	deadline := time.Now().Add(time.Second)
+	for {
+		concurrencyMutex.Lock()
+		calls := driverCalls
+		concurrencyMutex.Unlock()
+		if calls == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d calls in flight, expected 2", calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_, appErr = acquireDriver("switch")
+	if appErr == nil || appErr.Code() != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a busy error, got %v", appErr)
+	}
+	if re, ok := appErr.(common.RetryableError); !ok || re.RetryAfter() != 7 {
+		t.Errorf("Expected to retry after 7 s, got %v", appErr)
+	}
+
+	release()
+	(<-waiting)()
+
+	common.CurrentConfig.Device.MaxInFlightCommands = 1
+	common.CurrentConfig.Device.BusyRetryAfter = 0
+	release, _ = acquireDriver("meter")
+	defer release()
+	_, appErr = acquireDriver("meter")
+	if re, ok := appErr.(common.RetryableError); !ok || re.RetryAfter() != 1 {
+		t.Errorf("Expected to retry after 1 s by default, got %v", appErr)
+	}
+}
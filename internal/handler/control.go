@@ -10,10 +10,24 @@ import (
 	"fmt"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/discovery"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 )
 
-func DiscoveryHandler(requestMap map[string]string) {
+// DiscoveryHandler triggers the discovery of the driver, which runs
+// asynchronously, the Devices found being provisioned as they are returned.
+func DiscoveryHandler(requestMap map[string]string) common.AppError {
 	common.LoggingClient.Info(fmt.Sprintf("service: discovery request"))
+	if err := discovery.Trigger(); err != nil {
+		common.LoggingClient.Error(err.Error())
+		return common.NewNotImplementedError(err.Error(), err)
+	}
+	return nil
+}
+
+// DiscoveredDevicesHandler returns the Devices found by the last discovery.
+func DiscoveredDevicesHandler() []ds_models.DiscoveredDevice {
+	return discovery.Devices()
 }
 
 func TransformHandler(requestMap map[string]string) (map[string]string, common.AppError) {
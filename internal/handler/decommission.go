@@ -0,0 +1,195 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/anomaly"
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+	"github.com/edgexfoundry/device-sdk-go/internal/job"
+	"github.com/edgexfoundry/device-sdk-go/internal/metrics"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DecommissionReport describes the decommissioning of a Device.
+type DecommissionReport struct {
+	Device string `json:"device"`
+	// AutoEvents are the names of the Schedule Events removed.
+	AutoEvents []string `json:"autoEvents"`
+	// CanceledJobs are the ids of the jobs canceled.
+	CanceledJobs []string `json:"canceledJobs"`
+	// History is the command history of the Device, also exported to
+	// HistoryFile, relative to the state directory, if configured.
+	History     []history.Record `json:"history"`
+	HistoryFile string           `json:"historyFile,omitempty"`
+}
+
+// DecommissionHandler decommissions the Device specified by name: it's
+// marked as locked and disabled in Core Metadata before being removed, then
+// its auto events are stopped, its pending commands flushed, the driver
+// disconnects it and its command history is exported. Nothing is released
+// unless Core Metadata removed it, so that a failed decommissioning can be
+// retried.
+func DecommissionHandler(vars map[string]string) (DecommissionReport, common.AppError) {
+	device, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return DecommissionReport{}, appErr
+	}
+	common.LoggingClient.Info(fmt.Sprintf("Handler - Decommission: decommissioning Device %s", device.Name))
+
+	removeMutex.Lock()
+	if decommissioning[device.Name] {
+		removeMutex.Unlock()
+		msg := fmt.Sprintf("Handler - Decommission: Device %s is already being decommissioned", device.Name)
+		common.LoggingClient.Error(msg)
+		return DecommissionReport{}, common.NewLockedError(msg, nil)
+	}
+	decommissioning[device.Name] = true
+	removeMutex.Unlock()
+	defer func() {
+		removeMutex.Lock()
+		delete(decommissioning, device.Name)
+		removeMutex.Unlock()
+	}()
+
+	// locking the Device first rejects new commands meanwhile
+	err := common.DeviceClient.UpdateAdminStateByName(device.Name, models.Locked)
+	if err != nil {
+		msg := fmt.Sprintf("Handler - Decommission: locking Device %s failed: %v", device.Name, err)
+		common.LoggingClient.Error(msg)
+		return DecommissionReport{}, common.NewServerError(msg, err)
+	}
+	device.AdminState = models.Locked
+	cache.Devices().Update(device)
+
+	err = common.DeviceClient.UpdateOpStateByName(device.Name, models.Disabled)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - Decommission: disabling Device %s failed: %v", device.Name, err))
+	}
+	err = common.DeviceClient.DeleteByName(device.Name)
+	if err != nil {
+		// the Device stays locked, so that decommissioning can be retried
+		msg := fmt.Sprintf("Handler - Decommission: removing Device %s from Core Metadata failed: %v", device.Name, err)
+		common.LoggingClient.Error(msg)
+		return DecommissionReport{}, common.NewServerError(msg, err)
+	}
+
+	// the callback of Core Metadata leaves the release to the handler
+	removeMutex.Lock()
+	defer removeMutex.Unlock()
+	if _, ok := cache.Devices().ForName(device.Name); !ok || cache.Devices().RemoveByName(device.Name) != nil {
+		msg := fmt.Sprintf("Handler - Decommission: Device %s has already been removed", device.Name)
+		common.LoggingClient.Error(msg)
+		return DecommissionReport{}, common.NewNotFoundError(msg, nil)
+	}
+	report := releaseDevice(device)
+
+	common.LoggingClient.Info(fmt.Sprintf("Handler - Decommission: Device %s decommissioned", device.Name))
+	return report, nil
+}
+
+var (
+	// removeMutex makes the removal of a Device from the cache and the
+	// release of its resources happen once, whichever of the DS or the
+	// callback of Core Metadata removes it first.
+	removeMutex sync.Mutex
+	// decommissioning are the names of the Devices being decommissioned,
+	// released by DecommissionHandler rather than by the callback.
+	decommissioning = make(map[string]bool)
+)
+
+// RemoveCachedDevice removes the Device specified by name from the cache
+// and releases its resources, unless already done or the Device is being
+// decommissioned. It returns false if the Device wasn't removed.
+func RemoveCachedDevice(name string) (DecommissionReport, bool) {
+	removeMutex.Lock()
+	defer removeMutex.Unlock()
+
+	if decommissioning[name] {
+		return DecommissionReport{}, false
+	}
+	device, ok := cache.Devices().ForName(name)
+	if !ok || cache.Devices().RemoveByName(name) != nil {
+		return DecommissionReport{}, false
+	}
+	return releaseDevice(device), true
+}
+
+// releaseDevice releases the resources of a Device which is being removed,
+// and returns what was released.
+func releaseDevice(device models.Device) DecommissionReport {
+	report := DecommissionReport{Device: device.Name}
+	report.AutoEvents = provision.RemoveScheduleEventsForDevice(device.Name)
+	StopAutoEvents(device.Name)
+
+	removeSelections(device.Name)
+	forgetActivity(device.Name)
+	for _, s := range job.ForDevice(device.Name) {
+		if s.State == job.StateRunning || s.State == job.StateSuspended {
+			if err := job.Cancel(s.ID); err == nil {
+				report.CanceledJobs = append(report.CanceledJobs, s.ID)
+			}
+		}
+	}
+
+	addr, err := common.ResolveAddressable(device.Name, device.Addressable)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - Decommission: credentials of Device %s: %v", device.Name, err))
+	}
+	if err = common.Driver.DisconnectDevice(&addr); err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - Decommission: disconnecting Device %s failed: %v", device.Name, err))
+	}
+	common.NotifyDeviceRemoved(device)
+
+	report.History = history.ForDevice(device.Name)
+	if dir := common.CurrentConfig.Device.DecommissionDir; dir != "" && len(report.History) > 0 {
+		// the name is escaped so that the file can't be outside dir
+		file := filepath.Join(dir, url.PathEscape(device.Name)+".json")
+		if err := exportHistory(file, report.History); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Handler - Decommission: exporting the history of Device %s failed: %v", device.Name, err))
+		} else {
+			report.HistoryFile = file
+		}
+	}
+	history.Remove(device.Name)
+	history.Save()
+	opstate.Remove(device.Name)
+	opstate.Save()
+	cache.Readings().RemoveDevice(device.Name)
+	anomaly.RemoveDevice(device.Name)
+	transformer.ForgetAssertions(device.Name)
+	metrics.RemoveDevice(device.Name)
+
+	return report
+}
+
+// exportHistory writes the records as plain JSON, to be collected by
+// external tools.
+func exportHistory(file string, records []history.Record) error {
+	contents, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := statedir.Path(file)
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, contents, 0644)
+}
@@ -0,0 +1,170 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// testDriver is a ProtocolDriver whose reads are handled by read, if set,
+// recording the Devices disconnected.
+type testDriver struct {
+	mutex        sync.Mutex
+	read         func(reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error)
+	reads        int
+	disconnected []string
+}
+
+func (d *testDriver) DisconnectDevice(address *models.Addressable) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.disconnected = append(d.disconnected, address.Address)
+	return nil
+}
+
+func (d *testDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return nil
+}
+
+func (d *testDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	d.mutex.Lock()
+	d.reads++
+	read := d.read
+	d.mutex.Unlock()
+
+	if read == nil {
+		return nil, errors.New("no reads")
+	}
+	return read(reqs)
+}
+
+func (d *testDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	return nil
+}
+
+func (d *testDriver) Stop(force bool) error {
+	return nil
+}
+
+// decommissionClient records the Devices deleted from Core Metadata,
+// failing the deletions if err is set.
+type decommissionClient struct {
+	mock.DeviceClientMock
+	deleted []string
+	err     error
+}
+
+func (c *decommissionClient) DeleteByName(name string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.deleted = append(c.deleted, name)
+	return nil
+}
+
+func TestDecommissionHandler(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousClient, previousDriver := common.CurrentConfig, common.DeviceClient, common.Driver
+	defer func() {
+		common.CurrentConfig, common.DeviceClient, common.Driver = previousConfig, previousClient, previousDriver
+	}()
+	dir, err := ioutil.TempDir("", "handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.DecommissionDir = dir
+	client := &decommissionClient{err: errors.New("metadata unavailable")}
+	common.DeviceClient = client
+	driver := &testDriver{}
+	common.Driver = driver
+	initCache(t)
+
+	// the name of the Device can't take the exported history out of dir
+	name := "../rtu"
+	if _, ok := cache.Devices().ForName(name); !ok {
+		cache.Devices().Add(models.Device{Id: bson.NewObjectId(), Name: name, AdminState: models.Unlocked, Addressable: models.Addressable{Address: "rtu"}})
+	}
+	if err = history.Init(10, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer history.Init(0, "")
+	history.Add(name, history.Record{Method: "get", Command: "Voltage", Result: "OK"})
+	vars := map[string]string{"name": name}
+
+	// nothing is released unless Core Metadata removed the Device
+	if _, appErr := DecommissionHandler(vars); appErr == nil || appErr.Code() != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %v", http.StatusInternalServerError, appErr)
+	}
+	d, ok := cache.Devices().ForName(name)
+	if !ok || d.AdminState != models.Locked {
+		t.Fatalf("Device not kept locked after the failure: %v", d)
+	}
+	if len(driver.disconnected) != 0 || len(history.ForDevice(name)) != 1 {
+		t.Errorf("Device released after the failure: disconnected %v", driver.disconnected)
+	}
+
+	client.err = nil
+	report, appErr := DecommissionHandler(vars)
+	if appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	if _, ok = cache.Devices().ForName(name); ok {
+		t.Error("Device still cached")
+	}
+	if len(client.deleted) != 1 || len(driver.disconnected) != 1 || driver.disconnected[0] != "rtu" {
+		t.Errorf("Deleted %v, disconnected %v", client.deleted, driver.disconnected)
+	}
+	if len(report.History) != 1 || filepath.Dir(report.HistoryFile) != dir {
+		t.Errorf("History %v exported to %s, expected in %s", report.History, report.HistoryFile, dir)
+	}
+	if _, err = os.Stat(report.HistoryFile); err != nil {
+		t.Error(err)
+	}
+
+	if _, appErr = DecommissionHandler(vars); appErr == nil || appErr.Code() != http.StatusNotFound {
+		t.Errorf("Expected status %d decommissioning again, got %v", http.StatusNotFound, appErr)
+	}
+}
+
+func TestRemoveCachedDeviceWhileDecommissioning(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	initCache(t)
+
+	// the callback of Core Metadata leaves the release to the handler
+	removeMutex.Lock()
+	decommissioning["meter"] = true
+	removeMutex.Unlock()
+	defer func() {
+		removeMutex.Lock()
+		delete(decommissioning, "meter")
+		removeMutex.Unlock()
+	}()
+	if _, removed := RemoveCachedDevice("meter"); removed {
+		t.Fatal("Device being decommissioned removed by the callback")
+	}
+	if _, ok := cache.Devices().ForName("meter"); !ok {
+		t.Error("Device being decommissioned removed from the cache")
+	}
+}
@@ -0,0 +1,124 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/async"
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// DrainStatus describes the progress of the drain mode, in which the DS
+// finishes the in-flight commands but doesn't accept new ones, so that it
+// can be stopped without interrupting a transaction with a Device.
+type DrainStatus struct {
+	// Draining is true if the DS is in drain mode.
+	Draining bool `json:"draining"`
+	// InFlight is the number of commands being executed.
+	InFlight int `json:"inFlight"`
+	// Queued is the number of asynchronous readings not pushed yet.
+	Queued int `json:"queued"`
+	// ReadyToStop is true once the DS is draining and idle.
+	ReadyToStop bool `json:"readyToStop"`
+}
+
+var (
+	drainMutex sync.Mutex
+	draining   bool
+	inFlight   int
+	asyncCh    chan *ds_models.AsyncValues
+)
+
+// SetAsyncChannel sets the channel of the asynchronous readings, which must
+// be empty before the DS is ready to stop.
+func SetAsyncChannel(ch chan *ds_models.AsyncValues) {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	asyncCh = ch
+}
+
+// DrainHandler puts the DS in drain mode: new commands and Schedule Events
+// are rejected, and the command history and caches are persisted.
+func DrainHandler() DrainStatus {
+	drainMutex.Lock()
+	wasDraining := draining
+	draining = true
+	drainMutex.Unlock()
+
+	if !wasDraining {
+		common.LoggingClient.Info("Handler - Drain: entering drain mode")
+		if err := history.Save(); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Handler - Drain: couldn't save the command history: %v", err))
+		}
+		if err := opstate.Save(); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Handler - Drain: couldn't save the operating state history: %v", err))
+		}
+		cache.Persist()
+	}
+	// the readings batched so far won't be followed by others
+	async.Flush()
+	return DrainStatusHandler()
+}
+
+// ResumeHandler leaves the drain mode, e.g. if an upgrade has been aborted.
+func ResumeHandler() DrainStatus {
+	drainMutex.Lock()
+	if draining {
+		common.LoggingClient.Info("Handler - Drain: leaving drain mode")
+	}
+	draining = false
+	drainMutex.Unlock()
+
+	return DrainStatusHandler()
+}
+
+// DrainStatusHandler returns the progress of the drain mode.
+func DrainStatusHandler() DrainStatus {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	status := DrainStatus{Draining: draining, InFlight: inFlight, Queued: len(asyncCh) + async.Pending()}
+	status.ReadyToStop = draining && inFlight == 0 && status.Queued == 0
+	return status
+}
+
+// Draining returns true if the DS is in drain mode.
+func Draining() bool {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	return draining
+}
+
+// beginCommand registers an in-flight command, unless the DS is draining.
+func beginCommand() common.AppError {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	if draining {
+		msg := i18n.T(i18n.ServiceDraining)
+		common.LoggingClient.Warn(msg)
+		return common.NewServiceUnavailableError(msg, nil)
+	}
+	inFlight++
+	return nil
+}
+
+func endCommand() {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	inFlight--
+}
@@ -0,0 +1,65 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func TestDrain(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+	ch := make(chan *ds_models.AsyncValues, 1)
+	SetAsyncChannel(ch)
+	defer SetAsyncChannel(nil)
+	defer ResumeHandler()
+
+	expect := func(status DrainStatus, expected DrainStatus) {
+		t.Helper()
+		if status != expected {
+			t.Errorf("Drain status %+v, expected %+v", status, expected)
+		}
+	}
+
+	expect(DrainStatusHandler(), DrainStatus{})
+	if appErr := beginCommand(); appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	expect(DrainStatusHandler(), DrainStatus{InFlight: 1})
+
+	// the command in flight is finished, but no other is accepted
+	expect(DrainHandler(), DrainStatus{Draining: true, InFlight: 1})
+	if !Draining() {
+		t.Error("Not draining after the drain")
+	}
+	if appErr := beginCommand(); appErr == nil || appErr.Code() != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d for a command while draining, got %v", http.StatusServiceUnavailable, appErr)
+	}
+	expect(DrainStatusHandler(), DrainStatus{Draining: true, InFlight: 1})
+	endCommand()
+	expect(DrainStatusHandler(), DrainStatus{Draining: true, ReadyToStop: true})
+
+	// nor until the asynchronous readings are pushed
+	ch <- &ds_models.AsyncValues{DeviceName: "bay1"}
+	expect(DrainStatusHandler(), DrainStatus{Draining: true, Queued: 1})
+	<-ch
+	expect(DrainHandler(), DrainStatus{Draining: true, ReadyToStop: true})
+
+	expect(ResumeHandler(), DrainStatus{})
+	if appErr := beginCommand(); appErr != nil {
+		t.Fatalf("Command rejected after resuming: %s", appErr.Message())
+	}
+	endCommand()
+	expect(DrainStatusHandler(), DrainStatus{})
+}
@@ -0,0 +1,38 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/federation"
+)
+
+// FederationHandler returns the Devices of the DS and their profiles, for
+// the instances federating it.
+func FederationHandler() federation.Inventory {
+	return federation.LocalInventory()
+}
+
+// peerError returns the AppError of a command forwarded to a peer, keeping
+// the client errors of the peer, e.g. an unknown command.
+func peerError(msg string, err error) common.AppError {
+	if pe, ok := err.(federation.PeerError); ok {
+		switch pe.StatusCode {
+		case http.StatusBadRequest:
+			return common.NewBadRequestError(msg, err)
+		case http.StatusNotFound:
+			return common.NewNotFoundError(msg, err)
+		case http.StatusLocked:
+			return common.NewLockedError(msg, err)
+		case http.StatusServiceUnavailable:
+			return common.NewServiceUnavailableError(msg, err)
+		}
+	}
+	return common.NewServerError(msg, err)
+}
@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"strconv"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// commandGroup returns the command group of the configuration named cmd
+// applying to a Device, those of the Device itself taking precedence over
+// those of its profile.
+func commandGroup(device *models.Device, cmd string) (common.CommandGroupInfo, bool) {
+	var match common.CommandGroupInfo
+	found := false
+	for _, g := range common.CurrentConfig.CommandGroups {
+		if g.Name != cmd {
+			continue
+		}
+		if g.Device != "" && g.Device == device.Name {
+			return g, true
+		}
+		if !found && g.Device == "" && g.Profile == device.Profile.Name {
+			match, found = g, true
+		}
+	}
+	return match, found
+}
+
+// readOperations returns the resource operations of a read command, either
+// a command of the profile of the Device or a command group, the commands
+// of the profile taking precedence.
+func readOperations(device *models.Device, cmd string) ([]models.ResourceOperation, error) {
+	ros, err := cache.Profiles().ResourceOperations(device.Profile.Name, cmd, "get")
+	if err == nil {
+		return ros, nil
+	}
+	g, ok := commandGroup(device, cmd)
+	if !ok {
+		return nil, err
+	}
+	ros = make([]models.ResourceOperation, len(g.Resources))
+	for i, r := range g.Resources {
+		ros[i] = models.ResourceOperation{Index: strconv.Itoa(i + 1), Operation: "get", Object: r, Parameter: r}
+	}
+	return ros, nil
+}
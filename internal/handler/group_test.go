@@ -0,0 +1,107 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestCommandGroups(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousDriver := common.CurrentConfig, common.Driver
+	defer func() { common.CurrentConfig, common.Driver = previousConfig, previousDriver }()
+	common.CurrentConfig = &common.Config{CommandGroups: []common.CommandGroupInfo{
+		{Name: "Status", Profile: "Switchgear", Resources: []string{"Breaker", "Earthing"}},
+		{Name: "Status", Device: "bay1", Resources: []string{"Earthing"}},
+		{Name: "Breaker", Profile: "Switchgear", Resources: []string{"Earthing"}},
+		{Name: "Broken", Profile: "Switchgear", Resources: []string{"Breaker", "Missing"}},
+	}}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	initCache(t)
+	bay1, _ := cache.Devices().ForName("bay1")
+	meter, _ := cache.Devices().ForName("meter")
+	bay2 := models.Device{Id: bson.NewObjectId(), Name: "bay2", Profile: bay1.Profile, AdminState: models.Unlocked}
+	cache.Devices().Add(bay2)
+	defer cache.Devices().Remove(bay2.Id.Hex())
+
+	tests := []struct {
+		name    string
+		device  *models.Device
+		cmd     string
+		objects []string
+	}{
+		{"ProfileGroup", &bay2, "Status", []string{"Breaker", "Earthing"}},
+		{"DeviceGroup", &bay1, "Status", []string{"Earthing"}},
+		{"ProfileResourceFirst", &bay2, "Breaker", []string{"Breaker"}},
+		{"OtherProfile", &meter, "Status", nil},
+		{"Unknown", &bay2, "Voltage", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ros, err := readOperations(tt.device, tt.cmd)
+			if tt.objects == nil {
+				if err == nil {
+					t.Errorf("Operations %v of an unknown command", ros)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var objects []string
+			for _, ro := range ros {
+				objects = append(objects, ro.Object)
+			}
+			if !reflect.DeepEqual(objects, tt.objects) {
+				t.Errorf("Operations on %v, expected %v", objects, tt.objects)
+			}
+		})
+	}
+
+	var failed string
+	driver := &testDriver{read: func(reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+		cvs := make([]*ds_models.CommandValue, len(reqs))
+		for i, req := range reqs {
+			if req.DeviceObject.Name == failed {
+				return nil, errors.New("no response from " + failed)
+			}
+			cvs[i], _ = ds_models.NewBoolValue(&reqs[i].RO, 0, true)
+		}
+		return cvs, nil
+	}}
+	common.Driver = driver
+
+	readings, appErr := readCmd(&bay2, "Status")
+	if appErr != nil || len(readings) != 2 || readings[0].Name != "Breaker" || readings[1].Name != "Earthing" {
+		t.Fatalf("Readings %v of the group, error %v", readings, appErr)
+	}
+
+	// the group is read in a single transaction, failing as a whole
+	failed = "Earthing"
+	if readings, appErr = readCmd(&bay2, "Status"); appErr == nil {
+		t.Errorf("Readings %v of a group with a failed resource", readings)
+	}
+
+	// a group with an unknown resource isn't read at all
+	driver.reads = 0
+	if readings, appErr = readCmd(&bay2, "Broken"); appErr == nil || appErr.Code() != http.StatusInternalServerError {
+		t.Errorf("Expected status %d for a group with an unknown resource, got %v", http.StatusInternalServerError, appErr)
+	}
+	if driver.reads != 0 {
+		t.Errorf("Group with an unknown resource read")
+	}
+}
@@ -0,0 +1,157 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/anomaly"
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/clients"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/discovery"
+	"github.com/edgexfoundry/device-sdk-go/internal/federation"
+	"github.com/edgexfoundry/device-sdk-go/internal/metrics"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Health describes the state of the DS.
+type Health struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Draining bool   `json:"draining"`
+	Standby  bool   `json:"standby"`
+	// Peers are the connections of the Driver to its peers (e.g. the TCP
+	// servers of its Devices), if it implements PeerReporter.
+	Peers []ds_models.PeerStatus `json:"peers,omitempty"`
+	// Leads are the election groups (Devices or labels) led by the DS, if
+	// leader election is enabled.
+	Leads []string `json:"leads,omitempty"`
+	// Metrics are the metrics registered by the Driver.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+	// Ready is false if a dependency is unreachable, the endpoint then
+	// responding with 503 for readiness probes.
+	Ready bool `json:"ready"`
+	// Dependencies are the reachability of Core Metadata and Core Data.
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+	// Devices is the number of cached Devices per operating state.
+	Devices   map[string]int  `json:"devices"`
+	Scheduler SchedulerStatus `json:"scheduler"`
+	// AsyncQueued is the number of asynchronous readings not pushed yet.
+	AsyncQueued int `json:"asyncQueued"`
+	// Anomalies counts the anomalous readings.
+	Anomalies anomaly.Stats `json:"anomalies"`
+	// Federation is the synchronization with the federated instances, by
+	// name.
+	Federation map[string]federation.PeerStatus `json:"federation,omitempty"`
+	// Discovery is the status of the discoveries of the driver.
+	Discovery discovery.Status `json:"discovery"`
+
+	SDKAPIVersion    string `json:"sdkApiVersion"`
+	DriverAPIVersion string `json:"driverApiVersion,omitempty"`
+	// StartStatus is the start mode performed and whether the caches are
+	// reconciled with Core Metadata since.
+	common.StartStatus
+	// Caches are the sizes, limits and hit counts of the caches, by name.
+	Caches map[string]cache.Stats `json:"caches"`
+	// Throttle is the throttling of the Schedule Events under CPU or memory
+	// pressure.
+	Throttle throttle.Status `json:"throttle"`
+}
+
+// DependencyStatus describes the reachability of a service the DS depends on.
+type DependencyStatus struct {
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SchedulerStatus describes the internal Scheduler.
+type SchedulerStatus struct {
+	Running  bool      `json:"running"`
+	Jobs     int       `json:"jobs"`
+	LastTick time.Time `json:"lastTick,omitempty"`
+}
+
+// CurrentSchedulerStatus returns the status of the internal Scheduler. It's
+// set by the DS, as the scheduler package depends on this one.
+var CurrentSchedulerStatus = func() SchedulerStatus { return SchedulerStatus{} }
+
+// HealthHandler returns the state of the DS.
+func HealthHandler() Health {
+	var peers []ds_models.PeerStatus
+	if reporter, ok := common.Driver.(ds_models.PeerReporter); ok {
+		peers = reporter.Peers()
+	}
+	deps := dependencies()
+	return Health{
+		Name:             common.ServiceName,
+		Version:          common.ServiceVersion,
+		Draining:         Draining(),
+		Standby:          common.InStandby(),
+		Peers:            peers,
+		Leads:            common.LeadElectionGroups(),
+		Metrics:          metrics.Values(),
+		Ready:            ready(deps),
+		Dependencies:     deps,
+		Devices:          devicesByOperatingState(),
+		Scheduler:        CurrentSchedulerStatus(),
+		AsyncQueued:      DrainStatusHandler().Queued,
+		Anomalies:        anomaly.CurrentStats(),
+		Federation:       federation.Status(),
+		Discovery:        discovery.CurrentStatus(),
+		SDKAPIVersion:    ds_models.APIVersion,
+		DriverAPIVersion: common.DriverAPIVersion,
+		StartStatus:      common.CurrentStartStatus(),
+		Caches:           cache.Metrics(),
+		Throttle:         throttle.CurrentStatus(),
+	}
+}
+
+// ready returns whether all the dependencies are reachable.
+func ready(deps map[string]DependencyStatus) bool {
+	for _, d := range deps {
+		if !d.Reachable {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencies pings Core Metadata and Core Data concurrently.
+func dependencies() map[string]DependencyStatus {
+	ids := []string{common.ClientMetadata, common.ClientData}
+	statuses := make([]DependencyStatus, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			if err := clients.Ping(id); err != nil {
+				statuses[i].Error = err.Error()
+			} else {
+				statuses[i].Reachable = true
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	deps := make(map[string]DependencyStatus, len(ids))
+	for i, id := range ids {
+		deps[id] = statuses[i]
+	}
+	return deps
+}
+
+func devicesByOperatingState() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range cache.Devices().All() {
+		counts[string(d.OperatingState)]++
+	}
+	return counts
+}
@@ -0,0 +1,72 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func clientInfo(t *testing.T, server *httptest.Server) common.ClientInfo {
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return common.ClientInfo{Protocol: u.Scheme, Host: u.Hostname(), Port: port, Timeout: 1000}
+}
+
+func TestReadiness(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+
+	pong := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	defer pong.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	tests := []struct {
+		name     string
+		metadata *httptest.Server
+		data     *httptest.Server
+		ready    bool
+	}{
+		{"Reachable", pong, pong, true},
+		{"MetadataUnreachable", down, pong, false},
+		{"DataUnreachable", pong, down, false},
+		{"Unreachable", down, down, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.CurrentConfig = &common.Config{Clients: map[string]common.ClientInfo{
+				common.ClientMetadata: clientInfo(t, tt.metadata),
+				common.ClientData:     clientInfo(t, tt.data),
+			}}
+			deps := dependencies()
+			if ready(deps) != tt.ready {
+				t.Errorf("Ready %v with the dependencies %v", !tt.ready, deps)
+			}
+			if len(deps) != 2 || deps[common.ClientMetadata].Reachable != (tt.metadata == pong) || deps[common.ClientData].Reachable != (tt.data == pong) {
+				t.Errorf("Dependencies %v", deps)
+			}
+			for id, d := range deps {
+				if d.Reachable == (d.Error != "") {
+					t.Errorf("Dependency %s reachable %v with the error %q", id, d.Reachable, d.Error)
+				}
+			}
+		})
+	}
+}
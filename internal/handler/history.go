@@ -0,0 +1,35 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+)
+
+// HistoryHandler returns the recent command history of the Device
+// specified by name.
+func HistoryHandler(vars map[string]string) ([]history.Record, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return history.ForDevice(d.Name), nil
+}
+
+// HistoryShadowed returns whether the Device specified by name has a command
+// named history, which is run at the path of its history: the history then
+// stays available under _sdk only.
+func HistoryShadowed(vars map[string]string) bool {
+	d, ok := cache.Devices().ForName(vars["name"])
+	if !ok {
+		return false
+	}
+	exists, _ := cache.Profiles().CommandExists(d.Profile.Name, "history")
+	return exists
+}
@@ -0,0 +1,43 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestHistoryShadowed(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	initCache(t)
+
+	profile := models.DeviceProfile{Name: "Recorder", Commands: []models.Command{{Name: "history"}}}
+	if _, ok := cache.Profiles().ForName(profile.Name); !ok {
+		cache.Profiles().Add(profile)
+		cache.Devices().Add(models.Device{Id: bson.NewObjectId(), Name: "recorder", Profile: profile, AdminState: models.Unlocked})
+	}
+
+	tests := []struct {
+		name     string
+		shadowed bool
+	}{
+		{"recorder", true},
+		{"bay1", false},
+		{"meter", false},
+		{"unknown", false},
+	}
+	for _, tt := range tests {
+		if shadowed := HistoryShadowed(map[string]string{"name": tt.name}); shadowed != tt.shadowed {
+			t.Errorf("History of %s shadowed: %t, expected %t", tt.name, shadowed, tt.shadowed)
+		}
+	}
+}
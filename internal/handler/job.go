@@ -0,0 +1,44 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/job"
+)
+
+// DeviceJobsHandler returns the status of the jobs of the Device specified
+// by name.
+func DeviceJobsHandler(vars map[string]string) ([]job.Status, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return job.ForDevice(d.Name), nil
+}
+
+// JobHandler returns the status of the job specified by id, after canceling
+// it for a DELETE request.
+func JobHandler(vars map[string]string, method string) (job.Status, common.AppError) {
+	id := vars["jobid"]
+	if method == http.MethodDelete {
+		if err := job.Cancel(id); err == nil {
+			common.LoggingClient.Info(fmt.Sprintf("Job %s canceled", id))
+		}
+	}
+
+	s, ok := job.ForID(id)
+	if !ok {
+		msg := fmt.Sprintf("Job: %s not found", id)
+		common.LoggingClient.Debug(msg)
+		return s, common.NewNotFoundError(msg, nil)
+	}
+	return s, nil
+}
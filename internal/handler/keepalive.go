@@ -0,0 +1,132 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// keepaliveTick is the period at which the idle time of the Devices is
+// checked against their keepalive interval.
+const keepaliveTick = time.Second
+
+var (
+	activityMutex sync.Mutex
+	lastActivity  = make(map[string]time.Time)
+	keepaliveDone chan struct{}
+)
+
+// StartKeepalives starts issuing the keepalive reads of the Devices
+// configured in Keepalives, when they have been idle for their interval,
+// so that the sessions of NAT or serial gateways in front of them aren't
+// dropped between sparse reads.
+func StartKeepalives() {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	if keepaliveDone != nil {
+		return
+	}
+	keepaliveDone = make(chan struct{})
+	go runKeepalives(keepaliveDone)
+}
+
+// StopKeepalives stops the keepalive reads.
+func StopKeepalives() {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	if keepaliveDone != nil {
+		close(keepaliveDone)
+		keepaliveDone = nil
+	}
+}
+
+// markActive records a transaction with a Device.
+func markActive(deviceName string) {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	lastActivity[deviceName] = time.Now()
+}
+
+// forgetActivity stops tracking a Device, removed or renamed.
+func forgetActivity(deviceName string) {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	delete(lastActivity, deviceName)
+}
+
+// idleSince returns the time of the last transaction with a Device, and
+// starts tracking it if it's unknown.
+func idleSince(deviceName string) time.Time {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	t, ok := lastActivity[deviceName]
+	if !ok {
+		t = time.Now()
+		lastActivity[deviceName] = t
+	}
+	return t
+}
+
+func runKeepalives(done <-chan struct{}) {
+	ticker := time.NewTicker(keepaliveTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			checkKeepalives(now)
+		}
+	}
+}
+
+// checkKeepalives issues the keepalive reads of the Devices idle for their
+// interval at the time now.
+func checkKeepalives(now time.Time) {
+	// the configuration is read at each tick to follow hot restarts
+	for name, ka := range common.CurrentConfig.Keepalives {
+		interval := time.Duration(ka.Interval) * time.Millisecond
+		if interval <= 0 || now.Sub(idleSince(name)) < interval {
+			continue
+		}
+		keepalive(name, ka.Command)
+	}
+}
+
+// keepalive issues the keepalive read of a Device. Its readings are
+// cached, but not pushed to Core Data.
+func keepalive(deviceName string, cmd string) {
+	if Draining() {
+		return
+	}
+	device, ok := cache.Devices().ForName(deviceName)
+	if !ok || device.AdminState == models.Locked || device.OperatingState == models.Disabled || !common.IsLeader(device) {
+		return
+	}
+	if appErr := beginCommand(); appErr != nil {
+		return
+	}
+	defer endCommand()
+
+	markActive(deviceName)
+	if _, appErr := readCmd(&device, cmd); appErr != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - Keepalive: read of %s for Device %s failed: %s", cmd, deviceName, appErr.Message()))
+		return
+	}
+	common.LoggingClient.Debug(fmt.Sprintf("Handler - Keepalive: read %s for Device %s", cmd, deviceName))
+}
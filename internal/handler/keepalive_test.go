@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func tracked(deviceName string) bool {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	_, ok := lastActivity[deviceName]
+	return ok
+}
+
+func TestKeepalives(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousDriver := common.CurrentConfig, common.Driver
+	defer func() { common.CurrentConfig, common.Driver = previousConfig, previousDriver }()
+	common.CurrentConfig = &common.Config{Keepalives: map[string]common.KeepaliveInfo{
+		"bay1": {Command: "Breaker", Interval: 1000},
+	}}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	driver := &testDriver{}
+	common.Driver = driver
+	initCache(t)
+	defer forgetActivity("bay1")
+
+	reads := func() int {
+		driver.mutex.Lock()
+		defer driver.mutex.Unlock()
+		return driver.reads
+	}
+
+	markActive("bay1")
+	now := time.Now()
+	checkKeepalives(now.Add(500 * time.Millisecond))
+	if n := reads(); n != 0 {
+		t.Fatalf("%d keepalive reads of an active Device", n)
+	}
+
+	// the failure of the read doesn't matter, the transaction took place
+	checkKeepalives(now.Add(time.Second))
+	if n := reads(); n != 1 {
+		t.Fatalf("%d keepalive reads of an idle Device", n)
+	}
+	if idle := idleSince("bay1"); idle.Before(now) {
+		t.Errorf("Keepalive not recorded as activity: idle since %v", idle)
+	}
+
+	DrainHandler()
+	checkKeepalives(now.Add(time.Hour))
+	ResumeHandler()
+	if n := reads(); n != 1 {
+		t.Errorf("%d keepalive reads while draining", n)
+	}
+
+	checkKeepalives(now.Add(time.Hour))
+	if n := reads(); n != 2 {
+		t.Errorf("%d keepalive reads after resuming", n)
+	}
+}
+
+// renameClient returns the Devices of Core Metadata renamed to name.
+type renameClient struct {
+	mock.DeviceClientMock
+	name string
+}
+
+func (c *renameClient) Device(id string) (models.Device, error) {
+	return models.Device{Id: bson.ObjectIdHex(id), Name: c.name, AdminState: models.Unlocked}, nil
+}
+
+func TestForgetActivity(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousClient, previousDriver := common.CurrentConfig, common.DeviceClient, common.Driver
+	defer func() {
+		common.CurrentConfig, common.DeviceClient, common.Driver = previousConfig, previousClient, previousDriver
+	}()
+	common.CurrentConfig = &common.Config{}
+	common.Driver = &testDriver{}
+	initCache(t)
+
+	id := bson.NewObjectId()
+	cache.Devices().Add(models.Device{Id: id, Name: "pump", AdminState: models.Unlocked})
+	defer cache.Devices().Remove(id.Hex())
+	markActive("pump")
+
+	common.DeviceClient = &renameClient{name: "pump-1"}
+	if appErr := handleDevice(http.MethodPut, id.Hex()); appErr != nil {
+		t.Fatal(appErr.Message())
+	}
+	if tracked("pump") {
+		t.Error("Activity of the former name of a renamed Device still tracked")
+	}
+
+	markActive("pump-1")
+	releaseDevice(models.Device{Id: id, Name: "pump-1"})
+	if tracked("pump-1") {
+		t.Error("Activity of a removed Device still tracked")
+	}
+}
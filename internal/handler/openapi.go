@@ -0,0 +1,276 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const openAPIVersion = "3.0.0"
+
+// OpenAPI is an OpenAPI document, limited to the elements describing the
+// device commands and the routes of the SDK.
+type OpenAPI struct {
+	OpenAPI string                                  `json:"openapi"`
+	Info    OpenAPIInfo                             `json:"info"`
+	Servers []OpenAPIServer                         `json:"servers"`
+	Paths   map[string]map[string]*OpenAPIOperation `json:"paths"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPISchema struct {
+	Type        string                    `json:"type"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Enum        []string                  `json:"enum,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+}
+
+// OpenAPIRoute is a route of the REST API, relative to the server URL, with
+// its path variables in braces. No Methods means any method.
+type OpenAPIRoute struct {
+	Path    string
+	Methods []string
+}
+
+// sdkTag is the tag of the operations of the routes of the SDK.
+const sdkTag = "sdk"
+
+// anyMethods are the methods documented for the routes accepting any.
+var anyMethods = []string{"get", "put", "post", "delete"}
+
+var pathVariable = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPIHandler returns an OpenAPI document describing the commands of the
+// devices in cache, derived from their profiles, and the given routes of the
+// REST API, for the given server URL.
+func OpenAPIHandler(serverURL string, routes []OpenAPIRoute) OpenAPI {
+	doc := OpenAPI{
+		OpenAPI: openAPIVersion,
+		Info:    OpenAPIInfo{Title: common.ServiceName, Version: common.ServiceVersion},
+		Servers: []OpenAPIServer{{URL: serverURL + common.APIRoute(common.APIv1Prefix)}},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	devices := cache.Devices().All()
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+	for _, device := range devices {
+		profile, ok := cache.Profiles().ForName(device.Profile.Name)
+		if !ok {
+			common.LoggingClient.Warn(fmt.Sprintf("Handler - OpenAPI: profile %s of device %s not in cache", device.Profile.Name, device.Name))
+			continue
+		}
+		for _, cmd := range profile.Commands {
+			ops := make(map[string]*OpenAPIOperation)
+			if cmd.Get != nil {
+				ops["get"] = readOperation(device.Name, profile.Name, cmd)
+			}
+			if cmd.Put != nil {
+				ops["put"] = writeOperation(device.Name, cmd)
+			}
+			if len(ops) > 0 {
+				doc.Paths[fmt.Sprintf("/device/name/%s/%s", device.Name, cmd.Name)] = ops
+			}
+		}
+	}
+
+	for _, route := range routes {
+		ops := doc.Paths[route.Path]
+		if ops == nil {
+			ops = make(map[string]*OpenAPIOperation)
+			doc.Paths[route.Path] = ops
+		}
+		methods := route.Methods
+		if len(methods) == 0 {
+			methods = anyMethods
+		}
+		for _, m := range methods {
+			ops[strings.ToLower(m)] = routeOperation(route.Path, m)
+		}
+	}
+	return doc
+}
+
+// routeOperation describes an operation of a route of the SDK.
+func routeOperation(path string, method string) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		Summary:   strings.ToUpper(method) + " " + path,
+		Tags:      []string{sdkTag},
+		Responses: map[string]OpenAPIResponse{"200": {Description: "OK"}},
+	}
+	for _, m := range pathVariable.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		})
+	}
+	return op
+}
+
+// readOperation describes a GET command, which responds with an event
+// holding a reading per resource of the command.
+func readOperation(deviceName string, profileName string, cmd models.Command) *OpenAPIOperation {
+	var names []string
+	ros, _ := cache.Profiles().ResourceOperations(profileName, cmd.Name, "get")
+	for _, ro := range ros {
+		names = append(names, ro.Object)
+	}
+
+	reading := &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"name":   {Type: "string", Enum: names},
+			"value":  {Type: "string"},
+			"origin": {Type: "integer", Format: "int64"},
+		},
+	}
+	event := &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"device":   {Type: "string"},
+			"origin":   {Type: "integer", Format: "int64"},
+			"readings": {Type: "array", Items: reading},
+		},
+	}
+	for _, name := range names {
+		reading.Description += fmt.Sprintf("%s: %s\n", name, parameterDescription(name))
+	}
+
+	return &OpenAPIOperation{
+		Summary:   fmt.Sprintf("Read %s from %s", cmd.Name, deviceName),
+		Tags:      []string{deviceName},
+		Responses: commandResponses(cmd.Get.Responses, event),
+	}
+}
+
+// writeOperation describes a PUT command, whose body is a list of objects
+// mapping parameter names to their values, as strings.
+func writeOperation(deviceName string, cmd models.Command) *OpenAPIOperation {
+	params := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	for _, name := range cmd.Put.ParameterNames {
+		params.Properties[name] = &OpenAPISchema{
+			Type:        "string",
+			Format:      parameterType(name),
+			Description: parameterDescription(name),
+		}
+	}
+
+	return &OpenAPIOperation{
+		Summary: fmt.Sprintf("Write %s to %s", cmd.Name, deviceName),
+		Tags:    []string{deviceName},
+		RequestBody: &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]OpenAPIMediaType{
+				"application/json": {Schema: &OpenAPISchema{Type: "array", Items: params}},
+			},
+		},
+		Responses: commandResponses(cmd.Put.Responses, nil),
+	}
+}
+
+// commandResponses returns the responses declared by the profile, the
+// successful one returning the given schema, if any.
+func commandResponses(declared []models.Response, schema *OpenAPISchema) map[string]OpenAPIResponse {
+	responses := make(map[string]OpenAPIResponse)
+	for _, r := range declared {
+		if r.Code == "" {
+			continue
+		}
+		responses[r.Code] = OpenAPIResponse{Description: r.Description}
+	}
+
+	ok := responses["200"]
+	if ok.Description == "" {
+		ok.Description = "OK"
+	}
+	if schema != nil {
+		ok.Content = map[string]OpenAPIMediaType{"application/json": {Schema: schema}}
+	}
+	responses["200"] = ok
+	return responses
+}
+
+// parameterType returns the value type of a parameter, from its value
+// descriptor.
+func parameterType(name string) string {
+	if vd, ok := cache.ValueDescriptors().ForName(name); ok {
+		return strings.ToLower(vd.Type)
+	}
+	return "string"
+}
+
+// parameterDescription describes the type, range and unit of a parameter,
+// from its value descriptor.
+func parameterDescription(name string) string {
+	vd, ok := cache.ValueDescriptors().ForName(name)
+	if !ok {
+		return ""
+	}
+
+	parts := []string{strings.ToLower(vd.Type)}
+	if vd.Min != nil && vd.Min != "" {
+		parts = append(parts, fmt.Sprintf("min %v", vd.Min))
+	}
+	if vd.Max != nil && vd.Max != "" {
+		parts = append(parts, fmt.Sprintf("max %v", vd.Max))
+	}
+	if vd.UomLabel != "" {
+		parts = append(parts, vd.UomLabel)
+	}
+	desc := strings.Join(parts, ", ")
+	if vd.Description != "" {
+		desc = vd.Description + " (" + desc + ")"
+	}
+	return desc
+}
@@ -0,0 +1,37 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// OpStateReport describes the operating state of a Device and its changes.
+type OpStateReport struct {
+	Device string                `json:"device"`
+	State  models.OperatingState `json:"state"`
+	// Flapping is set while the changes aren't reported to Core Metadata.
+	Flapping    bool                 `json:"flapping"`
+	Transitions []opstate.Transition `json:"transitions"`
+}
+
+// OpStateHandler returns the operating state of the Device specified by
+// name, and the history of its changes.
+func OpStateHandler(vars map[string]string) (OpStateReport, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return OpStateReport{}, appErr
+	}
+	return OpStateReport{
+		Device:      d.Name,
+		State:       d.OperatingState,
+		Flapping:    opstate.Flapping(d.Name),
+		Transitions: opstate.ForDevice(d.Name),
+	}, nil
+}
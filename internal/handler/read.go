@@ -0,0 +1,42 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// ReadHandler executes a read command against the named Device and returns
+// the readings, without pushing them to Core Data, so that the caller can
+// combine the readings of several commands in a single event.
+func ReadHandler(deviceName string, cmd string) ([]models.Reading, common.AppError) {
+	if appErr := beginCommand(); appErr != nil {
+		return nil, appErr
+	}
+	defer endCommand()
+
+	d, ok := deviceForName(deviceName)
+	if !ok {
+		msg := i18n.T(i18n.DeviceNotFoundMethod, deviceName, "get")
+		common.LoggingClient.Error(msg)
+		return nil, common.NewNotFoundError(msg, nil)
+	}
+	if d.AdminState == models.Locked {
+		msg := i18n.T(i18n.DeviceLocked, d.Name, "get")
+		common.LoggingClient.Error(msg)
+		return nil, common.NewLockedError(msg, nil)
+	}
+
+	start := time.Now()
+	readings, appErr := readCmd(&d, cmd)
+	recordHistory(d.Name, "get", cmd, start, appErr)
+	return readings, appErr
+}
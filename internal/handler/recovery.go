@@ -0,0 +1,96 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var (
+	recoveryMutex sync.Mutex
+	recoveryDone  chan struct{}
+)
+
+// StartRecovery starts probing the disabled Devices every Recovery
+// Interval, unless zero, re-enabling those whose probe read succeeds.
+func StartRecovery() {
+	recoveryMutex.Lock()
+	defer recoveryMutex.Unlock()
+
+	interval := time.Duration(common.CurrentConfig.Recovery.Interval) * time.Millisecond
+	if recoveryDone != nil || interval <= 0 {
+		return
+	}
+	recoveryDone = make(chan struct{})
+	go runRecovery(recoveryDone, interval)
+}
+
+// StopRecovery stops the probes.
+func StopRecovery() {
+	recoveryMutex.Lock()
+	defer recoveryMutex.Unlock()
+
+	if recoveryDone != nil {
+		close(recoveryDone)
+		recoveryDone = nil
+	}
+}
+
+func runRecovery(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			for _, device := range cache.Devices().All() {
+				if device.OperatingState == models.Disabled {
+					probe(device)
+				}
+			}
+		}
+	}
+}
+
+// probeCommand returns the probe command of a Device, from its profile.
+func probeCommand(device models.Device) (string, bool) {
+	rc := common.CurrentConfig.Recovery
+	if cmd, ok := rc.ProfileCommands[device.Profile.Name]; ok {
+		return cmd, cmd != ""
+	}
+	return rc.Command, rc.Command != ""
+}
+
+// probe issues the probe read of a disabled Device, and enables it if the
+// read succeeds. Its readings are cached, but not pushed to Core Data.
+func probe(device models.Device) {
+	cmd, ok := probeCommand(device)
+	if !ok || Draining() || device.AdminState == models.Locked || !common.IsLeader(device) {
+		return
+	}
+	if appErr := beginCommand(); appErr != nil {
+		return
+	}
+	defer endCommand()
+
+	markActive(device.Name)
+	if _, appErr := readCmd(&device, cmd); appErr != nil {
+		common.LoggingClient.Debug(fmt.Sprintf("Handler - Recovery: probe %s of Device %s failed: %s", cmd, device.Name, appErr.Message()))
+		return
+	}
+
+	opstate.Set(device, models.Enabled, "probe")
+	common.LoggingClient.Info(fmt.Sprintf("Handler - Recovery: Device %s responds to %s again, enabled", device.Name, cmd))
+}
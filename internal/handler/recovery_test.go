@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestProbe(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousDriver := common.CurrentConfig, common.Driver
+	defer func() { common.CurrentConfig, common.Driver = previousConfig, previousDriver }()
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	common.CurrentConfig.Recovery.Command = "Breaker"
+	initCache(t)
+	profile, _ := cache.Profiles().ForName("Switchgear")
+
+	var fail bool
+	driver := &testDriver{read: func(reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+		if fail {
+			return nil, errors.New("no response")
+		}
+		cv, _ := ds_models.NewBoolValue(&reqs[0].RO, 0, true)
+		return []*ds_models.CommandValue{cv}, nil
+	}}
+	common.Driver = driver
+
+	tests := []struct {
+		name       string
+		adminState models.AdminState
+		draining   bool
+		fail       bool
+		commands   map[string]string
+		probed     bool
+		enabled    bool
+	}{
+		{"Success", models.Unlocked, false, false, nil, true, true},
+		{"Failure", models.Unlocked, false, true, nil, true, false},
+		{"Locked", models.Locked, false, false, nil, false, false},
+		{"Draining", models.Unlocked, true, false, nil, false, false},
+		{"NoProbeCommand", models.Unlocked, false, false, map[string]string{"Switchgear": ""}, false, false},
+		{"UnknownProbeCommand", models.Unlocked, false, false, map[string]string{"Switchgear": "Missing"}, false, false},
+		{"ProfileCommand", models.Unlocked, false, false, map[string]string{"Switchgear": "Earthing"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := models.Device{Id: bson.NewObjectId(), Name: "feeder", Profile: profile, AdminState: tt.adminState, OperatingState: models.Disabled}
+			cache.Devices().Add(device)
+			defer cache.Devices().Remove(device.Id.Hex())
+			defer forgetActivity(device.Name)
+			common.CurrentConfig.Recovery.ProfileCommands = tt.commands
+			fail = tt.fail
+			driver.reads = 0
+
+			if tt.draining {
+				DrainHandler()
+				defer ResumeHandler()
+			}
+			probe(device)
+
+			if probed := driver.reads > 0; probed != tt.probed {
+				t.Errorf("Probed %v, expected %v", probed, tt.probed)
+			}
+			d, _ := cache.Devices().ForName(device.Name)
+			if enabled := d.OperatingState == models.Enabled; enabled != tt.enabled {
+				t.Errorf("Operating state %s after the probe", d.OperatingState)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var (
+	selMutex   sync.Mutex
+	selections = make(map[string]time.Time) // key is Device name + command, value is expiry
+)
+
+// SelectHandler arms a set command of a Device, so that a following operate
+// (PUT) request on the same command is accepted for device resources which
+// require select-before-operate. The selection is valid until it is consumed
+// or its timeout expires.
+func SelectHandler(vars map[string]string) common.AppError {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return appErr
+	}
+	cmd := vars["command"]
+
+	if d.AdminState == models.Locked {
+		msg := i18n.T(i18n.DeviceLocked, d.Name, "select "+cmd)
+		common.LoggingClient.Error(msg)
+		return common.NewLockedError(msg, nil)
+	}
+
+	ros, err := cache.Profiles().ResourceOperations(d.Profile.Name, cmd, "set")
+	if err != nil {
+		msg := fmt.Sprintf("Handler - Select: can't find ResourceOperations in Profile(%s) and Command(%s), %v", d.Profile.Name, cmd, err)
+		common.LoggingClient.Error(msg)
+		return common.NewNotFoundError(msg, err)
+	}
+
+	timeout := time.Duration(common.CurrentConfig.Device.SelectTimeout) * time.Millisecond
+	for _, ro := range ros {
+		do, ok := cache.Profiles().DeviceObject(d.Profile.Name, ro.Object)
+		if !ok {
+			continue
+		}
+		if t, ok := selectTimeout(do); ok && t > timeout {
+			timeout = t
+		}
+	}
+
+	selMutex.Lock()
+	defer selMutex.Unlock()
+	removeExpiredSelections()
+	selections[selectionKey(d.Name, cmd)] = time.Now().Add(timeout)
+	common.LoggingClient.Debug(fmt.Sprintf("Handler - Select: Device %s command %s selected for %v", d.Name, cmd, timeout))
+
+	return nil
+}
+
+func deviceForVars(vars map[string]string) (models.Device, common.AppError) {
+	dKey := vars["id"]
+
+	var ok bool
+	var d models.Device
+	if dKey != "" {
+		d, ok = cache.Devices().ForId(dKey)
+	} else {
+		dKey = vars["name"]
+		d, ok = deviceForName(dKey)
+	}
+	if !ok {
+		msg := i18n.T(i18n.DeviceNotFound, dKey)
+		common.LoggingClient.Error(msg)
+		return d, common.NewNotFoundError(msg, nil)
+	}
+	return d, nil
+}
+
+// deviceForName returns the Device with the given name, or the current name
+// if it's the alias of a renamed Device.
+func deviceForName(name string) (models.Device, bool) {
+	if d, ok := cache.Devices().ForName(name); ok {
+		return d, true
+	}
+	if current, ok := common.ResolveDeviceName(name); ok {
+		common.LoggingClient.Debug(fmt.Sprintf("Handler - Device %s addressed by its former name %s", current, name))
+		return cache.Devices().ForName(current)
+	}
+	return models.Device{}, false
+}
+
+// requiresSelect returns true if any of the device resources addressed by
+// the requests is marked with the SelectBeforeOperate attribute.
+func requiresSelect(reqs []ds_models.CommandRequest) bool {
+	for _, req := range reqs {
+		v, ok := common.DeviceObjectAttribute(req.DeviceObject, common.AttrSelectBeforeOperate)
+		if !ok {
+			continue
+		}
+		if sbo, err := strconv.ParseBool(v); err == nil && sbo {
+			return true
+		}
+	}
+	return false
+}
+
+// consumeSelection checks whether the command has been selected and the
+// selection hasn't expired. A selection can only be used once.
+func consumeSelection(deviceName string, cmd string) bool {
+	selMutex.Lock()
+	defer selMutex.Unlock()
+
+	key := selectionKey(deviceName, cmd)
+	expiry, ok := selections[key]
+	if !ok {
+		return false
+	}
+	delete(selections, key)
+	return time.Now().Before(expiry)
+}
+
+func selectTimeout(do models.DeviceObject) (time.Duration, bool) {
+	v, ok := common.DeviceObjectAttribute(do, common.AttrSelectTimeout)
+	if !ok {
+		return 0, false
+	}
+	ms, err := strconv.Atoi(v)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - Select: invalid %s attribute %s for device resource %s", common.AttrSelectTimeout, v, do.Name))
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// removeSelections discards the selections of a Device.
+func removeSelections(deviceName string) {
+	selMutex.Lock()
+	defer selMutex.Unlock()
+
+	prefix := selectionKey(deviceName, "")
+	for k := range selections {
+		if strings.HasPrefix(k, prefix) {
+			delete(selections, k)
+		}
+	}
+}
+
+func removeExpiredSelections() {
+	now := time.Now()
+	for k, expiry := range selections {
+		if now.After(expiry) {
+			delete(selections, k)
+		}
+	}
+}
+
+func selectionKey(deviceName string, cmd string) string {
+	return deviceName + "/" + cmd
+}
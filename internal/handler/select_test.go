@@ -0,0 +1,109 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var cacheOnce sync.Once
+
+// initCache initializes the cache of the tests of the package, which can
+// only be initialized once.
+func initCache(t *testing.T) {
+	cacheOnce.Do(func() {
+		dir, err := ioutil.TempDir("", "handler")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		sbo := map[string]interface{}{common.AttrSelectBeforeOperate: "true"}
+		profile := models.DeviceProfile{Name: "Switchgear"}
+		profile.DeviceResources = []models.DeviceObject{
+			{Name: "Breaker", Attributes: sbo},
+			{Name: "Earthing", Attributes: sbo},
+		}
+		devices := []models.Device{
+			{Id: bson.NewObjectId(), Name: "bay1", Profile: profile, AdminState: models.Unlocked},
+			{Id: bson.NewObjectId(), Name: "meter", AdminState: models.Unlocked},
+		}
+		snap, _ := json.Marshal(cache.Snapshot{Devices: devices, Profiles: []models.DeviceProfile{profile}})
+		file := filepath.Join(dir, "cache.json")
+		if err = statedir.WriteFile(file, snap); err != nil {
+			t.Fatal(err)
+		}
+		if err = cache.InitCacheFromFile(file); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestSelectBeforeOperate(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.SelectTimeout = 60000
+	initCache(t)
+	defer removeSelections("bay1")
+
+	do, _ := cache.Profiles().DeviceObject("Switchgear", "Breaker")
+	if !requiresSelect([]ds_models.CommandRequest{{DeviceObject: do}}) {
+		t.Fatal("Breaker should require select-before-operate")
+	}
+
+	vars := func(cmd string) map[string]string {
+		return map[string]string{"name": "bay1", "command": cmd}
+	}
+	if consumeSelection("bay1", "Breaker") {
+		t.Error("Operate accepted without a select")
+	}
+
+	if appErr := SelectHandler(vars("Breaker")); appErr != nil {
+		t.Fatal(appErr)
+	}
+	if !consumeSelection("bay1", "Breaker") {
+		t.Error("Operate rejected after a select")
+	}
+	if consumeSelection("bay1", "Breaker") {
+		t.Error("Second operate accepted after a single select")
+	}
+
+	if appErr := SelectHandler(vars("Earthing")); appErr != nil {
+		t.Fatal(appErr)
+	}
+	if consumeSelection("bay1", "Breaker") {
+		t.Error("Operate accepted after selecting another command")
+	}
+	if !consumeSelection("bay1", "Earthing") {
+		t.Error("Selection of another command was lost")
+	}
+
+	common.CurrentConfig.Device.SelectTimeout = 1
+	if appErr := SelectHandler(vars("Breaker")); appErr != nil {
+		t.Fatal(appErr)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if consumeSelection("bay1", "Breaker") {
+		t.Error("Operate accepted after the selection expired")
+	}
+}
@@ -0,0 +1,164 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/clients"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_probe "github.com/edgexfoundry/device-sdk-go/internal/probe"
+	"github.com/edgexfoundry/device-sdk-go/internal/selfdevice"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// The outcomes of the checks of a self-test.
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// maxDiskUsage is the usage (in percent) of the file system of the state
+// directory above which the buffers may not be persisted.
+const maxDiskUsage = 90
+
+// SelfTestCheck is the outcome of a check of a self-test.
+type SelfTestCheck struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// SelfTestReport is the report of a self-test, passed unless a check
+// failed.
+type SelfTestReport struct {
+	Passed bool `json:"passed"`
+	// Started is the time (in milliseconds) the self-test started, and
+	// Duration its duration in milliseconds.
+	Started  int64           `json:"started"`
+	Duration int64           `json:"duration"`
+	Checks   []SelfTestCheck `json:"checks"`
+}
+
+// SelfTestHandler runs the diagnostics suite of the DS: the sanity of the
+// configuration, the connectivity to Core Data and Core Metadata, a probe
+// read per bus, i.e. per transport shared by Devices, the disk space of the
+// state directory and the synchronization of the clock.
+func SelfTestHandler() SelfTestReport {
+	start := time.Now()
+	common.LoggingClient.Info("Handler - SelfTest: self-test started")
+
+	var checks []SelfTestCheck
+	checks = append(checks, checkConfig())
+	for _, id := range []string{common.ClientMetadata, common.ClientData} {
+		checks = append(checks, result("client:"+id, clients.Ping(id)))
+	}
+	checks = append(checks, checkBuses()...)
+	checks = append(checks, checkDisk())
+	checks = append(checks, result("clock", ds_probe.Check(common.ProbeInfo{Type: ds_probe.TypeNTP})))
+
+	report := SelfTestReport{Passed: true, Started: start.UnixNano() / int64(time.Millisecond), Checks: checks}
+	for _, c := range checks {
+		if c.Result == CheckFailed {
+			report.Passed = false
+		}
+	}
+	report.Duration = int64(time.Since(start) / time.Millisecond)
+	common.LoggingClient.Info(fmt.Sprintf("Handler - SelfTest: self-test completed, passed: %v", report.Passed))
+	return report
+}
+
+func result(name string, err error) SelfTestCheck {
+	if err != nil {
+		return SelfTestCheck{Name: name, Result: CheckFailed, Detail: err.Error()}
+	}
+	return SelfTestCheck{Name: name, Result: CheckPassed}
+}
+
+// checkConfig checks the settings the DS can't run without.
+func checkConfig() SelfTestCheck {
+	var problems []string
+	config := common.CurrentConfig
+	if config.Service.Port <= 0 {
+		problems = append(problems, "Service Port not set")
+	}
+	if config.Device.MaxCmdOps <= 0 {
+		problems = append(problems, "Device MaxCmdOps not positive")
+	}
+	for _, id := range []string{common.ClientMetadata, common.ClientData} {
+		if c := config.Clients[id]; c.Host == "" || c.Port == 0 {
+			problems = append(problems, fmt.Sprintf("client %s not configured", id))
+		}
+	}
+	for _, d := range config.DeviceList {
+		if _, ok := cache.Profiles().ForName(d.Profile); !ok {
+			problems = append(problems, fmt.Sprintf("profile %s of Device %s not found", d.Profile, d.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return SelfTestCheck{Name: "config", Result: CheckFailed, Detail: strings.Join(problems, "; ")}
+	}
+	return SelfTestCheck{Name: "config", Result: CheckPassed}
+}
+
+// checkBuses reads the probe command (see Recovery) of one operational
+// Device per transport. The transports without such a Device are skipped.
+func checkBuses() []SelfTestCheck {
+	devices := filterOperationalDevices(cache.Devices().All())
+	var checks []SelfTestCheck
+	for _, group := range groupByTransport(devices) {
+		a := group[0].Addressable
+		name := "bus:" + group[0].Name
+		if a.Address != "" {
+			name = fmt.Sprintf("bus:%s://%s:%d", strings.ToLower(a.Protocol), a.Address, a.Port)
+		}
+		checks = append(checks, checkBus(name, group))
+	}
+	return checks
+}
+
+func checkBus(name string, group []*models.Device) SelfTestCheck {
+	for _, d := range group {
+		cmd, ok := probeCommand(*d)
+		if !ok || !common.IsLeader(*d) {
+			continue
+		}
+		if appErr := beginCommand(); appErr != nil {
+			return SelfTestCheck{Name: name, Result: CheckSkipped, Detail: appErr.Message()}
+		}
+		_, appErr := readCmd(d, cmd)
+		endCommand()
+		if appErr != nil {
+			return SelfTestCheck{Name: name, Result: CheckFailed, Detail: fmt.Sprintf("%s of Device %s: %s", cmd, d.Name, appErr.Message())}
+		}
+		return SelfTestCheck{Name: name, Result: CheckPassed, Detail: fmt.Sprintf("%s of Device %s", cmd, d.Name)}
+	}
+	return SelfTestCheck{Name: name, Result: CheckSkipped, Detail: "no Device with a probe command"}
+}
+
+// checkDisk checks the space left for the buffers persisted in the state
+// directory.
+func checkDisk() SelfTestCheck {
+	path := statedir.Path("")
+	if path == "" {
+		return SelfTestCheck{Name: "disk", Result: CheckSkipped, Detail: "no state directory"}
+	}
+	usage, err := selfdevice.FileSystemUsage(path)
+	if err != nil {
+		return result("disk", err)
+	}
+	detail := fmt.Sprintf("%.1f%% used", usage)
+	if usage > maxDiskUsage {
+		return SelfTestCheck{Name: "disk", Result: CheckFailed, Detail: detail}
+	}
+	return SelfTestCheck{Name: "disk", Result: CheckPassed, Detail: detail}
+}
@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestCheckConfig(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	initCache(t)
+
+	client := common.ClientInfo{Host: "localhost", Port: 48080}
+	common.CurrentConfig = &common.Config{Clients: map[string]common.ClientInfo{
+		common.ClientMetadata: client,
+		common.ClientData:     client,
+	}}
+	common.CurrentConfig.Service.Port = 49990
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	common.CurrentConfig.DeviceList = []common.DeviceConfig{{Name: "bay1", Profile: "Switchgear"}}
+	if c := checkConfig(); c.Result != CheckPassed {
+		t.Errorf("Sane configuration %s: %s", c.Result, c.Detail)
+	}
+
+	common.CurrentConfig.Device.MaxCmdOps = 0
+	common.CurrentConfig.DeviceList = append(common.CurrentConfig.DeviceList, common.DeviceConfig{Name: "bay2", Profile: "Missing"})
+	if c := checkConfig(); c.Result != CheckFailed || c.Detail != "Device MaxCmdOps not positive; profile Missing of Device bay2 not found" {
+		t.Errorf("Broken configuration %s: %s", c.Result, c.Detail)
+	}
+}
+
+func TestCheckBus(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previousConfig, previousDriver := common.CurrentConfig, common.Driver
+	defer func() { common.CurrentConfig, common.Driver = previousConfig, previousDriver }()
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Device.MaxCmdOps = 16
+	common.CurrentConfig.Recovery.ProfileCommands = map[string]string{"Switchgear": "Breaker"}
+	initCache(t)
+	bay1, _ := cache.Devices().ForName("bay1")
+	meter, _ := cache.Devices().ForName("meter")
+
+	var fail bool
+	common.Driver = &testDriver{read: func(reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+		if fail {
+			return nil, errors.New("no response")
+		}
+		cv, _ := ds_models.NewBoolValue(&reqs[0].RO, 0, true)
+		return []*ds_models.CommandValue{cv}, nil
+	}}
+
+	// the Device with a probe command is probed for the bus
+	group := []*models.Device{&meter, &bay1}
+	if c := checkBus("bus", group); c.Result != CheckPassed || c.Detail != "Breaker of Device bay1" {
+		t.Errorf("Bus check %s: %s", c.Result, c.Detail)
+	}
+	fail = true
+	if c := checkBus("bus", group); c.Result != CheckFailed {
+		t.Errorf("Bus check %s after a failed probe: %s", c.Result, c.Detail)
+	}
+	fail = false
+	if c := checkBus("bus", group[:1]); c.Result != CheckSkipped {
+		t.Errorf("Bus check %s without a probe command: %s", c.Result, c.Detail)
+	}
+
+	DrainHandler()
+	defer ResumeHandler()
+	if c := checkBus("bus", group); c.Result != CheckSkipped {
+		t.Errorf("Bus check %s while draining: %s", c.Result, c.Detail)
+	}
+}
+
+func TestSelfTestHandler(t *testing.T) {
+	common.LoggingClient = logger.NewClient("handler_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	initCache(t)
+
+	// Core Metadata and Core Data are unreachable
+	down := httptest.NewServer(nil)
+	down.Close()
+	common.CurrentConfig = &common.Config{Clients: map[string]common.ClientInfo{
+		common.ClientMetadata: clientInfo(t, down),
+		common.ClientData:     clientInfo(t, down),
+	}}
+	common.CurrentConfig.Service.Port = 49990
+	common.CurrentConfig.Device.MaxCmdOps = 16
+
+	report := SelfTestHandler()
+	if report.Passed {
+		t.Error("Self-test passed with the clients unreachable")
+	}
+	results := make(map[string]string)
+	for _, c := range report.Checks {
+		results[c.Name] = c.Result
+		// no Device has a probe command
+		if strings.HasPrefix(c.Name, "bus:") && c.Result != CheckSkipped {
+			t.Errorf("Check %s %s without a probe command", c.Name, c.Result)
+		}
+	}
+	expected := map[string]string{
+		"config":                          CheckPassed,
+		"client:" + common.ClientMetadata: CheckFailed,
+		"client:" + common.ClientData:     CheckFailed,
+		"disk":                            CheckSkipped,
+	}
+	for name, result := range expected {
+		if results[name] != result {
+			t.Errorf("Check %s %s, expected %s", name, results[name], result)
+		}
+	}
+	if _, ok := results["clock"]; !ok {
+		t.Error("Clock not checked")
+	}
+}
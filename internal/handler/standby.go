@@ -0,0 +1,35 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// StandbyStatus describes the standby mode, in which the DS mirrors an
+// active gateway without writing to the Devices nor publishing events.
+type StandbyStatus struct {
+	Standby bool `json:"standby"`
+}
+
+// StandbyHandler enters the standby mode if enabled is true, and otherwise
+// promotes the DS to active.
+func StandbyHandler(enabled bool) StandbyStatus {
+	if common.SetStandby(enabled) {
+		if enabled {
+			common.LoggingClient.Info("Handler - Standby: entering standby mode")
+		} else {
+			common.LoggingClient.Info("Handler - Standby: promoted to active")
+		}
+	}
+	return StandbyStatusHandler()
+}
+
+// StandbyStatusHandler returns whether the DS is in standby mode.
+func StandbyStatusHandler() StandbyStatus {
+	return StandbyStatus{Standby: common.InStandby()}
+}
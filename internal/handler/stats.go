@@ -0,0 +1,23 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/metrics"
+)
+
+// DeviceStatsHandler returns the access statistics of the device resources
+// of the Device specified by name, by resource name. The resources never
+// accessed are omitted.
+func DeviceStatsHandler(vars map[string]string) (map[string]metrics.ResourceStats, common.AppError) {
+	d, appErr := deviceForVars(vars)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return metrics.DeviceStats(d.Name), nil
+}
@@ -0,0 +1,44 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/pkg/trace"
+)
+
+// TraceHandler enables the protocol trace for a PUT request, disables it
+// for a DELETE request, and returns its state.
+func TraceHandler(method string) trace.Status {
+	switch method {
+	case http.MethodPut:
+		// a new trace session starts from scratch
+		trace.Clear()
+		trace.Enable()
+		common.LoggingClient.Info("Handler - Trace: protocol trace enabled")
+	case http.MethodDelete:
+		trace.Disable()
+		common.LoggingClient.Info("Handler - Trace: protocol trace disabled")
+	}
+	return trace.CurrentStatus()
+}
+
+// TraceFramesHandler returns the frames recorded on the bus specified by
+// name.
+func TraceFramesHandler(vars map[string]string) ([]trace.Frame, common.AppError) {
+	bus := vars["bus"]
+	frames, ok := trace.Frames(bus)
+	if !ok {
+		msg := fmt.Sprintf("Bus: %s not traced", bus)
+		common.LoggingClient.Debug(msg)
+		return nil, common.NewNotFoundError(msg, nil)
+	}
+	return frames, nil
+}
@@ -0,0 +1,19 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/buildinfo"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/feature"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// VersionHandler returns the build information of the DS.
+func VersionHandler() buildinfo.Info {
+	return buildinfo.Get(common.ServiceName, common.ServiceVersion, ds_models.APIVersion, feature.EnabledFeatures())
+}
@@ -0,0 +1,161 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package history keeps a bounded record of the most recent commands
+// executed against each Device, which can optionally be persisted to a
+// file so that it survives a restart of the device service.
+package history
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+)
+
+// saveInterval is the minimum interval between two saves of the history.
+var saveInterval = 10 * time.Second
+
+// Record describes the execution of a single command against a Device.
+type Record struct {
+	// Timestamp is the time (in milliseconds) the command was received.
+	Timestamp int64 `json:"timestamp"`
+	// Method is the type of the command, either "get" or "set".
+	Method string `json:"method"`
+	// Command is the name of the command (or device resource) executed.
+	Command string `json:"command"`
+	// Result is "OK", or the error message if the command failed.
+	Result string `json:"result"`
+	// Latency is the execution time of the command in milliseconds.
+	Latency int64 `json:"latency"`
+}
+
+var (
+	mutex    sync.Mutex
+	size     int
+	path     string
+	lastSave time.Time
+	saving   bool
+	records  = make(map[string][]Record) // key is Device name
+)
+
+// historySchema versions the format of the history file.
+var historySchema = statedir.Schema{Version: 1, Migrations: []statedir.Migration{statedir.Unversioned}}
+
+// Init sets the maximum number of records kept per Device and the file used
+// to persist them. If the file exists, its records are loaded. A size of zero
+// disables the history.
+func Init(maxSize int, file string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	size = maxSize
+	path = file
+	if path == "" {
+		return nil
+	}
+
+	contents, err := statedir.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := make(map[string][]Record)
+	if err = historySchema.Unmarshal(contents, &loaded); err != nil {
+		return err
+	}
+	for name, rs := range loaded {
+		records[name] = truncate(rs)
+	}
+	return nil
+}
+
+// Add appends a record to the history of the named Device, discarding the
+// oldest record when the history is full.
+func Add(deviceName string, r Record) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if size <= 0 {
+		return
+	}
+	records[deviceName] = truncate(append(records[deviceName], r))
+	scheduleSave()
+}
+
+// ForDevice returns the history of the named Device, oldest first.
+func ForDevice(deviceName string) []Record {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	rs := records[deviceName]
+	result := make([]Record, len(rs))
+	copy(result, rs)
+	return result
+}
+
+// Remove discards the history of the named Device.
+func Remove(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, ok := records[deviceName]; ok {
+		delete(records, deviceName)
+		scheduleSave()
+	}
+}
+
+// Save writes the history of all Devices to the configured file, if any.
+func Save() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return save()
+}
+
+func save() error {
+	saving = false
+	lastSave = time.Now()
+	if path == "" {
+		return nil
+	}
+	contents, err := historySchema.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(path, contents)
+}
+
+// scheduleSave saves the history in background, at most once per
+// saveInterval, so that a crash loses only the most recent records. It must
+// be called with the mutex held.
+func scheduleSave() {
+	if path == "" || saving {
+		return
+	}
+	saving = true
+	wait := saveInterval - time.Since(lastSave)
+	go func() {
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		if err := Save(); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Couldn't save the command history: %v", err))
+		}
+	}()
+}
+
+func truncate(rs []Record) []Record {
+	if len(rs) > size {
+		rs = rs[len(rs)-size:]
+	}
+	return rs
+}
@@ -0,0 +1,131 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("history_test", false, "", "DEBUG")
+	saveInterval = 0
+}
+
+func commands(rs []Record) []string {
+	result := make([]string, len(rs))
+	for i, r := range rs {
+		result[i] = r.Command
+	}
+	return result
+}
+
+func TestBound(t *testing.T) {
+	if err := Init(2, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer Init(0, "")
+	defer Remove("meter")
+
+	for _, command := range []string{"a", "b", "c"} {
+		Add("meter", Record{Method: "get", Command: command, Result: "OK"})
+	}
+	rs := ForDevice("meter")
+	if len(rs) != 2 || rs[0].Command != "b" || rs[1].Command != "c" {
+		t.Errorf("Expected the two most recent records, got %v", commands(rs))
+	}
+
+	rs[0].Command = "x"
+	if ForDevice("meter")[0].Command != "b" {
+		t.Error("ForDevice returned the history itself rather than a copy")
+	}
+
+	Remove("meter")
+	if rs = ForDevice("meter"); len(rs) != 0 {
+		t.Errorf("Expected no records after Remove, got %v", commands(rs))
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	if err := Init(0, ""); err != nil {
+		t.Fatal(err)
+	}
+	Add("meter", Record{Method: "get", Command: "a", Result: "OK"})
+	if rs := ForDevice("meter"); len(rs) != 0 {
+		t.Errorf("Expected no records with a size of zero, got %v", commands(rs))
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	file := filepath.Join(tmp, "history.json")
+	if err = Init(3, file); err != nil {
+		t.Fatal(err)
+	}
+	defer Init(0, "")
+	defer Remove("meter")
+
+	for _, command := range []string{"a", "b", "c"} {
+		Add("meter", Record{Timestamp: 1, Method: "set", Command: command, Result: "OK", Latency: 2})
+	}
+	if err = Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the records are loaded back, truncated to the new size
+	Remove("meter")
+	if err = Init(2, file); err != nil {
+		t.Fatal(err)
+	}
+	rs := ForDevice("meter")
+	if len(rs) != 2 || rs[0].Command != "b" || rs[1].Command != "c" {
+		t.Fatalf("Expected the two most recent records to be loaded, got %v", commands(rs))
+	}
+	if rs[1] != (Record{Timestamp: 1, Method: "set", Command: "c", Result: "OK", Latency: 2}) {
+		t.Errorf("Unexpected loaded record %+v", rs[1])
+	}
+}
+
+func TestSaveOnAdd(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	file := filepath.Join(tmp, "history.json")
+	if err = Init(3, file); err != nil {
+		t.Fatal(err)
+	}
+	defer Init(0, "")
+	defer Remove("meter")
+
+	Add("meter", Record{Method: "get", Command: "a", Result: "OK"})
+
+	// the history is written without any call to Save
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err = os.Stat(file); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected Add to save the history")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
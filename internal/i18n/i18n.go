@@ -0,0 +1,156 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package i18n provides the catalog of the user-facing messages returned by
+// the REST API, in the locale selected by the configuration. Additional
+// locales or overrides can be loaded from a JSON catalog file.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used for messages missing from the selected locale.
+const DefaultLocale = "en"
+
+// Message identifiers.
+const (
+	DeviceNotFound         = "DeviceNotFound"
+	DeviceNotFoundMethod   = "DeviceNotFoundMethod"
+	DeviceLocked           = "DeviceLocked"
+	CommandNotFound        = "CommandNotFound"
+	ValueRejected          = "ValueRejected"
+	SelectRequired         = "SelectRequired"
+	ServiceLocked          = "ServiceLocked"
+	ServiceDraining        = "ServiceDraining"
+	ServiceStandby         = "ServiceStandby"
+	NoRequestBody          = "NoRequestBody"
+	Unauthorized           = "Unauthorized"
+	Forbidden              = "Forbidden"
+	ClockOffsetNotMeasured = "ClockOffsetNotMeasured"
+)
+
+// catalogs holds the message formats per locale and message identifier.
+var catalogs = map[string]map[string]string{
+	"en": {
+		DeviceNotFound:         "Device: %s not found",
+		DeviceNotFoundMethod:   "Device: %s not found; %s",
+		DeviceLocked:           "%s is locked; %s",
+		CommandNotFound:        "%s for Device: %s not found; %s",
+		ValueRejected:          "Handler - execWriteCmd: CommandValue (%s) rejected: %v",
+		SelectRequired:         "Handler - execWriteCmd: Device: %s cmd: %s must be selected before operate",
+		ServiceLocked:          "%s is locked; %s %s",
+		ServiceDraining:        "Device service is draining, no new commands are accepted",
+		ServiceStandby:         "Device service is in standby, writes are rejected until promoted; %s",
+		NoRequestBody:          "no request body provided; %s %s",
+		Unauthorized:           "Unauthorized %s",
+		Forbidden:              "Forbidden %s",
+		ClockOffsetNotMeasured: "No clock offset measured for Device %s",
+	},
+	"es": {
+		DeviceNotFound:         "Dispositivo: %s no encontrado",
+		DeviceNotFoundMethod:   "Dispositivo: %s no encontrado; %s",
+		DeviceLocked:           "%s está bloqueado; %s",
+		CommandNotFound:        "%s no encontrado para el Dispositivo: %s; %s",
+		ValueRejected:          "Valor (%s) rechazado: %v",
+		SelectRequired:         "Dispositivo: %s comando: %s debe seleccionarse antes de operar",
+		ServiceLocked:          "%s está bloqueado; %s %s",
+		ServiceDraining:        "El servicio de dispositivos se está vaciando, no se aceptan comandos nuevos",
+		ServiceStandby:         "El servicio de dispositivos está en reserva, se rechazan las escrituras hasta su promoción; %s",
+		NoRequestBody:          "no se ha proporcionado cuerpo de la petición; %s %s",
+		Unauthorized:           "No autorizado %s",
+		Forbidden:              "Prohibido %s",
+		ClockOffsetNotMeasured: "No se ha medido el desfase de reloj del Dispositivo %s",
+	},
+	"fr": {
+		DeviceNotFound:         "Équipement : %s introuvable",
+		DeviceNotFoundMethod:   "Équipement : %s introuvable ; %s",
+		DeviceLocked:           "%s est verrouillé ; %s",
+		CommandNotFound:        "%s introuvable pour l'Équipement : %s ; %s",
+		ValueRejected:          "Valeur (%s) refusée : %v",
+		SelectRequired:         "Équipement : %s commande : %s doit être sélectionnée avant d'opérer",
+		ServiceLocked:          "%s est verrouillé ; %s %s",
+		ServiceDraining:        "Le service d'équipements est en cours de vidage, aucune nouvelle commande n'est acceptée",
+		ServiceStandby:         "Le service d'équipements est en attente, les écritures sont refusées jusqu'à sa promotion ; %s",
+		NoRequestBody:          "aucun corps de requête fourni ; %s %s",
+		Unauthorized:           "Non autorisé %s",
+		Forbidden:              "Interdit %s",
+		ClockOffsetNotMeasured: "Aucun décalage d'horloge mesuré pour l'Équipement %s",
+	},
+}
+
+var (
+	mutex  sync.RWMutex
+	locale = DefaultLocale
+)
+
+// SetLocale selects the locale of the messages, e.g. "es" or "fr-CA". A
+// locale without catalog falls back to its language, then to English.
+func SetLocale(l string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if l == "" {
+		locale = DefaultLocale
+		return nil
+	}
+	l = strings.ToLower(strings.Replace(l, "_", "-", -1))
+	if _, ok := catalogs[l]; !ok {
+		lang := strings.SplitN(l, "-", 2)[0]
+		if _, ok := catalogs[lang]; !ok {
+			locale = DefaultLocale
+			return fmt.Errorf("no message catalog for locale %s", l)
+		}
+		l = lang
+	}
+	locale = l
+	return nil
+}
+
+// LoadCatalog merges the messages of a JSON file, mapping locales to
+// message identifiers to formats, into the catalogs.
+func LoadCatalog(file string) error {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	loaded := make(map[string]map[string]string)
+	if err = json.Unmarshal(contents, &loaded); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for l, messages := range loaded {
+		l = strings.ToLower(l)
+		if catalogs[l] == nil {
+			catalogs[l] = make(map[string]string, len(messages))
+		}
+		for id, format := range messages {
+			catalogs[l][id] = format
+		}
+	}
+	return nil
+}
+
+// T returns the message in the selected locale, formatted with args.
+func T(id string, args ...interface{}) string {
+	mutex.RLock()
+	format, ok := catalogs[locale][id]
+	if !ok {
+		format, ok = catalogs[DefaultLocale][id]
+	}
+	mutex.RUnlock()
+
+	if !ok {
+		return fmt.Sprint(append([]interface{}{id, ": "}, args...)...)
+	}
+	return fmt.Sprintf(format, args...)
+}
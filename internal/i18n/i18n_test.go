@@ -0,0 +1,43 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+import (
+	"testing"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for l, messages := range catalogs {
+		for id := range catalogs[DefaultLocale] {
+			if _, ok := messages[id]; !ok {
+				t.Errorf("Message %s missing in locale %s", id, l)
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale("")
+
+	if msg := T(DeviceNotFound, "meter"); msg != "Device: meter not found" {
+		t.Errorf("Unexpected English message: %s", msg)
+	}
+
+	if err := SetLocale("es_ES"); err != nil {
+		t.Fatal(err)
+	}
+	if msg := T(DeviceNotFound, "meter"); msg != "Dispositivo: meter no encontrado" {
+		t.Errorf("Unexpected Spanish message: %s", msg)
+	}
+
+	if err := SetLocale("de"); err == nil {
+		t.Error("Locale without catalog accepted")
+	}
+	if msg := T(Forbidden, "/api/v1/drain"); msg != "Forbidden /api/v1/drain" {
+		t.Errorf("Unexpected fallback message: %s", msg)
+	}
+}
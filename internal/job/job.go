@@ -0,0 +1,330 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package job orchestrates the multi-step protocol sequences (jobs) of the
+// driver. The steps of a job are run in order, a failed step being retried
+// from the last checkpoint. Checkpoints are persisted in the state directory
+// so that an interrupted job can be resumed, even after a restart.
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const (
+	StateRunning   = "Running"
+	StateComplete  = "Complete"
+	StateFailed    = "Failed"
+	StateCanceled  = "Canceled"
+	StateSuspended = "Suspended"
+)
+
+var (
+	// ErrRunning is returned when submitting a job already running.
+	ErrRunning = errors.New("job already running")
+	// ErrNotFound is returned for an unknown job.
+	ErrNotFound = errors.New("job not found")
+)
+
+// Status describes the progress of a job.
+type Status struct {
+	ID     string `json:"id"`
+	Device string `json:"device"`
+	State  string `json:"state"`
+	// Step is the name of the current (or failed) step, StepIndex its index
+	// and Steps the number of steps of the job.
+	Step      string `json:"step"`
+	StepIndex int    `json:"stepIndex"`
+	Steps     int    `json:"steps"`
+	// Done and Total are the progress reported by the current step.
+	Done     int    `json:"done"`
+	Total    int    `json:"total"`
+	Attempts int    `json:"attempts"`
+	Started  int64  `json:"started"`
+	Updated  int64  `json:"updated"`
+	Error    string `json:"error,omitempty"`
+}
+
+// checkpoint is the persisted state of a job.
+type checkpoint struct {
+	StepIndex int               `json:"stepIndex"`
+	Values    map[string]string `json:"values"`
+}
+
+type run struct {
+	job      *ds_models.Job
+	status   Status
+	cp       checkpoint
+	attempt  int
+	canceled bool
+}
+
+var (
+	mutex    sync.Mutex
+	dir      string
+	stopping bool
+	wg       sync.WaitGroup
+	runs     = make(map[string]*run)
+)
+
+// Init sets the directory, relative to the state directory, the checkpoints
+// of the jobs are stored in.
+func Init(jobDir string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	dir = jobDir
+	stopping = false
+	if dir == "" {
+		return nil
+	}
+	return os.MkdirAll(statedir.Path(dir), 0755)
+}
+
+// Submit starts running a job in background. A job interrupted by a failure
+// or a restart is resumed from its last checkpoint.
+func Submit(j *ds_models.Job) error {
+	if j.ID == "" || len(j.Steps) == 0 {
+		return fmt.Errorf("job must have an ID and steps")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if stopping {
+		return fmt.Errorf("device service is stopping")
+	}
+	if r, ok := runs[j.ID]; ok && r.status.State == StateRunning {
+		return ErrRunning
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	r := &run{job: j, cp: checkpoint{Values: make(map[string]string)}}
+	r.status = Status{ID: j.ID, Device: j.DeviceName, State: StateRunning, Steps: len(j.Steps), Started: now, Updated: now}
+	if err := r.loadCheckpoint(); err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Job %s can't be resumed, restarting it: %v", j.ID, err))
+		r.cp = checkpoint{Values: make(map[string]string)}
+	} else if r.cp.StepIndex > 0 {
+		common.LoggingClient.Info(fmt.Sprintf("Resuming job %s at step %d", j.ID, r.cp.StepIndex))
+	}
+	runs[j.ID] = r
+
+	wg.Add(1)
+	go r.execute()
+	return nil
+}
+
+// Cancel cancels a running job. The current step is notified and the
+// checkpoint of the job is discarded.
+func Cancel(id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	r, ok := runs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	r.canceled = true
+	return nil
+}
+
+// Stop suspends the running jobs once their current step returns, keeping
+// their checkpoints so that they can be resumed.
+func Stop() {
+	mutex.Lock()
+	stopping = true
+	mutex.Unlock()
+
+	wg.Wait()
+}
+
+// ForID returns the status of a job.
+func ForID(id string) (Status, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	r, ok := runs[id]
+	if !ok {
+		return Status{}, false
+	}
+	return r.status, true
+}
+
+// ForDevice returns the status of the jobs of the named Device, oldest first.
+func ForDevice(deviceName string) []Status {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	result := make([]Status, 0)
+	for _, r := range runs {
+		if r.status.Device == deviceName {
+			result = append(result, r.status)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Started < result[j].Started })
+	return result
+}
+
+func (r *run) execute() {
+	defer wg.Done()
+
+	for r.cp.StepIndex < len(r.job.Steps) {
+		step := r.job.Steps[r.cp.StepIndex]
+		r.update(func(s *Status) {
+			s.Step = step.Name
+			s.StepIndex = r.cp.StepIndex
+			s.Done, s.Total = 0, 0
+		})
+
+		err := r.runStep(step)
+		if err == nil {
+			mutex.Lock()
+			r.cp.StepIndex++
+			if err := r.saveCheckpoint(); err != nil {
+				common.LoggingClient.Warn(fmt.Sprintf("Job %s checkpoint not saved: %v", r.job.ID, err))
+			}
+			mutex.Unlock()
+		}
+		if r.Canceled() && r.cp.StepIndex < len(r.job.Steps) {
+			r.finish(StateCanceled, err)
+			return
+		}
+		if err != nil {
+			r.finish(StateFailed, err)
+			return
+		}
+	}
+	r.finish(StateComplete, nil)
+}
+
+// runStep runs a step until it succeeds or its attempts are exhausted.
+func (r *run) runStep(step ds_models.JobStep) error {
+	attempts := r.job.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for r.attempt = 1; r.attempt <= attempts; r.attempt++ {
+		r.update(func(s *Status) { s.Attempts++ })
+		if err = step.Run(r); err == nil || r.Canceled() {
+			return err
+		}
+		common.LoggingClient.Warn(fmt.Sprintf("Job %s step %s attempt %d failed: %v", r.job.ID, step.Name, r.attempt, err))
+		if r.attempt < attempts {
+			time.Sleep(r.job.RetryInterval)
+		}
+	}
+	return err
+}
+
+func (r *run) finish(state string, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if state == StateCanceled && stopping && !r.canceled {
+		state = StateSuspended // keep the checkpoint for resumption
+	}
+	r.status.State = state
+	r.status.Updated = time.Now().UnixNano() / int64(time.Millisecond)
+	if err != nil {
+		r.status.Error = err.Error()
+	}
+	if state == StateComplete || state == StateCanceled {
+		os.Remove(statedir.Path(r.checkpointFile()))
+	}
+	common.LoggingClient.Info(fmt.Sprintf("Job %s of Device %s %s", r.job.ID, r.job.DeviceName, state))
+}
+
+func (r *run) update(f func(s *Status)) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	f(&r.status)
+	r.status.Updated = time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+func (r *run) loadCheckpoint() error {
+	contents, err := statedir.ReadFile(r.checkpointFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var cp checkpoint
+	if err = json.Unmarshal(contents, &cp); err != nil {
+		return err
+	}
+	if cp.StepIndex > len(r.job.Steps) {
+		return fmt.Errorf("checkpoint at step %d of %d", cp.StepIndex, len(r.job.Steps))
+	}
+	if cp.Values == nil {
+		cp.Values = make(map[string]string)
+	}
+	r.cp = cp
+	return nil
+}
+
+// saveCheckpoint must be called with the mutex held.
+func (r *run) saveCheckpoint() error {
+	contents, err := json.Marshal(r.cp)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(r.checkpointFile(), contents)
+}
+
+func (r *run) checkpointFile() string {
+	return filepath.Join(dir, filepath.Base(r.job.ID)+".job")
+}
+
+// Checkpoint implements JobContext.
+func (r *run) Checkpoint(key string, value string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	r.cp.Values[key] = value
+	return r.saveCheckpoint()
+}
+
+// Value implements JobContext.
+func (r *run) Value(key string) (string, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	v, ok := r.cp.Values[key]
+	return v, ok
+}
+
+// Progress implements JobContext.
+func (r *run) Progress(done int, total int) {
+	r.update(func(s *Status) { s.Done, s.Total = done, total })
+}
+
+// Attempt implements JobContext.
+func (r *run) Attempt() int {
+	return r.attempt
+}
+
+// Canceled implements JobContext.
+func (r *run) Canceled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return r.canceled || stopping
+}
@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("job_test", false, "", "DEBUG")
+}
+
+func initTestDir(t *testing.T) func() {
+	tmp, err := ioutil.TempDir("", "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = statedir.Init(tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err = Init("jobs"); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		statedir.Init("")
+		os.RemoveAll(tmp)
+	}
+}
+
+func waitFor(t *testing.T, id string) Status {
+	for i := 0; i < 100; i++ {
+		if s, ok := ForID(id); ok && s.State != StateRunning {
+			return s
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Job %s still running", id)
+	return Status{}
+}
+
+// readoutJob reads 4 chunks, failing to read chunk 2 the first failures times.
+func readoutJob(failures int, read *[]int) *ds_models.Job {
+	return &ds_models.Job{
+		ID:          "readout",
+		DeviceName:  "meter",
+		MaxAttempts: 2,
+		Steps: []ds_models.JobStep{
+			{Name: "select", Run: func(ctx ds_models.JobContext) error {
+				return ctx.Checkpoint("block", "7")
+			}},
+			{Name: "read", Run: func(ctx ds_models.JobContext) error {
+				next := 0
+				if v, ok := ctx.Value("next"); ok {
+					next, _ = strconv.Atoi(v)
+				}
+				for ; next < 4; next++ {
+					if next == 2 && failures > 0 {
+						failures--
+						return errors.New("timeout")
+					}
+					*read = append(*read, next)
+					ctx.Checkpoint("next", strconv.Itoa(next+1))
+					ctx.Progress(next+1, 4)
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+func TestRetryFromCheckpoint(t *testing.T) {
+	defer initTestDir(t)()
+
+	var read []int
+	if err := Submit(readoutJob(1, &read)); err != nil {
+		t.Fatal(err)
+	}
+	s := waitFor(t, "readout")
+	if s.State != StateComplete || s.Done != 4 || s.Attempts != 3 {
+		t.Fatalf("Unexpected status %+v", s)
+	}
+	if len(read) != 4 {
+		t.Errorf("Chunks read again after retry: %v", read)
+	}
+}
+
+func TestResumeAfterFailure(t *testing.T) {
+	defer initTestDir(t)()
+
+	var read []int
+	Submit(readoutJob(2, &read))
+	if s := waitFor(t, "readout"); s.State != StateFailed || s.Step != "read" {
+		t.Fatalf("Unexpected status %+v", s)
+	}
+
+	Submit(readoutJob(0, &read))
+	if s := waitFor(t, "readout"); s.State != StateComplete {
+		t.Fatalf("Unexpected status %+v", s)
+	}
+	if len(read) != 4 {
+		t.Errorf("Chunks read again after resumption: %v", read)
+	}
+}
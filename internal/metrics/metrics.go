@@ -0,0 +1,55 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package holds the metrics registered by the driver, and the access
+// statistics of the device resources, reported in the health of the DS.
+package metrics
+
+import (
+	"sync"
+)
+
+var (
+	mutex  sync.Mutex
+	gauges = make(map[string]func() float64)
+)
+
+// Registrar implements the MetricsRegistrar of the DriverContext.
+type Registrar struct{}
+
+// RegisterGauge registers a metric computed by gauge.
+func (Registrar) RegisterGauge(name string, gauge func() float64) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	gauges[name] = gauge
+}
+
+// Unregister removes a metric.
+func (Registrar) Unregister(name string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(gauges, name)
+}
+
+// Values returns the current value of each metric, including the totals of
+// the ResourceStats.
+func Values() map[string]float64 {
+	mutex.Lock()
+	fns := make(map[string]func() float64, len(gauges))
+	for name, fn := range gauges {
+		fns[name] = fn
+	}
+	values := resourceTotals()
+	mutex.Unlock()
+
+	// the gauges are called unlocked, as they may register metrics
+	for name, fn := range fns {
+		values[name] = fn()
+	}
+	return values
+}
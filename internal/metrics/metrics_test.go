@@ -0,0 +1,59 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValues(t *testing.T) {
+	var r Registrar
+	r.RegisterGauge("queued", func() float64 { return 3 })
+	r.RegisterGauge("errors", func() float64 { return 1 })
+	r.RegisterGauge("errors", func() float64 { return 2 })
+	defer r.Unregister("queued")
+
+	values := Values()
+	if values["queued"] != 3 || values["errors"] != 2 {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	r.Unregister("errors")
+	if _, ok := Values()["errors"]; ok {
+		t.Errorf("unregistered metric still reported")
+	}
+}
+
+func TestResourceStats(t *testing.T) {
+	defer RemoveDevice("meter")
+	RecordRead("meter", []string{"power", "energy"}, 20*time.Millisecond, nil)
+	RecordRead("meter", []string{"power"}, 5*time.Millisecond, errors.New("timeout"))
+	RecordWrite("meter", []string{"setpoint"}, 10*time.Millisecond, nil)
+
+	stats := DeviceStats("meter")
+	if s := stats["power"]; s.Reads != 2 || s.ReadErrors != 1 || s.Writes != 0 || s.LastLatency != 5 || s.LastAccess == 0 {
+		t.Errorf("unexpected power stats %+v", s)
+	}
+	if s := stats["energy"]; s.Reads != 1 || s.ReadErrors != 0 || s.LastLatency != 20 {
+		t.Errorf("unexpected energy stats %+v", s)
+	}
+	if s := stats["setpoint"]; s.Writes != 1 || s.WriteErrors != 0 || s.Reads != 0 {
+		t.Errorf("unexpected setpoint stats %+v", s)
+	}
+
+	values := Values()
+	if values[ResourceReads] != 3 || values[ResourceReadErrors] != 1 || values[ResourceWrites] != 1 || values[ResourceWriteErrors] != 0 {
+		t.Errorf("unexpected totals %v", values)
+	}
+
+	RemoveDevice("meter")
+	if len(DeviceStats("meter")) != 0 || Values()[ResourceReads] != 0 {
+		t.Errorf("stats of a removed Device still reported")
+	}
+}
@@ -0,0 +1,119 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+)
+
+// ResourceStats counts the accesses to a device resource of a Device, to
+// tell which resources of the profiles are used, and which always fail.
+type ResourceStats struct {
+	Reads       uint64 `json:"reads"`
+	ReadErrors  uint64 `json:"readErrors"`
+	Writes      uint64 `json:"writes"`
+	WriteErrors uint64 `json:"writeErrors"`
+	// LastLatency is the duration (in milliseconds) of the last driver call
+	// accessing the resource.
+	LastLatency float64 `json:"lastLatency"`
+	// LastAccess is the time (in milliseconds) of the last access.
+	LastAccess int64 `json:"lastAccess"`
+}
+
+// Names of the metrics aggregating the ResourceStats of all Devices.
+const (
+	ResourceReads       = "resource.reads"
+	ResourceReadErrors  = "resource.readErrors"
+	ResourceWrites      = "resource.writes"
+	ResourceWriteErrors = "resource.writeErrors"
+)
+
+// resourceStats are the ResourceStats by Device name and resource name,
+// guarded by mutex.
+var resourceStats = make(map[string]map[string]*ResourceStats)
+
+// RecordRead counts a driver call reading resources of a Device.
+func RecordRead(device string, resources []string, latency time.Duration, err error) {
+	record(device, resources, latency, func(s *ResourceStats) {
+		s.Reads++
+		if err != nil {
+			s.ReadErrors++
+		}
+	})
+}
+
+// RecordWrite counts a driver call writing resources of a Device.
+func RecordWrite(device string, resources []string, latency time.Duration, err error) {
+	record(device, resources, latency, func(s *ResourceStats) {
+		s.Writes++
+		if err != nil {
+			s.WriteErrors++
+		}
+	})
+}
+
+func record(device string, resources []string, latency time.Duration, count func(*ResourceStats)) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	ms := float64(latency) / float64(time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	stats, ok := resourceStats[device]
+	if !ok {
+		stats = make(map[string]*ResourceStats)
+		resourceStats[device] = stats
+	}
+	for _, r := range resources {
+		s, ok := stats[r]
+		if !ok {
+			s = &ResourceStats{}
+			stats[r] = s
+		}
+		count(s)
+		s.LastLatency = ms
+		s.LastAccess = now
+	}
+}
+
+// DeviceStats returns the ResourceStats of the resources of a Device
+// accessed since the start of the DS, by resource name.
+func DeviceStats(device string) map[string]ResourceStats {
+	mutex.Lock()
+	defer mutex.Unlock()
+	result := make(map[string]ResourceStats, len(resourceStats[device]))
+	for name, s := range resourceStats[device] {
+		result[name] = *s
+	}
+	return result
+}
+
+// RemoveDevice forgets the ResourceStats of a Device.
+func RemoveDevice(device string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(resourceStats, device)
+}
+
+// resourceTotals returns the ResourceStats counts summed over all Devices,
+// as metrics.
+func resourceTotals() map[string]float64 {
+	var total ResourceStats
+	for _, stats := range resourceStats {
+		for _, s := range stats {
+			total.Reads += s.Reads
+			total.ReadErrors += s.ReadErrors
+			total.Writes += s.Writes
+			total.WriteErrors += s.WriteErrors
+		}
+	}
+	return map[string]float64{
+		ResourceReads:       float64(total.Reads),
+		ResourceReadErrors:  float64(total.ReadErrors),
+		ResourceWrites:      float64(total.Writes),
+		ResourceWriteErrors: float64(total.WriteErrors),
+	}
+}
@@ -0,0 +1,279 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package opstate changes the OperatingState of the Devices, keeping a
+// bounded history of their transitions, and reports the changes to Core
+// Metadata, holding the reports back while a Device is flapping, i.e.
+// toggling between Enabled and Disabled faster than the flap settings allow.
+package opstate
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// reportTick is the period at which the reports held back are retried.
+const reportTick = time.Second
+
+// Transition is a change of the OperatingState of a Device.
+type Transition struct {
+	// Timestamp is the time (in milliseconds) of the change.
+	Timestamp int64                 `json:"timestamp"`
+	State     models.OperatingState `json:"state"`
+	// Reason is what changed the state, e.g. "assertion" or "metadata".
+	Reason string `json:"reason,omitempty"`
+	// Dampened is set if the report of the change to Core Metadata was
+	// held back, the Device flapping.
+	Dampened bool `json:"dampened,omitempty"`
+}
+
+var (
+	mutex       sync.Mutex
+	size        int
+	path        string
+	transitions = make(map[string][]Transition)          // key is Device name
+	recent      = make(map[string][]time.Time)           // changes within the flap window
+	reported    = make(map[string]models.OperatingState) // last state known by Core Metadata
+	pending     = make(map[string]models.OperatingState) // states to report
+	kick        = make(chan struct{}, 1)
+	done        chan struct{}
+)
+
+// opStateSchema versions the format of the history file.
+var opStateSchema = statedir.Schema{Version: 1, Migrations: []statedir.Migration{statedir.Unversioned}}
+
+// Init sets the maximum number of transitions kept per Device and the file
+// used to persist them. If the file exists, its transitions are loaded.
+func Init(maxSize int, file string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	size = maxSize
+	path = file
+	if path == "" {
+		return nil
+	}
+
+	contents, err := statedir.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := make(map[string][]Transition)
+	if err = opStateSchema.Unmarshal(contents, &loaded); err != nil {
+		return err
+	}
+	for name, ts := range loaded {
+		transitions[name] = truncate(ts)
+	}
+	return nil
+}
+
+// Start starts reporting the changes to Core Metadata.
+func Start() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		return
+	}
+	done = make(chan struct{})
+	go run(done)
+}
+
+// Stop stops reporting the changes, those held back being lost.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		close(done)
+		done = nil
+	}
+}
+
+// Set changes the OperatingState of a Device in cache, and reports it to
+// Core Metadata asynchronously, unless the Device is flapping. It returns
+// false if the Device already was in that state.
+func Set(device models.Device, state models.OperatingState, reason string) bool {
+	if device.OperatingState == state {
+		return false
+	}
+	previous := device.OperatingState
+	device.OperatingState = state
+	cache.Devices().Update(device)
+
+	mutex.Lock()
+	if _, ok := reported[device.Name]; !ok {
+		reported[device.Name] = previous
+	}
+	dampened := record(device.Name, state, reason)
+	pending[device.Name] = state
+	mutex.Unlock()
+
+	if dampened {
+		common.LoggingClient.Warn(fmt.Sprintf("Device %s flapping, %s not reported yet", device.Name, state))
+	}
+	select {
+	case kick <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Observe records a change of the OperatingState of a Device made in Core
+// Metadata, e.g. by the driver or a user.
+func Observe(deviceName string, state models.OperatingState, reason string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	record(deviceName, state, reason)
+	reported[deviceName] = state
+	delete(pending, deviceName)
+}
+
+// record appends a transition to the history of a Device, and returns
+// whether the Device is flapping.
+func record(deviceName string, state models.OperatingState, reason string) bool {
+	now := time.Now()
+	recent[deviceName] = append(withinWindow(deviceName, now), now)
+	t := Transition{
+		Timestamp: now.UnixNano() / int64(time.Millisecond),
+		State:     state,
+		Reason:    reason,
+		Dampened:  flapping(deviceName, now),
+	}
+	if size > 0 {
+		transitions[deviceName] = truncate(append(transitions[deviceName], t))
+	}
+	return t.Dampened
+}
+
+// withinWindow returns the recent changes of a Device within the flap
+// window.
+func withinWindow(deviceName string, now time.Time) []time.Time {
+	window := time.Duration(common.CurrentConfig.OperatingState.FlapWindow) * time.Millisecond
+	changes := recent[deviceName]
+	i := 0
+	for i < len(changes) && now.Sub(changes[i]) > window {
+		i++
+	}
+	return changes[i:]
+}
+
+func flapping(deviceName string, now time.Time) bool {
+	threshold := common.CurrentConfig.OperatingState.FlapThreshold
+	return threshold > 0 && len(withinWindow(deviceName, now)) >= threshold
+}
+
+// Flapping returns whether the reports of a Device are held back.
+func Flapping(deviceName string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return flapping(deviceName, time.Now())
+}
+
+func run(done <-chan struct{}) {
+	ticker := time.NewTicker(reportTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-kick:
+		case <-ticker.C:
+		}
+		report()
+	}
+}
+
+// report reports the pending states of the Devices which aren't flapping,
+// if they differ from the last state reported.
+func report() {
+	now := time.Now()
+	states := make(map[string]models.OperatingState)
+	mutex.Lock()
+	for name, state := range pending {
+		if !flapping(name, now) {
+			states[name] = state
+			delete(pending, name)
+		}
+	}
+	mutex.Unlock()
+
+	for name, state := range states {
+		mutex.Lock()
+		unchanged := reported[name] == state
+		mutex.Unlock()
+		if unchanged {
+			continue
+		}
+		if err := common.DeviceClient.UpdateOpStateByName(name, string(state)); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Reporting the operating state %s of Device %s failed: %v", state, name, err))
+			mutex.Lock()
+			if _, ok := pending[name]; !ok {
+				pending[name] = state
+			}
+			mutex.Unlock()
+			continue
+		}
+		mutex.Lock()
+		reported[name] = state
+		mutex.Unlock()
+	}
+}
+
+// ForDevice returns the transitions of the named Device, oldest first.
+func ForDevice(deviceName string) []Transition {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	ts := transitions[deviceName]
+	result := make([]Transition, len(ts))
+	copy(result, ts)
+	return result
+}
+
+// Remove discards the transitions of the named Device.
+func Remove(deviceName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(transitions, deviceName)
+	delete(recent, deviceName)
+	delete(reported, deviceName)
+	delete(pending, deviceName)
+}
+
+// Save writes the transitions of all Devices to the configured file, if
+// any.
+func Save() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if path == "" {
+		return nil
+	}
+	contents, err := opStateSchema.Marshal(transitions)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(path, contents)
+}
+
+func truncate(ts []Transition) []Transition {
+	if len(ts) > size {
+		ts = ts[len(ts)-size:]
+	}
+	return ts
+}
@@ -0,0 +1,93 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package opstate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// reportingClient records the operating states reported to Core Metadata.
+type reportingClient struct {
+	mock.DeviceClientMock
+	reports []string
+}
+
+func (c *reportingClient) UpdateOpStateByName(name string, opState string) error {
+	c.reports = append(c.reports, opState)
+	return nil
+}
+
+func TestFlapping(t *testing.T) {
+	common.LoggingClient = logger.NewClient("opstate_test", false, "", "DEBUG")
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{OperatingState: common.OperatingStateInfo{FlapThreshold: 3, FlapWindow: 60000}}
+	client := &reportingClient{}
+	common.DeviceClient = client
+	size = 10
+
+	dir, err := ioutil.TempDir("", "opstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	snap, _ := json.Marshal(cache.Snapshot{Devices: []models.Device{{Name: "meter", OperatingState: models.Enabled}}})
+	file := filepath.Join(dir, "cache.json")
+	if err = statedir.WriteFile(file, snap); err != nil {
+		t.Fatal(err)
+	}
+	if err = cache.InitCacheFromFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	set := func(state models.OperatingState) {
+		d, _ := cache.Devices().ForName("meter")
+		if !Set(d, state, "test") {
+			t.Fatalf("Device already %s", state)
+		}
+		report()
+	}
+	set(models.Disabled)
+	set(models.Enabled)
+	set(models.Disabled)
+
+	if d, _ := cache.Devices().ForName("meter"); d.OperatingState != models.Disabled {
+		t.Errorf("Device %s in cache", d.OperatingState)
+	}
+	if !Flapping("meter") || len(client.reports) != 2 {
+		t.Errorf("Flapping Device reported %v", client.reports)
+	}
+	ts := ForDevice("meter")
+	if len(ts) != 3 || !ts[2].Dampened || ts[1].Dampened {
+		t.Errorf("Unexpected transitions %+v", ts)
+	}
+
+	// the Device settles once its changes are out of the window
+	common.CurrentConfig.OperatingState.FlapWindow = 1
+	time.Sleep(2 * time.Millisecond)
+	report()
+	if Flapping("meter") || len(client.reports) != 3 || client.reports[2] != models.Disabled {
+		t.Errorf("Settled Device reported %v", client.reports)
+	}
+
+	Remove("meter")
+	if len(ForDevice("meter")) != 0 {
+		t.Error("Transitions of a removed Device kept")
+	}
+}
@@ -0,0 +1,129 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package probe waits for the hardware of the gateway to be ready before the
+// driver is initialized, e.g. a serial adapter enumerated late by udev, so
+// that slow-booting gateways don't fail the start of the DS.
+package probe
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// Types of probes.
+const (
+	// TypeFile waits for a file to exist, e.g. "/dev/ttyUSB0".
+	TypeFile = "file"
+	// TypeInterface waits for a network interface to be up with an address,
+	// e.g. "eth0".
+	TypeInterface = "interface"
+	// TypeNTP waits for the clock to be synchronized by NTP.
+	TypeNTP = "ntp"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// pollInterval is the interval between the checks of a probe.
+var pollInterval = 500 * time.Millisecond
+
+// WaitAll waits for the probes in turn, and fails on the first one which
+// times out, unless Optional.
+func WaitAll(probes []common.ProbeInfo) error {
+	for _, p := range probes {
+		err := Wait(p)
+		if err == nil {
+			continue
+		}
+		if !p.Optional {
+			common.LoggingClient.Error(err.Error())
+			return err
+		}
+		common.LoggingClient.Warn(fmt.Sprintf("%v, starting anyway", err))
+	}
+	return nil
+}
+
+// Wait waits for a probe to succeed, until its timeout.
+func Wait(p common.ProbeInfo) error {
+	check, err := checker(p)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(p.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	logged := false
+	for {
+		err = check()
+		if err == nil {
+			if logged {
+				common.LoggingClient.Info(fmt.Sprintf("Probe %s %s ready", p.Type, p.Target))
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("probe %s %s not ready after %v: %v", p.Type, p.Target, timeout, err)
+		}
+		if !logged {
+			common.LoggingClient.Info(fmt.Sprintf("Waiting for probe %s %s: %v", p.Type, p.Target, err))
+			logged = true
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Check checks a probe once, without waiting.
+func Check(p common.ProbeInfo) error {
+	check, err := checker(p)
+	if err != nil {
+		return err
+	}
+	return check()
+}
+
+func checker(p common.ProbeInfo) (func() error, error) {
+	switch strings.ToLower(p.Type) {
+	case TypeFile:
+		return func() error { return fileExists(p.Target) }, nil
+	case TypeInterface:
+		return func() error { return interfaceUp(p.Target) }, nil
+	case TypeNTP:
+		return ntpSynchronized, nil
+	}
+	return nil, fmt.Errorf("unknown probe type %q, expecting %s, %s or %s", p.Type, TypeFile, TypeInterface, TypeNTP)
+}
+
+func fileExists(path string) error {
+	_, err := os.Stat(path)
+	return err
+}
+
+func interfaceUp(name string) error {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return fmt.Errorf("interface %s down", name)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("interface %s without address", name)
+	}
+	return nil
+}
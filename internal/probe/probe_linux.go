@@ -0,0 +1,33 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// timeError is the clock state returned by adjtimex when unsynchronized.
+	timeError = 5
+	// staUnsync is the status flag of an unsynchronized clock.
+	staUnsync = 0x0040
+)
+
+// ntpSynchronized checks the synchronization status of the kernel clock,
+// maintained by the NTP daemon.
+func ntpSynchronized() error {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return err
+	}
+	if state == timeError || tx.Status&staUnsync != 0 {
+		return errors.New("clock not synchronized")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+// +build !linux
+
+package probe
+
+import "errors"
+
+func ntpSynchronized() error {
+	return errors.New("clock synchronization can't be checked on this platform")
+}
@@ -0,0 +1,60 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("probe_test", false, "", "DEBUG")
+	pollInterval = 5 * time.Millisecond
+}
+
+func TestWaitFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "probe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ttyUSB0")
+
+	time.AfterFunc(20*time.Millisecond, func() { ioutil.WriteFile(path, nil, 0644) })
+	if err = Wait(common.ProbeInfo{Type: "File", Target: path, Timeout: 1000}); err != nil {
+		t.Errorf("File not found: %v", err)
+	}
+
+	if err = Wait(common.ProbeInfo{Type: TypeFile, Target: filepath.Join(dir, "ttyUSB1"), Timeout: 20}); err == nil {
+		t.Error("Missing file found")
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	missing := common.ProbeInfo{Type: TypeInterface, Target: "nonexistent0", Timeout: 20}
+	if err := WaitAll([]common.ProbeInfo{missing}); err == nil {
+		t.Error("Missing interface found")
+	}
+
+	missing.Optional = true
+	if err := WaitAll([]common.ProbeInfo{missing}); err != nil {
+		t.Errorf("Optional probe failed the start: %v", err)
+	}
+
+	if err := WaitAll([]common.ProbeInfo{{Type: "udev", Target: "x", Optional: true}}); err != nil {
+		t.Errorf("Unknown probe type failed the start although optional: %v", err)
+	}
+	if err := Wait(common.ProbeInfo{Type: "udev", Target: "x"}); err == nil {
+		t.Error("Unknown probe type accepted")
+	}
+}
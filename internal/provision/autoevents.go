@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// ScheduleEventAdded is called with the Schedule Events of the default auto
+// events created for a Device, so that the internal Scheduler runs them
+// without a restart. It's set by the service, as the Scheduler depends on
+// this package.
+var ScheduleEventAdded func(schEvt models.ScheduleEvent)
+
+// ScheduleEventRemoved is called with the name of each Schedule Event
+// removed from the cache, so that the internal Scheduler stops running it.
+var ScheduleEventRemoved func(name string)
+
+// AutoEventName returns the name of the Schedule Event of a default auto
+// event for a Device.
+func AutoEventName(deviceName string, name string) string {
+	return deviceName + "-" + name
+}
+
+// CreateDefaultAutoEvents creates the Schedule Events of the default auto
+// events matching a Device which has just been added. Failures are logged,
+// as they don't prevent the Device from being used.
+func CreateDefaultAutoEvents(device models.Device) {
+	for _, ae := range common.CurrentConfig.DefaultAutoEvents {
+		if !autoEventMatches(ae, device) {
+			continue
+		}
+		if _, ok := cache.Schedules().ForName(ae.Schedule); !ok {
+			common.LoggingClient.Error(fmt.Sprintf("Schedule %s of default auto event %s cannot be found in cache", ae.Schedule, ae.Name))
+			continue
+		}
+
+		schEvt := models.ScheduleEvent{
+			Name:     AutoEventName(device.Name, ae.Name),
+			Schedule: ae.Schedule,
+			Service:  common.ServiceName,
+			Addressable: models.Addressable{
+				Path:       fmt.Sprintf("%s/device/name/%s/%s", common.APIv1Prefix, device.Name, ae.Command),
+				HTTPMethod: http.MethodGet,
+			},
+		}
+		created, err := createScheduleEvent(schEvt)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Creating default auto event %s for Device %s failed: %v", ae.Name, device.Name, err))
+			continue
+		}
+		if created && ScheduleEventAdded != nil {
+			schEvt, _ = cache.ScheduleEvents().ForName(schEvt.Name)
+			ScheduleEventAdded(schEvt)
+		}
+	}
+}
+
+func autoEventMatches(ae common.DefaultAutoEventInfo, device models.Device) bool {
+	if ae.Profile != "" && ae.Profile == device.Profile.Name {
+		return true
+	}
+	if ae.Label != "" {
+		for _, label := range device.Labels {
+			if label == ae.Label {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -20,6 +20,12 @@ import (
 func LoadDevices(deviceList []common.DeviceConfig) error {
 	common.LoggingClient.Debug(fmt.Sprintf("Loading pre-define Devices from configuration: %v", deviceList))
 	for _, d := range deviceList {
+		name, err := common.SanitizeDeviceName(d.Name)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("creating Device from config failed: %v", err))
+			return err
+		}
+		d.Name = name
 		if _, ok := cache.Devices().ForName(d.Name); ok {
 			common.LoggingClient.Debug(fmt.Sprintf("Device %s exists, using the existing one", d.Name))
 			continue
@@ -61,10 +67,18 @@ func createDevice(dc common.DeviceConfig) error {
 	}
 	device.Origin = millis
 	device.Description = dc.Description
+	if len(dc.Properties) > 0 {
+		device.Location = dc.Properties
+	}
+	if err = common.NotifyDeviceAdded(*device); err != nil {
+		common.LoggingClient.Error(err.Error())
+		return err
+	}
 	common.LoggingClient.Debug(fmt.Sprintf("Adding Device: %v", device))
 	id, err := common.DeviceClient.Add(device)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("Add Device failed %v, error: %v", device, err))
+		common.NotifyDeviceRemoved(*device)
 		return err
 	}
 	if err = common.VerifyIdFormat(id, "Device"); err != nil {
@@ -72,6 +86,20 @@ func createDevice(dc common.DeviceConfig) error {
 	}
 	device.Id = bson.ObjectIdHex(id)
 	cache.Devices().Add(*device)
+	CreateDefaultAutoEvents(*device)
 
 	return nil
 }
+
+// LoadDiscoveredDevice creates a Device found by discovery, named by a
+// provision watcher, unless a Device of the same name exists. It returns
+// whether the Device was created.
+func LoadDiscoveredDevice(dc common.DeviceConfig) (bool, error) {
+	if _, ok := cache.Devices().ForName(dc.Name); ok {
+		return false, nil
+	}
+	if err := createDevice(dc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
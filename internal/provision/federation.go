@@ -0,0 +1,27 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// LoadFederatedDevice creates a Device proxying the Device of a peer DS, and
+// its profile, copied from the peer, if they don't exist yet.
+func LoadFederatedDevice(dc common.DeviceConfig, profile models.DeviceProfile) error {
+	err := loadProfile(profile.Name, func() (models.DeviceProfile, error) {
+		// the copy gets its own id in the Core Metadata of the DS
+		p := profile
+		p.Id = ""
+		return p, nil
+	})
+	if err != nil {
+		return err
+	}
+	return LoadDevices([]common.DeviceConfig{dc})
+}
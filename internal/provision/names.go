@@ -0,0 +1,46 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// maxNameSuffix bounds the suffixes tried for a name already taken.
+const maxNameSuffix = 1000
+
+// UniqueDeviceName applies the DeviceNames uniqueness policy to the name of
+// a new Device: a name already taken by another Device is rejected, or
+// suffixed with "-2", "-3", etc. The suffixed names respect MaxLength.
+func UniqueDeviceName(name string) (string, error) {
+	if _, ok := cache.Devices().ForName(name); !ok {
+		return name, nil
+	}
+	info := common.CurrentConfig.DeviceNames
+	if info.Uniqueness != common.UniquenessSuffix {
+		return "", fmt.Errorf("name conflicted, Device %s exists", name)
+	}
+
+	for i := 2; i <= maxNameSuffix; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		base := name
+		if info.MaxLength > 0 && len(base)+len(suffix) > info.MaxLength {
+			if len(suffix) >= info.MaxLength {
+				break
+			}
+			base = base[:info.MaxLength-len(suffix)]
+		}
+		if _, ok := cache.Devices().ForName(base + suffix); !ok {
+			return base + suffix, nil
+		}
+	}
+	return "", fmt.Errorf("no unique name left for Device %s", name)
+}
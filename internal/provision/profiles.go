@@ -11,10 +11,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/derived"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/yaml.v2"
@@ -56,7 +58,7 @@ func LoadProfiles(path string) error {
 		fName := file.Name()
 		lfName := strings.ToLower(fName)
 		if strings.HasSuffix(lfName, yamlExt) || strings.HasSuffix(lfName, ymlExt) {
-			fullPath := absPath + "/" + fName
+			fullPath := filepath.Join(absPath, fName)
 			yamlFile, err := ioutil.ReadFile(fullPath)
 			if err != nil {
 				common.LoggingClient.Error(fmt.Sprintf("profiles: couldn't read file: %s; %v\n", fullPath, err))
@@ -68,6 +70,10 @@ func LoadProfiles(path string) error {
 				common.LoggingClient.Error(fmt.Sprintf("profiles: invalid Device Profile: %s; %v\n", fullPath, err))
 				continue
 			}
+			if profile.Name == "" {
+				common.LoggingClient.Error(fmt.Sprintf("profiles: Device Profile without a name: %s\n", fullPath))
+				continue
+			}
 
 			// if profile already exists in metadata, skip it
 			if p, ok := pMap[profile.Name]; ok {
@@ -106,6 +112,10 @@ func CreateDescriptorsFromProfile(profile *models.DeviceProfile) {
 	for _, pr := range prs {
 		for _, op := range pr.Get {
 			createDescriptorFromResourceOperation(profile.Name, op)
+			if devObj, ok := cache.Profiles().DeviceObject(profile.Name, op.Object); ok {
+				createDerivedDescriptors(op.Parameter, devObj)
+				createExpandedDescriptors(devObj)
+			}
 		}
 		for _, op := range pr.Set {
 			createDescriptorFromResourceOperation(profile.Name, op)
@@ -114,6 +124,163 @@ func CreateDescriptorsFromProfile(profile *models.DeviceProfile) {
 
 }
 
+// CreateDescriptorsForAliases creates the Value Descriptors for the reading
+// aliases configured for the Devices in the cache.
+func CreateDescriptorsForAliases(aliases map[string]map[string]string) {
+	for devName, params := range aliases {
+		device, ok := cache.Devices().ForName(devName)
+		if !ok {
+			common.LoggingClient.Warn(fmt.Sprintf("reading aliases defined for unknown Device %s", devName))
+			continue
+		}
+		createAliasDescriptors(device, params)
+	}
+}
+
+// CreateAliasDescriptors creates the Value Descriptors for the reading
+// aliases configured for a Device added or updated after the start.
+func CreateAliasDescriptors(device models.Device) {
+	if params, ok := common.CurrentConfig.ReadingAliases[device.Name]; ok {
+		createAliasDescriptors(device, params)
+	}
+}
+
+func createAliasDescriptors(device models.Device, params map[string]string) {
+	for param, alias := range params {
+		if _, ok := cache.ValueDescriptors().ForName(alias); ok {
+			continue
+		}
+		op, ok := resourceOperationForParameter(device.Profile, param)
+		if !ok {
+			common.LoggingClient.Error(fmt.Sprintf("can't find Resource Operation with parameter %s in Device Profile %s", param, device.Profile.Name))
+			continue
+		}
+		devObj, ok := cache.Profiles().DeviceObject(device.Profile.Name, op.Object)
+		if !ok {
+			common.LoggingClient.Error(fmt.Sprintf("can't find Device Object %s to match Resource Operation %v in Device Profile %s", op.Object, op, device.Profile.Name))
+			continue
+		}
+		desc, err := createDescriptor(alias, devObj)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("creating Value Descriptor %s for alias of %s failed: %v", alias, param, err))
+		} else {
+			cache.ValueDescriptors().Add(*desc)
+		}
+		createDerivedDescriptors(alias, devObj)
+	}
+}
+
+// CreateStringDescriptor creates the Value Descriptor of a String reading
+// added by the DS itself to the events it pushes, e.g. the Tenant.
+func CreateStringDescriptor(name string, description string) {
+	if _, ok := cache.ValueDescriptors().ForName(name); ok {
+		return
+	}
+	devObj := models.DeviceObject{Name: name, Description: description}
+	devObj.Properties.Value = models.PropertyValue{Type: "String", ReadWrite: "R"}
+	desc, err := createDescriptor(name, devObj)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("creating Value Descriptor %s failed: %v", name, err))
+	} else {
+		cache.ValueDescriptors().Add(*desc)
+	}
+}
+
+// createDerivedDescriptors creates the Value Descriptors of the statistics
+// of the named reading selected for its device resource.
+func createDerivedDescriptors(name string, devObj models.DeviceObject) {
+	for _, stat := range derived.Statistics(devObj) {
+		statName := derived.ReadingName(name, stat)
+		if _, ok := cache.ValueDescriptors().ForName(statName); ok {
+			continue
+		}
+		statObj := models.DeviceObject{Name: statName, Description: fmt.Sprintf("%s of %s", stat, name)}
+		statObj.Properties.Value = models.PropertyValue{Type: "Float64", ReadWrite: "R"}
+		statObj.Properties.Units = devObj.Properties.Units
+		if units := statObj.Properties.Units.DefaultValue; units != "" {
+			switch stat {
+			case derived.RateOfChange:
+				statObj.Properties.Units.DefaultValue = units + "/min"
+			case derived.Energy:
+				statObj.Properties.Units.DefaultValue = units + "h"
+			}
+		}
+		desc, err := createDescriptor(statName, statObj)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("creating Value Descriptor %s failed: %v", statName, err))
+		} else {
+			cache.ValueDescriptors().Add(*desc)
+		}
+	}
+}
+
+// createExpandedDescriptors creates the Value Descriptors of the elements of
+// an Array device resource expanded into a reading per element. The element
+// names are either listed by the ExpandNames attribute, or numbered up to the
+// Size of the array.
+func createExpandedDescriptors(devObj models.DeviceObject) {
+	names, ok := common.DeviceObjectAttribute(devObj, common.AttrExpandNames)
+	if !ok || names == "" {
+		return
+	}
+	var elementNames []string
+	if strings.Contains(names, ",") {
+		elementNames = strings.Split(names, ",")
+	} else {
+		size, err := strconv.Atoi(devObj.Properties.Value.Size)
+		if err != nil {
+			common.LoggingClient.Warn(fmt.Sprintf("no Size of expanded Device Object %s, Value Descriptors not created", devObj.Name))
+			return
+		}
+		start := 1
+		if v, ok := common.DeviceObjectAttribute(devObj, common.AttrExpandStart); ok {
+			if start, err = strconv.Atoi(v); err != nil {
+				common.LoggingClient.Error(fmt.Sprintf("invalid %s of Device Object %s: %v", common.AttrExpandStart, devObj.Name, err))
+				return
+			}
+		}
+		for i := 0; i < size; i++ {
+			elementNames = append(elementNames, names+strconv.Itoa(start+i))
+		}
+	}
+
+	elementType := "Float64"
+	if v, ok := common.DeviceObjectAttribute(devObj, common.AttrExpandType); ok {
+		elementType = v
+	}
+	for _, name := range elementNames {
+		name = strings.TrimSpace(name)
+		if _, ok := cache.ValueDescriptors().ForName(name); ok {
+			continue
+		}
+		elementObj := models.DeviceObject{Name: name, Description: fmt.Sprintf("Element of %s", devObj.Name)}
+		elementObj.Properties.Value = models.PropertyValue{Type: elementType, ReadWrite: "R"}
+		elementObj.Properties.Units = devObj.Properties.Units
+		desc, err := createDescriptor(name, elementObj)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("creating Value Descriptor %s failed: %v", name, err))
+		} else {
+			cache.ValueDescriptors().Add(*desc)
+		}
+	}
+}
+
+func resourceOperationForParameter(profile models.DeviceProfile, param string) (models.ResourceOperation, bool) {
+	for _, pr := range profile.Resources {
+		for _, op := range pr.Get {
+			if op.Parameter == param {
+				return op, true
+			}
+		}
+		for _, op := range pr.Set {
+			if op.Parameter == param {
+				return op, true
+			}
+		}
+	}
+	return models.ResourceOperation{}, false
+}
+
 func createDescriptorFromResourceOperation(profileName string, op models.ResourceOperation) {
 	if _, ok := cache.ValueDescriptors().ForName(op.Parameter); ok {
 		// Value Descriptor has been created
@@ -9,6 +9,8 @@ package provision
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
@@ -68,38 +70,69 @@ func isScheduleExist(scheduleName string) bool {
 
 func createScheduleEvents(scheduleEvents []models.ScheduleEvent) error {
 	for i := 0; i < len(scheduleEvents); i++ {
-		scheduleEvent := scheduleEvents[i]
-		if scheduleEvent.Service == "" {
-			scheduleEvent.Service = common.ServiceName
+		if _, err := createScheduleEvent(scheduleEvents[i]); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if isScheduleEventExist(scheduleEvent.Name) {
-			common.LoggingClient.Info(fmt.Sprintf("Schedule evnt (%v) exist", scheduleEvent.Name))
-			continue
-		}
+// createScheduleEvent creates a Schedule Event, unless it exists, and
+// returns whether it was created.
+func createScheduleEvent(scheduleEvent models.ScheduleEvent) (bool, error) {
+	if scheduleEvent.Service == "" {
+		scheduleEvent.Service = common.ServiceName
+	}
 
-		err := createScheduleEventAddressable(&scheduleEvent)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Add schedule event addressable (%v) fail: %v", scheduleEvent.Addressable.Name, err.Error()))
-			return err
-		}
+	if isScheduleEventExist(scheduleEvent.Name) {
+		common.LoggingClient.Info(fmt.Sprintf("Schedule evnt (%v) exist", scheduleEvent.Name))
+		return false, nil
+	}
 
-		id, err := common.ScheduleEventClient.Add(&scheduleEvent)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("Add schedule event (%v) fail: %v", scheduleEvent.Name, err.Error()))
-			return err
+	err := createScheduleEventAddressable(&scheduleEvent)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Add schedule event addressable (%v) fail: %v", scheduleEvent.Addressable.Name, err.Error()))
+		return false, err
+	}
+
+	id, err := common.ScheduleEventClient.Add(&scheduleEvent)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Add schedule event (%v) fail: %v", scheduleEvent.Name, err.Error()))
+		return false, err
+	}
+	if err = common.VerifyIdFormat(id, "Schedule Event"); err != nil {
+		return false, err
+	}
+	scheduleEvent.Id = bson.ObjectIdHex(id)
+	err = cache.ScheduleEvents().Add(scheduleEvent)
+	if err != nil {
+		return false, err
+	}
+	common.LoggingClient.Info(fmt.Sprintf(fmt.Sprintf("Add schedule event (%v) successful", scheduleEvent.Name)))
+	return true, nil
+}
+
+// RemoveScheduleEventsForDevice removes the Schedule Events running the
+// commands of a Device, both in the cache and in Core Metadata, and returns
+// their names.
+func RemoveScheduleEventsForDevice(deviceName string) []string {
+	prefix := fmt.Sprintf("%s/device/name/%s/", common.APIv1Prefix, deviceName)
+	var removed []string
+	for _, schEvt := range cache.ScheduleEvents().All() {
+		if !strings.HasPrefix(schEvt.Addressable.Path, prefix) {
+			continue
 		}
-		if err = common.VerifyIdFormat(id, "Schedule Event"); err != nil {
-			return err
+		if err := common.ScheduleEventClient.DeleteByName(schEvt.Name); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Delete schedule event (%v) fail: %v", schEvt.Name, err))
+			continue
 		}
-		scheduleEvent.Id = bson.ObjectIdHex(id)
-		err = cache.ScheduleEvents().Add(scheduleEvent)
-		if err != nil {
-			return err
+		cache.ScheduleEvents().RemoveByName(schEvt.Name)
+		if ScheduleEventRemoved != nil {
+			ScheduleEventRemoved(schEvt.Name)
 		}
-		common.LoggingClient.Info(fmt.Sprintf(fmt.Sprintf("Add schedule event (%v) successful", scheduleEvent.Name)))
+		removed = append(removed, schEvt.Name)
 	}
-	return nil
+	return removed
 }
 
 func createScheduleEventAddressable(scheduleEvent *models.ScheduleEvent) error {
@@ -150,3 +183,33 @@ func isScheduleEventExist(scheduleEventName string) bool {
 		return false
 	}
 }
+
+// ApplyScheduleFrequencies sets the Frequency of the cached Schedules to the
+// one of the Writable section of the configuration, or of its Schedules if
+// not overridden, updating Core Metadata. It returns true if any changed.
+func ApplyScheduleFrequencies(config *common.Config) bool {
+	frequencies := make(map[string]string)
+	for _, sch := range config.Schedules {
+		frequencies[sch.Name] = sch.Frequency
+	}
+	for name, frequency := range config.Writable.ScheduleFrequencies {
+		frequencies[name] = frequency
+	}
+
+	changed := false
+	for _, sch := range cache.Schedules().All() {
+		frequency, ok := frequencies[sch.Name]
+		if !ok || frequency == "" || frequency == sch.Frequency {
+			continue
+		}
+		common.LoggingClient.Info(fmt.Sprintf("Changing the frequency of Schedule %s from %s to %s", sch.Name, sch.Frequency, frequency))
+		sch.Frequency = frequency
+		if err := common.ScheduleClient.Update(sch); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Update schedule (%v) fail: %v", sch.Name, err))
+			continue
+		}
+		cache.Schedules().Update(sch)
+		changed = true
+	}
+	return changed
+}
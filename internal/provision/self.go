@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/selfdevice"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// LoadSelfDevice creates the Device representing the gateway, and its
+// standard profile, if enabled and they don't exist yet.
+func LoadSelfDevice(info common.SelfDeviceInfo) error {
+	if !info.Enabled {
+		return nil
+	}
+
+	if err := loadSelfProfile(); err != nil {
+		return err
+	}
+
+	name := common.SelfDeviceName()
+	dc := common.DeviceConfig{
+		Name:        name,
+		Profile:     selfdevice.ProfileName,
+		Description: "Gateway hosting " + common.ServiceName,
+		Labels:      info.Labels,
+		Addressable: models.Addressable{Name: name, Protocol: "OTHER", Address: "localhost"},
+	}
+	return LoadDevices([]common.DeviceConfig{dc})
+}
+
+// loadSelfProfile adds the standard profile of the Device to the cache,
+// creating it in Core Metadata if needed.
+func loadSelfProfile() error {
+	return loadProfile(selfdevice.ProfileName, selfdevice.Profile)
+}
+
+// loadProfile adds the named profile to the cache, reading it from Core
+// Metadata, or creating it there from the one returned by build if it
+// doesn't exist yet.
+func loadProfile(name string, build func() (models.DeviceProfile, error)) error {
+	if _, ok := cache.Profiles().ForName(name); ok {
+		return nil
+	}
+
+	profile, err := common.DeviceProfileClient.DeviceProfileForName(name)
+	if err == nil {
+		cache.Profiles().Add(profile)
+		return nil
+	}
+	if _, ok := err.(types.ErrNotFound); !ok {
+		common.LoggingClient.Error(fmt.Sprintf("profiles: couldn't read Device Profile %s from Core Metadata: %v", name, err))
+		return err
+	}
+
+	profile, err = build()
+	if err != nil {
+		return err
+	}
+	id, err := common.DeviceProfileClient.Add(&profile)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("profiles: Add Device Profile %s to Core Metadata failed: %v", profile.Name, err))
+		return err
+	}
+	if err = common.VerifyIdFormat(id, "Device Profile"); err != nil {
+		return err
+	}
+	profile.Id = bson.ObjectIdHex(id)
+	cache.Profiles().Add(profile)
+	CreateDescriptorsFromProfile(&profile)
+	return nil
+}
@@ -0,0 +1,190 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultNameTemplate names the discovered Devices after their watcher and
+// the values of their identifiers, sorted by identifier name.
+const DefaultNameTemplate = `{{.Watcher}}{{range .Values}}-{{.}}{{end}}`
+
+// Watcher is a compiled provision watcher, matching discovered Devices by
+// their identifiers.
+type Watcher struct {
+	Name    string
+	Profile string
+	// identifiers are the regular expressions the identifiers must fully
+	// match.
+	identifiers map[string]*regexp.Regexp
+	// blocking are the identifier values excluding a Device.
+	blocking map[string][]string
+	name     *template.Template
+}
+
+// NameData is the data of the device name templates.
+type NameData struct {
+	Watcher     string
+	Profile     string
+	Identifiers map[string]string
+	// Values are the values of the identifiers, sorted by identifier name.
+	Values []string
+}
+
+// NewWatcher compiles the identifiers of a watcher configuration. The Key
+// and MatchString settings are a shorthand for a single identifier.
+func NewWatcher(name string, info common.WatcherInfo) (*Watcher, error) {
+	identifiers := make(map[string]string, len(info.Identifiers)+1)
+	for k, v := range info.Identifiers {
+		identifiers[k] = v
+	}
+	if info.Key != "" {
+		identifiers[info.Key] = info.MatchString
+	}
+	return newWatcher(name, info.Profile, identifiers, info.BlockingIdentifiers, info.NameTemplate)
+}
+
+// WatcherFromModel compiles a provision watcher of Core Metadata.
+func WatcherFromModel(pw models.ProvisionWatcher) (*Watcher, error) {
+	return newWatcher(pw.Name, pw.Profile.Name, pw.Identifiers, nil, "")
+}
+
+func newWatcher(name string, profile string, identifiers map[string]string, blocking map[string][]string, nameTemplate string) (*Watcher, error) {
+	w := &Watcher{
+		Name:        name,
+		Profile:     profile,
+		identifiers: make(map[string]*regexp.Regexp, len(identifiers)),
+		blocking:    blocking,
+	}
+	for k, expr := range identifiers {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("watcher %s: invalid identifier %s: %v", name, k, err)
+		}
+		w.identifiers[k] = re
+	}
+
+	if nameTemplate == "" {
+		nameTemplate = DefaultNameTemplate
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("watcher %s: invalid name template: %v", name, err)
+	}
+	w.name = t
+	return w, nil
+}
+
+// Matches returns true if the identifiers of a discovered Device match all
+// the identifiers of the watcher, and none of its blocking identifiers.
+func (w *Watcher) Matches(identifiers map[string]string) bool {
+	for k, re := range w.identifiers {
+		v, ok := identifiers[k]
+		if !ok || !re.MatchString(v) {
+			return false
+		}
+	}
+	for k, values := range w.blocking {
+		v, ok := identifiers[k]
+		if !ok {
+			continue
+		}
+		for _, blocked := range values {
+			if v == blocked {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DeviceName returns the name of a discovered Device, from the name
+// template of the watcher, sanitized as per the DeviceNames settings.
+func (w *Watcher) DeviceName(identifiers map[string]string) (string, error) {
+	keys := make([]string, 0, len(identifiers))
+	for k := range identifiers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	data := NameData{Watcher: w.Name, Profile: w.Profile, Identifiers: identifiers}
+	for _, k := range keys {
+		data.Values = append(data.Values, identifiers[k])
+	}
+
+	var buf bytes.Buffer
+	if err := w.name.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("watcher %s: naming the device failed: %v", w.Name, err)
+	}
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", fmt.Errorf("watcher %s: empty device name", w.Name)
+	}
+	name, err := common.SanitizeDeviceName(name)
+	if err != nil {
+		return "", fmt.Errorf("watcher %s: %v", w.Name, err)
+	}
+	return name, nil
+}
+
+// WatcherMatch is a discovered Device matched by a watcher.
+type WatcherMatch struct {
+	Watcher    string
+	Profile    string
+	DeviceName string
+}
+
+// Matcher runs discovered Devices through a set of watchers.
+type Matcher struct {
+	watchers []*Watcher
+}
+
+// NewMatcher returns a Matcher of the given watchers, tried in name order.
+func NewMatcher(watchers ...*Watcher) *Matcher {
+	sorted := append([]*Watcher(nil), watchers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return &Matcher{watchers: sorted}
+}
+
+// LoadWatchers compiles the watchers of the configuration into a Matcher,
+// skipping the invalid ones.
+func LoadWatchers(infos map[string]common.WatcherInfo) *Matcher {
+	watchers := make([]*Watcher, 0, len(infos))
+	for name, info := range infos {
+		w, err := NewWatcher(name, info)
+		if err != nil {
+			common.LoggingClient.Error(err.Error())
+			continue
+		}
+		watchers = append(watchers, w)
+	}
+	return NewMatcher(watchers...)
+}
+
+// Match returns the match of the first watcher matching the identifiers of
+// a discovered Device, if any.
+func (m *Matcher) Match(identifiers map[string]string) (WatcherMatch, bool, error) {
+	for _, w := range m.watchers {
+		if !w.Matches(identifiers) {
+			continue
+		}
+		name, err := w.DeviceName(identifiers)
+		if err != nil {
+			return WatcherMatch{}, false, err
+		}
+		return WatcherMatch{Watcher: w.Name, Profile: w.Profile, DeviceName: name}, true, nil
+	}
+	return WatcherMatch{}, false, nil
+}
@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+func TestWatcherMatch(t *testing.T) {
+	meters, err := NewWatcher("meters", common.WatcherInfo{
+		Profile:             "Meter",
+		Key:                 "model",
+		MatchString:         "PM[0-9]+",
+		Identifiers:         map[string]string{"address": "10\\.0\\.0\\..*"},
+		BlockingIdentifiers: map[string][]string{"serial": {"0000"}},
+		NameTemplate:        "meter-{{.Identifiers.serial}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	any, err := NewWatcher("other", common.WatcherInfo{Profile: "Generic", Key: "model", MatchString: ".*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMatcher(any, meters)
+
+	tests := []struct {
+		identifiers map[string]string
+		watcher     string
+		deviceName  string
+	}{
+		{map[string]string{"model": "PM5560", "address": "10.0.0.7", "serial": "1234"}, "meters", "meter-1234"},
+		// the regular expressions match whole values
+		{map[string]string{"model": "XPM5560", "address": "10.0.0.7", "serial": "1234"}, "other", "other-10.0.0.7-XPM5560-1234"},
+		{map[string]string{"model": "PM5560", "address": "10.0.0.7", "serial": "0000"}, "other", "other-10.0.0.7-PM5560-0000"},
+		{map[string]string{"address": "10.0.0.7"}, "", ""},
+	}
+	for _, tt := range tests {
+		match, ok, err := m.Match(tt.identifiers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != (tt.watcher != "") || match.Watcher != tt.watcher || match.DeviceName != tt.deviceName {
+			t.Errorf("Unexpected match %+v for %v", match, tt.identifiers)
+		}
+	}
+
+	// the name template refers to a missing identifier
+	if _, _, err := NewMatcher(meters).Match(map[string]string{"model": "PM1", "address": "10.0.0.1"}); err == nil {
+		t.Errorf("Expected an error for a missing identifier in the name template")
+	}
+	if _, err := NewWatcher("bad", common.WatcherInfo{Key: "model", MatchString: "("}); err == nil {
+		t.Errorf("Expected an error for an invalid regular expression")
+	}
+}
+
+func TestWatcherDeviceNameSanitization(t *testing.T) {
+	saved := common.CurrentConfig
+	defer func() { common.CurrentConfig = saved }()
+
+	w, err := NewWatcher("meters", common.WatcherInfo{Key: "serial", MatchString: ".*", NameTemplate: "meter {{.Identifiers.serial}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		info     common.DeviceNamesInfo
+		serial   string
+		expected string
+	}{
+		{common.DeviceNamesInfo{}, "AB/12 34", "meter_AB_12_34"},
+		{common.DeviceNamesInfo{}, "AB//12", "meter_AB_12"},
+		{common.DeviceNamesInfo{Replacement: "-", MaxLength: 10}, "AB/12 34", "meter-AB-1"},
+		{common.DeviceNamesInfo{Allowed: "a-z"}, "AB", "meter_"},
+		{common.DeviceNamesInfo{Strict: true}, "AB/12", ""},
+	}
+	for _, tt := range tests {
+		common.CurrentConfig = &common.Config{DeviceNames: tt.info}
+		name, err := w.DeviceName(map[string]string{"serial": tt.serial})
+		if tt.expected == "" {
+			if err == nil {
+				t.Errorf("Expected an error for %s with %+v", tt.serial, tt.info)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %s with %+v: %v", tt.serial, tt.info, err)
+		} else if name != tt.expected {
+			t.Errorf("Expected name %s for %s with %+v, got %s", tt.expected, tt.serial, tt.info, name)
+		}
+	}
+}
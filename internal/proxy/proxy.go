@@ -0,0 +1,127 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package proxy routes the outbound HTTP requests of the DS, i.e. those of
+// all clients using the default transport, through the configured proxy.
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// Configure sets the proxy of the default HTTP transport. If no proxy is
+// configured, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+// keep applying.
+func Configure(info common.ProxyInfo) error {
+	if info.HTTPProxy == "" && info.HTTPSProxy == "" {
+		return nil
+	}
+
+	var user *url.Userinfo
+	if info.Username != "" {
+		password, err := common.LookupSecret(info.Password)
+		if err != nil {
+			return fmt.Errorf("couldn't look up the proxy password: %v", err)
+		}
+		user = url.UserPassword(info.Username, strings.TrimSpace(string(password)))
+	}
+	httpProxy, err := parseProxy(info.HTTPProxy, user)
+	if err != nil {
+		return err
+	}
+	httpsProxy, err := parseProxy(info.HTTPSProxy, user)
+	if err != nil {
+		return err
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("default HTTP transport can't be configured")
+	}
+	transport.Proxy = proxyFunc(httpProxy, httpsProxy, parseNoProxy(info.NoProxy))
+	common.LoggingClient.Info(fmt.Sprintf("Outbound requests use proxy %s (HTTP) and %s (HTTPS)", info.HTTPProxy, info.HTTPSProxy))
+	return nil
+}
+
+func parseProxy(proxy string, user *url.Userinfo) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %s: %v", proxy, err)
+	}
+	if user != nil {
+		u.User = user
+	}
+	return u, nil
+}
+
+func proxyFunc(httpProxy *url.URL, httpsProxy *url.URL, noProxy []string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass(req.URL, noProxy) {
+			return nil, nil
+		}
+		if req.URL.Scheme == "https" {
+			return httpsProxy, nil
+		}
+		return httpProxy, nil
+	}
+}
+
+func parseNoProxy(noProxy string) []string {
+	entries := make([]string, 0)
+	for _, e := range strings.Split(noProxy, ",") {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// bypass returns true if the request is to a loopback address or a host
+// matching the NO_PROXY style entries: "*", a host or domain (which also
+// matches its subdomains), an IP address or a CIDR, optionally with a port.
+func bypass(u *url.URL, noProxy []string) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	ip := net.ParseIP(host)
+	if host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return true
+	}
+
+	for _, e := range noProxy {
+		if e == "*" {
+			return true
+		}
+		if _, n, err := net.ParseCIDR(e); err == nil {
+			if ip != nil && n.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(e); err == nil {
+			if p != port {
+				continue
+			}
+			e = h
+		}
+		e = strings.TrimPrefix(e, "*")
+		if host == strings.TrimPrefix(e, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(e, ".")) {
+			return true
+		}
+	}
+	return false
+}
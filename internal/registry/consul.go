@@ -7,17 +7,47 @@
 package registry
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 	consulapi "github.com/hashicorp/consul/api"
 )
 
+const watchWaitTime = 5 * time.Minute
+
+// watchRetryDelay is the delay before retrying a failed watch or campaign.
+var watchRetryDelay = 5 * time.Second
+
 type ConsulClient struct {
 	Consul *consulapi.Client
+	// Logger reports the failures of the watches and campaigns, which are
+	// printed until it is set.
+	Logger logger.LoggingClient
+}
+
+// logError reports the failure of a watch or campaign.
+func (c *ConsulClient) logError(msg string) {
+	if c.Logger == nil {
+		fmt.Println(msg)
+		return
+	}
+	c.Logger.Error(msg)
+}
+
+// retryWait waits for watchRetryDelay, returning false if stopped meanwhile.
+func retryWait(stop <-chan struct{}) bool {
+	select {
+	case <-time.After(watchRetryDelay):
+		return true
+	case <-stop:
+		return false
+	}
 }
 
 func (c *ConsulClient) Init(config RegistryConfig) error {
@@ -37,6 +67,7 @@ func (c *ConsulClient) Init(config RegistryConfig) error {
 		Name:    config.ServiceName,
 		Address: config.ServiceAddress,
 		Port:    config.ServicePort,
+		Tags:    config.Tags,
 	})
 	if err != nil {
 		return err
@@ -77,129 +108,165 @@ func (c *ConsulClient) GetServiceEndpoint(serviceKey string) (ServiceEndpoint, e
 	return endpoint, nil
 }
 
-func (c *ConsulClient) CheckKeyValuePairs(configuration interface{}, applicationName string, profiles []string) error {
-	fmt.Println("Look at the key/value pairs to update configuration from registry ...")
-	// Consul wasn't initialized
+// GetKeyValues returns the values of the keys under a prefix, by their path
+// relative to it.
+func (c *ConsulClient) GetKeyValues(prefix string) (map[string]string, error) {
 	if c.Consul == nil {
-		err := errors.New("Consul wasn't initialized, can't check key/value pairs")
-		return err
+		return nil, errors.New("Consul wasn't initialized, can't get key/value pairs")
 	}
+	pairs, _, err := c.Consul.KV().List(prefix+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+	return relativeValues(prefix, pairs), nil
+}
 
+// PutKeyValues sets the values of keys under a prefix, given by their path
+// relative to it.
+func (c *ConsulClient) PutKeyValues(prefix string, values map[string]string) error {
+	if c.Consul == nil {
+		return errors.New("Consul wasn't initialized, can't put key/value pairs")
+	}
 	kv := c.Consul.KV()
+	for key, value := range values {
+		if _, err := kv.Put(&consulapi.KVPair{Key: prefix + "/" + key, Value: []byte(value)}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Reflection to get the field names (These will be part of the key names)
-	configValue := reflect.ValueOf(configuration)
-	// Loop through the fields
-	for i := 0; i < configValue.Elem().NumField(); i++ {
-		fieldName := configValue.Elem().Type().Field(i).Name
-		fieldValue := configValue.Elem().Field(i)
-		keyPath := "config/" + applicationName + ";" + strings.Join(profiles, ";") + "/" + fieldName
-		var byteValue []byte // Byte array that will be passed to Consul
-
-		// Switch off of the value type
-		switch fieldValue.Kind() {
-		case reflect.Bool:
-			byteValue = []byte(strconv.FormatBool(fieldValue.Bool()))
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
-			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue, err := strconv.ParseBool(string(pair.Value))
-				if err != nil {
-					return err
-				}
-
-				fieldValue.SetBool(newValue) // Set the new value
-			}
-			break
-		case reflect.String:
-			byteValue = []byte(fieldValue.String())
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
+// WatchPrefix calls onChange with the values of the keys under a prefix, as
+// returned by GetKeyValues, when they are first read and whenever they
+// change, using blocking queries, until stop is closed.
+func (c *ConsulClient) WatchPrefix(prefix string, stop <-chan struct{}, onChange func(values map[string]string)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	var index uint64
+	var last map[string]string
+	for ctx.Err() == nil {
+		opts := (&consulapi.QueryOptions{WaitIndex: index, WaitTime: watchWaitTime}).WithContext(ctx)
+		pairs, meta, err := c.Consul.KV().List(prefix+"/", opts)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logError(fmt.Sprintf("Watching prefix %s failed: %v", prefix, err))
+				retryWait(ctx.Done())
 			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue := string(pair.Value)
-
-				fieldValue.SetString(newValue) // Set the new value
+			continue
+		}
+		// the index is reset if it goes backwards, e.g. on a Consul restore
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		values := relativeValues(prefix, pairs)
+		if last == nil || !reflect.DeepEqual(last, values) {
+			last = values
+			onChange(values)
+		}
+	}
+}
+
+// relativeValues returns the values of key/value pairs by their path
+// relative to prefix, ignoring the folders.
+func relativeValues(prefix string, pairs consulapi.KVPairs) map[string]string {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		if strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		values[strings.TrimPrefix(pair.Key, prefix+"/")] = string(pair.Value)
+	}
+	return values
+}
+
+// WatchKey calls onChange with the value of a key, empty if missing, when it
+// is first read and whenever it changes, using blocking queries, until stop
+// is closed.
+func (c *ConsulClient) WatchKey(key string, stop <-chan struct{}, onChange func(value string)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	var index uint64
+	var last *string
+	for ctx.Err() == nil {
+		opts := (&consulapi.QueryOptions{WaitIndex: index, WaitTime: watchWaitTime}).WithContext(ctx)
+		pair, meta, err := c.Consul.KV().Get(key, opts)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logError(fmt.Sprintf("Watching key %s failed: %v", key, err))
+				retryWait(ctx.Done())
 			}
-			break
-		case reflect.Int:
-			byteValue = []byte(strconv.FormatInt(fieldValue.Int(), 10))
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
+			continue
+		}
+		// the index is reset if it goes backwards, e.g. on a Consul restore
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		value := ""
+		if pair != nil {
+			value = string(pair.Value)
+		}
+		if last == nil || *last != value {
+			last = &value
+			onChange(value)
+		}
+	}
+}
+
+// Campaign competes for the lock of a key, held through a session which
+// expires after sessionTTL if its holder disappears, calling onChange when
+// the leadership is gained or lost, until stop is closed. The failures are
+// retried after watchRetryDelay. The lock is released when stopped.
+func (c *ConsulClient) Campaign(key string, sessionTTL string, stop <-chan struct{}, onChange func(leader bool)) {
+	for {
+		lock, err := c.Consul.LockOpts(&consulapi.LockOptions{Key: key, SessionTTL: sessionTTL})
+		if err != nil {
+			c.logError(fmt.Sprintf("Campaigning for key %s failed: %v", key, err))
+			if retryWait(stop) {
+				continue
 			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue, err := strconv.ParseInt(string(pair.Value), 10, 64)
-				if err != nil {
-					return err
-				}
-
-				fieldValue.SetInt(newValue) // Set the new value
+			return
+		}
+		lost, err := lock.Lock(stop)
+		if err != nil {
+			c.logError(fmt.Sprintf("Campaigning for key %s failed: %v", key, err))
+			if retryWait(stop) {
+				continue
 			}
-			break
-
-		default:
-			// TODO Can't parse struct
-			fmt.Println("Unexpected fieldValue: ", fieldValue)
-			//err := errors.New("Can't get the type of field: " + keyPath)
-			//return err
+			return
+		}
+		if lost == nil {
+			return
+		}
 
+		onChange(true)
+		select {
+		case <-lost:
+			onChange(false)
+			lock.Unlock()
+		case <-stop:
+			onChange(false)
+			lock.Unlock()
+			return
 		}
 	}
+}
 
-	return nil
+// Deregister removes the service, registered by Init with its name as ID,
+// and its health check.
+func (c *ConsulClient) Deregister(serviceName string) error {
+	return c.Consul.Agent().ServiceDeregister(serviceName)
 }
@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// errorLogger records the errors logged.
+type errorLogger struct {
+	mutex  sync.Mutex
+	errors []string
+}
+
+func (l *errorLogger) Debug(msg string, labels ...string) error { return nil }
+func (l *errorLogger) Info(msg string, labels ...string) error  { return nil }
+func (l *errorLogger) Trace(msg string, labels ...string) error { return nil }
+func (l *errorLogger) Warn(msg string, labels ...string) error  { return nil }
+
+func (l *errorLogger) Error(msg string, labels ...string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.errors = append(l.errors, msg)
+	return nil
+}
+
+func (l *errorLogger) count() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.errors)
+}
+
+func TestCampaignRetries(t *testing.T) {
+	previous := watchRetryDelay
+	defer func() { watchRetryDelay = previous }()
+	watchRetryDelay = time.Millisecond
+
+	consul, err := consulapi.NewClient(consulapi.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc := &errorLogger{}
+	c := &ConsulClient{Consul: consul, Logger: lc}
+
+	// an invalid session TTL fails every attempt to campaign
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		c.Campaign("leader", "forever", stop, func(leader bool) {})
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for lc.count() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Campaign retried %d times", lc.count())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Campaign not stopped")
+	}
+	if msg := lc.errors[0]; !strings.Contains(msg, "leader") {
+		t.Errorf("Unexpected error %s", msg)
+	}
+}
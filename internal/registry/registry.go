@@ -12,8 +12,23 @@ type Client interface {
 
 	GetServiceEndpoint(serviceKey string) (ServiceEndpoint, error)
 
-	// Look at the key/value pairs to update configuration
-	CheckKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string) error
+	// Get the values of the keys under a prefix, by their relative path
+	GetKeyValues(prefix string) (map[string]string, error)
+
+	// Set the values of keys under a prefix, given by their relative path
+	PutKeyValues(prefix string, values map[string]string) error
+
+	// Watch the values of the keys under a prefix, calling onChange when they change, until stop is closed
+	WatchPrefix(prefix string, stop <-chan struct{}, onChange func(values map[string]string))
+
+	// Watch the value of a key, calling onChange when it changes, until stop is closed
+	WatchKey(key string, stop <-chan struct{}, onChange func(value string))
+
+	// Compete with the other instances for the leadership of key, calling onChange when it is gained or lost, until stop is closed
+	Campaign(key string, sessionTTL string, stop <-chan struct{}, onChange func(leader bool))
+
+	// Remove the service and its check from the registry
+	Deregister(serviceName string) error
 }
 
 type ServiceEndpoint struct {
@@ -30,4 +45,5 @@ type RegistryConfig struct {
 	ServicePort    int
 	CheckAddress   string
 	CheckInterval  string
+	Tags           []string
 }
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/robfig/cron"
+)
+
+// driverJobs are the jobs scheduled by the driver, by name, added to the
+// internal Scheduler whenever it starts.
+var driverJobs = make(map[string]*driverJob)
+
+type driverJob struct {
+	name string
+	spec string
+	job  func()
+}
+
+func (dj *driverJob) Run() {
+	defer func() {
+		if r := recover(); r != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Driver job %s panicked: %v", dj.name, r))
+		}
+	}()
+	dj.job()
+}
+
+// DriverScheduler implements the Scheduler of the DriverContext.
+type DriverScheduler struct{}
+
+// Schedule runs a job of the driver according to a cron spec.
+func (DriverScheduler) Schedule(name string, spec string, job func()) error {
+	if _, err := cron.Parse(spec); err != nil {
+		return fmt.Errorf("invalid spec %q of driver job %s: %v", spec, name, err)
+	}
+
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	dj := &driverJob{name: name, spec: spec, job: job}
+	driverJobs[name] = dj
+	if cr != nil {
+		return addJob(driverJobKey(name), spec, dj)
+	}
+	return nil
+}
+
+// Unschedule removes a job of the driver.
+func (DriverScheduler) Unschedule(name string) {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	delete(driverJobs, name)
+	removeJob(driverJobKey(name))
+}
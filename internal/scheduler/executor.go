@@ -14,8 +14,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -27,9 +29,23 @@ const (
 type schEvtExec struct {
 	sch    models.Schedule
 	schEvt models.ScheduleEvent
+	runs   uint64
 }
 
 func (se *schEvtExec) Run() {
+	if handler.Draining() {
+		common.LoggingClient.Debug(fmt.Sprintf("Schedule Event %s skipped, device service is draining", se.schEvt.Name))
+		return
+	}
+	if common.InStandby() {
+		common.LoggingClient.Debug(fmt.Sprintf("Schedule Event %s skipped, device service is in standby", se.schEvt.Name))
+		return
+	}
+	if !throttle.Admit(&se.runs) {
+		common.LoggingClient.Debug(fmt.Sprintf("Schedule Event %s skipped, device service is throttled", se.schEvt.Name))
+		return
+	}
+
 	isCmd, err := path.Match(common.SchedulerExecCMDPattern, se.schEvt.Addressable.Path)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("Schedule Event Path parsing failed: %v, %v", se.schEvt, err))
@@ -58,6 +74,15 @@ func execCmd(se *schEvtExec) {
 		common.LoggingClient.Error(fmt.Sprintf("Schedule Event execution failed: %v, %v", se.schEvt, err))
 		return
 	}
+	if current, renamed := common.ResolveDeviceName(deviceName); renamed {
+		if _, ok := cache.Devices().ForName(deviceName); !ok {
+			deviceName = current
+		}
+	}
+	if device, ok := cache.Devices().ForName(deviceName); ok && !common.IsLeader(device) {
+		common.LoggingClient.Debug(fmt.Sprintf("Schedule Event %s skipped, device %s is polled by another instance", se.schEvt.Name, deviceName))
+		return
+	}
 	vars := make(map[string]string, 2)
 	vars[nameVar] = deviceName
 	vars[commandVar] = cmdName
@@ -9,42 +9,262 @@ package scheduler
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"github.com/robfig/cron"
 )
 
+const heartbeatSpec = "@every 1s"
+
+// maxRemovedJobs is the number of removed jobs beyond which the cron of the
+// internal Scheduler is rebuilt without them.
+const maxRemovedJobs = 32
+
 var (
-	schMgrOnce sync.Once
-	cr         *cron.Cron
+	schMgrMutex sync.Mutex
+	cr          *cron.Cron
+	stopCh      chan struct{}
+	lastTick    time.Time
+	fixedJobs   []*removableJob                  // the heartbeat and Snapshots
+	jobs        = make(map[string]*removableJob) // key is jobKey
+	removedJobs int
 )
 
+// removableJob is a job of the internal Scheduler that can be removed while
+// it's running: as jobs can't be dropped from a running cron, a removed job
+// stays scheduled but does nothing until the cron is rebuilt, once there are
+// more than maxRemovedJobs, or the Scheduler is started again.
+type removableJob struct {
+	spec    string
+	job     cron.Job
+	removed int32
+}
+
+func (rj *removableJob) Run() {
+	if atomic.LoadInt32(&rj.removed) == 0 {
+		rj.job.Run()
+	}
+}
+
+func schEvtKey(name string) string {
+	return "scheduleEvent/" + name
+}
+
+func driverJobKey(name string) string {
+	return "driver/" + name
+}
+
+// addJob schedules a job under the given key, replacing the job scheduled
+// under it if any. It must be called with schMgrMutex held and the Scheduler
+// running.
+func addJob(key string, spec string, job cron.Job) error {
+	rj := &removableJob{spec: spec, job: job}
+	if err := cr.AddJob(spec, rj); err != nil {
+		return err
+	}
+	dropJob(key)
+	jobs[key] = rj
+	compactJobs()
+	return nil
+}
+
+// addFixedJob schedules a job which is never removed. It must be called with
+// schMgrMutex held and the Scheduler running.
+func addFixedJob(spec string, job cron.Job) error {
+	rj := &removableJob{spec: spec, job: job}
+	if err := cr.AddJob(spec, rj); err != nil {
+		return err
+	}
+	fixedJobs = append(fixedJobs, rj)
+	return nil
+}
+
+// removeJob stops running the job scheduled under the given key, if any. It
+// must be called with schMgrMutex held.
+func removeJob(key string) bool {
+	if !dropJob(key) {
+		return false
+	}
+	compactJobs()
+	return true
+}
+
+func dropJob(key string) bool {
+	rj, ok := jobs[key]
+	if !ok {
+		return false
+	}
+	atomic.StoreInt32(&rj.removed, 1)
+	delete(jobs, key)
+	removedJobs++
+	return true
+}
+
+// compactJobs replaces the cron by a new one without the removed jobs once
+// there are more than maxRemovedJobs of them, so that the jobs of the
+// Schedule Events repeatedly added and removed don't pile up. It must be
+// called with schMgrMutex held and the Scheduler running.
+func compactJobs() {
+	if removedJobs <= maxRemovedJobs {
+		return
+	}
+	old := cr
+	cr = cron.New()
+	for _, rj := range fixedJobs {
+		cr.AddJob(rj.spec, rj)
+	}
+	for _, rj := range jobs {
+		cr.AddJob(rj.spec, rj)
+	}
+	old.Stop()
+	cr.Start()
+	common.LoggingClient.Debug(fmt.Sprintf("Rebuilt internal Scheduler without %d removed jobs", removedJobs))
+	removedJobs = 0
+}
+
+// StartScheduler starts the internal Scheduler with the Schedule Events in
+// cache, unless it is already running.
 func StartScheduler() {
-	schMgrOnce.Do(func() {
-		cr = cron.New()
-		schEvtExecs := loadSchEvts()
-		for i, _ := range schEvtExecs {
-			common.LoggingClient.Info(fmt.Sprintf("Initializing Schedule Event Executor: %v", *schEvtExecs[i]))
-			spec, err := schEvtExecs[i].cronSpec()
-			if err != nil {
-				common.LoggingClient.Error(err.Error())
-				continue
-			}
-			cr.AddJob(spec, schEvtExecs[i])
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr != nil {
+		return
+	}
+	cr = cron.New()
+	fixedJobs = nil
+	jobs = make(map[string]*removableJob)
+	removedJobs = 0
+	lastTick = time.Now()
+	addFixedJob(heartbeatSpec, cron.FuncJob(tick))
+	schEvtExecs := loadSchEvts()
+	for i, _ := range schEvtExecs {
+		common.LoggingClient.Info(fmt.Sprintf("Initializing Schedule Event Executor: %v", *schEvtExecs[i]))
+		spec, err := schEvtExecs[i].cronSpec()
+		if err != nil {
+			common.LoggingClient.Error(err.Error())
+			continue
 		}
-		common.LoggingClient.Info("Starting internal Scheduler")
-		cr.Start()
-		common.LoggingClient.Info("Started internal Scheduler")
-	})
+		addJob(schEvtKey(schEvtExecs[i].schEvt.Name), spec, schEvtExecs[i])
+	}
+	stopCh = make(chan struct{})
+	for _, info := range common.CurrentConfig.Snapshots {
+		exec := &snapshotExec{info: info, stop: stopCh}
+		spec, err := exec.cronSpec()
+		if err != nil {
+			common.LoggingClient.Error(err.Error())
+			continue
+		}
+		common.LoggingClient.Info(fmt.Sprintf("Initializing Snapshot %s at %s", info.Name, info.Time))
+		addFixedJob(spec, exec)
+	}
+	for name, dj := range driverJobs {
+		addJob(driverJobKey(name), dj.spec, dj)
+	}
+	common.LoggingClient.Info("Starting internal Scheduler")
+	cr.Start()
+	common.LoggingClient.Info("Started internal Scheduler")
 }
 
+// StopScheduler stops the internal Scheduler, so that it can be started
+// again with the current Schedule Events in cache.
 func StopScheduler() {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr == nil {
+		return
+	}
 	common.LoggingClient.Info("Stopping internal Scheduler")
 	cr.Stop()
+	close(stopCh)
+	cr = nil
 	common.LoggingClient.Info("Stopped internal Scheduler")
 }
 
+// RestartScheduler restarts the internal Scheduler with the Schedule Events
+// in cache, dropping the jobs removed while it was running.
+func RestartScheduler() {
+	StopScheduler()
+	StartScheduler()
+}
+
+// AddScheduleEvent runs a Schedule Event added to the cache, if the
+// internal Scheduler is running.
+func AddScheduleEvent(schEvt models.ScheduleEvent) {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr == nil {
+		return
+	}
+	sch, ok := cache.Schedules().ForName(schEvt.Schedule)
+	if !ok {
+		common.LoggingClient.Error(fmt.Sprintf("Schedule %s for Schedule Event %s cannot be found in cache", schEvt.Schedule, schEvt.Name))
+		return
+	}
+	exec := &schEvtExec{schEvt: schEvt, sch: sch}
+	spec, err := exec.cronSpec()
+	if err != nil {
+		common.LoggingClient.Error(err.Error())
+		return
+	}
+	common.LoggingClient.Info(fmt.Sprintf("Initializing Schedule Event Executor: %v", *exec))
+	addJob(schEvtKey(schEvt.Name), spec, exec)
+}
+
+// RemoveScheduleEvent stops running a Schedule Event removed from the cache,
+// if the internal Scheduler is running.
+func RemoveScheduleEvent(name string) {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr == nil {
+		return
+	}
+	if removeJob(schEvtKey(name)) {
+		common.LoggingClient.Info(fmt.Sprintf("Removed Schedule Event Executor %s", name))
+	}
+}
+
+func tick() {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	lastTick = time.Now()
+}
+
+// Status returns the status of the internal Scheduler.
+func Status() handler.SchedulerStatus {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr == nil {
+		return handler.SchedulerStatus{}
+	}
+	return handler.SchedulerStatus{Running: true, Jobs: len(cr.Entries()) - removedJobs, LastTick: lastTick}
+}
+
+// CheckTicking returns an error if the internal Scheduler is running but
+// hasn't ticked within the given time.
+func CheckTicking(maxAge time.Duration) error {
+	schMgrMutex.Lock()
+	defer schMgrMutex.Unlock()
+
+	if cr == nil {
+		return nil
+	}
+	if age := time.Since(lastTick); age > maxAge {
+		return fmt.Errorf("internal Scheduler hasn't ticked for %v", age)
+	}
+	return nil
+}
+
 func loadSchEvts() []*schEvtExec {
 	schEvts := cache.ScheduleEvents().All()
 	result := make([]*schEvtExec, len(schEvts))
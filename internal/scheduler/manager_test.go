@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/robfig/cron"
+)
+
+type countingJob struct {
+	runs int
+}
+
+func (j *countingJob) Run() {
+	j.runs++
+}
+
+func TestRemoveJob(t *testing.T) {
+	common.LoggingClient = logger.NewClient("scheduler_test", false, "", "DEBUG")
+
+	schMgrMutex.Lock()
+	cr = cron.New()
+	jobs = make(map[string]*removableJob)
+	removedJobs = 0
+	first, second := &countingJob{}, &countingJob{}
+	addJob(schEvtKey("reading"), "@every 1h", first)
+	replaced := jobs[schEvtKey("reading")]
+	addJob(schEvtKey("reading"), "@every 1h", second)
+	schMgrMutex.Unlock()
+	defer func() {
+		schMgrMutex.Lock()
+		cr = nil
+		schMgrMutex.Unlock()
+	}()
+
+	if status := Status(); status.Jobs != 1 {
+		t.Errorf("Expected 1 job after replacing one, got %d", status.Jobs)
+	}
+	replaced.Run()
+	jobs[schEvtKey("reading")].Run()
+	if first.runs != 0 || second.runs != 1 {
+		t.Errorf("Expected only the replacing job to run, got %d and %d runs", first.runs, second.runs)
+	}
+
+	kept := jobs[schEvtKey("reading")]
+	RemoveScheduleEvent("reading")
+	kept.Run()
+	if second.runs != 1 {
+		t.Error("Removed job still runs")
+	}
+	if status := Status(); status.Jobs != 0 {
+		t.Errorf("Expected no jobs after removal, got %d", status.Jobs)
+	}
+
+	RemoveScheduleEvent("unknown")
+	if status := Status(); status.Jobs != 0 {
+		t.Errorf("Removing an unknown Schedule Event changed the jobs to %d", status.Jobs)
+	}
+}
+
+func TestCompactJobs(t *testing.T) {
+	common.LoggingClient = logger.NewClient("scheduler_test", false, "", "DEBUG")
+
+	schMgrMutex.Lock()
+	cr = cron.New()
+	fixedJobs = nil
+	jobs = make(map[string]*removableJob)
+	removedJobs = 0
+	stopCh = make(chan struct{})
+	addFixedJob(heartbeatSpec, &countingJob{})
+	addJob(schEvtKey("kept"), "@every 1h", &countingJob{})
+	for i := 0; i <= maxRemovedJobs; i++ {
+		addJob(schEvtKey("churn"), "@every 1h", &countingJob{})
+		removeJob(schEvtKey("churn"))
+	}
+	entries := len(cr.Entries())
+	schMgrMutex.Unlock()
+	defer StopScheduler()
+
+	// the removed jobs are dropped, the others kept
+	if entries != 2 {
+		t.Errorf("Expected the cron to be rebuilt with 2 jobs, got %d", entries)
+	}
+	if status := Status(); status.Jobs != 2 {
+		t.Errorf("Expected 2 jobs, got %d", status.Jobs)
+	}
+	if _, ok := jobs[schEvtKey("kept")]; !ok {
+		t.Error("Job lost by the rebuild")
+	}
+}
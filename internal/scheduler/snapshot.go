@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	defaultSnapshotWindow = time.Hour
+	defaultSnapshotRetry  = time.Minute
+)
+
+// snapshotExec reads a set of resources of a Device at a fixed time of day
+// and pushes their readings as a single event tagged with the snapshot name.
+// Resources failing to read are retried until the window expires.
+type snapshotExec struct {
+	info common.SnapshotInfo
+	stop <-chan struct{}
+}
+
+func (se *snapshotExec) cronSpec() (string, error) {
+	t, err := time.Parse("15:04", se.info.Time)
+	if err != nil {
+		return "", fmt.Errorf("invalid time %s of snapshot %s, expected HH:MM", se.info.Time, se.info.Name)
+	}
+	return fmt.Sprintf("0 %d %d * * *", t.Minute(), t.Hour()), nil
+}
+
+func (se *snapshotExec) Run() {
+	origin := time.Now()
+	window := time.Duration(se.info.Window) * time.Second
+	if window <= 0 {
+		window = defaultSnapshotWindow
+	}
+	retry := time.Duration(se.info.RetryInterval) * time.Second
+	if retry <= 0 {
+		retry = defaultSnapshotRetry
+	}
+	deadline := origin.Add(window)
+
+	pending := se.info.Resources
+	readings := make([]models.Reading, 0, len(pending)+1)
+	for {
+		var failed []string
+		for _, resource := range pending {
+			rs, appErr := handler.ReadHandler(se.info.Device, resource)
+			if appErr != nil {
+				common.LoggingClient.Warn(fmt.Sprintf("Snapshot %s: reading %s failed: %v", se.info.Name, resource, appErr.Message()))
+				failed = append(failed, resource)
+				continue
+			}
+			readings = append(readings, rs...)
+		}
+		pending = failed
+		if len(pending) == 0 {
+			break
+		}
+
+		if time.Now().Add(retry).After(deadline) {
+			common.LoggingClient.Error(fmt.Sprintf("Snapshot %s missed, resources %s couldn't be read within %v", se.info.Name, strings.Join(pending, ", "), window))
+			return
+		}
+		select {
+		case <-time.After(retry):
+		case <-se.stop:
+			common.LoggingClient.Warn(fmt.Sprintf("Snapshot %s abandoned, internal Scheduler stopped", se.info.Name))
+			return
+		}
+	}
+
+	// the snapshot is stamped with its scheduled time, not the time of the
+	// last successful read
+	millis := origin.UnixNano() / int64(time.Millisecond)
+	tag := models.Reading{Name: common.SnapshotReadingName, Device: se.info.Device, Value: se.info.Name, Origin: millis}
+	event := &models.Event{Device: se.info.Device, Readings: append(readings, tag)}
+	event.Origin = millis
+	common.SendEvent(event)
+	common.LoggingClient.Info(fmt.Sprintf("Snapshot %s of Device %s pushed", se.info.Name, se.info.Device))
+}
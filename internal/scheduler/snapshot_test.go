@@ -0,0 +1,34 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+func TestSnapshotCronSpec(t *testing.T) {
+	tests := []struct {
+		time     string
+		expected string
+		valid    bool
+	}{
+		{"00:00", "0 0 0 * * *", true},
+		{"23:45", "0 45 23 * * *", true},
+		{"7:05", "0 5 7 * * *", true},
+		{"24:00", "", false},
+		{"midnight", "", false},
+	}
+	for _, tt := range tests {
+		se := snapshotExec{info: common.SnapshotInfo{Name: "EndOfDay", Time: tt.time}}
+		spec, err := se.cronSpec()
+		if (err == nil) != tt.valid || spec != tt.expected {
+			t.Errorf("%s: unexpected spec %q, error %v", tt.time, spec, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package implements the Device representing the gateway itself, whose
+// standard profile reports the health of the host, read by the DS rather
+// than the driver.
+package selfdevice
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/yaml.v2"
+)
+
+// ProfileName is the name of the standard profile of the Device.
+const ProfileName = "Gateway-Self"
+
+// Device resources of the profile
+const (
+	Uptime      = "Uptime"
+	LoadAverage = "LoadAverage"
+	MemoryUsage = "MemoryUsage"
+	DiskUsage   = "DiskUsage"
+)
+
+const (
+	procUptime  = "/proc/uptime"
+	procLoadavg = "/proc/loadavg"
+)
+
+const profileYAML = `
+name: "Gateway-Self"
+manufacturer: "EdgeX"
+model: "Gateway"
+labels:
+ - "gateway"
+description: "Health of the gateway hosting the device service"
+
+deviceResources:
+    -
+        name: "Uptime"
+        description: "Time since the gateway booted."
+        properties:
+            value:
+                { type: "Float64", readWrite: "R" }
+            units:
+                { type: "String", readWrite: "R", defaultValue: "s" }
+    -
+        name: "LoadAverage"
+        description: "System load average over the last minute."
+        properties:
+            value:
+                { type: "Float64", readWrite: "R" }
+            units:
+                { type: "String", readWrite: "R", defaultValue: "" }
+    -
+        name: "MemoryUsage"
+        description: "Memory usage."
+        properties:
+            value:
+                { type: "Float64", readWrite: "R" }
+            units:
+                { type: "String", readWrite: "R", defaultValue: "%" }
+    -
+        name: "DiskUsage"
+        description: "Usage of the file system."
+        properties:
+            value:
+                { type: "Float64", readWrite: "R" }
+            units:
+                { type: "String", readWrite: "R", defaultValue: "%" }
+
+resources:
+    -
+        name: "Health"
+        get:
+            - { operation: "get", object: "Uptime", property: "value", parameter: "Uptime" }
+            - { operation: "get", object: "LoadAverage", property: "value", parameter: "LoadAverage" }
+            - { operation: "get", object: "MemoryUsage", property: "value", parameter: "MemoryUsage" }
+            - { operation: "get", object: "DiskUsage", property: "value", parameter: "DiskUsage" }
+
+commands:
+  -
+    name: "Health"
+    get:
+        path: "/api/v1/device/{deviceId}/Health"
+        responses:
+          -
+            code: "200"
+            description: ""
+            expectedValues: ["Uptime", "LoadAverage", "MemoryUsage", "DiskUsage"]
+          -
+            code: "503"
+            description: "service unavailable"
+            expectedValues: []
+`
+
+// Profile returns the standard profile of the Device.
+func Profile() (models.DeviceProfile, error) {
+	var profile models.DeviceProfile
+	err := yaml.Unmarshal([]byte(profileYAML), &profile)
+	return profile, err
+}
+
+// Read returns the values of the requested resources.
+func Read(reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	results := make([]*ds_models.CommandValue, len(reqs))
+	for i := range reqs {
+		value, err := sample(reqs[i].RO.Object)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read %s: %v", reqs[i].RO.Object, err)
+		}
+		results[i], err = ds_models.NewFloat64Value(&reqs[i].RO, now, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func sample(resource string) (float64, error) {
+	switch resource {
+	case Uptime:
+		return readFirstField(procUptime)
+	case LoadAverage:
+		return readFirstField(procLoadavg)
+	case MemoryUsage:
+		return throttle.MemoryUsage()
+	case DiskUsage:
+		path := common.CurrentConfig.SelfDevice.DiskPath
+		if path == "" {
+			path = "/"
+		}
+		return FileSystemUsage(path)
+	}
+	return 0, fmt.Errorf("unknown resource")
+}
+
+func readFirstField(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return parseFirstField(f)
+}
+
+// parseFirstField returns the first field of /proc/uptime or /proc/loadavg
+// contents.
+func parseFirstField(r io.Reader) (float64, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty contents")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// FileSystemUsage returns the usage of the file system of path, in percent.
+func FileSystemUsage(path string) (float64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, err
+	}
+	if fs.Blocks == 0 {
+		return 0, nil
+	}
+	return 100 * float64(fs.Blocks-fs.Bfree) / float64(fs.Blocks), nil
+}
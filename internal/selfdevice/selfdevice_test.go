@@ -0,0 +1,39 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package selfdevice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	profile, err := Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Name != ProfileName || len(profile.DeviceResources) != 4 || len(profile.Resources) != 1 || len(profile.Resources[0].Get) != 4 {
+		t.Errorf("unexpected profile %v", profile)
+	}
+	for _, op := range profile.Resources[0].Get {
+		switch op.Object {
+		case Uptime, LoadAverage, MemoryUsage, DiskUsage:
+		default:
+			t.Errorf("resource %s not sampled", op.Object)
+		}
+	}
+}
+
+func TestParseFirstField(t *testing.T) {
+	v, err := parseFirstField(strings.NewReader("0.52 0.58 0.59 1/467 12345\n"))
+	if err != nil || v != 0.52 {
+		t.Errorf("unexpected load average %v, %v", v, err)
+	}
+	if _, err = parseFirstField(strings.NewReader("")); err == nil {
+		t.Errorf("no error for empty contents")
+	}
+}
@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package statedir
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A Migration upgrades the data of a state file to the next version of its
+// schema.
+type Migration func(data []byte) ([]byte, error)
+
+// A Schema versions the JSON format of a state file, so that the files
+// written by an older version of the DS are upgraded when read, rather than
+// stranded by an update. The files are written as an envelope holding the
+// version and the data.
+type Schema struct {
+	// Version is the current version of the format.
+	Version int
+	// Migrations upgrade the data of each version to the next one, the
+	// migration at index i upgrading version i. Version 0 is the format
+	// of the files written before they carried a version.
+	Migrations []Migration
+}
+
+// Unversioned is the migration of the files written before they carried a
+// version, when the format didn't change with the introduction of the
+// versions.
+func Unversioned(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+type envelope struct {
+	SchemaVersion *int            `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Marshal returns the JSON encoding of v, in the current version.
+func (s Schema) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	version := s.Version
+	return json.Marshal(envelope{SchemaVersion: &version, Data: data})
+}
+
+// Unmarshal decodes the contents of a state file into v, upgrading them
+// from their version. The files of a newer version than the current one,
+// written by a newer DS, are rejected.
+func (s Schema) Unmarshal(contents []byte, v interface{}) error {
+	version, data := 0, contents
+	var e envelope
+	if json.Unmarshal(contents, &e) == nil && e.SchemaVersion != nil && e.Data != nil {
+		version, data = *e.SchemaVersion, e.Data
+	}
+	if version > s.Version {
+		return fmt.Errorf("schema version %d newer than %d", version, s.Version)
+	}
+	for ; version < s.Version; version++ {
+		if version >= len(s.Migrations) || s.Migrations[version] == nil {
+			return fmt.Errorf("no migration from schema version %d", version)
+		}
+		var err error
+		if data, err = s.Migrations[version](data); err != nil {
+			return fmt.Errorf("migration from schema version %d: %v", version, err)
+		}
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,172 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package statedir provides crash-safe persistence of the state of the DS
+// (command history, cache snapshots, ...). Files are written atomically by
+// renaming a synced temporary file, and carry a checksum so that a corrupted
+// file is detected and recovered from its previous version.
+package statedir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const (
+	header        = "#crc32:"
+	backupSuffix  = ".bak"
+	corruptSuffix = ".corrupt"
+	tmpSuffix     = ".tmp"
+)
+
+// ErrCorrupted is returned when neither a file nor its previous version
+// passes the checksum verification.
+var ErrCorrupted = errors.New("state file corrupted")
+
+var (
+	mutex sync.Mutex
+	dir   string
+)
+
+// Init sets the state directory, creating it if needed. Relative file names
+// are resolved against it; an empty directory means the working directory.
+func Init(stateDir string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	dir = stateDir
+	if dir == "" {
+		return nil
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// Path returns the path of the named file within the state directory.
+func Path(name string) string {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if dir == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// WriteFile atomically replaces the contents of the named file, keeping its
+// previous version as a backup.
+func WriteFile(name string, data []byte) error {
+	path := Path(name)
+
+	tmp := path + tmpSuffix
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(seal(data))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if _, err = os.Stat(path); err == nil {
+		if err = os.Rename(path, path+backupSuffix); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// ReadFile returns the contents of the named file. If the file is corrupted,
+// it is set aside and its previous version is returned instead. An error
+// satisfying os.IsNotExist is returned if neither exists.
+func ReadFile(name string) ([]byte, error) {
+	path := Path(name)
+
+	data, err := readVerified(path)
+	if err == nil || os.IsNotExist(err) && !exists(path+backupSuffix) {
+		return data, err
+	}
+	if err == ErrCorrupted {
+		os.Rename(path, path+corruptSuffix)
+	}
+
+	data, berr := readVerified(path + backupSuffix)
+	if berr != nil {
+		if berr == ErrCorrupted {
+			os.Rename(path+backupSuffix, path+backupSuffix+corruptSuffix)
+		}
+		return nil, fmt.Errorf("%s: %v, and its backup: %v", path, err, berr)
+	}
+	return data, nil
+}
+
+func readVerified(path string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return unseal(contents)
+}
+
+// seal prefixes data with a header line holding its checksum.
+func seal(data []byte) []byte {
+	sum := fmt.Sprintf("%s%08x\n", header, crc32.ChecksumIEEE(data))
+	return append([]byte(sum), data...)
+}
+
+// unseal verifies and strips the header line. Files without header, written
+// before the state directory was introduced, are returned as they are.
+func unseal(contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, []byte(header)) {
+		return contents, nil
+	}
+	i := bytes.IndexByte(contents, '\n')
+	if i < 0 {
+		return nil, ErrCorrupted
+	}
+	sum, err := strconv.ParseUint(string(contents[len(header):i]), 16, 32)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	data := contents[i+1:]
+	if crc32.ChecksumIEEE(data) != uint32(sum) {
+		return nil, ErrCorrupted
+	}
+	return data, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// syncDir makes a rename within the directory durable.
+func syncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// some file systems don't support syncing directories, which is ignored
+	d.Sync()
+	return nil
+}
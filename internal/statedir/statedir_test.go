@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package statedir
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReadFile(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "statedir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	if err = Init(filepath.Join(tmp, "state")); err != nil {
+		t.Fatal(err)
+	}
+	defer Init("")
+
+	if _, err = ReadFile("missing.json"); !os.IsNotExist(err) {
+		t.Errorf("Expected not exist error but got: %v", err)
+	}
+
+	if err = WriteFile("history.json", []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if err = WriteFile("history.json", []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile("history.json"); err != nil || string(data) != "v2" {
+		t.Errorf("Expected v2 but got: %s, %v", data, err)
+	}
+
+	// a torn write of the latest version falls back to the previous one
+	path := Path("history.json")
+	if err = ioutil.WriteFile(path, []byte(header+"00000000\nv3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile("history.json"); err != nil || string(data) != "v1" {
+		t.Errorf("Expected backup v1 but got: %s, %v", data, err)
+	}
+	if _, err = os.Stat(path + corruptSuffix); err != nil {
+		t.Errorf("Corrupted file not set aside: %v", err)
+	}
+}
+
+func TestReadLegacyFile(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "statedir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "legacy.json")
+	if err = ioutil.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile(path); err != nil || string(data) != "{}" {
+		t.Errorf("Expected legacy contents but got: %s, %v", data, err)
+	}
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	type record struct {
+		Device string `json:"device"`
+		Value  string `json:"value"`
+	}
+	// version 1 wrapped the legacy list in an object, version 2 renamed
+	// the name of the Devices
+	schema := Schema{Version: 2, Migrations: []Migration{
+		func(data []byte) ([]byte, error) {
+			return append(append([]byte(`{"records":`), data...), '}'), nil
+		},
+		func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"name"`), []byte(`"device"`), -1), nil
+		},
+	}}
+	var current struct {
+		Records []record `json:"records"`
+	}
+
+	legacy := []byte(`[{"name":"meter","value":"1"}]`)
+	if err := schema.Unmarshal(legacy, &current); err != nil {
+		t.Fatal(err)
+	}
+	if len(current.Records) != 1 || current.Records[0].Device != "meter" || current.Records[0].Value != "1" {
+		t.Errorf("Unexpected upgrade of a legacy file %+v", current)
+	}
+
+	v1 := []byte(`{"schemaVersion":1,"data":{"records":[{"name":"pump","value":"2"}]}}`)
+	if err := schema.Unmarshal(v1, &current); err != nil || current.Records[0].Device != "pump" {
+		t.Errorf("Unexpected upgrade of version 1 %+v, %v", current, err)
+	}
+
+	contents, err := schema.Marshal(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Records = nil
+	if err = schema.Unmarshal(contents, &current); err != nil || current.Records[0].Device != "pump" {
+		t.Errorf("Unexpected round trip %s: %+v, %v", contents, current, err)
+	}
+
+	newer := []byte(`{"schemaVersion":3,"data":{}}`)
+	if err = schema.Unmarshal(newer, &current); err == nil {
+		t.Error("Newer schema version accepted")
+	}
+}
@@ -0,0 +1,239 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package throttle monitors the CPU and memory usage of the gateway and
+// computes a throttle level, used by the DS to execute Schedule Events less
+// often and in smaller batches instead of starving other services.
+package throttle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// MaxLevel is the highest throttle level, at which only one in MaxLevel+1
+// Schedule Event executions is performed.
+const MaxLevel = 4
+
+const (
+	procStat    = "/proc/stat"
+	procMeminfo = "/proc/meminfo"
+)
+
+// Status describes the resource usage of the gateway and the resulting
+// throttling of the DS.
+type Status struct {
+	// CPU and Memory are the usage of the gateway, in percent.
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	// Level is the current throttle level, zero if not throttled.
+	Level int `json:"level"`
+	// Skipped is the number of Schedule Event executions skipped.
+	Skipped uint64 `json:"skipped"`
+}
+
+var (
+	mutex     sync.Mutex
+	status    Status
+	done      chan struct{}
+	lastIdle  uint64
+	lastTotal uint64
+)
+
+// Start samples the resource usage at the given interval. The throttle level
+// is raised by one each interval the usage is above any of the thresholds
+// (in percent, zero to ignore), and lowered by one otherwise.
+func Start(cpuThreshold float64, memThreshold float64, interval time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if done != nil || (cpuThreshold <= 0 && memThreshold <= 0) || interval <= 0 {
+		return
+	}
+	done = make(chan struct{})
+	go monitor(cpuThreshold, memThreshold, interval, done)
+}
+
+// Stop stops sampling the resource usage and resets the throttle level.
+func Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if done != nil {
+		close(done)
+		done = nil
+	}
+	status.Level = 0
+}
+
+// CurrentStatus returns the resource usage and throttling of the DS.
+func CurrentStatus() Status {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return status
+}
+
+// Admit decides whether an execution of a periodic job is performed, given
+// the number of its previous executions, which it increments.
+func Admit(runs *uint64) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	*runs++
+	if *runs%uint64(status.Level+1) == 0 {
+		return true
+	}
+	status.Skipped++
+	return false
+}
+
+// Parallelism reduces the given number of concurrent operations according to
+// the throttle level.
+func Parallelism(n int) int {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	n = n / (status.Level + 1)
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func monitor(cpuThreshold float64, memThreshold float64, interval time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	warned := false
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		cpu, cpuErr := sampleCPU()
+		mem, memErr := sampleMemory()
+		if (cpuErr != nil || memErr != nil) && !warned {
+			warned = true
+			common.LoggingClient.Warn(fmt.Sprintf("Couldn't measure the resource usage: %v, %v", cpuErr, memErr))
+		}
+
+		over := (cpuThreshold > 0 && cpu > cpuThreshold) || (memThreshold > 0 && mem > memThreshold)
+		update(cpu, mem, over)
+	}
+}
+
+func update(cpu float64, mem float64, over bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	status.CPU = cpu
+	status.Memory = mem
+	level := status.Level
+	if over && level < MaxLevel {
+		level++
+	} else if !over && level > 0 {
+		level--
+	}
+	if level != status.Level {
+		common.LoggingClient.Warn(fmt.Sprintf("Throttle level changed from %d to %d (CPU %.1f%%, memory %.1f%%)", status.Level, level, cpu, mem))
+		status.Level = level
+	}
+}
+
+// sampleCPU returns the CPU usage since the previous sample.
+func sampleCPU() (float64, error) {
+	f, err := os.Open(procStat)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	idle, total, err := parseStat(f)
+	if err != nil {
+		return 0, err
+	}
+	dIdle, dTotal := idle-lastIdle, total-lastTotal
+	lastIdle, lastTotal = idle, total
+	if dTotal == 0 {
+		return 0, nil
+	}
+	return 100 * float64(dTotal-dIdle) / float64(dTotal), nil
+}
+
+// parseStat returns the idle and total CPU time from /proc/stat contents.
+func parseStat(r io.Reader) (idle uint64, total uint64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			total += v
+			// idle and iowait
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+		return idle, total, nil
+	}
+	return 0, 0, fmt.Errorf("no cpu line in %s", procStat)
+}
+
+// MemoryUsage returns the memory usage of the gateway, in percent.
+func MemoryUsage() (float64, error) {
+	return sampleMemory()
+}
+
+func sampleMemory() (float64, error) {
+	f, err := os.Open(procMeminfo)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return parseMeminfo(f)
+}
+
+// parseMeminfo returns the memory usage from /proc/meminfo contents.
+func parseMeminfo(r io.Reader) (float64, error) {
+	var total, available uint64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = v
+		case "MemAvailable:":
+			available = v
+		}
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("no MemTotal in %s", procMeminfo)
+	}
+	return 100 * float64(total-available) / float64(total), nil
+}
@@ -0,0 +1,60 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package throttle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("throttle_test", false, "", "DEBUG")
+}
+
+func TestParse(t *testing.T) {
+	idle, total, err := parseStat(strings.NewReader("cpu  100 0 100 700 100 0 0 0 0 0\ncpu0 50 0 50 350 50 0 0 0 0 0\n"))
+	if err != nil || idle != 800 || total != 1000 {
+		t.Errorf("Unexpected CPU times: %d, %d, %v", idle, total, err)
+	}
+
+	mem, err := parseMeminfo(strings.NewReader("MemTotal:        1000 kB\nMemFree:          100 kB\nMemAvailable:     250 kB\n"))
+	if err != nil || mem != 75 {
+		t.Errorf("Unexpected memory usage: %v, %v", mem, err)
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	defer Stop()
+
+	update(95, 50, true)
+	update(95, 50, true)
+	if status.Level != 2 {
+		t.Fatalf("Expected level 2 but got: %d", status.Level)
+	}
+
+	var runs uint64
+	admitted := 0
+	for i := 0; i < 9; i++ {
+		if Admit(&runs) {
+			admitted++
+		}
+	}
+	if admitted != 3 {
+		t.Errorf("Expected 3 executions admitted but got: %d", admitted)
+	}
+	if p := Parallelism(8); p != 2 {
+		t.Errorf("Expected parallelism 2 but got: %d", p)
+	}
+
+	update(10, 50, false)
+	if status.Level != 1 {
+		t.Errorf("Expected level 1 but got: %d", status.Level)
+	}
+}
@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestCheckAssertion(t *testing.T) {
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+	common.DeviceClient = &mock.DeviceClientMock{}
+
+	dir, err := ioutil.TempDir("", "transformer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	device := models.Device{Name: "meter", OperatingState: models.Enabled}
+	snap, _ := json.Marshal(cache.Snapshot{Devices: []models.Device{device}})
+	file := filepath.Join(dir, "cache.json")
+	if err = statedir.WriteFile(file, snap); err != nil {
+		t.Fatal(err)
+	}
+	if err = cache.InitCacheFromFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	ro := &models.ResourceOperation{Object: "Status"}
+	cv, _ := ds_models.NewUint8Value(ro, 0, 2)
+	if err = CheckAssertion(cv, "1", &device); err == nil {
+		t.Fatal("Failed assertion accepted")
+	}
+	if d, _ := cache.Devices().ForName("meter"); d.OperatingState != models.Disabled {
+		t.Errorf("Device %s after a failed assertion", d.OperatingState)
+	}
+
+	cv, _ = ds_models.NewUint8Value(ro, 0, 1)
+	if err = CheckAssertion(cv, "1", &device); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := cache.Devices().ForName("meter"); d.OperatingState != models.Enabled {
+		t.Errorf("Device %s after the assertion passed again", d.OperatingState)
+	}
+
+	cv, _ = ds_models.NewFloat32Value(ro, 0, 1.5)
+	if err = CheckAssertion(cv, "1.5", &device); err != nil {
+		t.Errorf("Float assertion failed: %v", err)
+	}
+}
+
+func TestMapCommandValue(t *testing.T) {
+	ro := &models.ResourceOperation{Object: "Switch", Mappings: map[string]string{"1": "ON", "0": "OFF", "0.5": "HALF"}}
+
+	cv, _ := ds_models.NewUint16Value(ro, 0, 1)
+	if mapped, ok := MapCommandValue(cv); !ok || mapped.ValueToString() != "ON" {
+		t.Errorf("1 mapped to %v, %v", mapped, ok)
+	}
+
+	cv, _ = ds_models.NewFloat64Value(ro, 0, 0.5)
+	if mapped, ok := MapCommandValue(cv); !ok || mapped.ValueToString() != "HALF" {
+		t.Errorf("0.5 mapped to %v, %v", mapped, ok)
+	}
+
+	cv, _ = ds_models.NewUint16Value(ro, 0, 2)
+	if _, ok := MapCommandValue(cv); ok {
+		t.Error("Unmapped value mapped")
+	}
+}
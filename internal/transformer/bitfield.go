@@ -0,0 +1,160 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// Names of the transforms, which can be disabled in the Transforms of the
+// Writable section of the configuration.
+const (
+	TransformMask      = "Mask"
+	TransformShift     = "Shift"
+	TransformBase      = "Base"
+	TransformScale     = "Scale"
+	TransformOffset    = "Offset"
+	TransformPrecision = "Precision"
+	TransformRatio     = "Ratio"
+)
+
+const (
+	defaultMask  string = "0"
+	defaultShift string = "0"
+)
+
+// transformEnabled returns whether a transform is applied, i.e. unless
+// disabled in the configuration.
+func transformEnabled(name string) bool {
+	enabled, ok := common.CurrentConfig.Writable.Transforms[name]
+	return !ok || enabled
+}
+
+// transformMask keeps the bits of an integer value set in mask, e.g. "0xFF00".
+func transformMask(value interface{}, mask string) (interface{}, error) {
+	m, err := strconv.ParseUint(mask, 0, 64)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("the mask %s of PropertyValue cannot be parsed to uint64: %v", mask, err))
+		return value, err
+	}
+
+	switch v := value.(type) {
+	case uint8:
+		value = v & uint8(m)
+	case uint16:
+		value = v & uint16(m)
+	case uint32:
+		value = v & uint32(m)
+	case uint64:
+		value = v & m
+	case int8:
+		value = v & int8(m)
+	case int16:
+		value = v & int16(m)
+	case int32:
+		value = v & int32(m)
+	case int64:
+		value = v & int64(m)
+	default:
+		return value, fmt.Errorf("mask %s can't be applied to %T value", mask, v)
+	}
+	return value, nil
+}
+
+// transformShift shifts an integer value by shift bits, to the left if
+// positive and to the right if negative. The sign is kept for signed values.
+func transformShift(value interface{}, shift string, inverse bool) (interface{}, error) {
+	s, err := strconv.Atoi(shift)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("the shift %s of PropertyValue cannot be parsed to int: %v", shift, err))
+		return value, err
+	}
+	if inverse {
+		s = -s
+	}
+	left, n := s > 0, uint(s)
+	if s < 0 {
+		n = uint(-s)
+	}
+
+	switch v := value.(type) {
+	case uint8:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case uint16:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case uint32:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case uint64:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case int8:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case int16:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case int32:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	case int64:
+		if left {
+			value = v << n
+		} else {
+			value = v >> n
+		}
+	default:
+		return value, fmt.Errorf("shift %s can't be applied to %T value", shift, v)
+	}
+	return value, nil
+}
+
+// transformPrecision rounds a float value to precision decimals.
+func transformPrecision(value interface{}, precision string) (interface{}, error) {
+	p, err := strconv.Atoi(precision)
+	if err != nil || p < 0 {
+		err = fmt.Errorf("the precision %s of PropertyValue isn't a number of decimals", precision)
+		common.LoggingClient.Error(err.Error())
+		return value, err
+	}
+
+	scale := math.Pow10(p)
+	switch v := value.(type) {
+	case float32:
+		value = float32(math.Round(float64(v)*scale) / scale)
+	case float64:
+		value = math.Round(v*scale) / scale
+	}
+	return value, nil
+}
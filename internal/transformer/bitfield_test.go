@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestTransformMaskShift(t *testing.T) {
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+
+	pv := models.PropertyValue{Mask: "0x0FF0", Shift: "-4"}
+	cv, _ := ds_models.NewUint16Value(nil, 0, 0xABCD)
+	if err := TransformReadResult(cv, pv); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Uint16Value(); v != 0xBC {
+		t.Errorf("Read value %#x, expected 0xbc", v)
+	}
+
+	cv, _ = ds_models.NewUint16Value(nil, 0, 0x1BC)
+	if err := TransformWriteParameter(cv, pv); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Uint16Value(); v != 0xBC0 {
+		t.Errorf("Written value %#x, expected 0xbc0", v)
+	}
+
+	cv, _ = ds_models.NewInt16Value(nil, 0, -64)
+	if err := TransformReadResult(cv, models.PropertyValue{Shift: "-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Int16Value(); v != -16 {
+		t.Errorf("Signed value %d, expected -16", v)
+	}
+
+	cv, _ = ds_models.NewFloat32Value(nil, 0, 1.5)
+	if err := TransformReadResult(cv, models.PropertyValue{Mask: "0xFF"}); err == nil {
+		t.Error("Mask applied to a float")
+	}
+}
+
+func TestTransformToggles(t *testing.T) {
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+
+	pv := models.PropertyValue{Scale: "0.1", Precision: "1"}
+	cv, _ := ds_models.NewFloat64Value(nil, 0, 123.456)
+	if err := TransformReadResult(cv, pv); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Float64Value(); v != 12.3 {
+		t.Errorf("Value %v, expected 12.3", v)
+	}
+
+	common.CurrentConfig.Writable.Transforms = map[string]bool{TransformScale: false, TransformPrecision: true}
+	cv, _ = ds_models.NewFloat64Value(nil, 0, 123.456)
+	if err := TransformReadResult(cv, pv); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Float64Value(); v != 123.5 {
+		t.Errorf("Value %v with Scale disabled, expected 123.5", v)
+	}
+}
@@ -0,0 +1,49 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/pkg/compression"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// CompressReading compresses the value of a reading of an Array or String
+// device resource with the encoding given by its Compression attribute. The
+// value is left as is if shorter than the optional CompressionThreshold
+// attribute (in bytes).
+func CompressReading(reading *models.Reading, do models.DeviceObject) error {
+	encoding, ok := common.DeviceObjectAttribute(do, common.AttrCompression)
+	if !ok || encoding == "" {
+		return nil
+	}
+	switch strings.ToLower(do.Properties.Value.Type) {
+	case "array", "string":
+	default:
+		return nil
+	}
+
+	if v, ok := common.DeviceObjectAttribute(do, common.AttrCompressionThreshold); ok {
+		threshold, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		if len(reading.Value) < threshold {
+			return nil
+		}
+	}
+
+	value, err := compression.EncodeValue(encoding, reading.Value)
+	if err != nil {
+		return err
+	}
+	reading.Value = value
+	return nil
+}
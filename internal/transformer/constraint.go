@@ -0,0 +1,164 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// CheckWriteConstraints validates a write parameter against the Minimum and
+// Maximum of the device resource PropertyValue and against the comma separated
+// list of values given by its AllowedValues attribute. Out of range values are
+// either clamped to the nearest limit or rejected, depending on clamp.
+func CheckWriteConstraints(cv *ds_models.CommandValue, do models.DeviceObject, clamp bool) error {
+	if allowed, ok := common.DeviceObjectAttribute(do, common.AttrAllowedValues); ok {
+		if err := checkAllowedValues(cv, allowed); err != nil {
+			return err
+		}
+	}
+
+	if cv.Type == ds_models.String || cv.Type == ds_models.Bool || cv.Type == ds_models.Binary || cv.Type.IsArray() {
+		return nil // no range for String, Bool, Binary and arrays
+	}
+
+	value, err := commandValueForTransform(cv)
+	if err != nil {
+		return err
+	}
+	f, ok := toFloat64(value)
+	if !ok {
+		return nil
+	}
+
+	pv := do.Properties.Value
+	newF := f
+	if pv.Minimum != "" {
+		min, err := strconv.ParseFloat(pv.Minimum, 64)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("the minimum %s of PropertyValue cannot be parsed to float64: %v", pv.Minimum, err))
+			return err
+		}
+		if newF < min {
+			newF = min
+		}
+	}
+	if pv.Maximum != "" {
+		max, err := strconv.ParseFloat(pv.Maximum, 64)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("the maximum %s of PropertyValue cannot be parsed to float64: %v", pv.Maximum, err))
+			return err
+		}
+		if newF > max {
+			newF = max
+		}
+	}
+
+	if newF == f {
+		return nil
+	}
+	if !clamp {
+		return fmt.Errorf("value %v of device resource %s is out of range [%s, %s]", value, do.Name, pv.Minimum, pv.Maximum)
+	}
+
+	common.LoggingClient.Warn(fmt.Sprintf("value %v of device resource %s clamped to %v", value, do.Name, newF))
+	return replaceNewCommandValue(cv, fromFloat64(newF, value))
+}
+
+func checkAllowedValues(cv *ds_models.CommandValue, allowed string) error {
+	values := strings.Split(allowed, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	if cv.Type == ds_models.String || cv.Type == ds_models.Bool || cv.Type == ds_models.Binary || cv.Type.IsArray() {
+		str := cv.ValueToString()
+		for _, v := range values {
+			if v == str {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %s is not one of the allowed values: %s", str, allowed)
+	}
+
+	value, err := commandValueForTransform(cv)
+	if err != nil {
+		return err
+	}
+	f, _ := toFloat64(value)
+	for _, v := range values {
+		if av, err := strconv.ParseFloat(v, 64); err == nil && av == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("value %v is not one of the allowed values: %s", value, allowed)
+}
+
+// NumericValue returns the value of a numeric CommandValue as a float64.
+func NumericValue(cv *ds_models.CommandValue) (float64, bool) {
+	v, err := commandValueForTransform(cv)
+	if err != nil {
+		return 0, false
+	}
+	return toFloat64(v)
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// fromFloat64 converts f to the same type as value.
+func fromFloat64(f float64, value interface{}) interface{} {
+	switch value.(type) {
+	case uint8:
+		return uint8(f)
+	case uint16:
+		return uint16(f)
+	case uint32:
+		return uint32(f)
+	case uint64:
+		return uint64(f)
+	case int8:
+		return int8(f)
+	case int16:
+		return int16(f)
+	case int32:
+		return int32(f)
+	case int64:
+		return int64(f)
+	case float32:
+		return float32(f)
+	}
+	return f
+}
@@ -0,0 +1,72 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("transformer_test", false, "", "DEBUG")
+}
+
+func TestCheckWriteConstraintsRange(t *testing.T) {
+	do := models.DeviceObject{Name: "Setpoint"}
+	do.Properties.Value.Minimum = "10"
+	do.Properties.Value.Maximum = "20"
+
+	cv, _ := ds_models.NewInt16Value(nil, 0, 15)
+	if err := CheckWriteConstraints(cv, do, false); err != nil {
+		t.Errorf("Value in range rejected: %v", err)
+	}
+
+	cv, _ = ds_models.NewInt16Value(nil, 0, 25)
+	if err := CheckWriteConstraints(cv, do, false); err == nil {
+		t.Error("Value above Maximum accepted!")
+	}
+
+	cv, _ = ds_models.NewInt16Value(nil, 0, 25)
+	if err := CheckWriteConstraints(cv, do, true); err != nil {
+		t.Errorf("Value above Maximum not clamped: %v", err)
+	}
+	if v, _ := cv.Int16Value(); v != 20 {
+		t.Errorf("Expected clamped value 20 but got: %d", v)
+	}
+
+	cv, _ = ds_models.NewFloat32Value(nil, 0, 2.5)
+	if err := CheckWriteConstraints(cv, do, true); err != nil {
+		t.Errorf("Value below Minimum not clamped: %v", err)
+	}
+	if v, _ := cv.Float32Value(); v != 10 {
+		t.Errorf("Expected clamped value 10 but got: %v", v)
+	}
+}
+
+func TestCheckWriteConstraintsAllowedValues(t *testing.T) {
+	do := models.DeviceObject{Name: "Mode", Attributes: map[string]interface{}{common.AttrAllowedValues: "0, 2, 4"}}
+
+	cv, _ := ds_models.NewUint16Value(nil, 0, 2)
+	if err := CheckWriteConstraints(cv, do, false); err != nil {
+		t.Errorf("Allowed value rejected: %v", err)
+	}
+
+	cv, _ = ds_models.NewUint16Value(nil, 0, 3)
+	if err := CheckWriteConstraints(cv, do, true); err == nil {
+		t.Error("Value not in AllowedValues accepted!")
+	}
+
+	do.Attributes[common.AttrAllowedValues] = "AUTO,MANUAL"
+	cv = ds_models.NewStringValue(nil, 0, "MANUAL")
+	if err := CheckWriteConstraints(cv, do, false); err != nil {
+		t.Errorf("Allowed string value rejected: %v", err)
+	}
+}
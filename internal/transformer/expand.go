@@ -0,0 +1,156 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// ExpandReading expands the reading of an Array device resource with an
+// ExpandNames attribute into a reading per element, e.g. the magnitudes of
+// the harmonics. ExpandNames is either a comma separated list of names or a
+// prefix, the elements being named prefix1, prefix2, ... (the first index
+// being given by the optional ExpandStart attribute). The array is either a
+// JSON array, a comma separated list or a hex string of big-endian elements
+// of the type given by the ExpandType attribute (Float64 by default). It
+// returns false if the device resource isn't expanded.
+func ExpandReading(reading *models.Reading, do models.DeviceObject) ([]models.Reading, bool, error) {
+	names, ok := common.DeviceObjectAttribute(do, common.AttrExpandNames)
+	if !ok || names == "" || !strings.EqualFold(do.Properties.Value.Type, "array") {
+		return nil, false, nil
+	}
+
+	t := ds_models.Float64
+	if v, ok := common.DeviceObjectAttribute(do, common.AttrExpandType); ok {
+		var err error
+		if t, err = ds_models.ParseValueType(v); err != nil {
+			return nil, true, err
+		}
+		if t == ds_models.Bool || t == ds_models.String {
+			return nil, true, fmt.Errorf("unsupported array element type %s", v)
+		}
+	}
+
+	ro := &models.ResourceOperation{Object: do.Name}
+	values, err := arrayElements(reading.Value, t, ro, reading.Origin)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var elementNames []string
+	if strings.Contains(names, ",") {
+		elementNames = strings.Split(names, ",")
+		if len(elementNames) != len(values) {
+			return nil, true, fmt.Errorf("%d names for %d array elements", len(elementNames), len(values))
+		}
+	} else {
+		start := 1
+		if v, ok := common.DeviceObjectAttribute(do, common.AttrExpandStart); ok {
+			if start, err = strconv.Atoi(v); err != nil {
+				return nil, true, err
+			}
+		}
+		for i := range values {
+			elementNames = append(elementNames, names+strconv.Itoa(start+i))
+		}
+	}
+
+	result := make([]models.Reading, len(values))
+	for i, cv := range values {
+		result[i] = models.Reading{Name: strings.TrimSpace(elementNames[i]), Device: reading.Device, Origin: reading.Origin}
+		result[i].Value = cv.ValueToString()
+	}
+	return result, true, nil
+}
+
+func arrayElements(value string, t ds_models.ValueType, ro *models.ResourceOperation, origin int64) ([]*ds_models.CommandValue, error) {
+	value = strings.TrimSpace(value)
+
+	var texts []string
+	switch {
+	case strings.HasPrefix(value, "["):
+		var numbers []json.Number
+		if err := json.Unmarshal([]byte(value), &numbers); err != nil {
+			return nil, err
+		}
+		for _, n := range numbers {
+			texts = append(texts, n.String())
+		}
+	case strings.Contains(value, ","):
+		texts = strings.Split(value, ",")
+	default:
+		return hexElements(value, t, ro, origin)
+	}
+
+	result := make([]*ds_models.CommandValue, len(texts))
+	for i, text := range texts {
+		v, err := parseElement(strings.TrimSpace(text), t)
+		if err != nil {
+			return nil, err
+		}
+		if result[i], err = ds_models.NewCommandValue(ro, origin, v, t); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func hexElements(value string, t ds_models.ValueType, ro *models.ResourceOperation, origin int64) ([]*ds_models.CommandValue, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X"))
+	if err != nil {
+		return nil, err
+	}
+	size := t.Size()
+	if size == 0 || len(data)%size != 0 {
+		return nil, fmt.Errorf("%d bytes can't be split into elements of type %s", len(data), t.Name())
+	}
+
+	result := make([]*ds_models.CommandValue, len(data)/size)
+	for i := range result {
+		result[i] = &ds_models.CommandValue{RO: ro, Origin: origin, Type: t, NumericValue: data[i*size : (i+1)*size]}
+	}
+	return result, nil
+}
+
+func parseElement(s string, t ds_models.ValueType) (interface{}, error) {
+	switch t {
+	case ds_models.Uint8:
+		v, err := strconv.ParseUint(s, 10, 8)
+		return uint8(v), err
+	case ds_models.Uint16:
+		v, err := strconv.ParseUint(s, 10, 16)
+		return uint16(v), err
+	case ds_models.Uint32:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return uint32(v), err
+	case ds_models.Uint64:
+		return strconv.ParseUint(s, 10, 64)
+	case ds_models.Int8:
+		v, err := strconv.ParseInt(s, 10, 8)
+		return int8(v), err
+	case ds_models.Int16:
+		v, err := strconv.ParseInt(s, 10, 16)
+		return int16(v), err
+	case ds_models.Int32:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return int32(v), err
+	case ds_models.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	case ds_models.Float32:
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func harmonicsObject(attributes map[string]interface{}) models.DeviceObject {
+	do := models.DeviceObject{Name: "Harmonics", Attributes: attributes}
+	do.Properties.Value.Type = "Array"
+	return do
+}
+
+func TestExpandReading(t *testing.T) {
+	uint16String := func(v uint16) string {
+		cv, _ := ds_models.NewUint16Value(nil, 0, v)
+		return cv.ValueToString()
+	}
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		value      string
+		expected   map[string]string
+	}{
+		{"hex", map[string]interface{}{"ExpandNames": "H", "ExpandType": "Uint16"}, "0x000A00140FFF",
+			map[string]string{"H1": "10", "H2": "20", "H3": "4095"}},
+		{"json", map[string]interface{}{"ExpandNames": "H", "ExpandType": "Uint16", "ExpandStart": "0"}, "[10, 20]",
+			map[string]string{"H0": uint16String(10), "H1": uint16String(20)}},
+		{"list", map[string]interface{}{"ExpandNames": "Fundamental, Third", "ExpandType": "Int32"}, "50,-3",
+			map[string]string{"Fundamental": "50", "Third": "-3"}},
+	}
+	for _, tt := range tests {
+		reading := &models.Reading{Name: "Harmonics", Device: "meter", Value: tt.value}
+		rs, ok, err := ExpandReading(reading, harmonicsObject(tt.attributes))
+		if !ok || err != nil {
+			t.Fatalf("%s: not expanded: %v", tt.name, err)
+		}
+		if len(rs) != len(tt.expected) {
+			t.Fatalf("%s: unexpected readings %v", tt.name, rs)
+		}
+		for _, r := range rs {
+			if r.Value != tt.expected[r.Name] || r.Device != "meter" {
+				t.Errorf("%s: unexpected reading %v", tt.name, r)
+			}
+		}
+	}
+}
+
+func TestExpandReadingErrors(t *testing.T) {
+	reading := &models.Reading{Name: "Harmonics", Device: "meter", Value: "0x000A00"}
+	if _, _, err := ExpandReading(reading, harmonicsObject(map[string]interface{}{"ExpandNames": "H", "ExpandType": "Uint16"})); err == nil {
+		t.Error("Odd number of bytes expanded into Uint16 elements")
+	}
+
+	reading.Value = "1,2,3"
+	if _, _, err := ExpandReading(reading, harmonicsObject(map[string]interface{}{"ExpandNames": "A,B"})); err == nil {
+		t.Error("Array expanded with too few names")
+	}
+
+	if _, ok, _ := ExpandReading(reading, harmonicsObject(nil)); ok {
+		t.Error("Array expanded without ExpandNames")
+	}
+}
+
+func TestResolveProperties(t *testing.T) {
+	pv := models.PropertyValue{Scale: "${ctRatio}", Offset: "0", Assertion: "${missing}"}
+	pv = ResolveProperties(pv, map[string]string{"ctRatio": "200"})
+	if pv.Scale != "200" || pv.Offset != "0" {
+		t.Errorf("Scale %s, Offset %s", pv.Scale, pv.Offset)
+	}
+	if pv.Assertion != "${missing}" {
+		t.Errorf("Missing property resolved to %s", pv.Assertion)
+	}
+}
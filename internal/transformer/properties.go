@@ -0,0 +1,41 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"regexp"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// propertyRef matches the references to the custom properties of a Device
+// in the transforms of a profile, e.g. Scale "${ctRatio}".
+var propertyRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ResolveProperties returns the transforms of a device resource with the
+// references to the custom properties of the Device replaced by their
+// values, so that e.g. the CT ratio of each meter scales the currents of a
+// shared profile. The references to missing properties are left, failing
+// the transform.
+func ResolveProperties(pv models.PropertyValue, props map[string]string) models.PropertyValue {
+	resolve := func(s string) string {
+		return propertyRef.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := props[propertyRef.FindStringSubmatch(ref)[1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+	pv.Mask = resolve(pv.Mask)
+	pv.Shift = resolve(pv.Shift)
+	pv.Base = resolve(pv.Base)
+	pv.Scale = resolve(pv.Scale)
+	pv.Offset = resolve(pv.Offset)
+	pv.Precision = resolve(pv.Precision)
+	pv.Assertion = resolve(pv.Assertion)
+	return pv
+}
@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Values of the TransformerRatio attribute of a device resource, i.e.
+// whether it is measured through the current transformers (e.g. currents),
+// the voltage transformers (e.g. voltages) or both (e.g. powers and
+// energies).
+const (
+	RatioCT   = "CT"
+	RatioPT   = "PT"
+	RatioCTPT = "CTPT"
+)
+
+// ApplyRatio scales the value read of a device resource with a
+// TransformerRatio attribute by the CT and/or PT ratios of the Device, given
+// by its ctRatio and ptRatio properties, so that the readings are the
+// primary values whatever the transformers of the site. A Device without
+// the ratio property is connected directly, i.e. its ratio is 1.
+func ApplyRatio(cv *ds_models.CommandValue, do models.DeviceObject, props map[string]string) error {
+	return applyRatio(cv, do, props, false)
+}
+
+// InvertRatio scales the value written to a device resource with a
+// TransformerRatio attribute back to the secondary of the transformers,
+// e.g. for the setpoints of the currents.
+func InvertRatio(cv *ds_models.CommandValue, do models.DeviceObject, props map[string]string) error {
+	return applyRatio(cv, do, props, true)
+}
+
+func applyRatio(cv *ds_models.CommandValue, do models.DeviceObject, props map[string]string, inverse bool) error {
+	kind, ok := common.DeviceObjectAttribute(do, common.AttrTransformerRatio)
+	if !ok || kind == "" || !transformEnabled(TransformRatio) {
+		return nil
+	}
+	if cv.Type == ds_models.String || cv.Type == ds_models.Bool || cv.Type == ds_models.Binary || cv.Type.IsArray() {
+		return nil
+	}
+
+	ratio, err := deviceRatio(kind, props)
+	if err != nil || ratio == 1 {
+		return err
+	}
+	if inverse {
+		ratio = 1 / ratio
+	}
+
+	value, err := commandValueForTransform(cv)
+	if err != nil {
+		return err
+	}
+	f, ok := toFloat64(value)
+	if !ok {
+		return fmt.Errorf("the value of %s isn't numeric", do.Name)
+	}
+	f *= ratio
+	if _, isFloat := value.(float32); !isFloat {
+		if _, isFloat = value.(float64); !isFloat {
+			f = math.Round(f)
+		}
+	}
+	if err = checkRange(f, value); err != nil {
+		return err
+	}
+	return replaceNewCommandValue(cv, fromFloat64(f, value))
+}
+
+// deviceRatio returns the ratio of the transformers of kind (CT, PT or
+// CTPT) given by the properties of a Device.
+func deviceRatio(kind string, props map[string]string) (float64, error) {
+	switch strings.ToUpper(kind) {
+	case RatioCT:
+		return parseRatio(props[common.CTRatioProperty])
+	case RatioPT:
+		return parseRatio(props[common.PTRatioProperty])
+	case RatioCTPT:
+		ct, err := parseRatio(props[common.CTRatioProperty])
+		if err != nil {
+			return 0, err
+		}
+		pt, err := parseRatio(props[common.PTRatioProperty])
+		return ct * pt, err
+	}
+	return 0, fmt.Errorf("unknown transformer ratio %s", kind)
+}
+
+// parseRatio parses a ratio given either as a factor, e.g. "200", or as the
+// rated primary and secondary of the transformer, e.g. "1000:5" or
+// "20000/100". An empty ratio is 1.
+func parseRatio(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 1, nil
+	}
+	primary, secondary := s, "1"
+	if i := strings.IndexAny(s, ":/"); i >= 0 {
+		primary, secondary = s[:i], s[i+1:]
+	}
+	p, err := strconv.ParseFloat(strings.TrimSpace(primary), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transformer ratio %s: %v", s, err)
+	}
+	q, err := strconv.ParseFloat(strings.TrimSpace(secondary), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transformer ratio %s: %v", s, err)
+	}
+	if p <= 0 || q <= 0 {
+		return 0, fmt.Errorf("invalid transformer ratio %s", s)
+	}
+	return p / q, nil
+}
+
+// checkRange returns an error if f overflows the integer type of value.
+func checkRange(f float64, value interface{}) error {
+	var min, max float64
+	switch value.(type) {
+	case uint8:
+		max = math.MaxUint8
+	case uint16:
+		max = math.MaxUint16
+	case uint32:
+		max = math.MaxUint32
+	case uint64:
+		max = math.MaxUint64
+	case int8:
+		min, max = math.MinInt8, math.MaxInt8
+	case int16:
+		min, max = math.MinInt16, math.MaxInt16
+	case int32:
+		min, max = math.MinInt32, math.MaxInt32
+	case int64:
+		min, max = math.MinInt64, math.MaxInt64
+	default:
+		return nil
+	}
+	if f < min || f > max {
+		return fmt.Errorf("the value %v overflows %T", f, value)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestApplyRatio(t *testing.T) {
+	previous := common.CurrentConfig
+	defer func() { common.CurrentConfig = previous }()
+	common.CurrentConfig = &common.Config{}
+
+	props := map[string]string{common.CTRatioProperty: "1000:5", common.PTRatioProperty: "20000/100"}
+	current := models.DeviceObject{Name: "Current", Attributes: map[string]interface{}{common.AttrTransformerRatio: "CT"}}
+	power := models.DeviceObject{Name: "Power", Attributes: map[string]interface{}{common.AttrTransformerRatio: "CTPT"}}
+	ro := &models.ResourceOperation{Object: "Current"}
+
+	cv, _ := ds_models.NewFloat32Value(ro, 0, 2.5)
+	if err := ApplyRatio(cv, current, props); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Float32Value(); v != 500 {
+		t.Errorf("Current %v", v)
+	}
+	if err := InvertRatio(cv, current, props); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Float32Value(); v != 2.5 {
+		t.Errorf("Current written %v", v)
+	}
+
+	cv, _ = ds_models.NewUint32Value(ro, 0, 3)
+	if err := ApplyRatio(cv, power, props); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Uint32Value(); v != 120000 {
+		t.Errorf("Power %v", v)
+	}
+
+	cv, _ = ds_models.NewUint8Value(ro, 0, 3)
+	if err := ApplyRatio(cv, current, props); err == nil {
+		t.Error("Overflow accepted")
+	}
+
+	cv, _ = ds_models.NewUint8Value(ro, 0, 3)
+	if err := ApplyRatio(cv, current, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Uint8Value(); v != 3 {
+		t.Errorf("Current %v without ratio", v)
+	}
+
+	if err := ApplyRatio(cv, current, map[string]string{common.CTRatioProperty: "0"}); err == nil {
+		t.Error("Null ratio accepted")
+	}
+}
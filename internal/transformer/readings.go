@@ -0,0 +1,42 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/derived"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// CommandValueToReadings returns the readings of a (transformed) read result
+// of a device resource: either the reading of the result itself, possibly
+// compressed, along with the readings of its statistics, or the readings of
+// its array elements when expanded. The Binary results of the device
+// resources with a MediaType attribute are binary readings, pushed to Core
+// Data in CBOR encoded events.
+func CommandValueToReadings(cv *ds_models.CommandValue, devName string, do models.DeviceObject) []models.Reading {
+	reading := common.CommandValueToReading(cv, devName)
+	if mediaType, ok := common.DeviceObjectAttribute(do, common.AttrMediaType); ok && cv.Type == ds_models.Binary {
+		reading.Value = common.BinaryReadingValue(mediaType, cv.NumericValue)
+		common.MarkBinaryReading(devName, reading.Name, mediaType)
+	}
+
+	expanded, ok, err := ExpandReading(reading, do)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Expansion of reading %s of Device %s failed: %v", reading.Name, devName, err))
+	} else if ok {
+		return expanded
+	}
+
+	if err = CompressReading(reading, do); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Compression of reading %s of Device %s failed: %v", reading.Name, devName, err))
+	}
+	return append([]models.Reading{*reading}, derived.Readings(do, cv, reading)...)
+}
@@ -18,29 +18,45 @@ import (
 
 func TransformWriteParameter(cv *ds_models.CommandValue, pv models.PropertyValue) error {
 	var err error
-	if cv.Type == ds_models.String || cv.Type == ds_models.Bool {
-		return nil // do nothing for String and Bool
+	if cv.Type == ds_models.String || cv.Type == ds_models.Bool || cv.Type == ds_models.Binary || cv.Type.IsArray() {
+		return nil // do nothing for String, Bool, Binary and arrays
 	}
 
 	value, err := commandValueForTransform(cv)
 	newValue := value
 
-	if pv.Offset != "" && pv.Offset != defaultOffset {
+	if pv.Offset != "" && pv.Offset != defaultOffset && transformEnabled(TransformOffset) {
 		newValue, err = transformWriteOffset(newValue, pv.Offset)
 		if err != nil {
 			return err
 		}
 	}
 
-	if pv.Scale != "" && pv.Scale != defaultScale {
+	if pv.Scale != "" && pv.Scale != defaultScale && transformEnabled(TransformScale) {
 		newValue, err = transformWriteScale(newValue, pv.Scale)
 		if err != nil {
 			return err
 		}
 	}
 
-	if pv.Base != "" && pv.Base != defaultBase {
+	if pv.Base != "" && pv.Base != defaultBase && transformEnabled(TransformBase) {
 		newValue, err = transformWriteBase(newValue, pv.Base)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pv.Shift != "" && pv.Shift != defaultShift && transformEnabled(TransformShift) {
+		newValue, err = transformShift(newValue, pv.Shift, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the bits outside the mask are cleared, as they weren't part of the
+	// value read
+	if pv.Mask != "" && pv.Mask != defaultMask && transformEnabled(TransformMask) {
+		newValue, err = transformMask(newValue, pv.Mask)
 	}
 
 	if value != newValue {
@@ -10,11 +10,12 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"math"
 	"strconv"
+	"sync"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
@@ -26,29 +27,50 @@ const (
 )
 
 func TransformReadResult(cv *ds_models.CommandValue, pv models.PropertyValue) error {
-	if cv.Type == ds_models.String || cv.Type == ds_models.Bool {
-		return nil // do nothing for String and Bool
+	if cv.Type == ds_models.String || cv.Type == ds_models.Bool || cv.Type == ds_models.Binary || cv.Type.IsArray() {
+		return nil // do nothing for String, Bool, Binary and arrays
 	}
 
 	value, err := commandValueForTransform(cv)
 	newValue := value
 
-	if pv.Base != "" && pv.Base != defaultBase {
+	if pv.Mask != "" && pv.Mask != defaultMask && transformEnabled(TransformMask) {
+		newValue, err = transformMask(newValue, pv.Mask)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pv.Shift != "" && pv.Shift != defaultShift && transformEnabled(TransformShift) {
+		newValue, err = transformShift(newValue, pv.Shift, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pv.Base != "" && pv.Base != defaultBase && transformEnabled(TransformBase) {
 		newValue, err = transformReadBase(newValue, pv.Base)
 		if err != nil {
 			return err
 		}
 	}
 
-	if pv.Scale != "" && pv.Scale != defaultScale {
+	if pv.Scale != "" && pv.Scale != defaultScale && transformEnabled(TransformScale) {
 		newValue, err = transformReadScale(newValue, pv.Scale)
 		if err != nil {
 			return err
 		}
 	}
 
-	if pv.Offset != "" && pv.Offset != defaultOffset {
+	if pv.Offset != "" && pv.Offset != defaultOffset && transformEnabled(TransformOffset) {
 		newValue, err = transformReadOffset(newValue, pv.Offset)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pv.Precision != "" && transformEnabled(TransformPrecision) {
+		newValue, err = transformPrecision(newValue, pv.Precision)
 	}
 
 	if value != newValue {
@@ -356,21 +378,82 @@ func replaceNewCommandValue(cv *ds_models.CommandValue, newValue interface{}) er
 	return err
 }
 
+var (
+	assertionMutex sync.Mutex
+	// failedAssertions are the resources failing their assertion, per
+	// Device disabled as a result.
+	failedAssertions = make(map[string]map[string]bool)
+)
+
+// CheckAssertion checks a value against the assertion of its resource. The
+// Device is disabled when it fails, and enabled again once the assertions
+// of all its resources pass.
 func CheckAssertion(cv *ds_models.CommandValue, assertion string, device *models.Device) error {
-	if assertion != "" && cv.ValueToString() != assertion {
-		device.OperatingState = models.Disabled
-		cache.Devices().Update(*device)
-		go common.DeviceClient.UpdateOpStateByName(device.Name, models.Disabled)
-		msg := fmt.Sprintf("assertion (%s) failed with value: %s", assertion, cv.ValueToString())
+	if assertion == "" {
+		return nil
+	}
+
+	assertionMutex.Lock()
+	defer assertionMutex.Unlock()
+
+	value := comparableValue(cv)
+	failed := failedAssertions[device.Name]
+	if value != assertion {
+		if failed == nil {
+			failed = make(map[string]bool)
+			failedAssertions[device.Name] = failed
+		}
+		failed[cv.RO.Object] = true
+		setOperatingState(device, models.Disabled)
+		msg := fmt.Sprintf("assertion (%s) failed with value: %s", assertion, value)
 		common.LoggingClient.Error(msg)
 		return fmt.Errorf(msg)
 	}
+
+	if failed[cv.RO.Object] {
+		delete(failed, cv.RO.Object)
+		if len(failed) == 0 {
+			delete(failedAssertions, device.Name)
+			common.LoggingClient.Info(fmt.Sprintf("assertions of Device %s pass again", device.Name))
+			setOperatingState(device, models.Enabled)
+		}
+	}
 	return nil
 }
 
+// ForgetAssertions forgets the failed assertions of a removed Device.
+func ForgetAssertions(deviceName string) {
+	assertionMutex.Lock()
+	defer assertionMutex.Unlock()
+	delete(failedAssertions, deviceName)
+}
+
+func setOperatingState(device *models.Device, state models.OperatingState) {
+	if opstate.Set(*device, state, "assertion") {
+		device.OperatingState = state
+	}
+}
+
+// comparableValue returns the string form of a value compared to assertions
+// and mapped, floats being formatted as decimals rather than encoded.
+func comparableValue(cv *ds_models.CommandValue) string {
+	if cv.Type == ds_models.Float32 || cv.Type == ds_models.Float64 {
+		if f, ok := NumericValue(cv); ok {
+			bits := 64
+			if cv.Type == ds_models.Float32 {
+				bits = 32
+			}
+			return strconv.FormatFloat(f, 'f', -1, bits)
+		}
+	}
+	return cv.ValueToString()
+}
+
+// MapCommandValue returns the string value mapped to a value by the mappings
+// of its resource operation.
 func MapCommandValue(value *ds_models.CommandValue) (*ds_models.CommandValue, bool) {
 	mappings := value.RO.Mappings
-	newValue, ok := mappings[value.ValueToString()]
+	newValue, ok := mappings[comparableValue(value)]
 	var result *ds_models.CommandValue
 	if ok {
 		result = ds_models.NewStringValue(value.RO, value.Origin, newValue)
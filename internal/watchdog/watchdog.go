@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package watchdog implements the systemd notification protocol (sd_notify),
+// so that the DS can run as a Type=notify unit whose watchdog restarts it
+// when its liveness check stops passing.
+package watchdog
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+const (
+	notifySocketEnv = "NOTIFY_SOCKET"
+	watchdogUsecEnv = "WATCHDOG_USEC"
+	watchdogPidEnv  = "WATCHDOG_PID"
+
+	stateReady    = "READY=1"
+	stateStopping = "STOPPING=1"
+	stateWatchdog = "WATCHDOG=1"
+)
+
+var (
+	mutex sync.Mutex
+	done  chan struct{}
+)
+
+// Start notifies systemd that the DS is ready and, if the watchdog is
+// enabled for the unit, sends keepalives at half the watchdog interval as
+// long as check passes. It does nothing unless the DS is run by systemd.
+func Start(check func(timeout time.Duration) error) {
+	if !notify(stateReady) {
+		return
+	}
+
+	interval, ok := watchdogInterval()
+	if !ok {
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if done != nil {
+		return
+	}
+	done = make(chan struct{})
+
+	common.LoggingClient.Info(fmt.Sprintf("Watchdog enabled, interval %v", interval))
+	go keepalive(interval/2, check, done)
+}
+
+// Stop stops sending keepalives and notifies systemd that the DS is stopping.
+func Stop() {
+	mutex.Lock()
+	if done != nil {
+		close(done)
+		done = nil
+	}
+	mutex.Unlock()
+
+	notify(stateStopping)
+}
+
+func keepalive(period time.Duration, check func(timeout time.Duration) error, done chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// the check must complete before the next keepalive is due
+			if err := check(period / 2); err != nil {
+				common.LoggingClient.Error(fmt.Sprintf("Liveness check failed, watchdog keepalive not sent: %v", err))
+				continue
+			}
+			notify(stateWatchdog)
+		}
+	}
+}
+
+// watchdogInterval returns the watchdog interval set by systemd for this
+// process, if any.
+func watchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv(watchdogUsecEnv), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv(watchdogPidEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// notify sends the given state to systemd, and returns false if the DS
+// isn't run by systemd or the state couldn't be sent.
+func notify(state string) bool {
+	socket := os.Getenv(notifySocketEnv)
+	if socket == "" {
+		return false
+	}
+	// a leading @ denotes an abstract socket
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't connect to the systemd notification socket: %v", err))
+		return false
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't notify systemd of %s: %v", state, err))
+		return false
+	}
+	return true
+}
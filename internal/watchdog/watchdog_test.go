@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watchdog
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func init() {
+	common.LoggingClient = logger.NewClient("watchdog_test", false, "", "DEBUG")
+}
+
+func TestKeepalive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	os.Setenv(notifySocketEnv, path)
+	os.Setenv(watchdogUsecEnv, "100000")
+	defer os.Unsetenv(notifySocketEnv)
+	defer os.Unsetenv(watchdogUsecEnv)
+
+	var wedged int32
+	Start(func(timeout time.Duration) error {
+		if atomic.LoadInt32(&wedged) != 0 {
+			return errors.New("wedged")
+		}
+		return nil
+	})
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for _, expected := range []string{stateReady, stateWatchdog} {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != expected {
+			t.Errorf("Expected %s but got: %s", expected, buf[:n])
+		}
+	}
+
+	// no keepalive is sent while the check fails, a keepalive being sent
+	// at the time the check started failing at most
+	atomic.StoreInt32(&wedged, 1)
+	time.Sleep(200 * time.Millisecond)
+	Stop()
+	keepalives := 0
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) == stateStopping {
+			break
+		}
+		keepalives++
+	}
+	if keepalives > 1 {
+		t.Errorf("%d keepalives sent while wedged", keepalives)
+	}
+}
@@ -0,0 +1,30 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/job"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// RunJob starts running a multi-step Job in background. Its progress is
+// reported by the REST API. Running a Job with the ID of an interrupted one
+// resumes it from its last checkpoint.
+func (*Service) RunJob(j *ds_models.Job) error {
+	if _, ok := cache.Devices().ForName(j.DeviceName); !ok {
+		return fmt.Errorf("Device %s not found", j.DeviceName)
+	}
+	return job.Submit(j)
+}
+
+// CancelJob cancels the running Job with the given ID.
+func (*Service) CancelJob(id string) error {
+	return job.Cancel(id)
+}
@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/scheduler"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// maxTickAge is the time after which a running internal Scheduler which
+// hasn't ticked is considered wedged.
+const maxTickAge = 10 * time.Second
+
+// checkLiveness verifies that the internal Scheduler is ticking, the REST API
+// is served and the Driver is responsive, within the given timeout.
+func checkLiveness(timeout time.Duration) error {
+	if err := scheduler.CheckTicking(maxTickAge); err != nil {
+		return err
+	}
+
+	client := http.Client{Timeout: timeout}
+	url := fmt.Sprintf("http://localhost:%d%s", svc.svcInfo.Port, common.APIRoute(common.APIPingRoute))
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("REST API not served: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("REST API not served: %s", resp.Status)
+	}
+
+	lc, ok := common.Driver.(ds_models.LivenessChecker)
+	if !ok {
+		return nil
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- lc.CheckLiveness()
+	}()
+	select {
+	case err = <-result:
+		if err != nil {
+			return fmt.Errorf("Driver not responsive: %v", err)
+		}
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("Driver liveness check timed out")
+	}
+}
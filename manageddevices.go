@@ -12,20 +12,34 @@
 package device
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/autoevent"
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/device-sdk-go/internal/scheduler"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"gopkg.in/mgo.v2/bson"
 )
 
 // AddDevice adds a new Device to the device service and Core Metadata
-// Returns new Device id or non-nil error.
+// Returns new Device id or non-nil error. The name of the Device is
+// sanitized, and made unique, as per the DeviceNames settings.
 func (s *Service) AddDevice(device models.Device) (id string, err error) {
-	if d, ok := cache.Devices().ForName(device.Name); ok {
-		return d.Id.Hex(), fmt.Errorf("name conflicted, Device %s exists", device.Name)
+	name, err := common.SanitizeDeviceName(device.Name)
+	if err != nil {
+		return "", err
+	}
+	if device.Name, err = provision.UniqueDeviceName(name); err != nil {
+		if d, ok := cache.Devices().ForName(name); ok {
+			return d.Id.Hex(), err
+		}
+		return "", err
 	}
 
 	common.LoggingClient.Debug(fmt.Sprintf("Adding managed device: : %v\n", device))
@@ -48,11 +62,16 @@ func (s *Service) AddDevice(device models.Device) (id string, err error) {
 	device.Service = common.CurrentDeviceService
 	device.Profile = prf
 	device.Addressable = *addr
+	if err = common.NotifyDeviceAdded(device); err != nil {
+		common.LoggingClient.Error(err.Error())
+		return "", err
+	}
 	common.LoggingClient.Debug(fmt.Sprintf("Adding Device: %v", device))
 
 	id, err = common.DeviceClient.Add(&device)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("Add Device failed %v, error: %v", device, err))
+		common.NotifyDeviceRemoved(device)
 		return "", err
 	}
 	if err = common.VerifyIdFormat(id, "Device"); err != nil {
@@ -60,6 +79,8 @@ func (s *Service) AddDevice(device models.Device) (id string, err error) {
 	}
 	device.Id = bson.ObjectIdHex(id)
 	cache.Devices().Add(device)
+	provision.CreateAliasDescriptors(device)
+	provision.CreateDefaultAutoEvents(device)
 
 	return id, nil
 }
@@ -86,8 +107,9 @@ func (s *Service) RemoveDevice(id string) error {
 		return err
 	}
 
-	err = cache.Devices().Remove(id)
-	return err
+	// unless already done by the callback of Core Metadata
+	handler.RemoveCachedDevice(device.Name)
+	return nil
 }
 
 // RemoveDevice removes the specified Device by name from the cache and ensures that the
@@ -107,8 +129,9 @@ func (s *Service) RemoveDeviceByName(name string) error {
 		return err
 	}
 
-	err = cache.Devices().RemoveByName(name)
-	return err
+	// unless already done by the callback of Core Metadata
+	handler.RemoveCachedDevice(device.Name)
+	return nil
 }
 
 // UpdateDevice updates the Device in the cache and ensures that the
@@ -122,6 +145,10 @@ func (s *Service) UpdateDevice(device models.Device) error {
 	}
 
 	common.LoggingClient.Debug(fmt.Sprintf("Updating managed Device: : %v\n", device))
+	if err := common.NotifyDeviceUpdated(device); err != nil {
+		common.LoggingClient.Error(err.Error())
+		return err
+	}
 	err := common.DeviceClient.Update(device)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("Update Device %s from Core Metadata failed: %v", device.Name, err))
@@ -129,5 +156,45 @@ func (s *Service) UpdateDevice(device models.Device) error {
 	}
 
 	err = cache.Devices().Update(device)
+	if err == nil {
+		provision.CreateAliasDescriptors(device)
+	}
 	return err
 }
+
+// RemoveAutoEvent removes the default auto event with the given name from
+// the specified Device, both in the cache and in Core Metadata. It isn't
+// created again unless the Device is added again.
+func (s *Service) RemoveAutoEvent(deviceName string, name string) error {
+	schEvtName := provision.AutoEventName(deviceName, name)
+	if _, ok := cache.ScheduleEvents().ForName(schEvtName); !ok {
+		msg := fmt.Sprintf("Auto event %s of Device %s cannot be found in cache", name, deviceName)
+		common.LoggingClient.Error(msg)
+		return errors.New(msg)
+	}
+
+	common.LoggingClient.Debug(fmt.Sprintf("Removing auto event %s of Device %s", name, deviceName))
+	err := common.ScheduleEventClient.DeleteByName(schEvtName)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Delete Schedule Event %s from Core Metadata failed", schEvtName))
+		return err
+	}
+
+	err = cache.ScheduleEvents().RemoveByName(schEvtName)
+	scheduler.RemoveScheduleEvent(schEvtName)
+	return err
+}
+
+// SetAutoEvents replaces the AutoEvents set for the specified Device, i.e.
+// the periodic reads of its resources, run by the Service rather than by
+// Schedule Events. They override those defined by its profile for the same
+// resources. No AutoEvents removes them.
+func (s *Service) SetAutoEvents(deviceName string, aes []ds_models.AutoEvent) error {
+	if _, ok := cache.Devices().ForName(deviceName); !ok {
+		msg := fmt.Sprintf("Device %s cannot be found in cache", deviceName)
+		common.LoggingClient.Error(msg)
+		return errors.New(msg)
+	}
+	autoevent.SetAutoEvents(deviceName, aes)
+	return nil
+}
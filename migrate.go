@@ -0,0 +1,27 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/config"
+)
+
+// MigrateConfig upgrades the configuration file selected by the given
+// profile and configuration directory from an older SDK layout to the
+// current one, keeping the original with an .orig suffix. It returns a
+// description of each changed key, which is empty if the file is current.
+func MigrateConfig(confProfile string, confDir string) ([]string, error) {
+	changes, err := config.MigrateFile(confProfile, confDir)
+	if err != nil {
+		return nil, err
+	}
+	report := make([]string, len(changes))
+	for i, c := range changes {
+		report[i] = c.String()
+	}
+	return report, nil
+}
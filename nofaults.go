@@ -0,0 +1,22 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !faults
+// +build !faults
+
+package device
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+// initFaults reports that faults can't be injected, as the DS has been
+// built without the "faults" tag.
+func initFaults() {
+	if common.CurrentConfig.Faults.Enabled {
+		common.LoggingClient.Warn("Fault injection requires a build with the faults tag, ignored")
+	}
+}
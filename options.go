@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"github.com/edgexfoundry/device-sdk-go/internal/clients"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/metadata"
+)
+
+// Clients are the clients of Core Data and Core Metadata injected into a
+// Service, e.g. clients shared with the application embedding it, or mocks.
+// The nil clients are created from the configuration, and the Service
+// doesn't wait for Core Data and Core Metadata if all of them are set.
+type Clients struct {
+	Event           coredata.EventClient
+	ValueDescriptor coredata.ValueDescriptorClient
+	Addressable     metadata.AddressableClient
+	Device          metadata.DeviceClient
+	DeviceService   metadata.DeviceServiceClient
+	DeviceProfile   metadata.DeviceProfileClient
+	Schedule        metadata.ScheduleClient
+	ScheduleEvent   metadata.ScheduleEventClient
+}
+
+// An Option configures the Service built by NewServiceWithOptions.
+type Option func(*serviceOptions)
+
+type serviceOptions struct {
+	confProfile string
+	confDir     string
+	useRegistry bool
+	overrides   clients.Overrides
+}
+
+// WithConfig loads the configuration of the given profile from the given
+// directory, instead of the default res directory.
+func WithConfig(profile string, dir string) Option {
+	return func(o *serviceOptions) {
+		o.confProfile = profile
+		o.confDir = dir
+	}
+}
+
+// WithRegistry loads the configuration from the registry, and registers the
+// Service in it.
+func WithRegistry() Option {
+	return func(o *serviceOptions) {
+		o.useRegistry = true
+	}
+}
+
+// WithLogger logs through the given client instead of the one of the
+// Logging settings.
+func WithLogger(lc logger.LoggingClient) Option {
+	return func(o *serviceOptions) {
+		o.overrides.Logging = lc
+	}
+}
+
+// WithClients injects the given clients of Core Data and Core Metadata.
+func WithClients(c Clients) Option {
+	return func(o *serviceOptions) {
+		logging := o.overrides.Logging
+		o.overrides = clients.Overrides{
+			Logging:         logging,
+			Event:           c.Event,
+			ValueDescriptor: c.ValueDescriptor,
+			Addressable:     c.Addressable,
+			Device:          c.Device,
+			DeviceService:   c.DeviceService,
+			DeviceProfile:   c.DeviceProfile,
+			Schedule:        c.Schedule,
+			ScheduleEvent:   c.ScheduleEvent,
+		}
+	}
+}
+
+// NewServiceWithOptions creates the device service of the given name and
+// version, with the given Driver, which cannot be nil, configured by the
+// options. As NewService, it returns an error if a Service already exists:
+// the configuration, the clients, the caches and the state of the handlers
+// are still shared by the whole process, so a supervisor running several
+// device services has to run each in its own process.
+func NewServiceWithOptions(serviceName string, serviceVersion string, proto ds_models.ProtocolDriver, opts ...Option) (*Service, error) {
+	var o serviceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s, err := NewService(serviceName, serviceVersion, o.confProfile, o.confDir, o.useRegistry, proto)
+	if err != nil {
+		return nil, err
+	}
+	clients.Override(o.overrides)
+	return s, nil
+}
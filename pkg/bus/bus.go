@@ -0,0 +1,149 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bus arbitrates the access of drivers to buses shared by several
+// Devices, e.g. the serial port of an RS-485 bus of Modbus RTU devices (see
+// modbus.RTUClient), so that the reads of the AutoEvents and the commands of
+// the REST API don't collide. The requests of a bus are executed one at a time, by priority,
+// in their order within a priority, with a quiet time between them.
+package bus
+
+import (
+	"sync"
+	"time"
+)
+
+// The priorities of the requests, the higher executed first, e.g. the
+// writes of actuation commands before the polling reads.
+const (
+	PriorityLow    = 0
+	PriorityNormal = 1
+	PriorityHigh   = 2
+)
+
+// Manager arbitrates the buses, keyed by path, e.g. "/dev/ttyUSB0". The
+// zero Manager is ready to use, without quiet time.
+type Manager struct {
+	// QuietTime is the silence between the requests of a bus, e.g. the
+	// inter-frame delay of slow devices, unless set per bus by
+	// SetQuietTime.
+	QuietTime time.Duration
+
+	mutex sync.Mutex
+	buses map[string]*bus
+}
+
+type bus struct {
+	quietTime time.Duration
+	busy      bool
+	lastEnd   time.Time
+	queue     []*waiter
+	seq       uint64
+}
+
+type waiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+}
+
+// NewManager returns a Manager with the given quiet time between the
+// requests of a bus.
+func NewManager(quietTime time.Duration) *Manager {
+	return &Manager{QuietTime: quietTime}
+}
+
+// SetQuietTime sets the quiet time of a bus, e.g. from its baud rate.
+func (m *Manager) SetQuietTime(path string, quietTime time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.bus(path).quietTime = quietTime
+}
+
+// Do calls op once the bus is free, after the requests of higher priority
+// and those of the same priority queued before, and the quiet time since
+// the previous request.
+func (m *Manager) Do(path string, priority int, op func() error) error {
+	m.acquire(path, priority)
+	defer m.release(path)
+	return op()
+}
+
+// Pending returns the number of requests waiting for a bus.
+func (m *Manager) Pending(path string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if b, ok := m.buses[path]; ok {
+		return len(b.queue)
+	}
+	return 0
+}
+
+// bus returns the state of a bus, the mutex being held.
+func (m *Manager) bus(path string) *bus {
+	if m.buses == nil {
+		m.buses = make(map[string]*bus)
+	}
+	b, ok := m.buses[path]
+	if !ok {
+		b = &bus{quietTime: -1}
+		m.buses[path] = b
+	}
+	return b
+}
+
+func (m *Manager) acquire(path string, priority int) {
+	m.mutex.Lock()
+	b := m.bus(path)
+	var w *waiter
+	if b.busy {
+		w = &waiter{priority: priority, seq: b.seq, ready: make(chan struct{})}
+		b.seq++
+		b.queue = append(b.queue, w)
+	}
+	b.busy = true
+	m.mutex.Unlock()
+
+	if w != nil {
+		<-w.ready
+	}
+
+	m.mutex.Lock()
+	quietTime := b.quietTime
+	if quietTime < 0 {
+		quietTime = m.QuietTime
+	}
+	var wait time.Duration
+	if !b.lastEnd.IsZero() {
+		wait = quietTime - time.Since(b.lastEnd)
+	}
+	m.mutex.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// release hands the bus over to the next request, if any.
+func (m *Manager) release(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	b := m.buses[path]
+	b.lastEnd = time.Now()
+	if len(b.queue) == 0 {
+		b.busy = false
+		return
+	}
+	next := 0
+	for i, w := range b.queue {
+		if w.priority > b.queue[next].priority || (w.priority == b.queue[next].priority && w.seq < b.queue[next].seq) {
+			next = i
+		}
+	}
+	w := b.queue[next]
+	b.queue = append(b.queue[:next], b.queue[next+1:]...)
+	close(w.ready)
+}
@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorities(t *testing.T) {
+	var m Manager
+	started := make(chan struct{})
+	hold := make(chan struct{})
+	go m.Do("/dev/ttyUSB0", PriorityLow, func() error {
+		close(started)
+		<-hold
+		return nil
+	})
+	<-started
+
+	var mutex sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	queued := 0
+	queue := func(name string, priority int) {
+		wg.Add(1)
+		go m.Do("/dev/ttyUSB0", priority, func() error {
+			defer wg.Done()
+			mutex.Lock()
+			order = append(order, name)
+			mutex.Unlock()
+			return nil
+		})
+		queued++
+		for m.Pending("/dev/ttyUSB0") != queued {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	queue("poll1", PriorityLow)
+	queue("poll2", PriorityLow)
+	queue("write", PriorityHigh)
+	if n := m.Pending("/dev/ttyUSB0"); n != 3 {
+		t.Fatalf("%d requests pending", n)
+	}
+
+	// another bus isn't blocked
+	if err := m.Do("/dev/ttyUSB1", PriorityLow, func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	close(hold)
+	wg.Wait()
+	if len(order) != 3 || order[0] != "write" || order[1] != "poll1" || order[2] != "poll2" {
+		t.Errorf("Unexpected order %v", order)
+	}
+}
+
+func TestQuietTime(t *testing.T) {
+	m := NewManager(20 * time.Millisecond)
+	var ends []time.Time
+	for i := 0; i < 2; i++ {
+		m.Do("/dev/ttyS0", PriorityNormal, func() error {
+			ends = append(ends, time.Now())
+			return nil
+		})
+	}
+	if gap := ends[1].Sub(ends[0]); gap < 20*time.Millisecond {
+		t.Errorf("Requests %v apart", gap)
+	}
+
+	m.SetQuietTime("/dev/ttyS0", 0)
+	start := time.Now()
+	m.Do("/dev/ttyS0", PriorityNormal, func() error { return nil })
+	if d := time.Since(start); d >= 20*time.Millisecond {
+		t.Errorf("Quiet time of the bus ignored, waited %v", d)
+	}
+}
@@ -0,0 +1,139 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cbor encodes values in the Concise Binary Object Representation
+// (RFC 7049), limited to the types of the events pushed to Core Data. The
+// keys of the maps are sorted as per the canonical CBOR, so that an event is
+// always encoded the same way.
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The major types.
+const (
+	majorUint   = 0
+	majorNegint = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorSimple = 7
+)
+
+const (
+	simpleFalse = 20
+	simpleTrue  = 21
+	simpleNull  = 22
+)
+
+// Marshal returns the CBOR encoding of v, which is nil, a bool, an integer,
+// a float64, a string, a []byte, a []interface{}, or a
+// map[string]interface{} of these.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		writeHead(buf, majorSimple, simpleNull)
+	case bool:
+		if v {
+			writeHead(buf, majorSimple, simpleTrue)
+		} else {
+			writeHead(buf, majorSimple, simpleFalse)
+		}
+	case int:
+		encodeInt(buf, int64(v))
+	case int64:
+		encodeInt(buf, v)
+	case uint64:
+		writeHead(buf, majorUint, v)
+	case float64:
+		buf.WriteByte(majorSimple<<5 | 27)
+		writeUint(buf, math.Float64bits(v), 8)
+	case string:
+		writeHead(buf, majorText, uint64(len(v)))
+		buf.WriteString(v)
+	case []byte:
+		writeHead(buf, majorBytes, uint64(len(v)))
+		buf.Write(v)
+	case []interface{}:
+		writeHead(buf, majorArray, uint64(len(v)))
+		for _, e := range v {
+			if err := encode(buf, e); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		// canonical order: shorter keys first, then bytewise
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		writeHead(buf, majorMap, uint64(len(v)))
+		for _, k := range keys {
+			encode(buf, k)
+			if err := encode(buf, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: unsupported type %T", v)
+	}
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, i int64) {
+	if i < 0 {
+		// -1 - n is encoded as n
+		writeHead(buf, majorNegint, uint64(-(i + 1)))
+		return
+	}
+	writeHead(buf, majorUint, uint64(i))
+}
+
+// writeHead writes the initial byte of a data item, with its argument in
+// the shortest form.
+func writeHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		writeUint(buf, n, 1)
+	case n <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		writeUint(buf, n, 2)
+	case n <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		writeUint(buf, n, 4)
+	default:
+		buf.WriteByte(major<<5 | 27)
+		writeUint(buf, n, 8)
+	}
+}
+
+func writeUint(buf *bytes.Buffer, n uint64, size int) {
+	for i := size - 1; i >= 0; i-- {
+		buf.WriteByte(byte(n >> (8 * uint(i))))
+	}
+}
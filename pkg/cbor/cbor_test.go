@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cbor
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The expected encodings are the examples of RFC 7049, appendix A.
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected []byte
+	}{
+		{"Zero", 0, []byte{0x00}},
+		{"23", 23, []byte{0x17}},
+		{"24", 24, []byte{0x18, 0x18}},
+		{"1000", int64(1000), []byte{0x19, 0x03, 0xe8}},
+		{"1000000", 1000000, []byte{0x1a, 0x00, 0x0f, 0x42, 0x40}},
+		{"MaxUint64", uint64(18446744073709551615), []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"-1", -1, []byte{0x20}},
+		{"-1000", -1000, []byte{0x39, 0x03, 0xe7}},
+		{"1.1", 1.1, []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}},
+		{"False", false, []byte{0xf4}},
+		{"True", true, []byte{0xf5}},
+		{"Null", nil, []byte{0xf6}},
+		{"Bytes", []byte{1, 2, 3, 4}, []byte{0x44, 0x01, 0x02, 0x03, 0x04}},
+		{"Text", "IETF", []byte{0x64, 0x49, 0x45, 0x54, 0x46}},
+		{"Array", []interface{}{1, []interface{}{2, 3}}, []byte{0x82, 0x01, 0x82, 0x02, 0x03}},
+		{"Map", map[string]interface{}{"b": []interface{}{2, 3}, "a": 1}, []byte{0xa2, 0x61, 0x61, 0x01, 0x61, 0x62, 0x82, 0x02, 0x03}},
+		{"Canonical", map[string]interface{}{"aa": 2, "b": 1}, []byte{0xa2, 0x61, 0x62, 0x01, 0x62, 0x61, 0x61, 0x02}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, tt.expected) {
+				t.Errorf("Expected % x, got % x", tt.expected, b)
+			}
+		})
+	}
+
+	if _, err := Marshal(map[string]interface{}{"a": struct{}{}}); err == nil {
+		t.Error("Unsupported type encoded")
+	}
+}
@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package codec
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Binary decodes and encodes numeric values of the Type of the device
+// resource PropertyValue. The ByteOrder attribute is either "BigEndian" (the
+// default) or "LittleEndian", and the WordSwap attribute ("true") swaps the
+// 16-bit words of 32 and 64-bit values, as done by many Modbus devices. The
+// RawType attribute gives the type of the raw value if it differs, see
+// AttrRawType.
+type Binary struct{}
+
+func (Binary) Decode(req ds_models.CommandRequest, raw []byte) (*ds_models.CommandValue, error) {
+	t, err := ds_models.ParseValueType(req.DeviceObject.Properties.Value.Type)
+	if err != nil {
+		return nil, err
+	}
+	size := t.Size()
+	if size == 0 {
+		return nil, fmt.Errorf("binary codec doesn't support %s values", t.Name())
+	}
+	origin := time.Now().UnixNano() / int64(time.Millisecond)
+
+	r, ok, err := rawTypeOf(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		v, err := r.decode(toBigEndian(req, raw))
+		if err != nil {
+			return nil, err
+		}
+		return ds_models.NewCommandValueBuilder().Resource(&req.RO).Origin(origin).Value(v, t).Build()
+	}
+
+	if len(raw) != size {
+		return nil, fmt.Errorf("%d bytes read for a %s value", len(raw), t.Name())
+	}
+	cv := &ds_models.CommandValue{RO: &req.RO, Origin: origin, Type: t}
+	cv.NumericValue = toBigEndian(req, raw)
+	return cv, nil
+}
+
+func (Binary) Encode(req ds_models.CommandRequest, cv *ds_models.CommandValue) ([]byte, error) {
+	if cv.Type.Size() == 0 {
+		return nil, fmt.Errorf("binary codec doesn't support %s values", cv.Type.Name())
+	}
+
+	r, ok, err := rawTypeOf(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		v, err := numericValue(cv)
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.encode(v)
+		if err != nil {
+			return nil, err
+		}
+		return toBigEndian(req, data), nil
+	}
+	// the conversion is its own inverse
+	return toBigEndian(req, cv.NumericValue), nil
+}
+
+// toBigEndian converts a value between the byte order of the device
+// resource and big-endian, the order of CommandValue.NumericValue.
+func toBigEndian(req ds_models.CommandRequest, raw []byte) []byte {
+	data := make([]byte, len(raw))
+	copy(data, raw)
+
+	if order, ok := attribute(req, "ByteOrder"); ok && strings.EqualFold(order, "LittleEndian") {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+	if swap, ok := attribute(req, "WordSwap"); ok && strings.EqualFold(swap, "true") && len(data) >= 4 {
+		for i, j := 0, len(data)-2; i < j; i, j = i+2, j-2 {
+			data[i], data[j] = data[j], data[i]
+			data[i+1], data[j+1] = data[j+1], data[i+1]
+		}
+	}
+	return data
+}
+
+// String decodes ASCII text, trimming the trailing NUL and space padding,
+// and encodes it padded with NULs to the Size of the device resource
+// PropertyValue, if given.
+type String struct{}
+
+func (String) Decode(req ds_models.CommandRequest, raw []byte) (*ds_models.CommandValue, error) {
+	s := strings.TrimRight(string(raw), "\x00 ")
+	return ds_models.NewStringValue(&req.RO, time.Now().UnixNano()/int64(time.Millisecond), s), nil
+}
+
+func (String) Encode(req ds_models.CommandRequest, cv *ds_models.CommandValue) ([]byte, error) {
+	s, err := cv.StringValue()
+	if err != nil {
+		return nil, err
+	}
+	data := []byte(s)
+
+	var size int
+	if v := req.DeviceObject.Properties.Value.Size; v != "" {
+		if _, err = fmt.Sscanf(v, "%d", &size); err != nil {
+			return nil, fmt.Errorf("invalid Size %s: %v", v, err)
+		}
+	}
+	if size > 0 {
+		if len(data) > size {
+			return nil, fmt.Errorf("string of %d bytes exceeds the Size %d", len(data), size)
+		}
+		data = append(data, make([]byte, size-len(data))...)
+	}
+	return data, nil
+}
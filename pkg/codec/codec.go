@@ -0,0 +1,101 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package codec converts between the raw bytes exchanged with a device and
+// CommandValues. Codecs are registered by name and selected per device
+// resource by its Codec attribute, so that a driver handles new encodings
+// without changes, e.g.:
+//
+//	cv, err := codec.Decode(req, raw)
+//
+// The "Binary" (numeric values, see Binary) and "String" (ASCII text padded
+// with NULs or spaces) codecs are built in.
+package codec
+
+import (
+	"fmt"
+	"sync"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Codec decodes the raw bytes read for a device resource into a
+// CommandValue, and encodes a CommandValue to write it.
+type Codec interface {
+	Decode(req ds_models.CommandRequest, raw []byte) (*ds_models.CommandValue, error)
+	Encode(req ds_models.CommandRequest, cv *ds_models.CommandValue) ([]byte, error)
+}
+
+// AttrCodec is the device resource attribute naming its Codec.
+const AttrCodec = "Codec"
+
+var (
+	mutex  sync.RWMutex
+	codecs = map[string]Codec{
+		"Binary": Binary{},
+		"String": String{},
+	}
+)
+
+// Register adds, or replaces, the named Codec.
+func Register(name string, c Codec) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	codecs[name] = c
+}
+
+// Lookup returns the named Codec.
+func Lookup(name string) (Codec, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	c, ok := codecs[name]
+	return c, ok
+}
+
+// For returns the Codec named by the Codec attribute of the device resource
+// of the request.
+func For(req ds_models.CommandRequest) (Codec, error) {
+	v, ok := req.DeviceObject.Attributes[AttrCodec]
+	if !ok {
+		return nil, fmt.Errorf("no %s attribute for device resource %s", AttrCodec, req.DeviceObject.Name)
+	}
+	name := fmt.Sprintf("%v", v)
+	c, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %s for device resource %s", name, req.DeviceObject.Name)
+	}
+	return c, nil
+}
+
+// Decode decodes raw bytes with the Codec of the device resource of the
+// request.
+func Decode(req ds_models.CommandRequest, raw []byte) (*ds_models.CommandValue, error) {
+	c, err := For(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decode(req, raw)
+}
+
+// Encode encodes a CommandValue with the Codec of the device resource of the
+// request.
+func Encode(req ds_models.CommandRequest, cv *ds_models.CommandValue) ([]byte, error) {
+	c, err := For(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encode(req, cv)
+}
+
+// attribute returns the string form of an attribute of the device resource
+// of the request.
+func attribute(req ds_models.CommandRequest, name string) (string, bool) {
+	v, ok := req.DeviceObject.Attributes[name]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%v", v), true
+}
@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+func request(valueType string, attributes map[string]interface{}) ds_models.CommandRequest {
+	req := ds_models.CommandRequest{}
+	req.DeviceObject.Name = "Power"
+	req.DeviceObject.Properties.Value.Type = valueType
+	req.DeviceObject.Attributes = attributes
+	return req
+}
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		raw        []byte
+	}{
+		{"ABCD", map[string]interface{}{"Codec": "Binary"}, []byte{0x00, 0x01, 0x02, 0x03}},
+		{"DCBA", map[string]interface{}{"Codec": "Binary", "ByteOrder": "LittleEndian"}, []byte{0x03, 0x02, 0x01, 0x00}},
+		{"CDAB", map[string]interface{}{"Codec": "Binary", "WordSwap": true}, []byte{0x02, 0x03, 0x00, 0x01}},
+	}
+	for _, tt := range tests {
+		req := request("Int32", tt.attributes)
+		cv, err := Decode(req, tt.raw)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if v, err := cv.Int32Value(); err != nil || v != 0x00010203 {
+			t.Errorf("%s: unexpected value %x, %v", tt.name, v, err)
+		}
+
+		raw, err := Encode(req, cv)
+		if err != nil || !bytes.Equal(raw, tt.raw) {
+			t.Errorf("%s: unexpected encoding %x, %v", tt.name, raw, err)
+		}
+	}
+
+	if _, err := Decode(request("Int32", map[string]interface{}{"Codec": "Binary"}), []byte{0x00}); err == nil {
+		t.Error("Int32 decoded from a single byte")
+	}
+}
+
+func TestString(t *testing.T) {
+	req := request("String", map[string]interface{}{"Codec": "String"})
+	req.DeviceObject.Properties.Value.Size = "8"
+	cv, err := Decode(req, []byte("METER\x00\x00\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := cv.StringValue(); s != "METER" {
+		t.Errorf("Unexpected value %q", s)
+	}
+	raw, err := Encode(req, cv)
+	if err != nil || len(raw) != 8 {
+		t.Errorf("Unexpected encoding %q, %v", raw, err)
+	}
+}
+
+type bcd struct{}
+
+func (bcd) Decode(req ds_models.CommandRequest, raw []byte) (*ds_models.CommandValue, error) {
+	var v uint32
+	for _, b := range raw {
+		if b>>4 > 9 || b&0x0f > 9 {
+			return nil, errors.New("invalid BCD digit")
+		}
+		v = v*100 + uint32(b>>4)*10 + uint32(b&0x0f)
+	}
+	return ds_models.NewUint32Value(&req.RO, 0, v)
+}
+
+func (bcd) Encode(req ds_models.CommandRequest, cv *ds_models.CommandValue) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestRegister(t *testing.T) {
+	req := request("Uint32", map[string]interface{}{"Codec": "BCD"})
+	if _, err := Decode(req, []byte{0x12, 0x34}); err == nil {
+		t.Fatal("Decoded with unregistered codec")
+	}
+
+	Register("BCD", bcd{})
+	cv, err := Decode(req, []byte{0x12, 0x34})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cv.Uint32Value(); v != 1234 {
+		t.Errorf("Unexpected value %d", v)
+	}
+
+	if _, err = Decode(request("Uint32", nil), nil); err == nil {
+		t.Error("Decoded without Codec attribute")
+	}
+}
+
+func TestBinaryRawType(t *testing.T) {
+	tests := []struct {
+		name       string
+		valueType  string
+		attributes map[string]interface{}
+		raw        []byte
+		expected   string
+	}{
+		{"negative Int8", "Int32", map[string]interface{}{"RawType": "Int8"}, []byte{0xfe}, "-2"},
+		{"negative Int16", "Int32", map[string]interface{}{"RawType": "Int16"}, []byte{0xff, 0x85}, "-123"},
+		{"swapped Int16", "Int64", map[string]interface{}{"RawType": "Int16", "ByteOrder": "LittleEndian"}, []byte{0x85, 0xff}, "-123"},
+		{"word swapped Int32", "Int64", map[string]interface{}{"RawType": "Int32", "WordSwap": "true"}, []byte{0x1c, 0x1d, 0xff, 0xfe}, "-123875"},
+		{"Uint16", "Int32", map[string]interface{}{"RawType": "Uint16"}, []byte{0xff, 0x85}, "65413"},
+		{"Float16", "Float32", map[string]interface{}{"RawType": "FLOAT16"}, []byte{0x3e, 0x00}, "1.5"},
+		{"negative Float16", "Float64", map[string]interface{}{"RawType": "Float16"}, []byte{0xc5, 0x00}, "-5"},
+		{"subnormal Float16", "Float32", map[string]interface{}{"RawType": "Float16"}, []byte{0x00, 0x01}, "5.9604645e-08"},
+		{"swapped Float16", "Float32", map[string]interface{}{"RawType": "Float16", "ByteOrder": "LittleEndian"}, []byte{0xff, 0x7b}, "65504"},
+	}
+	for _, tt := range tests {
+		tt.attributes["Codec"] = "Binary"
+		req := request(tt.valueType, tt.attributes)
+		cv, err := Decode(req, tt.raw)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if v, err := numericValue(cv); err != nil || fmt.Sprint(v) != tt.expected {
+			t.Errorf("%s: expected %s, got %v, %v", tt.name, tt.expected, v, err)
+		}
+
+		raw, err := Encode(req, cv)
+		if err != nil || !bytes.Equal(raw, tt.raw) {
+			t.Errorf("%s: unexpected encoding %x, %v", tt.name, raw, err)
+		}
+	}
+
+	// out of the range of the raw type
+	req := request("Int32", map[string]interface{}{"Codec": "Binary", "RawType": "Int16"})
+	cv, _ := ds_models.NewInt32Value(nil, 0, 40000)
+	if _, err := Encode(req, cv); err == nil {
+		t.Error("Int32 40000 encoded as an Int16")
+	}
+	// the value doesn't fit the Type
+	if _, err := Decode(request("Int8", map[string]interface{}{"Codec": "Binary", "RawType": "Int16"}), []byte{0x01, 0x00}); err == nil {
+		t.Error("Int16 256 decoded as an Int8")
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	tests := []struct {
+		half  uint16
+		value float32
+	}{
+		{0x0000, 0},
+		{0x3c00, 1},
+		{0xc000, -2},
+		{0x7bff, 65504},
+		{0x0400, 6.1035156e-05},
+		{0x3555, 0.33325195},
+		{0x7c00, float32(math.Inf(1))},
+		{0xfc00, float32(math.Inf(-1))},
+	}
+	for _, tt := range tests {
+		if f := halfToFloat32(tt.half); f != tt.value {
+			t.Errorf("%04x: expected %v, got %v", tt.half, tt.value, f)
+		}
+		if h := float32ToHalf(tt.value); h != tt.half {
+			t.Errorf("%v: expected %04x, got %04x", tt.value, tt.half, h)
+		}
+	}
+	if f := halfToFloat32(0x7e00); !math.IsNaN(float64(f)) {
+		t.Errorf("Expected NaN, got %v", f)
+	}
+	// rounding to nearest, and overflow
+	if h := float32ToHalf(1.0004883); h != 0x3c00 {
+		t.Errorf("Unexpected rounding %04x", h)
+	}
+	if h := float32ToHalf(70000); h != 0x7c00 {
+		t.Errorf("Expected infinity, got %04x", h)
+	}
+}
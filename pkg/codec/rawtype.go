@@ -0,0 +1,210 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// AttrRawType is the device resource attribute giving the type of the raw
+// value, when it differs from the Type of the PropertyValue, e.g. "Int16"
+// for the signed register of an Int32 resource, or "Float16" for an IEEE 754
+// half-precision register. Signed values are sign-extended.
+const AttrRawType = "RawType"
+
+// float16 is the raw type of half-precision values, unknown to ValueType.
+const float16 = "Float16"
+
+// rawType is the type of the raw values of a device resource: a ValueType,
+// or a half-precision float if half is set.
+type rawType struct {
+	t    ds_models.ValueType
+	half bool
+}
+
+// rawTypeOf returns the RawType of the device resource of the request, if
+// given.
+func rawTypeOf(req ds_models.CommandRequest) (rawType, bool, error) {
+	name, ok := attribute(req, AttrRawType)
+	if !ok {
+		return rawType{}, false, nil
+	}
+	if strings.EqualFold(name, float16) {
+		return rawType{half: true}, true, nil
+	}
+	t, err := ds_models.ParseValueType(name)
+	if err != nil {
+		return rawType{}, false, err
+	}
+	if t.Size() == 0 {
+		return rawType{}, false, fmt.Errorf("invalid %s %s", AttrRawType, name)
+	}
+	return rawType{t: t}, true, nil
+}
+
+func (r rawType) name() string {
+	if r.half {
+		return float16
+	}
+	return r.t.Name()
+}
+
+func (r rawType) size() int {
+	if r.half {
+		return 2
+	}
+	return r.t.Size()
+}
+
+// decode returns the value of big-endian raw bytes of the raw type.
+func (r rawType) decode(data []byte) (interface{}, error) {
+	if len(data) != r.size() {
+		return nil, fmt.Errorf("%d bytes read for a %s value", len(data), r.name())
+	}
+	if r.half {
+		return halfToFloat32(binary.BigEndian.Uint16(data)), nil
+	}
+	switch r.t {
+	case ds_models.Bool:
+		return data[0] != 0, nil
+	case ds_models.Uint8:
+		return data[0], nil
+	case ds_models.Uint16:
+		return binary.BigEndian.Uint16(data), nil
+	case ds_models.Uint32:
+		return binary.BigEndian.Uint32(data), nil
+	case ds_models.Uint64:
+		return binary.BigEndian.Uint64(data), nil
+	case ds_models.Int8:
+		return int8(data[0]), nil
+	case ds_models.Int16:
+		return int16(binary.BigEndian.Uint16(data)), nil
+	case ds_models.Int32:
+		return int32(binary.BigEndian.Uint32(data)), nil
+	case ds_models.Int64:
+		return int64(binary.BigEndian.Uint64(data)), nil
+	case ds_models.Float32:
+		return math.Float32frombits(binary.BigEndian.Uint32(data)), nil
+	case ds_models.Float64:
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	}
+	return nil, fmt.Errorf("unsupported raw type %s", r.name())
+}
+
+// encode returns the big-endian raw bytes of a value, failing if out of the
+// range of the raw type.
+func (r rawType) encode(value interface{}) ([]byte, error) {
+	if r.half {
+		cv, err := ds_models.NewCommandValueBuilder().Value(value, ds_models.Float32).Build()
+		if err != nil {
+			return nil, err
+		}
+		f, _ := cv.Float32Value()
+		data := make([]byte, 2)
+		binary.BigEndian.PutUint16(data, float32ToHalf(f))
+		return data, nil
+	}
+	cv, err := ds_models.NewCommandValueBuilder().Value(value, r.t).Build()
+	if err != nil {
+		return nil, err
+	}
+	return cv.NumericValue, nil
+}
+
+// numericValue returns the value of a numeric CommandValue.
+func numericValue(cv *ds_models.CommandValue) (interface{}, error) {
+	switch cv.Type {
+	case ds_models.Bool:
+		return cv.BoolValue()
+	case ds_models.Uint8:
+		return cv.Uint8Value()
+	case ds_models.Uint16:
+		return cv.Uint16Value()
+	case ds_models.Uint32:
+		return cv.Uint32Value()
+	case ds_models.Uint64:
+		return cv.Uint64Value()
+	case ds_models.Int8:
+		return cv.Int8Value()
+	case ds_models.Int16:
+		return cv.Int16Value()
+	case ds_models.Int32:
+		return cv.Int32Value()
+	case ds_models.Int64:
+		return cv.Int64Value()
+	case ds_models.Float32:
+		return cv.Float32Value()
+	case ds_models.Float64:
+		return cv.Float64Value()
+	}
+	return nil, fmt.Errorf("binary codec doesn't support %s values", cv.Type.Name())
+}
+
+// halfToFloat32 converts an IEEE 754 half-precision value.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		// zero or subnormal: mant * 2^-24
+		f := float32(math.Ldexp(float64(mant), -24))
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	case 0x1f:
+		// infinity or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp-15+127)<<23 | mant<<13)
+}
+
+// float32ToHalf converts a value to IEEE 754 half precision, rounding to
+// nearest even, out of range values becoming infinite.
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	mant := bits & 0x7fffff
+	if bits>>23&0xff == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+
+	exp := int(bits>>23&0xff) - 127 + 15
+	if exp >= 0x1f {
+		return sign | 0x7c00
+	}
+	if exp <= 0 {
+		// subnormal, or zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - exp)
+		half := uint16(mant >> shift)
+		rem, halfway := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	// a carry out of the mantissa increments the exponent, as expected
+	if rem := mant & 0x1fff; rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+	return half
+}
@@ -0,0 +1,148 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compression compresses the values of large readings, such as
+// waveform captures or load profiles read as register blocks. A compressed
+// value is carried as "<encoding>:<base64 data>", the encoding acting as
+// the content-encoding tag of the reading.
+//
+// gzip and deflate are built in. Other encodings, e.g. zstd, can be added
+// with Register by the driver.
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+	Zstd    = "zstd"
+)
+
+// Codec compresses and decompresses data with a given encoding.
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	mutex  sync.RWMutex
+	codecs = map[string]Codec{
+		Gzip:    streamCodec{newWriter: newGzipWriter, newReader: newGzipReader},
+		Deflate: streamCodec{newWriter: newFlateWriter, newReader: newFlateReader},
+	}
+)
+
+// Register adds, or replaces, the Codec of an encoding.
+func Register(encoding string, c Codec) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	codecs[strings.ToLower(encoding)] = c
+}
+
+// Supported returns whether a Codec is registered for the encoding.
+func Supported(encoding string) bool {
+	_, ok := codec(encoding)
+	return ok
+}
+
+func codec(encoding string) (Codec, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	c, ok := codecs[strings.ToLower(encoding)]
+	return c, ok
+}
+
+// EncodeValue returns the value compressed with the given encoding, in the
+// form carried by a reading.
+func EncodeValue(encoding string, value string) (string, error) {
+	c, ok := codec(encoding)
+	if !ok {
+		return "", fmt.Errorf("unsupported compression %s", encoding)
+	}
+	data, err := c.Compress([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(encoding) + ":" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeValue returns the original value of a reading compressed by
+// EncodeValue, along with its encoding.
+func DecodeValue(value string) (string, string, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("reading value has no content encoding")
+	}
+	c, ok := codec(parts[0])
+	if !ok {
+		return "", "", fmt.Errorf("unsupported compression %s", parts[0])
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", err
+	}
+	data, err = c.Decompress(data)
+	if err != nil {
+		return "", "", err
+	}
+	return string(data), parts[0], nil
+}
+
+// streamCodec adapts the compress/* writers and readers to a Codec.
+type streamCodec struct {
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+func (s streamCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := s.newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s streamCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := s.newReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func newGzipWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, gzip.BestCompression)
+}
+
+func newGzipReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+func newFlateWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.BestCompression)
+}
+
+func newFlateReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
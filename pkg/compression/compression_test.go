@@ -0,0 +1,48 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package compression
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeValue(t *testing.T) {
+	value := "[" + strings.Repeat("1023, 0, ", 512) + "1023]"
+	for _, encoding := range []string{Gzip, Deflate} {
+		encoded, err := EncodeValue(encoding, value)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		if !strings.HasPrefix(encoded, encoding+":") {
+			t.Errorf("%s: encoding missing from %s", encoding, encoded)
+		}
+		if len(encoded) >= len(value) {
+			t.Errorf("%s: value not compressed, %d >= %d bytes", encoding, len(encoded), len(value))
+		}
+
+		decoded, enc, err := DecodeValue(encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		if decoded != value || enc != encoding {
+			t.Errorf("%s: decoded value differs", encoding)
+		}
+	}
+}
+
+func TestUnsupportedEncoding(t *testing.T) {
+	if Supported(Zstd) {
+		t.Fatal("zstd supported without registered codec")
+	}
+	if _, err := EncodeValue(Zstd, "value"); err == nil {
+		t.Error("Value encoded with unsupported encoding")
+	}
+	if _, _, err := DecodeValue("zstd:AAAA"); err == nil {
+		t.Error("Value decoded with unsupported encoding")
+	}
+}
@@ -0,0 +1,147 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driverhost
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+type testDriver struct {
+	asyncCh chan<- *ds_models.AsyncValues
+	written string
+	devices map[string]bool
+}
+
+func (d *testDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+func (d *testDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	d.asyncCh = asyncCh
+	return lc.Info("initialized")
+}
+
+func (d *testDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	if addr.Address != "meter01" {
+		return nil, errors.New("unknown address")
+	}
+	cv, err := ds_models.NewUint16Value(&reqs[0].RO, 1000, 230)
+	return []*ds_models.CommandValue{cv}, err
+}
+
+func (d *testDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	var err error
+	d.written, err = params[0].StringValue()
+	return err
+}
+
+func (d *testDriver) AddDevice(device models.Device) error {
+	if device.Addressable.Address == "" {
+		return errors.New("no address")
+	}
+	d.devices[device.Name] = true
+	return nil
+}
+
+func (d *testDriver) UpdateDevice(device models.Device) error {
+	return nil
+}
+
+func (d *testDriver) RemoveDevice(device models.Device) error {
+	delete(d.devices, device.Name)
+	return nil
+}
+
+func (d *testDriver) APIVersion() string {
+	return ds_models.APIVersion
+}
+
+func (d *testDriver) Stop(force bool) error {
+	return nil
+}
+
+func TestProcessDriver(t *testing.T) {
+	driverParent, driverChild := net.Pipe()
+	hostParent, hostChild := net.Pipe()
+	driver := &testDriver{devices: make(map[string]bool)}
+	go serve(driver, driverChild, hostChild)
+
+	asyncCh := make(chan *ds_models.AsyncValues, 1)
+	p := NewProcessDriver("none")
+	p.lc = logger.NewClient("driverhost_test", false, "", "DEBUG")
+	p.asyncCh = asyncCh
+	p.mutex.Lock()
+	err := p.connect(driverParent, hostParent)
+	p.mutex.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := &models.Addressable{Address: "meter01"}
+	reqs := []ds_models.CommandRequest{{RO: models.ResourceOperation{Object: "Voltage"}}}
+	cvs, err := p.HandleReadCommands(addr, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := cvs[0].Uint16Value(); err != nil || v != 230 || cvs[0].RO.Object != "Voltage" {
+		t.Errorf("Unexpected read result %v", cvs[0])
+	}
+	if _, err = p.HandleReadCommands(&models.Addressable{Address: "meter02"}, reqs); err == nil {
+		t.Error("Driver error not returned")
+	}
+
+	param := ds_models.NewStringValue(&reqs[0].RO, 0, "on")
+	if err = p.HandleWriteCommands(addr, reqs, []*ds_models.CommandValue{param}); err != nil || driver.written != "on" {
+		t.Errorf("Unexpected write result %q, %v", driver.written, err)
+	}
+
+	cv := ds_models.NewStringValue(&reqs[0].RO, 0, "async")
+	driver.asyncCh <- &ds_models.AsyncValues{DeviceName: "meter", CommandValues: []*ds_models.CommandValue{cv}}
+	select {
+	case acv := <-asyncCh:
+		if s, _ := acv.CommandValues[0].StringValue(); acv.DeviceName != "meter" || s != "async" {
+			t.Errorf("Unexpected async values %v", acv)
+		}
+	case <-time.After(time.Second):
+		t.Error("Async values not forwarded")
+	}
+
+	if err = p.CheckLiveness(); err != nil {
+		t.Error(err)
+	}
+
+	device := models.Device{Name: "meter", Addressable: *addr}
+	if err = p.AddDevice(device); err != nil || !driver.devices["meter"] {
+		t.Errorf("Device not added: %v", err)
+	}
+	if err = p.AddDevice(models.Device{Name: "invalid"}); err == nil {
+		t.Error("Invalid device not rejected")
+	}
+	if err = p.RemoveDevice(device); err != nil || driver.devices["meter"] {
+		t.Errorf("Device not removed: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	d, err := Load("exec:/usr/bin/driver -v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := d.(*ProcessDriver); !ok || p.path != "/usr/bin/driver" || len(p.args) != 1 {
+		t.Errorf("Unexpected driver %v", d)
+	}
+	if _, err = Load("/usr/bin/driver"); err == nil {
+		t.Error("Invalid reference loaded")
+	}
+}
@@ -0,0 +1,60 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package driverhost hosts a ProtocolDriver outside of the device service
+// binary, either in a Go plugin or in a separate process, so that the driver
+// can be updated without recompiling the device service, and, for a
+// process, so that an unstable driver can't take the device service down.
+package driverhost
+
+import (
+	"fmt"
+	"plugin"
+	"strings"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// PluginSymbol is the symbol of a Go plugin holding its ProtocolDriver.
+const PluginSymbol = "Driver"
+
+// LoadPlugin returns the ProtocolDriver exported by a Go plugin, as a
+// variable named Driver, e.g.:
+//
+//	var Driver driver.SimpleDriver
+func LoadPlugin(path string) (ds_models.ProtocolDriver, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+	if driver, ok := sym.(ds_models.ProtocolDriver); ok {
+		return driver, nil
+	}
+	if driver, ok := sym.(*ds_models.ProtocolDriver); ok && *driver != nil {
+		return *driver, nil
+	}
+	return nil, fmt.Errorf("%s of plugin %s isn't a ProtocolDriver", PluginSymbol, path)
+}
+
+// Load returns the ProtocolDriver referenced as "plugin:<file>" or as
+// "exec:<command> [args...]".
+func Load(ref string) (ds_models.ProtocolDriver, error) {
+	switch {
+	case strings.HasPrefix(ref, "plugin:"):
+		return LoadPlugin(strings.TrimPrefix(ref, "plugin:"))
+	case strings.HasPrefix(ref, "exec:"):
+		fields := strings.Fields(strings.TrimPrefix(ref, "exec:"))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("no command in driver reference %s", ref)
+		}
+		return NewProcessDriver(fields[0], fields[1:]...), nil
+	}
+	return nil, fmt.Errorf("invalid driver reference %s, expected plugin:<file> or exec:<command>", ref)
+}
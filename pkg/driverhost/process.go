@@ -0,0 +1,284 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driverhost
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	restartDelay = time.Second
+	stopTimeout  = 5 * time.Second
+)
+
+// ErrNotRunning is returned when the driver process isn't running.
+var ErrNotRunning = errors.New("driver process not running")
+
+// ProcessDriver is a ProtocolDriver forwarding the calls of the device
+// service to a driver hosted in a separate process by Serve, so that a
+// crash of the driver doesn't take the device service down. The process is
+// restarted if it exits unexpectedly.
+type ProcessDriver struct {
+	path string
+	args []string
+
+	mutex    sync.Mutex
+	lc       logger.LoggingClient
+	asyncCh  chan<- *ds_models.AsyncValues
+	cmd      *exec.Cmd
+	client   *rpc.Client
+	stopping bool
+}
+
+// NewProcessDriver returns a ProtocolDriver hosted by the given command.
+func NewProcessDriver(path string, args ...string) *ProcessDriver {
+	return &ProcessDriver{path: path, args: args}
+}
+
+func (p *ProcessDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.lc = lc
+	p.asyncCh = asyncCh
+	return p.start()
+}
+
+// start must be called with the mutex held.
+func (p *ProcessDriver) start() error {
+	cmd := exec.Command(p.path, p.args...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	// the driver calls the device service through a second pair of pipes
+	hostIn, childOut, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	childIn, hostOut, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	cmd.ExtraFiles = []*os.File{childIn, childOut}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	childIn.Close()
+	childOut.Close()
+	p.cmd = cmd
+	p.lc.Info(fmt.Sprintf("Started driver process %s (pid %d)", p.path, cmd.Process.Pid))
+
+	driverConn := pipeConn{Reader: stdout, WriteCloser: stdin, r: stdout}
+	hostConn := pipeConn{Reader: hostIn, WriteCloser: hostOut, r: hostIn}
+	go p.wait(cmd)
+	if err = p.connect(driverConn, hostConn); err != nil {
+		// not restarted, as p.cmd no longer refers to it
+		p.client.Close()
+		p.client = nil
+		p.cmd = nil
+		cmd.Process.Kill()
+	}
+	return err
+}
+
+// connect must be called with the mutex held.
+func (p *ProcessDriver) connect(driverConn io.ReadWriteCloser, hostConn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Host", &hostService{p: p}); err != nil {
+		return err
+	}
+	go server.ServeCodec(jsonrpc.NewServerCodec(hostConn))
+
+	p.client = jsonrpc.NewClient(driverConn)
+	var version string
+	if err := p.client.Call("Driver.APIVersion", struct{}{}, &version); err != nil {
+		return err
+	}
+	compat, err := ds_models.CheckAPIVersion(version)
+	if err != nil {
+		return fmt.Errorf("driver process %s: %v", p.path, err)
+	}
+	if compat {
+		p.lc.Warn(fmt.Sprintf("Driver process %s built against SDK API %q, running in compatibility mode with SDK API %s", p.path, version, ds_models.APIVersion))
+	}
+	return p.client.Call("Driver.Initialize", struct{}{}, &struct{}{})
+}
+
+// wait restarts the driver process if it exits while not stopping.
+func (p *ProcessDriver) wait(cmd *exec.Cmd) {
+	err := cmd.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.cmd != cmd {
+		return
+	}
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+	}
+	p.cmd = nil
+	if p.stopping {
+		return
+	}
+
+	p.lc.Error(fmt.Sprintf("Driver process %s exited: %v, restarting it", p.path, err))
+	for !p.stopping {
+		p.mutex.Unlock()
+		time.Sleep(restartDelay)
+		p.mutex.Lock()
+		if p.stopping || p.cmd != nil {
+			return
+		}
+		if err = p.start(); err == nil {
+			return
+		}
+		p.lc.Error(fmt.Sprintf("Restarting driver process %s failed: %v", p.path, err))
+	}
+}
+
+func (p *ProcessDriver) call(method string, args interface{}, reply interface{}) error {
+	p.mutex.Lock()
+	client := p.client
+	p.mutex.Unlock()
+
+	if client == nil {
+		return ErrNotRunning
+	}
+	return client.Call(method, args, reply)
+}
+
+func (p *ProcessDriver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	var reply []WireValue
+	if err := p.call("Driver.HandleReadCommands", ReadArgs{Addr: *addr, Reqs: reqs}, &reply); err != nil {
+		return nil, err
+	}
+	return fromWire(reply), nil
+}
+
+func (p *ProcessDriver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	return p.call("Driver.HandleWriteCommands", WriteArgs{Addr: *addr, Reqs: reqs, Params: toWire(params)}, &struct{}{})
+}
+
+func (p *ProcessDriver) DisconnectDevice(addr *models.Addressable) error {
+	return p.call("Driver.DisconnectDevice", *addr, &struct{}{})
+}
+
+// CheckLiveness implements LivenessChecker, checking the driver process
+// responds.
+func (p *ProcessDriver) CheckLiveness() error {
+	return p.call("Driver.CheckLiveness", struct{}{}, &struct{}{})
+}
+
+// Peers implements PeerReporter, returning the peers of the driver process
+// if its driver implements it, or none if it doesn't respond.
+func (p *ProcessDriver) Peers() []ds_models.PeerStatus {
+	var reply []ds_models.PeerStatus
+	if err := p.call("Driver.Peers", struct{}{}, &reply); err != nil {
+		return nil
+	}
+	return reply
+}
+
+// AddDevice implements DeviceLifecycleHandler, forwarding the Device to the
+// driver process if its driver implements it.
+func (p *ProcessDriver) AddDevice(device models.Device) error {
+	return p.call("Driver.AddDevice", device, &struct{}{})
+}
+
+// UpdateDevice implements DeviceLifecycleHandler.
+func (p *ProcessDriver) UpdateDevice(device models.Device) error {
+	return p.call("Driver.UpdateDevice", device, &struct{}{})
+}
+
+// RemoveDevice implements DeviceLifecycleHandler.
+func (p *ProcessDriver) RemoveDevice(device models.Device) error {
+	return p.call("Driver.RemoveDevice", device, &struct{}{})
+}
+
+// Stop stops the driver, and kills its process if it doesn't exit in time.
+func (p *ProcessDriver) Stop(force bool) error {
+	p.mutex.Lock()
+	p.stopping = true
+	cmd := p.cmd
+	p.mutex.Unlock()
+
+	err := p.call("Driver.Stop", force, &struct{}{})
+	if cmd == nil {
+		return err
+	}
+	p.mutex.Lock()
+	if p.client != nil {
+		p.client.Close() // closes the standard input, so that Serve returns
+	}
+	p.mutex.Unlock()
+
+	exited := make(chan struct{})
+	go func() {
+		for {
+			p.mutex.Lock()
+			running := p.cmd == cmd
+			p.mutex.Unlock()
+			if !running {
+				close(exited)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		cmd.Process.Kill()
+	}
+	return err
+}
+
+// hostService exposes the device service to the driver process.
+type hostService struct {
+	p *ProcessDriver
+}
+
+func (h *hostService) PushAsync(args AsyncArgs, _ *struct{}) error {
+	h.p.asyncCh <- &ds_models.AsyncValues{DeviceName: args.DeviceName, CommandValues: fromWire(args.CommandValues), Capture: args.Capture}
+	return nil
+}
+
+func (h *hostService) Log(args LogArgs, _ *struct{}) error {
+	switch args.Level {
+	case "TRACE":
+		return h.p.lc.Trace(args.Msg)
+	case "DEBUG":
+		return h.p.lc.Debug(args.Msg)
+	case "WARN":
+		return h.p.lc.Warn(args.Msg)
+	case "ERROR":
+		return h.p.lc.Error(args.Msg)
+	}
+	return h.p.lc.Info(args.Msg)
+}
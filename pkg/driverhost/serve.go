@@ -0,0 +1,143 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driverhost
+
+import (
+	"errors"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const asyncBufferSize = 16
+
+// Serve hosts a ProtocolDriver in the current process, started by a
+// ProcessDriver, until the driver is stopped or the device service exits.
+// As the standard input and output carry the calls of the device service,
+// the driver must log through the LoggingClient it is initialized with, or
+// to the standard error.
+func Serve(driver ds_models.ProtocolDriver) error {
+	hostIn := os.NewFile(3, "host-in")
+	hostOut := os.NewFile(4, "host-out")
+	if hostIn == nil || hostOut == nil {
+		return errors.New("driver process not started by a device service")
+	}
+	driverConn := pipeConn{Reader: os.Stdin, WriteCloser: os.Stdout, r: os.Stdin}
+	hostConn := pipeConn{Reader: hostIn, WriteCloser: hostOut, r: hostIn}
+	return serve(driver, driverConn, hostConn)
+}
+
+func serve(driver ds_models.ProtocolDriver, driverConn io.ReadWriteCloser, hostConn io.ReadWriteCloser) error {
+	host := jsonrpc.NewClient(hostConn)
+	defer host.Close()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Driver", &driverService{driver: driver, host: host}); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(driverConn))
+	return nil
+}
+
+// driverService exposes a ProtocolDriver to the device service.
+type driverService struct {
+	driver ds_models.ProtocolDriver
+	host   *rpc.Client
+}
+
+func (d *driverService) APIVersion(_ struct{}, reply *string) error {
+	if vd, ok := d.driver.(ds_models.VersionedDriver); ok {
+		*reply = vd.APIVersion()
+	}
+	return nil
+}
+
+func (d *driverService) Initialize(_ struct{}, _ *struct{}) error {
+	asyncCh := make(chan *ds_models.AsyncValues, asyncBufferSize)
+	go func() {
+		for acv := range asyncCh {
+			args := AsyncArgs{DeviceName: acv.DeviceName, CommandValues: toWire(acv.CommandValues), Capture: acv.Capture}
+			d.host.Call("Host.PushAsync", args, &struct{}{})
+		}
+	}()
+	return d.driver.Initialize(&remoteLogger{host: d.host}, asyncCh)
+}
+
+func (d *driverService) HandleReadCommands(args ReadArgs, reply *[]WireValue) error {
+	cvs, err := d.driver.HandleReadCommands(&args.Addr, args.Reqs)
+	if err != nil {
+		return err
+	}
+	*reply = toWire(cvs)
+	return nil
+}
+
+func (d *driverService) HandleWriteCommands(args WriteArgs, _ *struct{}) error {
+	return d.driver.HandleWriteCommands(&args.Addr, args.Reqs, fromWire(args.Params))
+}
+
+func (d *driverService) DisconnectDevice(addr models.Addressable, _ *struct{}) error {
+	return d.driver.DisconnectDevice(&addr)
+}
+
+func (d *driverService) Stop(force bool, _ *struct{}) error {
+	return d.driver.Stop(force)
+}
+
+func (d *driverService) CheckLiveness(_ struct{}, _ *struct{}) error {
+	if checker, ok := d.driver.(ds_models.LivenessChecker); ok {
+		return checker.CheckLiveness()
+	}
+	return nil
+}
+
+func (d *driverService) Peers(_ struct{}, reply *[]ds_models.PeerStatus) error {
+	if reporter, ok := d.driver.(ds_models.PeerReporter); ok {
+		*reply = reporter.Peers()
+	}
+	return nil
+}
+
+func (d *driverService) AddDevice(device models.Device, _ *struct{}) error {
+	if h, ok := d.driver.(ds_models.DeviceLifecycleHandler); ok {
+		return h.AddDevice(device)
+	}
+	return nil
+}
+
+func (d *driverService) UpdateDevice(device models.Device, _ *struct{}) error {
+	if h, ok := d.driver.(ds_models.DeviceLifecycleHandler); ok {
+		return h.UpdateDevice(device)
+	}
+	return nil
+}
+
+func (d *driverService) RemoveDevice(device models.Device, _ *struct{}) error {
+	if h, ok := d.driver.(ds_models.DeviceLifecycleHandler); ok {
+		return h.RemoveDevice(device)
+	}
+	return nil
+}
+
+// remoteLogger forwards the log messages of the driver to the device service.
+type remoteLogger struct {
+	host *rpc.Client
+}
+
+func (l *remoteLogger) log(level string, msg string) error {
+	return l.host.Call("Host.Log", LogArgs{Level: level, Msg: msg}, &struct{}{})
+}
+
+func (l *remoteLogger) Debug(msg string, labels ...string) error { return l.log("DEBUG", msg) }
+func (l *remoteLogger) Error(msg string, labels ...string) error { return l.log("ERROR", msg) }
+func (l *remoteLogger) Info(msg string, labels ...string) error  { return l.log("INFO", msg) }
+func (l *remoteLogger) Trace(msg string, labels ...string) error { return l.log("TRACE", msg) }
+func (l *remoteLogger) Warn(msg string, labels ...string) error  { return l.log("WARN", msg) }
@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driverhost
+
+import (
+	"io"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// WireValue is the serializable form of a CommandValue, whose string value
+// isn't exported.
+type WireValue struct {
+	RO           models.ResourceOperation
+	Origin       int64
+	Type         ds_models.ValueType
+	NumericValue []byte
+	StringValue  string
+}
+
+func toWire(cvs []*ds_models.CommandValue) []WireValue {
+	result := make([]WireValue, len(cvs))
+	for i, cv := range cvs {
+		if cv.RO != nil {
+			result[i].RO = *cv.RO
+		}
+		result[i].Origin = cv.Origin
+		result[i].Type = cv.Type
+		if cv.Type == ds_models.String {
+			result[i].StringValue, _ = cv.StringValue()
+		} else {
+			result[i].NumericValue = cv.NumericValue
+		}
+	}
+	return result
+}
+
+func fromWire(wvs []WireValue) []*ds_models.CommandValue {
+	result := make([]*ds_models.CommandValue, len(wvs))
+	for i := range wvs {
+		wv := wvs[i]
+		if wv.Type == ds_models.String {
+			result[i] = ds_models.NewStringValue(&wv.RO, wv.Origin, wv.StringValue)
+		} else {
+			result[i] = &ds_models.CommandValue{RO: &wv.RO, Origin: wv.Origin, Type: wv.Type, NumericValue: wv.NumericValue}
+		}
+	}
+	return result
+}
+
+// ReadArgs are the arguments of Driver.HandleReadCommands.
+type ReadArgs struct {
+	Addr models.Addressable
+	Reqs []ds_models.CommandRequest
+}
+
+// WriteArgs are the arguments of Driver.HandleWriteCommands.
+type WriteArgs struct {
+	Addr   models.Addressable
+	Reqs   []ds_models.CommandRequest
+	Params []WireValue
+}
+
+// AsyncArgs are the arguments of Host.PushAsync.
+type AsyncArgs struct {
+	DeviceName    string
+	CommandValues []WireValue
+	Capture       *ds_models.CaptureChunk
+}
+
+// LogArgs are the arguments of Host.Log.
+type LogArgs struct {
+	Level string
+	Msg   string
+}
+
+// pipeConn joins the two ends of a pair of pipes into a connection.
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+	r io.Closer
+}
+
+func (c pipeConn) Close() error {
+	err := c.WriteCloser.Close()
+	if rerr := c.r.Close(); err == nil {
+		err = rerr
+	}
+	return err
+}
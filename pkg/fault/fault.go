@@ -0,0 +1,199 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package injects faults for resilience testing: timeouts, garbled
+// frames and slow responses in the transport and driver layers, and errors
+// from Core Data. The device service only enables it when built with the
+// "faults" tag, so production builds never inject faults; drivers wrap
+// their connections with Conn, which is a no-op unless enabled.
+package fault
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+var (
+	// ErrTimeout is returned by an injected timeout.
+	ErrTimeout = errors.New("injected fault: timeout")
+	// ErrCoreData is returned by an injected Core Data failure.
+	ErrCoreData = errors.New("injected fault: Core Data returned 500 Internal Server Error")
+)
+
+// Config sets the probabilities, between 0 and 1, of each fault.
+type Config struct {
+	// Timeout is the probability of an operation timing out after
+	// TimeoutDelay milliseconds.
+	Timeout      float64
+	TimeoutDelay int
+	// Garble is the probability of a byte of a received frame being
+	// corrupted, or of a read command failing in the driver layer.
+	Garble float64
+	// Slow is the probability of an operation being delayed by SlowDelay
+	// milliseconds.
+	Slow      float64
+	SlowDelay int
+	// CoreDataError is the probability of pushing an event failing.
+	CoreDataError float64
+	// Seed makes the faults reproducible, if not zero.
+	Seed int64
+}
+
+var (
+	mutex   sync.Mutex
+	enabled bool
+	config  Config
+	rnd     *rand.Rand
+)
+
+// Enable starts injecting faults.
+func Enable(cfg Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	config = cfg
+	rnd = rand.New(rand.NewSource(seed))
+	enabled = true
+}
+
+// Disable stops injecting faults.
+func Disable() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	enabled = false
+}
+
+// hit returns whether a fault of the given probability occurs, and the
+// current configuration.
+func hit(probability func(Config) float64) (bool, Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !enabled {
+		return false, config
+	}
+	p := probability(config)
+	return p > 0 && rnd.Float64() < p, config
+}
+
+// delay injects slow responses and timeouts.
+func delay() error {
+	if ok, cfg := hit(func(c Config) float64 { return c.Slow }); ok {
+		time.Sleep(time.Duration(cfg.SlowDelay) * time.Millisecond)
+	}
+	if ok, cfg := hit(func(c Config) float64 { return c.Timeout }); ok {
+		time.Sleep(time.Duration(cfg.TimeoutDelay) * time.Millisecond)
+		return ErrTimeout
+	}
+	return nil
+}
+
+type conn struct {
+	io.ReadWriteCloser
+}
+
+// Conn wraps a driver connection, e.g. a serial port or a TCP connection,
+// to inject faults into it.
+func Conn(c io.ReadWriteCloser) io.ReadWriteCloser {
+	return conn{c}
+}
+
+func (c conn) Read(b []byte) (int, error) {
+	if err := delay(); err != nil {
+		return 0, err
+	}
+	n, err := c.ReadWriteCloser.Read(b)
+	for i := 0; i < n; i++ {
+		if ok, _ := hit(func(c Config) float64 { return c.Garble }); ok {
+			b[i] ^= 0xff
+		}
+	}
+	return n, err
+}
+
+type driver struct {
+	ds_models.ProtocolDriver
+}
+
+// Driver wraps a driver to inject faults into its commands.
+func Driver(d ds_models.ProtocolDriver) ds_models.ProtocolDriver {
+	return driver{d}
+}
+
+func (d driver) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	if err := delay(); err != nil {
+		return nil, err
+	}
+	if ok, _ := hit(func(c Config) float64 { return c.Garble }); ok {
+		return nil, errors.New("injected fault: garbled response")
+	}
+	return d.ProtocolDriver.HandleReadCommands(addr, reqs)
+}
+
+func (d driver) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	if err := delay(); err != nil {
+		return err
+	}
+	return d.ProtocolDriver.HandleWriteCommands(addr, reqs, params)
+}
+
+type eventClient struct {
+	coredata.EventClient
+}
+
+// cborAdder is a Core Data client pushing CBOR encoded events.
+type cborAdder interface {
+	AddCBOR(body []byte) (string, error)
+}
+
+// cborEventClient is an eventClient which also pushes CBOR encoded events.
+type cborEventClient struct {
+	eventClient
+	cbor cborAdder
+}
+
+// EventClient wraps a Core Data client to inject failures into pushing
+// events, keeping its support of CBOR encoded events.
+func EventClient(c coredata.EventClient) coredata.EventClient {
+	if cc, ok := c.(cborAdder); ok {
+		return cborEventClient{eventClient{c}, cc}
+	}
+	return eventClient{c}
+}
+
+// coreDataError injects a failure of Core Data.
+func coreDataError() error {
+	if ok, _ := hit(func(c Config) float64 { return c.CoreDataError }); ok {
+		return ErrCoreData
+	}
+	return nil
+}
+
+func (c eventClient) Add(event *models.Event) (string, error) {
+	if err := coreDataError(); err != nil {
+		return "", err
+	}
+	return c.EventClient.Add(event)
+}
+
+func (c cborEventClient) AddCBOR(body []byte) (string, error) {
+	if err := coreDataError(); err != nil {
+		return "", err
+	}
+	return c.cbor.AddCBOR(body)
+}
@@ -0,0 +1,87 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fault
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients/coredata"
+)
+
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Close() error {
+	return nil
+}
+
+func TestConn(t *testing.T) {
+	b := &buffer{}
+	b.Write([]byte{0x01, 0x02})
+	c := Conn(b)
+	data, _ := ioutil.ReadAll(c)
+	if !bytes.Equal(data, []byte{0x01, 0x02}) {
+		t.Errorf("Faults injected while disabled: %x", data)
+	}
+
+	Enable(Config{Garble: 1})
+	defer Disable()
+	b.Write([]byte{0x01, 0x02})
+	data, _ = ioutil.ReadAll(c)
+	if !bytes.Equal(data, []byte{0xfe, 0xfd}) {
+		t.Errorf("Frame not garbled: %x", data)
+	}
+
+	Enable(Config{Timeout: 1, TimeoutDelay: 1})
+	if _, err := c.Read(make([]byte, 1)); err != ErrTimeout {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestEventClient(t *testing.T) {
+	Enable(Config{CoreDataError: 1, Seed: 1})
+	defer Disable()
+	c := EventClient(nil)
+	if _, err := c.Add(nil); err != ErrCoreData {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+type cborClient struct {
+	pushed int
+}
+
+func (c *cborClient) AddCBOR(body []byte) (string, error) {
+	c.pushed++
+	return "", nil
+}
+
+func TestCBOREventClient(t *testing.T) {
+	cc := &cborClient{}
+	c, ok := EventClient(struct {
+		coredata.EventClient
+		*cborClient
+	}{nil, cc}).(cborAdder)
+	if !ok {
+		t.Fatal("Support of CBOR encoded events lost")
+	}
+	if _, err := c.AddCBOR(nil); err != nil || cc.pushed != 1 {
+		t.Errorf("Pushed %d events while disabled: %v", cc.pushed, err)
+	}
+
+	Enable(Config{CoreDataError: 1, Seed: 1})
+	defer Disable()
+	if _, err := c.AddCBOR(nil); err != ErrCoreData || cc.pushed != 1 {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if _, ok = EventClient(nil).(cborAdder); ok {
+		t.Error("Support of CBOR encoded events added")
+	}
+}
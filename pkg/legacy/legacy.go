@@ -0,0 +1,49 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package allows drivers written against the older, single entry point
+// driver API to run as a ProtocolDriver while being ported.
+package legacy
+
+import (
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Driver is the older driver API, where read and write commands are both
+// passed to HandleCommands, with nil params for reads.
+type Driver interface {
+	DisconnectDevice(address *models.Addressable) error
+	Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error
+	HandleCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) ([]*ds_models.CommandValue, error)
+	Stop(force bool) error
+}
+
+// adapter implements ProtocolDriver for a Driver.
+type adapter struct {
+	Driver
+}
+
+// NewAdapter returns a ProtocolDriver running the given older driver.
+func NewAdapter(d Driver) ds_models.ProtocolDriver {
+	return adapter{d}
+}
+
+func (a adapter) HandleReadCommands(addr *models.Addressable, reqs []ds_models.CommandRequest) ([]*ds_models.CommandValue, error) {
+	return a.HandleCommands(addr, reqs, nil)
+}
+
+func (a adapter) HandleWriteCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) error {
+	_, err := a.HandleCommands(addr, reqs, params)
+	return err
+}
+
+// APIVersion reports the API version of the older drivers, so that they run
+// in compatibility mode.
+func (a adapter) APIVersion() string {
+	return "1.0"
+}
@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package legacy
+
+import (
+	"testing"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+type oldDriver struct {
+	written string
+}
+
+func (d *oldDriver) DisconnectDevice(address *models.Addressable) error {
+	return nil
+}
+
+func (d *oldDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *ds_models.AsyncValues) error {
+	return nil
+}
+
+func (d *oldDriver) HandleCommands(addr *models.Addressable, reqs []ds_models.CommandRequest, params []*ds_models.CommandValue) ([]*ds_models.CommandValue, error) {
+	if params != nil {
+		var err error
+		d.written, err = params[0].StringValue()
+		return nil, err
+	}
+	return []*ds_models.CommandValue{ds_models.NewStringValue(&reqs[0].RO, 0, "read")}, nil
+}
+
+func (d *oldDriver) Stop(force bool) error {
+	return nil
+}
+
+func TestAdapter(t *testing.T) {
+	d := &oldDriver{}
+	p := NewAdapter(d)
+	addr := &models.Addressable{Address: "meter01"}
+	reqs := []ds_models.CommandRequest{{RO: models.ResourceOperation{Object: "Mode"}}}
+
+	cvs, err := p.HandleReadCommands(addr, reqs)
+	if err != nil || len(cvs) != 1 {
+		t.Fatalf("Unexpected read result %v, %v", cvs, err)
+	}
+	if s, _ := cvs[0].StringValue(); s != "read" {
+		t.Errorf("Unexpected value %s", s)
+	}
+
+	param := ds_models.NewStringValue(&reqs[0].RO, 0, "write")
+	if err = p.HandleWriteCommands(addr, reqs, []*ds_models.CommandValue{param}); err != nil || d.written != "write" {
+		t.Errorf("Unexpected write result %q, %v", d.written, err)
+	}
+
+	if compat, err := ds_models.CheckAPIVersion(p.(ds_models.VersionedDriver).APIVersion()); !compat || err != nil {
+		t.Errorf("Not run in compatibility mode: %v", err)
+	}
+}
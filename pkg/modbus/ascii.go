@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	asciiStart = ':'
+	asciiEnd   = "\r\n"
+	// maxASCIIFrame is the maximum length of an ASCII frame: the start
+	// character, 255 bytes of address, PDU and LRC in hex, and the end.
+	maxASCIIFrame = 1 + 2*255 + 2
+)
+
+// ErrLRC is returned for an ASCII response with an invalid LRC.
+var ErrLRC = errors.New("modbus: invalid LRC")
+
+// LRC returns the longitudinal redundancy check of an ASCII frame: the
+// two's complement of the sum of its bytes.
+func LRC(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+// EncodeASCII returns the ASCII frame of a PDU for the given unit: the
+// unit, PDU and LRC in uppercase hex, between ':' and CR LF.
+func EncodeASCII(unit byte, pdu []byte) []byte {
+	adu := append([]byte{unit}, pdu...)
+	adu = append(adu, LRC(adu))
+	frame := make([]byte, 0, 1+2*len(adu)+len(asciiEnd))
+	frame = append(frame, asciiStart)
+	frame = append(frame, strings.ToUpper(hex.EncodeToString(adu))...)
+	return append(frame, asciiEnd...)
+}
+
+// DecodeASCII checks the LRC of an ASCII frame and returns its unit and
+// PDU.
+func DecodeASCII(frame []byte) (byte, []byte, error) {
+	if len(frame) < 1+2*3+len(asciiEnd) || frame[0] != asciiStart || !bytes.HasSuffix(frame, []byte(asciiEnd)) {
+		return 0, nil, ErrResponse
+	}
+	adu := make([]byte, hex.DecodedLen(len(frame)-1-len(asciiEnd)))
+	if _, err := hex.Decode(adu, frame[1:len(frame)-len(asciiEnd)]); err != nil {
+		return 0, nil, ErrResponse
+	}
+	n := len(adu) - 1
+	if LRC(adu[:n]) != adu[n] {
+		return 0, nil, ErrLRC
+	}
+	return adu[0], adu[1:n], nil
+}
+
+// readASCIIFrame reads an ASCII frame, skipping any noise before its start
+// character, up to its end.
+func readASCIIFrame(r io.Reader) ([]byte, error) {
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		if b[0] == asciiStart {
+			break
+		}
+	}
+
+	frame := make([]byte, 1, 64)
+	frame[0] = asciiStart
+	for !bytes.HasSuffix(frame, []byte(asciiEnd)) {
+		if len(frame) >= maxASCIIFrame {
+			return nil, ErrResponse
+		}
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		frame = append(frame, b[0])
+	}
+	return frame, nil
+}
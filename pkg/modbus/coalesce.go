@@ -0,0 +1,135 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxReadQuantity is the maximum number of registers of a read request.
+const MaxReadQuantity = 125
+
+// Range is a range of registers of a table, read with Function, i.e.
+// ReadHoldingRegisters or ReadInputRegisters. A non-zero Timeout overrides
+// the response timeout of the client, e.g. for slow EEPROM-backed
+// registers.
+type Range struct {
+	Function byte
+	Address  uint16
+	Quantity uint16
+	Timeout  time.Duration
+}
+
+// Batch is a single read covering several ranges, given by their indices,
+// with the longest of their timeouts.
+type Batch struct {
+	Range
+	Indices []int
+}
+
+// end returns the address following the range.
+func (r Range) end() int {
+	return int(r.Address) + int(r.Quantity)
+}
+
+// Coalesce merges the adjacent or overlapping ranges of each table into
+// batches of at most MaxReadQuantity registers. Ranges separated by up to
+// maxGap registers are merged too, reading the registers between them,
+// which some devices reject if unmapped.
+func Coalesce(ranges []Range, maxGap int) []Batch {
+	order := make([]int, len(ranges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := ranges[order[i]], ranges[order[j]]
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		return a.Address < b.Address
+	})
+
+	var batches []Batch
+	for _, i := range order {
+		r := ranges[i]
+		if n := len(batches); n > 0 {
+			b := &batches[n-1]
+			end := b.end()
+			if r.end() > end {
+				end = r.end()
+			}
+			if b.Function == r.Function && int(r.Address) <= b.end()+maxGap && end-int(b.Address) <= MaxReadQuantity {
+				b.Quantity = uint16(end - int(b.Address))
+				if r.Timeout > b.Timeout {
+					b.Timeout = r.Timeout
+				}
+				b.Indices = append(b.Indices, i)
+				continue
+			}
+		}
+		batches = append(batches, Batch{Range: r, Indices: []int{i}})
+	}
+	return batches
+}
+
+// rangeReader is a client reading registers.
+type rangeReader interface {
+	read(unit byte, pdu []byte, timeout time.Duration) ([]byte, error)
+}
+
+// ReadRegisters reads a range of registers, within its Timeout if set.
+func (c *RTUClient) ReadRegisters(unit byte, r Range) ([]byte, error) {
+	return readRange(c, unit, r)
+}
+
+// ReadRegisters reads a range of registers, within its Timeout if set.
+func (c *TCPClient) ReadRegisters(unit byte, r Range) ([]byte, error) {
+	return readRange(c, unit, r)
+}
+
+// ReadRanges reads the given ranges of registers in as few requests as
+// possible, see Coalesce, and returns the values of each range.
+func (c *RTUClient) ReadRanges(unit byte, ranges []Range, maxGap int) ([][]byte, error) {
+	return readRanges(c, unit, ranges, maxGap)
+}
+
+// ReadRanges reads the given ranges of registers in as few requests as
+// possible, see Coalesce, and returns the values of each range.
+func (c *TCPClient) ReadRanges(unit byte, ranges []Range, maxGap int) ([][]byte, error) {
+	return readRanges(c, unit, ranges, maxGap)
+}
+
+func readRange(c rangeReader, unit byte, r Range) ([]byte, error) {
+	if r.Function != ReadHoldingRegisters && r.Function != ReadInputRegisters {
+		return nil, fmt.Errorf("modbus: unsupported read function 0x%02x", r.Function)
+	}
+	data, err := c.read(unit, readRequest(r.Function, r.Address, r.Quantity), r.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 2*int(r.Quantity) {
+		return nil, ErrResponse
+	}
+	return data, nil
+}
+
+func readRanges(c rangeReader, unit byte, ranges []Range, maxGap int) ([][]byte, error) {
+	values := make([][]byte, len(ranges))
+	for _, b := range Coalesce(ranges, maxGap) {
+		data, err := readRange(c, unit, b.Range)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range b.Indices {
+			offset := 2 * int(ranges[i].Address-b.Address)
+			values[i] = data[offset : offset+2*int(ranges[i].Quantity)]
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/hex"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	ranges := []Range{
+		{ReadHoldingRegisters, 10, 2, 0},
+		{ReadInputRegisters, 0, 1, 0},
+		{ReadHoldingRegisters, 0, 2, 0},
+		{ReadHoldingRegisters, 2, 2, 0},
+		{ReadHoldingRegisters, 3, 1, 0},
+		{ReadHoldingRegisters, 200, 2, 0},
+	}
+	batches := Coalesce(ranges, 0)
+	expected := []Batch{
+		{Range{ReadHoldingRegisters, 0, 4, 0}, []int{2, 3, 4}},
+		{Range{ReadHoldingRegisters, 10, 2, 0}, []int{0}},
+		{Range{ReadHoldingRegisters, 200, 2, 0}, []int{5}},
+		{Range{ReadInputRegisters, 0, 1, 0}, []int{1}},
+	}
+	if !reflect.DeepEqual(batches, expected) {
+		t.Errorf("Unexpected batches %v", batches)
+	}
+
+	// the gap between 4 and 10 is read, but not up to 200
+	if batches = Coalesce(ranges, 6); len(batches) != 3 || batches[0].Quantity != 12 {
+		t.Errorf("Unexpected batches with gap %v", batches)
+	}
+
+	// a batch doesn't exceed the maximum quantity
+	if batches = Coalesce([]Range{{ReadHoldingRegisters, 0, 100, 0}, {ReadHoldingRegisters, 100, 100, 0}}, 0); len(batches) != 2 {
+		t.Errorf("Unexpected large batches %v", batches)
+	}
+}
+
+func TestReadRanges(t *testing.T) {
+	p := player("010300000003"+"05cb", hex.EncodeToString(EncodeRTU(1, []byte{0x03, 0x06, 0x00, 0x01, 0x00, 0x02, 0x00, 0x03})))
+	c := &RTUClient{Port: p}
+	values, err := c.ReadRanges(1, []Range{{ReadHoldingRegisters, 2, 1, 0}, {ReadHoldingRegisters, 0, 2, 0}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(values[0]) != "0003" || hex.EncodeToString(values[1]) != "00010002" {
+		t.Errorf("Unexpected values %x", values)
+	}
+}
+
+func TestBatchTimeout(t *testing.T) {
+	batches := Coalesce([]Range{
+		{ReadHoldingRegisters, 0, 2, 0},
+		{ReadHoldingRegisters, 2, 2, 2 * time.Second},
+	}, 0)
+	if len(batches) != 1 || batches[0].Timeout != 2*time.Second {
+		t.Errorf("Expected the longest timeout, got %v", batches)
+	}
+}
+
+func TestReadRegistersTimeout(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	c := &TCPClient{Conn: client, Timeout: time.Hour}
+
+	start := time.Now()
+	_, err := c.ReadRegisters(1, Range{ReadHoldingRegisters, 0, 1, 10 * time.Millisecond})
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected the timeout of the range to apply")
+	}
+}
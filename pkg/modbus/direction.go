@@ -0,0 +1,93 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DirectionControl switches a half-duplex RS-485 transceiver between
+// transmitting and receiving, for transceivers which don't switch
+// automatically.
+type DirectionControl interface {
+	SetTransmit(transmit bool) error
+}
+
+// gpioControl drives the driver enable pin of a transceiver through a GPIO
+// line of the sysfs interface.
+type gpioControl struct {
+	value      *os.File
+	activeHigh bool
+}
+
+// gpioRoot is the sysfs GPIO directory, replaced by tests.
+var gpioRoot = "/sys/class/gpio"
+
+// NewGPIOControl returns a DirectionControl driving the given GPIO line,
+// which is high while transmitting if activeHigh. The line is exported and
+// configured as an output if needed.
+func NewGPIOControl(line int, activeHigh bool) (DirectionControl, error) {
+	dir := filepath.Join(gpioRoot, "gpio"+strconv.Itoa(line))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err = ioutil.WriteFile(filepath.Join(gpioRoot, "export"), []byte(strconv.Itoa(line)), 0); err != nil {
+			return nil, fmt.Errorf("modbus: couldn't export GPIO %d: %v", line, err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "direction"), []byte("out"), 0); err != nil {
+		return nil, fmt.Errorf("modbus: couldn't configure GPIO %d: %v", line, err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "value"), os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	g := &gpioControl{value: f, activeHigh: activeHigh}
+	return g, g.SetTransmit(false)
+}
+
+func (g *gpioControl) SetTransmit(transmit bool) error {
+	value := "0"
+	if transmit == g.activeHigh {
+		value = "1"
+	}
+	_, err := g.value.WriteAt([]byte(value), 0)
+	return err
+}
+
+// frameTime returns the transmission time of n bytes at the given baud
+// rate, a character being 11 bits, or zero if unknown.
+func frameTime(n int, baudRate int) time.Duration {
+	if baudRate <= 0 {
+		return 0
+	}
+	return time.Duration(int64(time.Second) * int64(n) * 11 / int64(baudRate))
+}
+
+// transmit writes a frame, switching the transceiver to transmit for its
+// duration if direction control is configured.
+func (c *RTUClient) transmit(adu []byte) error {
+	if c.Direction == nil {
+		_, err := c.Port.Write(adu)
+		return err
+	}
+
+	if err := c.Direction.SetTransmit(true); err != nil {
+		return err
+	}
+	time.Sleep(c.PreDelay)
+	_, err := c.Port.Write(adu)
+	// the write returns once the frame is buffered, not sent
+	time.Sleep(frameTime(len(adu), c.BaudRate) + c.PostDelay)
+	if derr := c.Direction.SetTransmit(false); err == nil {
+		err = derr
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// rtsControl drives the driver enable pin of a transceiver through the RTS
+// line of the serial port.
+type rtsControl struct {
+	port       *os.File
+	activeHigh bool
+}
+
+// NewRTSControl returns a DirectionControl toggling the RTS line of the
+// given serial port, which is asserted while transmitting if activeHigh.
+func NewRTSControl(port *os.File, activeHigh bool) (DirectionControl, error) {
+	r := &rtsControl{port: port, activeHigh: activeHigh}
+	return r, r.SetTransmit(false)
+}
+
+func (r *rtsControl) SetTransmit(transmit bool) error {
+	req := syscall.TIOCMBIC
+	if transmit == r.activeHigh {
+		req = syscall.TIOCMBIS
+	}
+	bits := syscall.TIOCM_RTS
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, r.port.Fd(), uintptr(req), uintptr(unsafe.Pointer(&bits))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type recordingControl struct {
+	states []bool
+}
+
+func (r *recordingControl) SetTransmit(transmit bool) error {
+	r.states = append(r.states, transmit)
+	return nil
+}
+
+func TestDirectionControl(t *testing.T) {
+	p := player("010300000002c40b", hex.EncodeToString(EncodeRTU(1, []byte{0x03, 0x04, 0x00, 0xe6, 0x00, 0x0a})))
+	r := &recordingControl{}
+	c := &RTUClient{Port: p, Direction: r, BaudRate: 19200}
+	if _, err := c.ReadHoldingRegisters(1, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.states) != 2 || !r.states[0] || r.states[1] {
+		t.Errorf("Unexpected direction switches %v", r.states)
+	}
+}
+
+func TestGPIOControl(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "gpio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	gpioRoot = tmp
+	defer func() { gpioRoot = "/sys/class/gpio" }()
+
+	dir := filepath.Join(tmp, "gpio17")
+	os.Mkdir(dir, 0755)
+	ioutil.WriteFile(filepath.Join(dir, "value"), []byte("0"), 0644)
+	g, err := NewGPIOControl(17, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := ioutil.ReadFile(filepath.Join(dir, "value")); string(value) != "1" {
+		t.Errorf("Active low line not high while receiving: %s", value)
+	}
+	g.SetTransmit(true)
+	if value, _ := ioutil.ReadFile(filepath.Join(dir, "value")); string(value) != "0" {
+		t.Errorf("Active low line not low while transmitting: %s", value)
+	}
+}
@@ -0,0 +1,136 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package is a Modbus client for drivers, which frames the requests
+// and responses and handles the bus timing, leaving the mapping of device
+// resources to registers, e.g. through pkg/codec, to the driver.
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Function codes
+const (
+	ReadCoils              byte = 0x01
+	ReadDiscreteInputs     byte = 0x02
+	ReadHoldingRegisters   byte = 0x03
+	ReadInputRegisters     byte = 0x04
+	WriteSingleCoil        byte = 0x05
+	WriteSingleRegister    byte = 0x06
+	WriteMultipleCoils     byte = 0x0F
+	WriteMultipleRegisters byte = 0x10
+)
+
+// BroadcastUnitID addresses all the devices of a serial bus, none of them
+// responding.
+const BroadcastUnitID byte = 0
+
+const exceptionFlag byte = 0x80
+
+var (
+	// ErrCRC is returned for a response with an invalid CRC.
+	ErrCRC = errors.New("modbus: invalid CRC")
+	// ErrResponse is returned for a response not matching the request.
+	ErrResponse = errors.New("modbus: unexpected response")
+)
+
+// Exception is an exception response of a device.
+type Exception struct {
+	Function byte
+	Code     byte
+}
+
+func (e Exception) Error() string {
+	return fmt.Sprintf("modbus: exception %d for function 0x%02x", e.Code, e.Function)
+}
+
+// CRC16 returns the CRC of an RTU frame.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// EncodeRTU returns the RTU frame of a PDU for the given unit.
+func EncodeRTU(unit byte, pdu []byte) []byte {
+	adu := append([]byte{unit}, pdu...)
+	crc := CRC16(adu)
+	return append(adu, byte(crc), byte(crc>>8))
+}
+
+// DecodeRTU checks the CRC of an RTU frame and returns its unit and PDU.
+func DecodeRTU(adu []byte) (byte, []byte, error) {
+	if len(adu) < 4 {
+		return 0, nil, ErrResponse
+	}
+	n := len(adu) - 2
+	if CRC16(adu[:n]) != binary.LittleEndian.Uint16(adu[n:]) {
+		return 0, nil, ErrCRC
+	}
+	return adu[0], adu[1:n], nil
+}
+
+// readRequest returns the PDU of a read request.
+func readRequest(function byte, address uint16, quantity uint16) []byte {
+	pdu := []byte{function, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(pdu[1:], address)
+	binary.BigEndian.PutUint16(pdu[3:], quantity)
+	return pdu
+}
+
+// writeSingleRequest returns the PDU of a WriteSingleRegister request.
+func writeSingleRequest(address uint16, value uint16) []byte {
+	pdu := []byte{WriteSingleRegister, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(pdu[1:], address)
+	binary.BigEndian.PutUint16(pdu[3:], value)
+	return pdu
+}
+
+// writeMultipleRequest returns the PDU of a WriteMultipleRegisters request.
+func writeMultipleRequest(address uint16, values []byte) ([]byte, error) {
+	if len(values) == 0 || len(values)%2 != 0 || len(values) > 246 {
+		return nil, fmt.Errorf("modbus: invalid register values length %d", len(values))
+	}
+	pdu := []byte{WriteMultipleRegisters, 0, 0, 0, 0, byte(len(values))}
+	binary.BigEndian.PutUint16(pdu[1:], address)
+	binary.BigEndian.PutUint16(pdu[3:], uint16(len(values)/2))
+	return append(pdu, values...), nil
+}
+
+// readData returns the register values of a read response PDU.
+func readData(response []byte) ([]byte, error) {
+	if len(response) < 2 || int(response[1]) != len(response)-2 {
+		return nil, ErrResponse
+	}
+	return response[2:], nil
+}
+
+// checkResponse returns the exception of a response PDU, or an error if it
+// doesn't match the request.
+func checkResponse(request []byte, response []byte) error {
+	if len(response) == 0 {
+		return ErrResponse
+	}
+	if response[0] == request[0]|exceptionFlag && len(response) == 2 {
+		return Exception{Function: request[0], Code: response[1]}
+	}
+	if response[0] != request[0] {
+		return ErrResponse
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/reconnect"
+	"github.com/edgexfoundry/device-sdk-go/pkg/retry"
+)
+
+// Driver settings of the TCP connection pool, in milliseconds for the
+// durations.
+const (
+	SettingPoolSize        = "PoolSize"
+	SettingPoolIdleTimeout = "PoolIdleTimeout"
+	SettingDialTimeout     = "DialTimeout"
+)
+
+// DefaultPoolConfig keeps a single connection per server, closed after a
+// minute of inactivity.
+var DefaultPoolConfig = PoolConfig{
+	Size:        1,
+	IdleTimeout: time.Minute,
+	DialTimeout: 5 * time.Second,
+	Reconnect:   reconnect.DefaultPolicy,
+}
+
+// ErrPoolClosed is returned by Pool.Do once the pool is closed.
+var ErrPoolClosed = errors.New("modbus: connection pool closed")
+
+// healthCheckTimeout is how long a health check waits for the connection to
+// report being closed by the server.
+const healthCheckTimeout = time.Millisecond
+
+// PoolConfig configures a Pool.
+type PoolConfig struct {
+	// Size is the maximum number of idle connections kept per server.
+	Size int
+	// IdleTimeout is the inactivity after which idle connections are
+	// closed, or zero to keep them open.
+	IdleTimeout time.Duration
+	// DialTimeout bounds the establishment of the connections.
+	DialTimeout time.Duration
+	// Timeout is the response timeout of the clients, see TCPClient.
+	Timeout time.Duration
+	// Retry is the policy retrying the failed transactions, on a new
+	// connection, but not the exception responses.
+	Retry retry.Policy
+	// Reconnect is the policy pacing the connections to a server after
+	// failures, unless overridden for the server by PeerReconnect.
+	Reconnect     reconnect.Policy
+	PeerReconnect map[string]reconnect.Policy
+	// Protected are the registers never written, by server address and
+	// unit, and ProtectedAllUnits those never written on any server and
+	// unit, see Protection.
+	Protected         map[string]map[byte]Protection
+	ProtectedAllUnits Protection
+}
+
+// PoolConfigFromSettings returns base overridden by the pool, retry and
+// protection settings of the Driver configuration section, durations being
+// in milliseconds. See the retry and reconnect packages for their settings.
+func PoolConfigFromSettings(base PoolConfig, settings map[string]string) (PoolConfig, error) {
+	cfg := base
+	for name, v := range settings {
+		v = strings.TrimSpace(v)
+		var n int
+		var err error
+		switch name {
+		case SettingPoolSize, SettingPoolIdleTimeout, SettingDialTimeout:
+			n, err = strconv.Atoi(v)
+			if err == nil && n < 0 {
+				err = fmt.Errorf("negative value")
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return base, fmt.Errorf("invalid pool setting %s %q: %v", name, v, err)
+		}
+		switch name {
+		case SettingPoolSize:
+			cfg.Size = n
+		case SettingPoolIdleTimeout:
+			cfg.IdleTimeout = time.Duration(n) * time.Millisecond
+		case SettingDialTimeout:
+			cfg.DialTimeout = time.Duration(n) * time.Millisecond
+		}
+	}
+
+	policy, err := retry.Parse(base.Retry, settings)
+	if err != nil {
+		return base, err
+	}
+	cfg.Retry = policy
+	cfg.Reconnect, err = reconnect.Parse(base.Reconnect, settings)
+	if err != nil {
+		return base, err
+	}
+	if _, ok := settings[ProtectedRangesSetting]; ok {
+		if cfg.ProtectedAllUnits, err = ProtectionFromSettings(settings); err != nil {
+			return base, err
+		}
+	}
+	return cfg, nil
+}
+
+// Pool keeps Modbus TCP connections open between commands, reconnecting
+// only on errors.
+type Pool struct {
+	config PoolConfig
+	dial   func(address string, timeout time.Duration) (net.Conn, error)
+
+	mutex  sync.Mutex
+	idle   map[string][]*pooledClient
+	peers  map[string]*reconnect.Peer
+	closed bool
+	stop   chan struct{}
+}
+
+type pooledClient struct {
+	*TCPClient
+	lastUsed time.Time
+}
+
+// NewPool returns a Pool with the given configuration, closing the idle
+// connections after IdleTimeout until closed.
+func NewPool(config PoolConfig) *Pool {
+	if config.Size < 1 {
+		config.Size = 1
+	}
+	p := &Pool{
+		config: config,
+		dial: func(address string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("tcp", address, timeout)
+		},
+		idle:  make(map[string][]*pooledClient),
+		peers: make(map[string]*reconnect.Peer),
+		stop:  make(chan struct{}),
+	}
+	if config.IdleTimeout > 0 {
+		go p.expire()
+	}
+	return p
+}
+
+// Do calls op with a client connected to address, e.g. "host:502",
+// retrying according to the Retry policy. The connection returns to the
+// pool unless op fails with an error other than an Exception.
+func (p *Pool) Do(address string, op func(c *TCPClient) error) error {
+	return p.config.Retry.Do(func() error {
+		c, err := p.get(address)
+		if err != nil {
+			return err
+		}
+		err = op(c.TCPClient)
+		if _, ok := err.(Exception); ok {
+			// the device answered, retrying won't change its mind
+			p.put(address, c)
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			c.Conn.Close()
+			p.peer(address).Disconnected(err)
+			return err
+		}
+		p.put(address, c)
+		return nil
+	})
+}
+
+// Close closes the pool and its idle connections. Connections in use are
+// closed when released.
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	close(p.stop)
+	for address, clients := range p.idle {
+		for _, c := range clients {
+			c.Conn.Close()
+		}
+		delete(p.idle, address)
+	}
+}
+
+// Idle returns the number of idle connections to address.
+func (p *Pool) Idle(address string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.idle[address])
+}
+
+// Peers returns the state of the connections to the servers, implementing
+// the PeerReporter interface of drivers.
+func (p *Pool) Peers() []ds_models.PeerStatus {
+	p.mutex.Lock()
+	peers := make([]*reconnect.Peer, 0, len(p.peers))
+	for _, peer := range p.peers {
+		peers = append(peers, peer)
+	}
+	p.mutex.Unlock()
+
+	now := time.Now()
+	statuses := make([]ds_models.PeerStatus, 0, len(peers))
+	for _, peer := range peers {
+		statuses = append(statuses, peer.Status(now))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Address < statuses[j].Address })
+	return statuses
+}
+
+// peer returns the connection state of a server.
+func (p *Pool) peer(address string) *reconnect.Peer {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	peer, ok := p.peers[address]
+	if !ok {
+		policy, ok := p.config.PeerReconnect[address]
+		if !ok {
+			policy = p.config.Reconnect
+		}
+		peer = reconnect.NewPeer(address, policy)
+		p.peers[address] = peer
+	}
+	return peer
+}
+
+// get returns a healthy idle connection to address, most recently used
+// first, or a new one.
+func (p *Pool) get(address string) (*pooledClient, error) {
+	for {
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			return nil, retry.Permanent(ErrPoolClosed)
+		}
+		clients := p.idle[address]
+		if len(clients) == 0 {
+			p.mutex.Unlock()
+			break
+		}
+		c := clients[len(clients)-1]
+		p.idle[address] = clients[:len(clients)-1]
+		p.mutex.Unlock()
+
+		if healthy(c.Conn) {
+			return c, nil
+		}
+		c.Conn.Close()
+		p.peer(address).Disconnected(nil)
+	}
+
+	peer := p.peer(address)
+	if err := peer.Allow(time.Now()); err != nil {
+		// retrying won't be allowed before the next attempt
+		return nil, retry.Permanent(err)
+	}
+	conn, err := p.dial(address, p.config.DialTimeout)
+	if err != nil {
+		peer.Failed(time.Now(), err)
+		return nil, err
+	}
+	peer.Connected()
+	return &pooledClient{TCPClient: &TCPClient{Conn: conn, Timeout: p.config.Timeout, Protected: p.config.Protected[address], ProtectedAllUnits: p.config.ProtectedAllUnits}}, nil
+}
+
+// put returns a connection to the pool, or closes it if the pool is full
+// or closed.
+func (p *Pool) put(address string, c *pooledClient) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed || len(p.idle[address]) >= p.config.Size {
+		c.Conn.Close()
+		return
+	}
+	c.lastUsed = time.Now()
+	p.idle[address] = append(p.idle[address], c)
+}
+
+// expire closes the connections idle for longer than IdleTimeout.
+func (p *Pool) expire() {
+	ticker := time.NewTicker(p.config.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.mutex.Lock()
+			for address, clients := range p.idle {
+				kept := clients[:0]
+				for _, c := range clients {
+					if now.Sub(c.lastUsed) >= p.config.IdleTimeout {
+						c.Conn.Close()
+					} else {
+						kept = append(kept, c)
+					}
+				}
+				if len(kept) == 0 {
+					delete(p.idle, address)
+				} else {
+					p.idle[address] = kept
+				}
+			}
+			p.mutex.Unlock()
+		}
+	}
+}
+
+// healthy checks that an idle connection wasn't closed by the server, and
+// has no unsolicited data pending.
+func healthy(conn net.Conn) bool {
+	conn.SetReadDeadline(time.Now().Add(healthCheckTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+	var b [1]byte
+	_, err := conn.Read(b[:])
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/reconnect"
+	"github.com/edgexfoundry/device-sdk-go/pkg/retry"
+)
+
+// server is a Modbus TCP server whose holding registers all hold 0x0102,
+// closing each connection after maxRequests requests if set.
+type server struct {
+	listener    net.Listener
+	accepted    int32
+	maxRequests int
+}
+
+func newServer(t *testing.T, maxRequests int) *server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{listener: l, maxRequests: maxRequests}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&s.accepted, 1)
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *server) serve(conn net.Conn) {
+	defer conn.Close()
+	for n := 0; s.maxRequests == 0 || n < s.maxRequests; n++ {
+		header := make([]byte, mbapHeaderLength)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		pdu := make([]byte, binary.BigEndian.Uint16(header[4:])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+		quantity := binary.BigEndian.Uint16(pdu[3:])
+		response := []byte{pdu[0], byte(2 * quantity)}
+		for i := uint16(0); i < quantity; i++ {
+			response = append(response, 0x01, 0x02)
+		}
+		binary.BigEndian.PutUint16(header[4:], uint16(len(response)+1))
+		conn.Write(append(header, response...))
+	}
+}
+
+func (s *server) Accepted() int {
+	return int(atomic.LoadInt32(&s.accepted))
+}
+
+func readTwo(p *Pool, address string) error {
+	return p.Do(address, func(c *TCPClient) error {
+		values, err := c.ReadHoldingRegisters(1, 0, 2)
+		if err == nil && hex.EncodeToString(values) != "01020102" {
+			err = ErrResponse
+		}
+		return err
+	})
+}
+
+func TestPoolReuse(t *testing.T) {
+	s := newServer(t, 0)
+	defer s.listener.Close()
+	p := NewPool(DefaultPoolConfig)
+	defer p.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := readTwo(p, s.listener.Addr().String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Accepted() != 1 {
+		t.Errorf("Expected a single connection, got %d", s.Accepted())
+	}
+}
+
+func TestPoolReconnect(t *testing.T) {
+	s := newServer(t, 1)
+	defer s.listener.Close()
+	p := NewPool(DefaultPoolConfig)
+	defer p.Close()
+
+	address := s.listener.Addr().String()
+	for i := 0; i < 2; i++ {
+		if err := readTwo(p, address); err != nil {
+			t.Fatal(err)
+		}
+		// let the server close the connection
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s.Accepted() != 2 {
+		t.Errorf("Expected 2 connections, got %d", s.Accepted())
+	}
+}
+
+func TestPoolIdleTimeout(t *testing.T) {
+	s := newServer(t, 0)
+	defer s.listener.Close()
+	cfg := DefaultPoolConfig
+	cfg.IdleTimeout = 20 * time.Millisecond
+	p := NewPool(cfg)
+	defer p.Close()
+
+	address := s.listener.Addr().String()
+	if err := readTwo(p, address); err != nil {
+		t.Fatal(err)
+	}
+	if p.Idle(address) != 1 {
+		t.Fatalf("Expected an idle connection")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if p.Idle(address) != 0 {
+		t.Errorf("Expected the idle connection to be closed")
+	}
+}
+
+func TestPoolConfigFromSettings(t *testing.T) {
+	cfg, err := PoolConfigFromSettings(DefaultPoolConfig, map[string]string{
+		SettingPoolSize:         "4",
+		SettingPoolIdleTimeout:  "30000",
+		retry.SettingMaxRetries: "3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Size != 4 || cfg.IdleTimeout != 30*time.Second || cfg.DialTimeout != DefaultPoolConfig.DialTimeout || cfg.Retry.MaxRetries != 3 {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	if _, err := PoolConfigFromSettings(DefaultPoolConfig, map[string]string{SettingPoolSize: "x"}); err == nil {
+		t.Errorf("Expected an error for an invalid size")
+	}
+
+	cfg, err = PoolConfigFromSettings(DefaultPoolConfig, map[string]string{ProtectedRangesSetting: "9000-9099, 9500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.ProtectedAllUnits) != 2 || cfg.ProtectedAllUnits[1] != (RegisterSpan{First: 9500, Last: 9500}) {
+		t.Errorf("Unexpected protection %v", cfg.ProtectedAllUnits)
+	}
+	if _, err := PoolConfigFromSettings(DefaultPoolConfig, map[string]string{ProtectedRangesSetting: "9100-9000"}); err == nil {
+		t.Errorf("Expected an error for invalid protected registers")
+	}
+}
+
+func TestPoolReconnectBackoff(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	cfg := DefaultPoolConfig
+	cfg.Reconnect = reconnect.Policy{Strategy: reconnect.Backoff, Delay: time.Minute}
+	p := NewPool(cfg)
+	defer p.Close()
+
+	if err := readTwo(p, address); err == nil {
+		t.Fatal("Expected the connection to fail")
+	}
+	if _, ok := readTwo(p, address).(reconnect.NotAllowedError); !ok {
+		t.Errorf("Expected the reconnection to be delayed")
+	}
+	peers := p.Peers()
+	if len(peers) != 1 || peers[0].State != ds_models.PeerWaiting || peers[0].Failures != 1 {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+}
@@ -0,0 +1,109 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProtectedRangesSetting is the Driver setting listing the holding registers
+// protected on all the units, e.g. "9000-9099,9500", see ParseProtection and
+// ProtectionFromSettings.
+const ProtectedRangesSetting = "ProtectedRanges"
+
+// Protection lists the holding registers of a device never written, e.g.
+// its factory or calibration registers, whatever the device profile says.
+type Protection []RegisterSpan
+
+// RegisterSpan is a span of registers, from First to Last included.
+type RegisterSpan struct {
+	First uint16
+	Last  uint16
+}
+
+// ProtectedRegisterError is returned for a write to a protected register.
+type ProtectedRegisterError struct {
+	Unit    byte
+	Address uint16
+}
+
+func (e ProtectedRegisterError) Error() string {
+	return fmt.Sprintf("modbus: register %d of unit %d is write-protected", e.Address, e.Unit)
+}
+
+// ParseProtection parses a comma separated list of register addresses and
+// inclusive address spans, e.g. "9000-9099,9500".
+func ParseProtection(s string) (Protection, error) {
+	var p Protection
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.ParseUint(strings.TrimSpace(bounds[0]), 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("modbus: invalid protected registers %q: %v", item, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(strings.TrimSpace(bounds[1]), 0, 16); err != nil {
+				return nil, fmt.Errorf("modbus: invalid protected registers %q: %v", item, err)
+			}
+		}
+		if last < first {
+			return nil, fmt.Errorf("modbus: invalid protected registers %q: empty span", item)
+		}
+		p = append(p, RegisterSpan{First: uint16(first), Last: uint16(last)})
+	}
+	return p, nil
+}
+
+// ProtectionFromSettings returns the Protection given by the
+// ProtectedRangesSetting of the Driver configuration section, if any.
+func ProtectionFromSettings(settings map[string]string) (Protection, error) {
+	v, ok := settings[ProtectedRangesSetting]
+	if !ok {
+		return nil, nil
+	}
+	return ParseProtection(v)
+}
+
+// Check returns the error of a write to quantity registers from address
+// overlapping a protected span.
+func (p Protection) Check(unit byte, address uint16, quantity int) error {
+	last := int(address) + quantity - 1
+	for _, s := range p {
+		if int(s.First) <= last && int(s.Last) >= int(address) {
+			overlap := address
+			if s.First > overlap {
+				overlap = s.First
+			}
+			return ProtectedRegisterError{Unit: unit, Address: overlap}
+		}
+	}
+	return nil
+}
+
+// checkProtected checks a write against the Protection of all the units and
+// that of the unit, or of each unit for a broadcast.
+func checkProtected(all Protection, protected map[byte]Protection, unit byte, address uint16, quantity int) error {
+	if err := all.Check(unit, address, quantity); err != nil {
+		return err
+	}
+	if unit != BroadcastUnitID {
+		return protected[unit].Check(unit, address, quantity)
+	}
+	for u, p := range protected {
+		if err := p.Check(u, address, quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/bus"
+	"github.com/edgexfoundry/device-sdk-go/pkg/retry"
+	"github.com/edgexfoundry/device-sdk-go/pkg/trace"
+)
+
+// DefaultTurnaroundDelay is the delay after a broadcast, letting the
+// devices process it before the next request, unless set by the client.
+const DefaultTurnaroundDelay = 100 * time.Millisecond
+
+// SilentInterval returns the minimum interval between frames at the given
+// baud rate: 3.5 character times, a character being 11 bits, or 1.75ms
+// above 19200 bauds as recommended by the specification.
+func SilentInterval(baudRate int) time.Duration {
+	if baudRate <= 0 || baudRate > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return time.Duration(int64(time.Second) * 35 * 11 / 10 / int64(baudRate))
+}
+
+// ErrBroadcastDisabled is returned for a request to BroadcastUnitID by a
+// client which doesn't allow broadcasts.
+var ErrBroadcastDisabled = errors.New("modbus: broadcast not enabled")
+
+// readDeadliner is implemented by the ports supporting read deadlines, e.g.
+// serial ports opened as files, or network connections to serial gateways.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// RTUClient exchanges RTU, or ASCII, frames with the devices of a serial
+// bus. Requests are serialized by a bus.Manager, as a bus carries a single
+// transaction at a time, the writes before the reads.
+type RTUClient struct {
+	// Port is the serial port, whose read timeout bounds the wait for a
+	// response, unless overridden per Range if it supports read deadlines.
+	Port io.ReadWriter
+	// Bus names the bus in the protocol trace and in Buses, e.g. the path
+	// of its serial port.
+	Bus string
+	// Buses arbitrates the transactions of the clients sharing the bus,
+	// e.g. those of several drivers, the silent interval of the client
+	// being the quiet time of the bus. Nil arbitrates the transactions of
+	// this client only.
+	Buses *bus.Manager
+	// Broadcast allows writes to BroadcastUnitID, which all the devices of
+	// the bus execute without responding, e.g. time synchronization. It's
+	// disabled by default as a safeguard.
+	Broadcast bool
+	// TurnaroundDelay is the delay after a broadcast. Zero selects
+	// DefaultTurnaroundDelay.
+	TurnaroundDelay time.Duration
+	// BaudRate of the port, determining the silent interval enforced
+	// between transactions by Buses, as slow devices fail to delimit
+	// back-to-back frames, see SilentInterval.
+	BaudRate int
+	// InterFrameGap is an explicit silent interval between transactions,
+	// overriding the one of BaudRate if greater.
+	InterFrameGap time.Duration
+	// Direction switches the RS-485 transceiver to transmit while sending
+	// frames, PreDelay before and PostDelay after them, if the transceiver
+	// doesn't switch automatically. BaudRate is required to determine the
+	// end of the frames.
+	Direction DirectionControl
+	PreDelay  time.Duration
+	PostDelay time.Duration
+	// Retry is the policy retrying the failed transactions, e.g. timeouts
+	// or CRC errors, but not the exception responses. The zero Policy
+	// doesn't retry.
+	Retry retry.Policy
+	// Protected are the registers never written, by unit, and
+	// ProtectedAllUnits those never written on any unit, see Protection.
+	Protected         map[byte]Protection
+	ProtectedAllUnits Protection
+	// ASCII selects the ASCII framing ("ModbusASCII") instead of RTU, for
+	// the legacy devices only speaking ASCII. The bus settings are the
+	// same.
+	ASCII bool
+
+	once sync.Once
+}
+
+// Configure sets the retry policy and the registers protected on all the
+// units from the settings of the Driver configuration section, see the retry
+// package and ProtectedRangesSetting. It must be called before the client
+// is used.
+func (c *RTUClient) Configure(settings map[string]string) error {
+	policy, err := retry.Parse(c.Retry, settings)
+	if err != nil {
+		return err
+	}
+	protected := c.ProtectedAllUnits
+	if _, ok := settings[ProtectedRangesSetting]; ok {
+		if protected, err = ProtectionFromSettings(settings); err != nil {
+			return err
+		}
+	}
+	c.Retry = policy
+	c.ProtectedAllUnits = protected
+	return nil
+}
+
+// ReadHoldingRegisters returns the values of quantity holding registers,
+// two bytes each, big-endian.
+func (c *RTUClient) ReadHoldingRegisters(unit byte, address uint16, quantity uint16) ([]byte, error) {
+	return c.read(unit, readRequest(ReadHoldingRegisters, address, quantity), 0)
+}
+
+// ReadInputRegisters returns the values of quantity input registers, two
+// bytes each, big-endian.
+func (c *RTUClient) ReadInputRegisters(unit byte, address uint16, quantity uint16) ([]byte, error) {
+	return c.read(unit, readRequest(ReadInputRegisters, address, quantity), 0)
+}
+
+// WriteSingleRegister writes a holding register, unless protected. The write
+// is broadcast if unit is BroadcastUnitID.
+func (c *RTUClient) WriteSingleRegister(unit byte, address uint16, value uint16) error {
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, 1); err != nil {
+		return err
+	}
+	_, err := c.send(unit, writeSingleRequest(address, value), 0)
+	return err
+}
+
+// WriteMultipleRegisters writes consecutive holding registers, two bytes
+// each, big-endian, unless any is protected. The write is broadcast if unit
+// is BroadcastUnitID.
+func (c *RTUClient) WriteMultipleRegisters(unit byte, address uint16, values []byte) error {
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, len(values)/2); err != nil {
+		return err
+	}
+	pdu, err := writeMultipleRequest(address, values)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(unit, pdu, 0)
+	return err
+}
+
+func (c *RTUClient) read(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	if unit == BroadcastUnitID {
+		return nil, ErrBroadcastDisabled
+	}
+	response, err := c.send(unit, pdu, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return readData(response)
+}
+
+// send sends a request PDU and returns the response PDU, or nil for a
+// broadcast, retrying according to the Retry policy. A non-zero timeout
+// overrides the read timeout of the Port, if it supports deadlines.
+func (c *RTUClient) send(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	if unit == BroadcastUnitID && !c.Broadcast {
+		return nil, ErrBroadcastDisabled
+	}
+
+	var response []byte
+	err := c.Retry.Do(func() error {
+		var err error
+		response, err = c.exchange(unit, pdu, timeout)
+		if _, ok := err.(Exception); ok {
+			// the device answered, retrying won't change its mind
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	return response, err
+}
+
+// exchange performs a single transaction once the bus is free, the writes
+// taking precedence over the reads.
+func (c *RTUClient) exchange(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	priority := bus.PriorityNormal
+	if pdu[0] > ReadInputRegisters {
+		priority = bus.PriorityHigh
+	}
+	var response []byte
+	err := c.arbiter().Do(c.Bus, priority, func() error {
+		var err error
+		response, err = c.transaction(unit, pdu, timeout)
+		return err
+	})
+	return response, err
+}
+
+// arbiter returns the Manager of the bus, its quiet time being set to the
+// silent interval of the client.
+func (c *RTUClient) arbiter() *bus.Manager {
+	c.once.Do(func() {
+		if c.Buses == nil {
+			c.Buses = bus.NewManager(0)
+		}
+		c.Buses.SetQuietTime(c.Bus, c.silentInterval())
+	})
+	return c.Buses
+}
+
+// transaction sends a request frame and reads its response, the bus being
+// held.
+func (c *RTUClient) transaction(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	adu := c.encode(unit, pdu)
+	trace.Record(c.Bus, trace.Tx, adu, trace.CRCNone)
+	if err := c.transmit(adu); err != nil {
+		return nil, err
+	}
+
+	if unit == BroadcastUnitID {
+		delay := c.TurnaroundDelay
+		if delay == 0 {
+			delay = DefaultTurnaroundDelay
+		}
+		time.Sleep(delay)
+		return nil, nil
+	}
+
+	if d, ok := c.Port.(readDeadliner); ok && timeout > 0 {
+		d.SetReadDeadline(time.Now().Add(timeout))
+		defer d.SetReadDeadline(time.Time{})
+	}
+	response, err := c.readFrame(pdu[0])
+	if err != nil {
+		return nil, err
+	}
+	respUnit, respPDU, err := c.decode(response)
+	if err == ErrCRC || err == ErrLRC {
+		trace.Record(c.Bus, trace.Rx, response, trace.CRCError)
+		return nil, err
+	}
+	trace.Record(c.Bus, trace.Rx, response, trace.CRCOK)
+	if err != nil {
+		return nil, err
+	}
+	if respUnit != unit {
+		return nil, ErrResponse
+	}
+	if err = checkResponse(pdu, respPDU); err != nil {
+		return nil, err
+	}
+	return respPDU, nil
+}
+
+// silentInterval returns the silent interval between the transactions,
+// that of BaudRate unless InterFrameGap is greater.
+func (c *RTUClient) silentInterval() time.Duration {
+	gap := SilentInterval(c.BaudRate)
+	if c.InterFrameGap > gap {
+		gap = c.InterFrameGap
+	}
+	return gap
+}
+
+// encode returns the frame of a request PDU, in the framing of the client.
+func (c *RTUClient) encode(unit byte, pdu []byte) []byte {
+	if c.ASCII {
+		return EncodeASCII(unit, pdu)
+	}
+	return EncodeRTU(unit, pdu)
+}
+
+// decode returns the unit and PDU of a response frame, in the framing of
+// the client.
+func (c *RTUClient) decode(frame []byte) (byte, []byte, error) {
+	if c.ASCII {
+		return DecodeASCII(frame)
+	}
+	return DecodeRTU(frame)
+}
+
+// readFrame reads a response frame to the given function. An RTU frame's
+// length is determined by its header, an ASCII frame ends with CR LF.
+func (c *RTUClient) readFrame(function byte) ([]byte, error) {
+	if c.ASCII {
+		return readASCIIFrame(c.Port)
+	}
+	frame := make([]byte, 3, 260)
+	if _, err := io.ReadFull(c.Port, frame); err != nil {
+		return nil, err
+	}
+
+	var remaining int
+	switch {
+	case frame[1] == function|exceptionFlag:
+		// exception code already read, CRC remaining
+		remaining = 2
+	case function <= ReadInputRegisters:
+		remaining = int(frame[2]) + 2
+	default:
+		// echo of the address and value or quantity, and CRC
+		remaining = 5
+	}
+	frame = frame[:3+remaining]
+	if _, err := io.ReadFull(c.Port, frame[3:]); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
@@ -0,0 +1,175 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/bus"
+	"github.com/edgexfoundry/device-sdk-go/pkg/retry"
+	"github.com/edgexfoundry/device-sdk-go/pkg/trace"
+)
+
+// player replays the given exchanges of hex encoded frames.
+func player(frames ...string) *trace.Player {
+	var recorded []trace.Frame
+	for i, f := range frames {
+		dir := trace.Tx
+		if i%2 == 1 {
+			dir = trace.Rx
+		}
+		recorded = append(recorded, trace.Frame{Direction: dir, Data: f})
+	}
+	return trace.NewPlayer(recorded)
+}
+
+func TestCRC16(t *testing.T) {
+	frame, _ := hex.DecodeString("010300000002")
+	if crc := CRC16(frame); crc != 0x0bc4 {
+		t.Errorf("Unexpected CRC %04x", crc)
+	}
+}
+
+func TestReadHoldingRegisters(t *testing.T) {
+	p := player("010300000002c40b", hex.EncodeToString(EncodeRTU(1, []byte{0x03, 0x04, 0x00, 0xe6, 0x00, 0x0a})))
+	c := &RTUClient{Port: p}
+	values, err := c.ReadHoldingRegisters(1, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(values) != "00e6000a" {
+		t.Errorf("Unexpected values %x", values)
+	}
+}
+
+func TestException(t *testing.T) {
+	p := player(hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 1))), hex.EncodeToString(EncodeRTU(1, []byte{0x86, 0x02})))
+	c := &RTUClient{Port: p}
+	err := c.WriteSingleRegister(1, 100, 1)
+	if e, ok := err.(Exception); !ok || e.Code != 2 || e.Function != WriteSingleRegister {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	request := "010300000002c40b"
+	response := EncodeRTU(1, []byte{0x03, 0x04, 0x00, 0xe6, 0x00, 0x0a})
+	garbled := append([]byte{}, response...)
+	garbled[4] ^= 0xff
+	p := player(request, hex.EncodeToString(garbled), request, hex.EncodeToString(response))
+	c := &RTUClient{Port: p, Retry: retry.Policy{MaxRetries: 1}}
+	values, err := c.ReadHoldingRegisters(1, 0, 2)
+	if err != nil || hex.EncodeToString(values) != "00e6000a" {
+		t.Errorf("Unexpected result %x, %v", values, err)
+	}
+
+	// exceptions aren't retried
+	p = player(hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 1))), hex.EncodeToString(EncodeRTU(1, []byte{0x86, 0x02})))
+	c = &RTUClient{Port: p, Retry: retry.Policy{MaxRetries: 1}}
+	if _, ok := c.WriteSingleRegister(1, 100, 1).(Exception); !ok {
+		t.Error("Exception not returned")
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	values := []byte{0x07, 0xe2, 0x00, 0x0b}
+	pdu, _ := writeMultipleRequest(200, values)
+	p := player(hex.EncodeToString(EncodeRTU(BroadcastUnitID, pdu)))
+
+	c := &RTUClient{Port: p}
+	if err := c.WriteMultipleRegisters(BroadcastUnitID, 200, values); err != ErrBroadcastDisabled {
+		t.Errorf("Broadcast not rejected: %v", err)
+	}
+
+	c.Broadcast = true
+	c.TurnaroundDelay = 10 * time.Millisecond
+	start := time.Now()
+	if err := c.WriteMultipleRegisters(BroadcastUnitID, 200, values); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < c.TurnaroundDelay {
+		t.Error("Turnaround delay not enforced")
+	}
+	if !p.Done() {
+		t.Error("Broadcast not sent")
+	}
+	if _, err := c.ReadHoldingRegisters(BroadcastUnitID, 0, 1); err != ErrBroadcastDisabled {
+		t.Errorf("Broadcast read not rejected: %v", err)
+	}
+}
+
+func TestSilentInterval(t *testing.T) {
+	if d := SilentInterval(9600); d < 4*time.Millisecond || d > 4100*time.Microsecond {
+		t.Errorf("Unexpected interval at 9600 bauds %v", d)
+	}
+	if d := SilentInterval(115200); d != 1750*time.Microsecond {
+		t.Errorf("Unexpected interval at 115200 bauds %v", d)
+	}
+}
+
+func TestInterFrameGap(t *testing.T) {
+	request := hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 1)))
+	p := player(request, request, request, request)
+	c := &RTUClient{Port: p, InterFrameGap: 20 * time.Millisecond}
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := c.WriteSingleRegister(1, 100, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if time.Since(start) < c.InterFrameGap {
+		t.Error("Inter-frame gap not enforced")
+	}
+}
+
+func TestSharedBus(t *testing.T) {
+	request := hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 1)))
+	buses := bus.NewManager(0)
+	gap := 20 * time.Millisecond
+	c1 := &RTUClient{Port: player(request, request), Bus: "/dev/ttyS0", Buses: buses, InterFrameGap: gap}
+	c2 := &RTUClient{Port: player(request, request), Bus: "/dev/ttyS0", Buses: buses, InterFrameGap: gap}
+
+	start := time.Now()
+	if err := c1.WriteSingleRegister(1, 100, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.WriteSingleRegister(1, 100, 1); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < gap {
+		t.Error("Inter-frame gap not enforced between the clients of the bus")
+	}
+}
+
+func TestASCII(t *testing.T) {
+	request := ":010300000002FA\r\n"
+	if frame := string(EncodeASCII(1, readRequest(ReadHoldingRegisters, 0, 2))); frame != request {
+		t.Errorf("Unexpected frame %q", frame)
+	}
+
+	// noise before the start of the response is skipped
+	response := append([]byte{0x00}, EncodeASCII(1, []byte{0x03, 0x04, 0x00, 0xe6, 0x00, 0x0a})...)
+	p := player(hex.EncodeToString([]byte(request)), hex.EncodeToString(response))
+	c := &RTUClient{Port: p, ASCII: true}
+	values, err := c.ReadHoldingRegisters(1, 0, 2)
+	if err != nil || hex.EncodeToString(values) != "00e6000a" {
+		t.Errorf("Unexpected result %x, %v", values, err)
+	}
+
+	p = player(hex.EncodeToString([]byte(request)), hex.EncodeToString([]byte(":01030400E6000A00\r\n")))
+	c = &RTUClient{Port: p, ASCII: true}
+	if _, err = c.ReadHoldingRegisters(1, 0, 2); err != ErrLRC {
+		t.Errorf("Expected an LRC error, got %v", err)
+	}
+
+	if _, _, err = DecodeASCII([]byte(":01030G\r\n")); err != ErrResponse {
+		t.Errorf("Expected a response error for invalid hex, got %v", err)
+	}
+}
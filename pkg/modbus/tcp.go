@@ -0,0 +1,138 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/pkg/trace"
+)
+
+// DefaultTCPTimeout is the response timeout of a TCPClient, unless set.
+const DefaultTCPTimeout = 5 * time.Second
+
+const mbapHeaderLength = 7
+
+// TCPClient exchanges Modbus TCP frames over a connection, usually obtained
+// from a Pool. Requests are serialized.
+type TCPClient struct {
+	Conn net.Conn
+	// Timeout bounds the wait for a response, unless overridden per Range.
+	// Zero selects DefaultTCPTimeout.
+	Timeout time.Duration
+	// Protected are the registers never written, by unit, and
+	// ProtectedAllUnits those never written on any unit, see Protection.
+	Protected         map[byte]Protection
+	ProtectedAllUnits Protection
+
+	mutex         sync.Mutex
+	transactionID uint16
+}
+
+// ReadHoldingRegisters returns the values of quantity holding registers,
+// two bytes each, big-endian.
+func (c *TCPClient) ReadHoldingRegisters(unit byte, address uint16, quantity uint16) ([]byte, error) {
+	return c.read(unit, readRequest(ReadHoldingRegisters, address, quantity), 0)
+}
+
+// ReadInputRegisters returns the values of quantity input registers, two
+// bytes each, big-endian.
+func (c *TCPClient) ReadInputRegisters(unit byte, address uint16, quantity uint16) ([]byte, error) {
+	return c.read(unit, readRequest(ReadInputRegisters, address, quantity), 0)
+}
+
+// WriteSingleRegister writes a holding register, unless protected.
+func (c *TCPClient) WriteSingleRegister(unit byte, address uint16, value uint16) error {
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, 1); err != nil {
+		return err
+	}
+	_, err := c.send(unit, writeSingleRequest(address, value), 0)
+	return err
+}
+
+// WriteMultipleRegisters writes consecutive holding registers, two bytes
+// each, big-endian, unless any is protected.
+func (c *TCPClient) WriteMultipleRegisters(unit byte, address uint16, values []byte) error {
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, len(values)/2); err != nil {
+		return err
+	}
+	pdu, err := writeMultipleRequest(address, values)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(unit, pdu, 0)
+	return err
+}
+
+func (c *TCPClient) read(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	response, err := c.send(unit, pdu, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return readData(response)
+}
+
+// send sends a request PDU and returns the response PDU. A non-zero timeout
+// overrides the Timeout of the client.
+func (c *TCPClient) send(unit byte, pdu []byte, timeout time.Duration) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.transactionID++
+	adu := make([]byte, mbapHeaderLength, mbapHeaderLength+len(pdu))
+	binary.BigEndian.PutUint16(adu, c.transactionID)
+	binary.BigEndian.PutUint16(adu[4:], uint16(len(pdu)+1))
+	adu[6] = unit
+	adu = append(adu, pdu...)
+
+	bus := c.Conn.RemoteAddr().String()
+	if timeout == 0 {
+		timeout = c.Timeout
+	}
+	if timeout == 0 {
+		timeout = DefaultTCPTimeout
+	}
+	c.Conn.SetDeadline(time.Now().Add(timeout))
+	defer c.Conn.SetDeadline(time.Time{})
+
+	trace.Record(bus, trace.Tx, adu, trace.CRCNone)
+	if _, err := c.Conn.Write(adu); err != nil {
+		return nil, err
+	}
+
+	// responses to earlier, timed out, transactions are skipped
+	for {
+		header := make([]byte, mbapHeaderLength)
+		if _, err := io.ReadFull(c.Conn, header); err != nil {
+			return nil, err
+		}
+		length := int(binary.BigEndian.Uint16(header[4:]))
+		if length < 2 || length > 254 {
+			return nil, ErrResponse
+		}
+		response := make([]byte, length-1)
+		if _, err := io.ReadFull(c.Conn, response); err != nil {
+			return nil, err
+		}
+		trace.Record(bus, trace.Rx, append(header, response...), trace.CRCNone)
+
+		if binary.BigEndian.Uint16(header) != c.transactionID {
+			continue
+		}
+		if header[6] != unit {
+			return nil, ErrResponse
+		}
+		if err := checkResponse(pdu, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+}
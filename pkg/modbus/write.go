@@ -0,0 +1,124 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tables, as named by the PrimaryTable attribute of device resources.
+const (
+	Coils            = "COILS"
+	DiscreteInputs   = "DISCRETE_INPUTS"
+	InputRegisters   = "INPUT_REGISTERS"
+	HoldingRegisters = "HOLDING_REGISTERS"
+)
+
+// Write functions, as named by the WriteFunction attribute of device
+// resources.
+const (
+	// WriteMultiple writes with function 0x10, the default.
+	WriteMultiple = "Multiple"
+	// WriteSingle writes each register with function 0x06, for devices
+	// which don't implement 0x10.
+	WriteSingle = "Single"
+)
+
+// Attribute names of device resources.
+const (
+	TableAttribute         = "PrimaryTable"
+	WriteFunctionAttribute = "WriteFunction"
+)
+
+// registerWriter is a client writing registers.
+type registerWriter interface {
+	WriteSingleRegister(unit byte, address uint16, value uint16) error
+	WriteMultipleRegisters(unit byte, address uint16, values []byte) error
+}
+
+// ReadOnlyTableError is returned for a write to a read-only table.
+type ReadOnlyTableError struct {
+	Table string
+}
+
+func (e ReadOnlyTableError) Error() string {
+	return fmt.Sprintf("modbus: %s are read-only", e.Table)
+}
+
+// WriteFunction returns the write function selected by the attributes of a
+// device resource, checking its table can be written: WriteSingle or
+// WriteMultiple for holding registers, the table defaulting to holding
+// registers.
+func WriteFunction(attributes map[string]interface{}) (string, error) {
+	table := HoldingRegisters
+	if v, ok := attributes[TableAttribute]; ok {
+		table = strings.ToUpper(fmt.Sprint(v))
+	}
+	switch table {
+	case HoldingRegisters:
+	case InputRegisters, DiscreteInputs:
+		return "", ReadOnlyTableError{Table: table}
+	default:
+		return "", fmt.Errorf("modbus: writes to %s not supported", table)
+	}
+
+	function := WriteMultiple
+	if v, ok := attributes[WriteFunctionAttribute]; ok {
+		function = fmt.Sprint(v)
+	}
+	switch {
+	case strings.EqualFold(function, WriteMultiple):
+		return WriteMultiple, nil
+	case strings.EqualFold(function, WriteSingle):
+		return WriteSingle, nil
+	}
+	return "", fmt.Errorf("modbus: invalid %s %q, expected %s or %s", WriteFunctionAttribute, function, WriteSingle, WriteMultiple)
+}
+
+// WriteRegisters writes consecutive holding registers, two bytes each,
+// big-endian, with the given write function: a single request with
+// WriteMultiple, or a request per register with WriteSingle. Nothing is
+// written if any register is protected.
+func (c *RTUClient) WriteRegisters(unit byte, address uint16, values []byte, function string) error {
+	// checked upfront, not to write the registers preceding a protected one
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, len(values)/2); err != nil {
+		return err
+	}
+	return writeRegisters(c, unit, address, values, function)
+}
+
+// WriteRegisters writes consecutive holding registers, two bytes each,
+// big-endian, with the given write function: a single request with
+// WriteMultiple, or a request per register with WriteSingle. Nothing is
+// written if any register is protected.
+func (c *TCPClient) WriteRegisters(unit byte, address uint16, values []byte, function string) error {
+	// checked upfront, not to write the registers preceding a protected one
+	if err := checkProtected(c.ProtectedAllUnits, c.Protected, unit, address, len(values)/2); err != nil {
+		return err
+	}
+	return writeRegisters(c, unit, address, values, function)
+}
+
+func writeRegisters(c registerWriter, unit byte, address uint16, values []byte, function string) error {
+	if len(values) == 0 || len(values)%2 != 0 {
+		return fmt.Errorf("modbus: invalid register values length %d", len(values))
+	}
+	switch function {
+	case WriteMultiple, "":
+		return c.WriteMultipleRegisters(unit, address, values)
+	case WriteSingle:
+		for i := 0; i < len(values); i += 2 {
+			value := uint16(values[i])<<8 | uint16(values[i+1])
+			if err := c.WriteSingleRegister(unit, address+uint16(i/2), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("modbus: invalid write function %q", function)
+}
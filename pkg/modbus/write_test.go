@@ -0,0 +1,121 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package modbus
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestWriteFunction(t *testing.T) {
+	tests := []struct {
+		attributes map[string]interface{}
+		expected   string
+		readOnly   bool
+	}{
+		{map[string]interface{}{}, WriteMultiple, false},
+		{map[string]interface{}{TableAttribute: "holding_registers", WriteFunctionAttribute: "single"}, WriteSingle, false},
+		{map[string]interface{}{TableAttribute: InputRegisters}, "", true},
+		{map[string]interface{}{TableAttribute: DiscreteInputs}, "", true},
+	}
+	for _, tt := range tests {
+		function, err := WriteFunction(tt.attributes)
+		if _, ok := err.(ReadOnlyTableError); ok != tt.readOnly || function != tt.expected {
+			t.Errorf("Unexpected function %q, %v for %v", function, err, tt.attributes)
+		}
+	}
+
+	if _, err := WriteFunction(map[string]interface{}{WriteFunctionAttribute: "Both"}); err == nil {
+		t.Errorf("Expected an error for an invalid write function")
+	}
+}
+
+func TestWriteRegistersSingle(t *testing.T) {
+	p := player(
+		hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 0x0102))),
+		hex.EncodeToString(EncodeRTU(1, writeSingleRequest(100, 0x0102))),
+		hex.EncodeToString(EncodeRTU(1, writeSingleRequest(101, 0x0304))),
+		hex.EncodeToString(EncodeRTU(1, writeSingleRequest(101, 0x0304))),
+	)
+	c := &RTUClient{Port: p}
+	if err := c.WriteRegisters(1, 100, []byte{0x01, 0x02, 0x03, 0x04}, WriteSingle); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Done() {
+		t.Errorf("Expected a request per register")
+	}
+}
+
+func TestProtection(t *testing.T) {
+	p, err := ParseProtection(" 9000-9099, 9500,0x10 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		address   uint16
+		quantity  int
+		protected uint16
+		ok        bool
+	}{
+		{8990, 10, 0, true},
+		{8990, 11, 9000, false},
+		{9050, 2, 9050, false},
+		{9099, 1, 9099, false},
+		{9100, 399, 0, true},
+		{9499, 2, 9500, false},
+		{16, 1, 16, false},
+	}
+	for _, tt := range tests {
+		err := p.Check(1, tt.address, tt.quantity)
+		if e, ok := err.(ProtectedRegisterError); (err == nil) != tt.ok || (!tt.ok && (!ok || e.Address != tt.protected)) {
+			t.Errorf("Unexpected result %v for %d registers from %d", err, tt.quantity, tt.address)
+		}
+	}
+
+	for _, s := range []string{"9100-9000", "x", "70000"} {
+		if _, err := ParseProtection(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+
+	// nothing is written, not even the registers preceding a protected one
+	c := &RTUClient{Port: player(), Protected: map[byte]Protection{1: p}}
+	if _, ok := c.WriteRegisters(1, 8999, []byte{0, 1, 0, 2}, WriteSingle).(ProtectedRegisterError); !ok {
+		t.Errorf("Expected a protected register error")
+	}
+	if _, ok := c.WriteSingleRegister(1, 9500, 1).(ProtectedRegisterError); !ok {
+		t.Errorf("Expected a protected register error")
+	}
+	// the protection of a unit applies to broadcasts
+	c.Broadcast = true
+	if _, ok := c.WriteMultipleRegisters(BroadcastUnitID, 9000, []byte{0, 1}).(ProtectedRegisterError); !ok {
+		t.Errorf("Expected a protected register error for a broadcast")
+	}
+}
+
+func TestConfigureProtection(t *testing.T) {
+	c := &RTUClient{Port: player()}
+	if err := c.Configure(map[string]string{ProtectedRangesSetting: "9000-9099"}); err != nil {
+		t.Fatal(err)
+	}
+	// the registers are protected on every unit
+	for _, unit := range []byte{1, 7} {
+		if e, ok := c.WriteSingleRegister(unit, 9042, 1).(ProtectedRegisterError); !ok || e.Unit != unit {
+			t.Errorf("Expected a protected register error for unit %d", unit)
+		}
+	}
+	if _, ok := c.WriteRegisters(3, 8999, []byte{0, 1, 0, 2}, WriteMultiple).(ProtectedRegisterError); !ok {
+		t.Errorf("Expected a protected register error")
+	}
+
+	if err := c.Configure(map[string]string{ProtectedRangesSetting: "x"}); err == nil {
+		t.Errorf("Expected an error for invalid protected registers")
+	}
+	if len(c.ProtectedAllUnits) != 1 {
+		t.Errorf("Protection lost on an invalid setting: %v", c.ProtectedAllUnits)
+	}
+}
@@ -0,0 +1,34 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// AnomalyAction is what the DS does with an anomalous reading.
+type AnomalyAction int
+
+const (
+	// AnomalyTag publishes the reading, with an additional reading
+	// describing the anomaly.
+	AnomalyTag AnomalyAction = iota + 1
+	// AnomalyAlarm tags the reading and logs an alarm.
+	AnomalyAlarm
+	// AnomalySuppress drops the reading, e.g. an obviously corrupt value
+	// read after a communication glitch.
+	AnomalySuppress
+)
+
+// Anomaly describes an anomalous reading.
+type Anomaly struct {
+	Action AnomalyAction
+	Reason string
+}
+
+// AnomalyDetector may optionally be implemented by a ProtocolDriver to check
+// each reading, after its transformation, before it's published. It returns
+// nil for a normal reading. It's called concurrently for different Devices.
+type AnomalyDetector interface {
+	DetectAnomaly(deviceName string, cv *CommandValue) *Anomaly
+}
@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// APIVersion is the version of the driver API provided by this SDK, as
+// <major>.<minor>. The minor version is raised for backwards compatible
+// additions and the major version for incompatible changes.
+const APIVersion = "1.1"
+
+// VersionedDriver may optionally be implemented by a ProtocolDriver to
+// declare the SDK API version it was built against, normally by returning
+// the APIVersion constant of the SDK it was compiled with.
+type VersionedDriver interface {
+	APIVersion() string
+}
+
+// CheckAPIVersion checks whether a driver built against the given API version
+// can run with this SDK. Drivers built against an older minor version, or
+// which don't declare a version, run in compatibility mode. An error is
+// returned if the major versions differ or the driver requires a newer SDK.
+func CheckAPIVersion(driverVersion string) (compat bool, err error) {
+	if driverVersion == "" {
+		return true, nil
+	}
+	major, minor, err := parseAPIVersion(driverVersion)
+	if err != nil {
+		return false, err
+	}
+	sdkMajor, sdkMinor, _ := parseAPIVersion(APIVersion)
+	if major != sdkMajor || minor > sdkMinor {
+		return false, fmt.Errorf("driver built against SDK API %s is incompatible with SDK API %s", driverVersion, APIVersion)
+	}
+	return minor < sdkMinor, nil
+}
+
+func parseAPIVersion(version string) (major int, minor int, err error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid SDK API version %q", version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid SDK API version %q", version)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid SDK API version %q", version)
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,32 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import "testing"
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		compat  bool
+		fails   bool
+	}{
+		{APIVersion, false, false},
+		{"", true, false},
+		{"1.0", true, false},
+		{"1.9", false, true},
+		{"2.1", false, true},
+		{"0.1", false, true},
+		{"1", false, true},
+		{"1.x", false, true},
+	}
+	for _, tt := range tests {
+		compat, err := CheckAPIVersion(tt.version)
+		if (err != nil) != tt.fails || compat != tt.compat {
+			t.Errorf("CheckAPIVersion(%q) = %v, %v", tt.version, compat, err)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// NewBoolArrayValue creates a CommandValue of Type BoolArray with the given
+// value.
+func NewBoolArrayValue(ro *models.ResourceOperation, origin int64, value []bool) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, BoolArray)
+}
+
+// NewUint8ArrayValue creates a CommandValue of Type Uint8Array with the
+// given value.
+func NewUint8ArrayValue(ro *models.ResourceOperation, origin int64, value []uint8) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Uint8Array)
+}
+
+// NewUint16ArrayValue creates a CommandValue of Type Uint16Array with the
+// given value.
+func NewUint16ArrayValue(ro *models.ResourceOperation, origin int64, value []uint16) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Uint16Array)
+}
+
+// NewUint32ArrayValue creates a CommandValue of Type Uint32Array with the
+// given value.
+func NewUint32ArrayValue(ro *models.ResourceOperation, origin int64, value []uint32) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Uint32Array)
+}
+
+// NewUint64ArrayValue creates a CommandValue of Type Uint64Array with the
+// given value.
+func NewUint64ArrayValue(ro *models.ResourceOperation, origin int64, value []uint64) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Uint64Array)
+}
+
+// NewInt8ArrayValue creates a CommandValue of Type Int8Array with the given
+// value.
+func NewInt8ArrayValue(ro *models.ResourceOperation, origin int64, value []int8) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Int8Array)
+}
+
+// NewInt16ArrayValue creates a CommandValue of Type Int16Array with the
+// given value.
+func NewInt16ArrayValue(ro *models.ResourceOperation, origin int64, value []int16) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Int16Array)
+}
+
+// NewInt32ArrayValue creates a CommandValue of Type Int32Array with the
+// given value.
+func NewInt32ArrayValue(ro *models.ResourceOperation, origin int64, value []int32) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Int32Array)
+}
+
+// NewInt64ArrayValue creates a CommandValue of Type Int64Array with the
+// given value.
+func NewInt64ArrayValue(ro *models.ResourceOperation, origin int64, value []int64) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Int64Array)
+}
+
+// NewFloat32ArrayValue creates a CommandValue of Type Float32Array with the
+// given value.
+func NewFloat32ArrayValue(ro *models.ResourceOperation, origin int64, value []float32) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Float32Array)
+}
+
+// NewFloat64ArrayValue creates a CommandValue of Type Float64Array with the
+// given value.
+func NewFloat64ArrayValue(ro *models.ResourceOperation, origin int64, value []float64) (*CommandValue, error) {
+	return NewCommandValue(ro, origin, value, Float64Array)
+}
+
+func (cv *CommandValue) BoolArrayValue() ([]bool, error) {
+	var value []bool
+	err := cv.decodeArray(BoolArray, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Uint8ArrayValue() ([]uint8, error) {
+	var value []uint8
+	err := cv.decodeArray(Uint8Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Uint16ArrayValue() ([]uint16, error) {
+	var value []uint16
+	err := cv.decodeArray(Uint16Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Uint32ArrayValue() ([]uint32, error) {
+	var value []uint32
+	err := cv.decodeArray(Uint32Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Uint64ArrayValue() ([]uint64, error) {
+	var value []uint64
+	err := cv.decodeArray(Uint64Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Int8ArrayValue() ([]int8, error) {
+	var value []int8
+	err := cv.decodeArray(Int8Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Int16ArrayValue() ([]int16, error) {
+	var value []int16
+	err := cv.decodeArray(Int16Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Int32ArrayValue() ([]int32, error) {
+	var value []int32
+	err := cv.decodeArray(Int32Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Int64ArrayValue() ([]int64, error) {
+	var value []int64
+	err := cv.decodeArray(Int64Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Float32ArrayValue() ([]float32, error) {
+	var value []float32
+	err := cv.decodeArray(Float32Array, &value)
+	return value, err
+}
+
+func (cv *CommandValue) Float64ArrayValue() ([]float64, error) {
+	var value []float64
+	err := cv.decodeArray(Float64Array, &value)
+	return value, err
+}
+
+// decodeArray decodes the elements of an array value into the slice pointed
+// to by value, sized after NumericValue.
+func (cv *CommandValue) decodeArray(t ValueType, value interface{}) error {
+	slice := reflect.ValueOf(value).Elem()
+	if cv.Type != t {
+		return fmt.Errorf("the data type is not %s", slice.Type())
+	}
+	et, _ := t.ElementType()
+	if len(cv.NumericValue)%et.Size() != 0 {
+		return fmt.Errorf("%d bytes can't be split into %s elements", len(cv.NumericValue), et.Name())
+	}
+	n := len(cv.NumericValue) / et.Size()
+	slice.Set(reflect.MakeSlice(slice.Type(), n, n))
+	return decodeValue(bytes.NewReader(cv.NumericValue), slice.Interface())
+}
+
+// arrayToString returns an array value as a JSON array, e.g. [1,2,3], the
+// floats being formatted as decimals.
+func arrayToString(cv *CommandValue) string {
+	slice := reflect.New(goTypes[cv.Type])
+	if err := cv.decodeArray(cv.Type, slice.Interface()); err != nil {
+		return err.Error()
+	}
+	elements := slice.Elem()
+	strs := make([]string, elements.Len())
+	for i := range strs {
+		e := elements.Index(i)
+		switch e.Kind() {
+		case reflect.Bool:
+			strs[i] = strconv.FormatBool(e.Bool())
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			strs[i] = strconv.FormatUint(e.Uint(), 10)
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			strs[i] = strconv.FormatInt(e.Int(), 10)
+		case reflect.Float32:
+			strs[i] = strconv.FormatFloat(e.Float(), 'f', -1, 32)
+		case reflect.Float64:
+			strs[i] = strconv.FormatFloat(e.Float(), 'f', -1, 64)
+		}
+	}
+	return "[" + strings.Join(strs, ",") + "]"
+}
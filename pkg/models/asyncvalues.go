@@ -10,4 +10,21 @@ package models
 type AsyncValues struct {
 	DeviceName    string
 	CommandValues []*CommandValue
+	// Capture, if set, is a chunk of a long-running capture (e.g. a waveform
+	// file) to be assembled and stored by the SDK, instead of readings.
+	Capture *CaptureChunk
+}
+
+// CaptureChunk is a part of the data of a capture streamed by a ProtocolDriver.
+type CaptureChunk struct {
+	// ID identifies the capture, and must be unique per Device service.
+	ID string
+	// Sequence is the index of the chunk, starting at 0.
+	Sequence int
+	// Data is the contents of the chunk.
+	Data []byte
+	// Last is set on the final chunk of the capture.
+	Last bool
+	// Error, if set, aborts the capture with the given reason.
+	Error string
 }
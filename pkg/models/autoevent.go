@@ -0,0 +1,19 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// AutoEvent is the periodic acquisition of a resource of a Device, its
+// readings being pushed to Core Data.
+type AutoEvent struct {
+	// Frequency is the interval between the reads, e.g. "10s" or "500ms".
+	Frequency string
+	// OnChange pushes the readings only when they differ from the last
+	// ones pushed.
+	OnChange bool
+	// Resource is the device resource, or command, read.
+	Resource string
+}
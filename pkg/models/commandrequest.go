@@ -6,7 +6,13 @@
 
 package models
 
-import "github.com/edgexfoundry/edgex-go/pkg/models"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
 
 type CommandRequest struct {
 	// RO is a ResourceOperation
@@ -15,4 +21,37 @@ type CommandRequest struct {
 	// to be read or set. It can be used to access the attributes map,
 	// PropertyValue, and PropertyUnit structs.
 	DeviceObject models.DeviceObject
+	// Properties are the custom properties of the Device, e.g. its phase
+	// or CT ratio.
+	Properties map[string]string
+}
+
+// TimeoutAttribute is the attribute of a device resource overriding the
+// timeout of its reads, in milliseconds, e.g. for slow registers.
+const TimeoutAttribute = "Timeout"
+
+// Timeout returns the Timeout attribute of the device resource, or def if
+// it isn't set or invalid, so that drivers can pass it to their transport.
+func (r CommandRequest) Timeout(def time.Duration) time.Duration {
+	var ms float64
+	switch v := r.DeviceObject.Attributes[TimeoutAttribute].(type) {
+	case int:
+		ms = float64(v)
+	case int64:
+		ms = float64(v)
+	case float64:
+		ms = v
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return def
+		}
+		ms = f
+	default:
+		return def
+	}
+	if ms <= 0 {
+		return def
+	}
+	return time.Duration(ms * float64(time.Millisecond))
 }
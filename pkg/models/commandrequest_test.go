@@ -0,0 +1,37 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestCommandRequestTimeout(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected time.Duration
+	}{
+		{nil, time.Second},
+		{2500.0, 2500 * time.Millisecond},
+		{3000, 3 * time.Second},
+		{" 500 ", 500 * time.Millisecond},
+		{"slow", time.Second},
+		{-1.0, time.Second},
+	}
+	for _, tt := range tests {
+		req := CommandRequest{DeviceObject: models.DeviceObject{Attributes: map[string]interface{}{}}}
+		if tt.value != nil {
+			req.DeviceObject.Attributes[TimeoutAttribute] = tt.value
+		}
+		if timeout := req.Timeout(time.Second); timeout != tt.expected {
+			t.Errorf("Expected %v for %v, got %v", tt.expected, tt.value, timeout)
+		}
+	}
+}
@@ -59,6 +59,24 @@ const (
 	// Float64 indicates that the value is a float64 that
 	// is stored in CommandValue's NumericRes member.
 	Float64
+	// BoolArray to Float64Array indicate that the value is an
+	// array of the element type, whose elements are stored
+	// one after the other in CommandValue's NumericRes member.
+	BoolArray
+	Uint8Array
+	Uint16Array
+	Uint32Array
+	Uint64Array
+	Int8Array
+	Int16Array
+	Int32Array
+	Int64Array
+	Float32Array
+	Float64Array
+	// Binary indicates that the value is a blob, e.g. a
+	// waveform capture, stored in CommandValue's NumericRes
+	// member.
+	Binary
 )
 
 type CommandValue struct {
@@ -83,96 +101,76 @@ type CommandValue struct {
 	stringValue string
 }
 
+// NewBoolValue creates a CommandValue of Type Bool with the given value.
 func NewBoolValue(ro *models.ResourceOperation, origin int64, value bool) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Bool}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Bool(value).Build()
 }
 
+// NewStringValue creates a CommandValue of Type String with the given value.
 func NewStringValue(ro *models.ResourceOperation, origin int64, value string) (cv *CommandValue) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: String, stringValue: value}
+	cv, _ = NewCommandValueBuilder().Resource(ro).Origin(origin).String(value).Build()
 	return
 }
 
 // NewUint8Value creates a CommandValue of Type Uint8 with the given value.
 func NewUint8Value(ro *models.ResourceOperation, origin int64, value uint8) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Uint8}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Uint8(value).Build()
 }
 
 // NewUint16Value creates a CommandValue of Type Uint16 with the given value.
 func NewUint16Value(ro *models.ResourceOperation, origin int64, value uint16) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Uint16}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Uint16(value).Build()
 }
 
 // NewUint32Value creates a CommandValue of Type Uint32 with the given value.
 func NewUint32Value(ro *models.ResourceOperation, origin int64, value uint32) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Uint32}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Uint32(value).Build()
 }
 
 // NewUint64Value creates a CommandValue of Type Uint64 with the given value.
 func NewUint64Value(ro *models.ResourceOperation, origin int64, value uint64) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Uint64}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Uint64(value).Build()
 }
 
 // NewInt8Value creates a CommandValue of Type Int8 with the given value.
 func NewInt8Value(ro *models.ResourceOperation, origin int64, value int8) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Int8}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Int8(value).Build()
 }
 
 // NewInt16Value creates a CommandValue of Type Int16 with the given value.
 func NewInt16Value(ro *models.ResourceOperation, origin int64, value int16) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Int16}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Int16(value).Build()
 }
 
 // NewInt32Value creates a CommandValue of Type Int32 with the given value.
 func NewInt32Value(ro *models.ResourceOperation, origin int64, value int32) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Int32}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Int32(value).Build()
 }
 
 // NewInt64Value creates a CommandValue of Type Int64 with the given value.
 func NewInt64Value(ro *models.ResourceOperation, origin int64, value int64) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Int64}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Int64(value).Build()
 }
 
 // NewFloat32Value creates a CommandValue of Type Float32 with the given value.
 func NewFloat32Value(ro *models.ResourceOperation, origin int64, value float32) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Float32}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Float32(value).Build()
 }
 
 // NewFloat64Value creates a CommandValue of Type Float64 with the given value.
 func NewFloat64Value(ro *models.ResourceOperation, origin int64, value float64) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: Float64}
-	err = encodeValue(cv, value)
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Float64(value).Build()
+}
+
+// NewBinaryValue creates a CommandValue of Type Binary with the given value.
+func NewBinaryValue(ro *models.ResourceOperation, origin int64, value []byte) (cv *CommandValue, err error) {
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Value(value, Binary).Build()
 }
 
-//NewCommandValue create a CommandValue according to the Type supplied
+// NewCommandValue creates a CommandValue of the given Type, converting a
+// numeric value to its Go type, see CommandValueBuilder.Value.
 func NewCommandValue(ro *models.ResourceOperation, origin int64, value interface{}, t ValueType) (cv *CommandValue, err error) {
-	cv = &CommandValue{RO: ro, Origin: origin, Type: t}
-	if t != String {
-		err = encodeValue(cv, value)
-	} else {
-		cv.stringValue = value.(string)
-	}
-	return
+	return NewCommandValueBuilder().Resource(ro).Origin(origin).Value(value, t).Build()
 }
 
 func encodeValue(cv *CommandValue, value interface{}) error {
@@ -195,6 +193,12 @@ func (cv *CommandValue) ValueToString() (str string) {
 		str = cv.stringValue
 		return
 	}
+	if cv.Type.IsArray() {
+		return arrayToString(cv)
+	}
+	if cv.Type == Binary {
+		return base64.StdEncoding.EncodeToString(cv.NumericValue)
+	}
 
 	reader := bytes.NewReader(cv.NumericValue)
 
@@ -309,6 +313,8 @@ func (cv *CommandValue) String() (str string) {
 		typeStr = "Float32: "
 	case Float64:
 		typeStr = "Float64: "
+	default:
+		typeStr = cv.Type.Name() + ": "
 	}
 
 	valueStr := typeStr + cv.ValueToString()
@@ -424,3 +430,10 @@ func (cv *CommandValue) Float64Value() (float64, error) {
 	err := decodeValue(bytes.NewReader(cv.NumericValue), &value)
 	return value, err
 }
+
+func (cv *CommandValue) BinaryValue() ([]byte, error) {
+	if cv.Type != Binary {
+		return nil, fmt.Errorf("the data type is not %T", []byte{})
+	}
+	return cv.NumericValue, nil
+}
@@ -567,3 +567,62 @@ func TestNewFloat64Value(t *testing.T) {
 		t.Errorf("NewFloat64Value #2: invalid reading Value: %s", cv.ValueToString())
 	}
 }
+
+// Test the array values.
+func TestNewArrayValues(t *testing.T) {
+	cv, err := NewInt16ArrayValue(nil, 0, []int16{-1, 0, 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.Type != Int16Array || !bytes.Equal(cv.NumericValue, []byte{0xff, 0xff, 0, 0, 0x01, 0x2c}) {
+		t.Errorf("NewInt16ArrayValue: invalid value %v %v", cv.Type, cv.NumericValue)
+	}
+	if v, err := cv.Int16ArrayValue(); err != nil || len(v) != 3 || v[2] != 300 {
+		t.Errorf("Int16ArrayValue: %v, %v", v, err)
+	}
+	if _, err = cv.Int32ArrayValue(); err == nil {
+		t.Error("Int32ArrayValue of an Int16Array")
+	}
+	if cv.ValueToString() != "[-1,0,300]" {
+		t.Errorf("NewInt16ArrayValue: invalid reading Value: %s", cv.ValueToString())
+	}
+
+	cv, _ = NewFloat32ArrayValue(nil, 0, []float32{1.5, -0.1})
+	if cv.ValueToString() != "[1.5,-0.1]" {
+		t.Errorf("NewFloat32ArrayValue: invalid reading Value: %s", cv.ValueToString())
+	}
+	cv, _ = NewUint8ArrayValue(nil, 0, []uint8{})
+	if cv.ValueToString() != "[]" {
+		t.Errorf("NewUint8ArrayValue: invalid reading Value: %s", cv.ValueToString())
+	}
+	cv, _ = NewBoolArrayValue(nil, 0, []bool{true, false})
+	if v, err := cv.BoolArrayValue(); err != nil || len(v) != 2 || !v[0] || cv.ValueToString() != "[true,false]" {
+		t.Errorf("NewBoolArrayValue: %v, %v", v, err)
+	}
+
+	if _, err = NewCommandValue(nil, 0, []int32{1}, Int16Array); err == nil {
+		t.Error("[]int32 accepted for Int16Array")
+	}
+	for _, tt := range []struct{ array, element ValueType }{{BoolArray, Bool}, {Uint8Array, Uint8}, {Int64Array, Int64}, {Float64Array, Float64}} {
+		if e, ok := tt.array.ElementType(); !ok || e != tt.element {
+			t.Errorf("%s elements are %s", tt.array.Name(), e.Name())
+		}
+	}
+}
+
+// Test NewBinaryValue function.
+func TestNewBinaryValue(t *testing.T) {
+	cv, err := NewBinaryValue(nil, 0, []byte{0, 1, 2})
+	if err != nil || cv.Type != Binary {
+		t.Fatalf("NewBinaryValue: %v, %v", cv, err)
+	}
+	if v, err := cv.BinaryValue(); err != nil || !bytes.Equal(v, []byte{0, 1, 2}) {
+		t.Errorf("BinaryValue: %v, %v", v, err)
+	}
+	if cv.ValueToString() != "AAEC" {
+		t.Errorf("NewBinaryValue: invalid reading Value: %s", cv.ValueToString())
+	}
+	if _, err = cv.Uint8ArrayValue(); err == nil {
+		t.Error("Uint8ArrayValue of a Binary value")
+	}
+}
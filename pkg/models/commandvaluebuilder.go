@@ -0,0 +1,299 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// goTypes are the Go types of the values of the ValueTypes, determining the
+// layout of NumericValue.
+var goTypes = map[ValueType]reflect.Type{
+	Bool:    reflect.TypeOf(false),
+	String:  reflect.TypeOf(""),
+	Uint8:   reflect.TypeOf(uint8(0)),
+	Uint16:  reflect.TypeOf(uint16(0)),
+	Uint32:  reflect.TypeOf(uint32(0)),
+	Uint64:  reflect.TypeOf(uint64(0)),
+	Int8:    reflect.TypeOf(int8(0)),
+	Int16:   reflect.TypeOf(int16(0)),
+	Int32:   reflect.TypeOf(int32(0)),
+	Int64:   reflect.TypeOf(int64(0)),
+	Float32: reflect.TypeOf(float32(0)),
+	Float64: reflect.TypeOf(float64(0)),
+
+	BoolArray:    reflect.TypeOf([]bool{}),
+	Uint8Array:   reflect.TypeOf([]uint8{}),
+	Uint16Array:  reflect.TypeOf([]uint16{}),
+	Uint32Array:  reflect.TypeOf([]uint32{}),
+	Uint64Array:  reflect.TypeOf([]uint64{}),
+	Int8Array:    reflect.TypeOf([]int8{}),
+	Int16Array:   reflect.TypeOf([]int16{}),
+	Int32Array:   reflect.TypeOf([]int32{}),
+	Int64Array:   reflect.TypeOf([]int64{}),
+	Float32Array: reflect.TypeOf([]float32{}),
+	Float64Array: reflect.TypeOf([]float64{}),
+	Binary:       reflect.TypeOf([]byte{}),
+}
+
+// CommandValueBuilder builds a CommandValue whose NumericValue is laid out
+// according to its Type, e.g.
+//
+//	cv, err := NewCommandValueBuilder().Resource(ro).Origin(origin).Float(3.14159).Precision(2).Build()
+//
+// The first error, e.g. a value out of the range of its Type, is returned
+// by Build.
+type CommandValueBuilder struct {
+	ro        *models.ResourceOperation
+	origin    int64
+	valueType ValueType
+	value     interface{}
+	precision int
+	err       error
+}
+
+// NewCommandValueBuilder returns a builder without value.
+func NewCommandValueBuilder() *CommandValueBuilder {
+	return &CommandValueBuilder{precision: -1}
+}
+
+// Resource sets the ResourceOperation of the value.
+func (b *CommandValueBuilder) Resource(ro *models.ResourceOperation) *CommandValueBuilder {
+	b.ro = ro
+	return b
+}
+
+// Origin sets the time of the value, in milliseconds.
+func (b *CommandValueBuilder) Origin(origin int64) *CommandValueBuilder {
+	b.origin = origin
+	return b
+}
+
+// Bool sets a Bool value.
+func (b *CommandValueBuilder) Bool(value bool) *CommandValueBuilder {
+	return b.Value(value, Bool)
+}
+
+// String sets a String value.
+func (b *CommandValueBuilder) String(value string) *CommandValueBuilder {
+	return b.Value(value, String)
+}
+
+// Uint8 sets a Uint8 value.
+func (b *CommandValueBuilder) Uint8(value uint8) *CommandValueBuilder {
+	return b.Value(value, Uint8)
+}
+
+// Uint16 sets a Uint16 value.
+func (b *CommandValueBuilder) Uint16(value uint16) *CommandValueBuilder {
+	return b.Value(value, Uint16)
+}
+
+// Uint32 sets a Uint32 value.
+func (b *CommandValueBuilder) Uint32(value uint32) *CommandValueBuilder {
+	return b.Value(value, Uint32)
+}
+
+// Uint64 sets a Uint64 value.
+func (b *CommandValueBuilder) Uint64(value uint64) *CommandValueBuilder {
+	return b.Value(value, Uint64)
+}
+
+// Int8 sets an Int8 value.
+func (b *CommandValueBuilder) Int8(value int8) *CommandValueBuilder {
+	return b.Value(value, Int8)
+}
+
+// Int16 sets an Int16 value.
+func (b *CommandValueBuilder) Int16(value int16) *CommandValueBuilder {
+	return b.Value(value, Int16)
+}
+
+// Int32 sets an Int32 value.
+func (b *CommandValueBuilder) Int32(value int32) *CommandValueBuilder {
+	return b.Value(value, Int32)
+}
+
+// Int64 sets an Int64 value.
+func (b *CommandValueBuilder) Int64(value int64) *CommandValueBuilder {
+	return b.Value(value, Int64)
+}
+
+// Float32 sets a Float32 value.
+func (b *CommandValueBuilder) Float32(value float32) *CommandValueBuilder {
+	return b.Value(value, Float32)
+}
+
+// Float64 sets a Float64 value.
+func (b *CommandValueBuilder) Float64(value float64) *CommandValueBuilder {
+	return b.Value(value, Float64)
+}
+
+// Float sets a Float64 value.
+func (b *CommandValueBuilder) Float(value float64) *CommandValueBuilder {
+	return b.Value(value, Float64)
+}
+
+// Value sets a value of the given Type. Numeric values are converted to the
+// Go type of the ValueType, e.g. the uint64 returned by strconv.ParseUint to
+// uint8 for Uint8, failing if out of its range.
+func (b *CommandValueBuilder) Value(value interface{}, t ValueType) *CommandValueBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.value, b.err = convertValue(value, t)
+	b.valueType = t
+	return b
+}
+
+// Precision rounds a Float32 or Float64 value to the given number of
+// decimals.
+func (b *CommandValueBuilder) Precision(decimals int) *CommandValueBuilder {
+	if b.err == nil && decimals < 0 {
+		b.err = fmt.Errorf("invalid precision %d", decimals)
+	}
+	b.precision = decimals
+	return b
+}
+
+// Build returns the CommandValue.
+func (b *CommandValueBuilder) Build() (*CommandValue, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.value == nil {
+		return nil, errors.New("no value set")
+	}
+
+	value := b.value
+	if b.precision >= 0 {
+		scale := math.Pow10(b.precision)
+		switch v := value.(type) {
+		case float32:
+			value = float32(math.Round(float64(v)*scale) / scale)
+		case float64:
+			value = math.Round(v*scale) / scale
+		default:
+			return nil, fmt.Errorf("precision of a %s value", b.valueType.Name())
+		}
+	}
+
+	cv := &CommandValue{RO: b.ro, Origin: b.origin, Type: b.valueType}
+	if s, ok := value.(string); ok {
+		cv.stringValue = s
+		return cv, nil
+	}
+	if err := encodeValue(cv, value); err != nil {
+		return nil, err
+	}
+	return cv, nil
+}
+
+// convertValue converts a value to the Go type of a ValueType.
+func convertValue(value interface{}, t ValueType) (interface{}, error) {
+	goType, ok := goTypes[t]
+	if !ok {
+		return nil, fmt.Errorf("unsupported value type %d", t)
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, errors.New("nil value")
+	}
+	if v.Type() == goType {
+		return value, nil
+	}
+
+	result := reflect.New(goType).Elem()
+	mismatch := fmt.Errorf("%T value %v can't be converted to %s", value, value, t.Name())
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !setInt(result, v.Int()) {
+			return nil, mismatch
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !setUint(result, v.Uint()) {
+			return nil, mismatch
+		}
+	case reflect.Float32, reflect.Float64:
+		if !setFloat(result, v.Float()) {
+			return nil, mismatch
+		}
+	default:
+		return nil, mismatch
+	}
+	return result.Interface(), nil
+}
+
+func setInt(result reflect.Value, i int64) bool {
+	switch result.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if result.OverflowInt(i) {
+			return false
+		}
+		result.SetInt(i)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i < 0 {
+			return false
+		}
+		return setUint(result, uint64(i))
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(float64(i))
+	default:
+		return false
+	}
+	return true
+}
+
+func setUint(result reflect.Value, u uint64) bool {
+	switch result.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if u > math.MaxInt64 {
+			return false
+		}
+		return setInt(result, int64(u))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if result.OverflowUint(u) {
+			return false
+		}
+		result.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(float64(u))
+	default:
+		return false
+	}
+	return true
+}
+
+// setFloat sets a float, or an integer if it's integral and within range.
+func setFloat(result reflect.Value, f float64) bool {
+	switch result.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if result.OverflowFloat(f) {
+			return false
+		}
+		result.SetFloat(f)
+		return true
+	}
+	if f != math.Trunc(f) || math.IsInf(f, 0) {
+		return false
+	}
+	if f < 0 {
+		if f < math.MinInt64 {
+			return false
+		}
+		return setInt(result, int64(f))
+	}
+	if f >= math.MaxUint64 {
+		return false
+	}
+	return setUint(result, uint64(f))
+}
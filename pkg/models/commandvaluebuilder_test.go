@@ -0,0 +1,164 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func float32String(f float32) string {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, math.Float32bits(f))
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func float64String(f float64) string {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(f))
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestCommandValueBuilderFormatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     *CommandValueBuilder
+		vt    ValueType
+		value string
+	}{
+		{"BoolTrue", NewCommandValueBuilder().Bool(true), Bool, "true"},
+		{"BoolFalse", NewCommandValueBuilder().Bool(false), Bool, "false"},
+		{"String", NewCommandValueBuilder().String("on"), String, "on"},
+		{"StringEmpty", NewCommandValueBuilder().String(""), String, ""},
+		{"Uint8", NewCommandValueBuilder().Uint8(math.MaxUint8), Uint8, "255"},
+		{"Uint16", NewCommandValueBuilder().Uint16(math.MaxUint16), Uint16, "65535"},
+		{"Uint32", NewCommandValueBuilder().Uint32(math.MaxUint32), Uint32, "4294967295"},
+		{"Uint64", NewCommandValueBuilder().Uint64(math.MaxUint64), Uint64, "18446744073709551615"},
+		{"Int8", NewCommandValueBuilder().Int8(math.MinInt8), Int8, "-128"},
+		{"Int16", NewCommandValueBuilder().Int16(math.MinInt16), Int16, "-32768"},
+		{"Int32", NewCommandValueBuilder().Int32(math.MinInt32), Int32, "-2147483648"},
+		{"Int64", NewCommandValueBuilder().Int64(math.MinInt64), Int64, "-9223372036854775808"},
+		{"Float32", NewCommandValueBuilder().Float32(1.5), Float32, float32String(1.5)},
+		{"Float64", NewCommandValueBuilder().Float64(-2.25), Float64, float64String(-2.25)},
+		{"Float", NewCommandValueBuilder().Float(3.14), Float64, float64String(3.14)},
+		{"ValueUint64ToUint8", NewCommandValueBuilder().Value(uint64(200), Uint8), Uint8, "200"},
+		{"ValueInt64ToInt16", NewCommandValueBuilder().Value(int64(-300), Int16), Int16, "-300"},
+		{"ValueIntToUint32", NewCommandValueBuilder().Value(7, Uint32), Uint32, "7"},
+		{"ValueUintToInt64", NewCommandValueBuilder().Value(uint(7), Int64), Int64, "7"},
+		{"ValueFloat64ToFloat32", NewCommandValueBuilder().Value(float64(0.5), Float32), Float32, float32String(0.5)},
+		{"ValueIntToFloat64", NewCommandValueBuilder().Value(int8(-3), Float64), Float64, float64String(-3)},
+		{"ValueIntegralFloatToInt32", NewCommandValueBuilder().Value(float64(-42), Int32), Int32, "-42"},
+		{"ValueIntegralFloatToUint16", NewCommandValueBuilder().Value(float32(42), Uint16), Uint16, "42"},
+		{"PrecisionFloat64", NewCommandValueBuilder().Float(3.14159).Precision(2), Float64, float64String(3.14)},
+		{"PrecisionFloat32", NewCommandValueBuilder().Float32(2.71828).Precision(3), Float32, float32String(2.718)},
+		{"PrecisionZero", NewCommandValueBuilder().Float(2.5).Precision(0), Float64, float64String(3)},
+		{"PrecisionBeforeValue", NewCommandValueBuilder().Precision(1).Float(-1.26), Float64, float64String(-1.3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv, err := tt.b.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cv.Type != tt.vt {
+				t.Errorf("Type %s, expected %s", cv.Type.Name(), tt.vt.Name())
+			}
+			if tt.vt != String && len(cv.NumericValue) != tt.vt.Size() {
+				t.Errorf("NumericValue of %d bytes, expected %d", len(cv.NumericValue), tt.vt.Size())
+			}
+			if s := cv.ValueToString(); s != tt.value {
+				t.Errorf("ValueToString %q, expected %q", s, tt.value)
+			}
+		})
+	}
+}
+
+func TestCommandValueBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *CommandValueBuilder
+	}{
+		{"NoValue", NewCommandValueBuilder()},
+		{"NilValue", NewCommandValueBuilder().Value(nil, Int32)},
+		{"UnknownType", NewCommandValueBuilder().Value(1, ValueType(-1))},
+		{"Uint8Overflow", NewCommandValueBuilder().Value(uint64(256), Uint8)},
+		{"Int8Overflow", NewCommandValueBuilder().Value(int64(128), Int8)},
+		{"Int8Underflow", NewCommandValueBuilder().Value(int64(-129), Int8)},
+		{"NegativeUint", NewCommandValueBuilder().Value(-1, Uint32)},
+		{"Uint64ToInt64Overflow", NewCommandValueBuilder().Value(uint64(math.MaxUint64), Int64)},
+		{"FractionalToInt", NewCommandValueBuilder().Value(1.5, Int16)},
+		{"InfToInt", NewCommandValueBuilder().Value(math.Inf(1), Int64)},
+		{"FloatToUint64Overflow", NewCommandValueBuilder().Value(float64(math.MaxUint64), Uint64)},
+		{"Float32Overflow", NewCommandValueBuilder().Value(math.MaxFloat64, Float32)},
+		{"StringToInt", NewCommandValueBuilder().Value("1", Int32)},
+		{"IntToString", NewCommandValueBuilder().Value(1, String)},
+		{"IntToBool", NewCommandValueBuilder().Value(1, Bool)},
+		{"BoolToInt", NewCommandValueBuilder().Value(true, Uint8)},
+		{"PrecisionOfInt", NewCommandValueBuilder().Int32(1).Precision(2)},
+		{"PrecisionOfString", NewCommandValueBuilder().String("a").Precision(2)},
+		{"NegativePrecision", NewCommandValueBuilder().Float(1).Precision(-2)},
+		{"FirstErrorKept", NewCommandValueBuilder().Value(uint64(256), Uint8).Uint8(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cv, err := tt.b.Build(); err == nil {
+				t.Errorf("expected an error, got %v", cv)
+			}
+		})
+	}
+}
+
+func TestCommandValueBuilderResource(t *testing.T) {
+	ro := &models.ResourceOperation{Object: "Switch"}
+	cv, err := NewCommandValueBuilder().Resource(ro).Origin(1234).Bool(true).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cv.RO != ro {
+		t.Errorf("RO %v, expected %v", cv.RO, ro)
+	}
+	if cv.Origin != 1234 {
+		t.Errorf("Origin %d, expected 1234", cv.Origin)
+	}
+}
+
+// NewCommandValue used to encode the values parsed by strconv with their
+// 64 bits layout whatever the Type.
+func TestNewCommandValueLayout(t *testing.T) {
+	types := []ValueType{Uint8, Uint16, Uint32, Uint64, Int8, Int16, Int32, Int64}
+	for _, vt := range types {
+		var value interface{} = int64(1)
+		if vt <= Uint64 {
+			value = uint64(1)
+		}
+		cv, err := NewCommandValue(nil, 0, value, vt)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", vt.Name(), err)
+			continue
+		}
+		if len(cv.NumericValue) != vt.Size() {
+			t.Errorf("%s: NumericValue of %d bytes, expected %d", vt.Name(), len(cv.NumericValue), vt.Size())
+		}
+		if s := cv.ValueToString(); s != "1" {
+			t.Errorf("%s: ValueToString %q, expected \"1\"", vt.Name(), s)
+		}
+	}
+
+	cv, err := NewCommandValue(nil, 0, float64(0.25), Float32)
+	if err != nil {
+		t.Fatalf("Float32: unexpected error: %v", err)
+	}
+	if v, err := cv.Float32Value(); err != nil || v != 0.25 {
+		t.Errorf("Float32: value %v, error %v", v, err)
+	}
+}
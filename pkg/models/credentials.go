@@ -0,0 +1,32 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import "errors"
+
+// ErrNoCredentials is returned by a CredentialsProvider for the Devices
+// without credentials.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Credentials authenticate the DS to a Device, e.g. the user of an SNMPv3
+// agent, the password of an MQTT broker or the update key of DNP3 secure
+// authentication. They are read from the secrets of the DS, never from
+// Core Metadata.
+type Credentials struct {
+	Username string
+	Password string
+	Key      []byte
+	// Properties are the other secrets of the protocol, by name.
+	Properties map[string]string
+}
+
+// CredentialsProvider looks up the credentials of the Devices.
+type CredentialsProvider interface {
+	// Credentials returns the credentials of the named Device, or
+	// ErrNoCredentials.
+	Credentials(deviceName string) (Credentials, error)
+}
@@ -0,0 +1,73 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+// DriverContext gives a driver access to the facilities of the SDK, without
+// importing its internal packages. Fields may be added in later versions.
+type DriverContext struct {
+	// Logger is the logging client of the DS.
+	Logger logger.LoggingClient
+	// AsyncCh is the channel of the asynchronous readings, nil if they
+	// are disabled.
+	AsyncCh chan<- *AsyncValues
+	// Config holds the Driver section of the configuration.
+	Config map[string]string
+	// StateDir is a directory persisting the state of the driver across
+	// restarts, within the state directory of the DS.
+	StateDir string
+	// Metrics registers the metrics of the driver, reported in the health
+	// of the DS.
+	Metrics MetricsRegistrar
+	// Scheduler runs the periodic jobs of the driver on the internal
+	// Scheduler of the DS.
+	Scheduler Scheduler
+	// Discovered is the channel of the Devices found by the driver, see
+	// DiscoveredDevice.
+	Discovered chan<- []DiscoveredDevice
+	// Credentials looks up the credentials of the Devices. The User and
+	// Password of the Addressables passed to the driver are already
+	// resolved from them.
+	Credentials CredentialsProvider
+}
+
+// MetricsRegistrar registers the metrics of a driver.
+type MetricsRegistrar interface {
+	// RegisterGauge registers a metric whose value is returned by gauge,
+	// called when the metrics are reported. It replaces a metric of the
+	// same name.
+	RegisterGauge(name string, gauge func() float64)
+	// Unregister removes a metric.
+	Unregister(name string)
+}
+
+// Scheduler runs the jobs of a driver.
+type Scheduler interface {
+	// Schedule runs job according to a cron spec, with seconds, or a
+	// descriptor such as "@every 30s". It replaces a job of the same name.
+	Schedule(name string, spec string, job func()) error
+	// Unschedule removes a job.
+	Unschedule(name string)
+}
+
+// ConfigUpdater may optionally be implemented by a ProtocolDriver to apply
+// changes of its settings at runtime, e.g. timeouts or retries, made in the
+// Writable section of the configuration held by the registry. UpdateConfig
+// is passed all the settings of the driver, as updated.
+type ConfigUpdater interface {
+	UpdateConfig(config map[string]string) error
+}
+
+// ContextInitializer may optionally be implemented by a ProtocolDriver to be
+// initialized with a DriverContext, in which case it is called instead of
+// Initialize.
+type ContextInitializer interface {
+	InitializeWithContext(ctx DriverContext) error
+}
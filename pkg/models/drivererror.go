@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import "fmt"
+
+// ErrorCategory classifies the errors of the drivers, the SDK mapping each
+// category to the HTTP status of the command responses.
+type ErrorCategory string
+
+const (
+	// ErrorTimeout is a Device not answering in time.
+	ErrorTimeout ErrorCategory = "Timeout"
+	// ErrorProtocolException is a Device rejecting a request, e.g. a Modbus
+	// exception response.
+	ErrorProtocolException ErrorCategory = "ProtocolException"
+	// ErrorNotSupported is an operation the Device or the driver doesn't
+	// support.
+	ErrorNotSupported ErrorCategory = "NotSupported"
+	// ErrorDeviceOffline is a Device which can't be reached at all, e.g. a
+	// refused connection.
+	ErrorDeviceOffline ErrorCategory = "DeviceOffline"
+)
+
+// DriverError is an error of a driver the SDK reports in the command
+// responses, with its category and the protocol-specific code, e.g.
+// Code "0x02" and Description "Illegal Data Address" for a Modbus
+// exception.
+type DriverError struct {
+	Category    ErrorCategory
+	Code        string
+	Description string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// NewDriverError returns a DriverError of the given category, without a
+// protocol-specific code.
+func NewDriverError(category ErrorCategory, err error) *DriverError {
+	return &DriverError{Category: category, Err: err}
+}
+
+// NewProtocolError returns a DriverError for an exception of the protocol.
+func NewProtocolError(code string, description string, err error) *DriverError {
+	return &DriverError{Category: ErrorProtocolException, Code: code, Description: description, Err: err}
+}
+
+func (e *DriverError) Error() string {
+	msg := string(e.Category)
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s %s", msg, e.Code)
+	}
+	if e.Description != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Description)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
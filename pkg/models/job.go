@@ -0,0 +1,52 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import "time"
+
+// Job is a multi-step protocol sequence, such as the readout of a meter
+// table (select block, read N chunks, verify checksum, clear flag). The
+// ProtocolDriver implements the steps, while the SDK runs them in order,
+// retries a failed step and reports the progress.
+type Job struct {
+	// ID identifies the job. Submitting a job with the ID of an interrupted
+	// one resumes it from its last checkpoint.
+	ID string
+	// DeviceName is the name of the Device the job runs against.
+	DeviceName string
+	// Steps are run in order.
+	Steps []JobStep
+	// MaxAttempts is the number of times a failing step is run before the
+	// job fails. Zero means a single attempt.
+	MaxAttempts int
+	// RetryInterval is the time to wait before running a failed step again.
+	RetryInterval time.Duration
+}
+
+// JobStep is a step of a Job. Run is called again when resuming the step,
+// so long running steps should record their progress with checkpoints.
+type JobStep struct {
+	Name string
+	Run  func(ctx JobContext) error
+}
+
+// JobContext gives a running JobStep access to the state of its Job.
+type JobContext interface {
+	// Checkpoint records a value, persisted so that it is available to the
+	// steps of the Job even after a communication failure or a restart.
+	Checkpoint(key string, value string) error
+	// Value returns a value recorded by Checkpoint.
+	Value(key string) (string, bool)
+	// Progress reports the progress of the step, e.g. chunks read so far.
+	Progress(done int, total int)
+	// Attempt returns the number of the current attempt of the step,
+	// starting at 1.
+	Attempt() int
+	// Canceled returns whether the Job was canceled or the DS is stopping,
+	// in which case the step should return as soon as possible.
+	Canceled() bool
+}
@@ -0,0 +1,32 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// Connection states of a peer
+const (
+	PeerConnected    = "connected"
+	PeerDisconnected = "disconnected"
+	PeerWaiting      = "waiting"
+)
+
+// PeerStatus is the state of the connection to a peer of a driver.
+type PeerStatus struct {
+	// Address identifies the peer, e.g. "host:502".
+	Address string `json:"address"`
+	// State is PeerConnected, PeerDisconnected, or PeerWaiting if the
+	// reconnection is delayed until NextAttempt.
+	State string `json:"state"`
+	// Strategy is the reconnect strategy of the peer.
+	Strategy string `json:"strategy"`
+	// Failures is the number of consecutive failed connections.
+	Failures int `json:"failures"`
+	// LastError is the error of the last failed connection.
+	LastError string `json:"lastError,omitempty"`
+	// NextAttempt is the earliest time of the next connection, in
+	// milliseconds since the epoch, if waiting.
+	NextAttempt int64 `json:"nextAttempt,omitempty"`
+}
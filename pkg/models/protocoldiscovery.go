@@ -6,6 +6,10 @@
 
 package models
 
+import (
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
 // ProtocolDiscovery is a low-level device-specific interface implemented
 // by device services that support dynamic device discovery.
 type ProtocolDiscovery interface {
@@ -15,6 +19,32 @@ type ProtocolDiscovery interface {
 	// config. This function may also optionally trigger sensor
 	// discovery, which could result in dynamic device profile creation.
 	//
-	// TODO: add models.ScanList (or define locally) for devices
+	// The devices returned are ignored; a driver implementing
+	// DeviceDiscovery returns them to the SDK instead.
 	Discover() (devices *interface{}, err error)
 }
+
+// DeviceDiscovery is implemented by the drivers returning the Devices found
+// by a discovery, the SDK deciding whether to provision them.
+type DeviceDiscovery interface {
+	// DiscoverDevices runs a protocol specific discovery, synchronously,
+	// and returns the Devices found.
+	DiscoverDevices() ([]DiscoveredDevice, error)
+}
+
+// DiscoveredDevice is a Device found by a driver, returned by
+// DiscoverDevices or sent on the Discovered channel of the DriverContext,
+// e.g. when a device announces itself. It is provisioned if its Identifiers
+// match a provision watcher, with the profile and name given by the watcher.
+type DiscoveredDevice struct {
+	// Name is the name of the Device as known by the driver, e.g. reported
+	// by the device, as opposed to the name given by the watcher.
+	Name string
+	// Identifiers are the protocol identifiers of the Device, e.g. its
+	// address, model and serial number.
+	Identifiers map[string]string
+	Description string
+	Labels      []string
+	// Addressable is how the driver reaches the Device.
+	Addressable models.Addressable
+}
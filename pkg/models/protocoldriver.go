@@ -40,12 +40,13 @@ type ProtocolDriver interface {
 
 	// HandleReadCommands passes a slice of CommandRequest struct each representing
 	// a ResourceOperation for a specific device resource (aka DeviceObject).
+	// A *DriverError sets the HTTP status of the command response.
 	HandleReadCommands(addr *models.Addressable, reqs []CommandRequest) ([]*CommandValue, error)
 
 	// HandleWriteCommands passes a slice of CommandRequest struct each representing
 	// a ResourceOperation for a specific device resource (aka DeviceObject).
 	// Since the commands are actuation commands, params provide parameters for the individual
-	// command.
+	// command. A *DriverError sets the HTTP status of the command response.
 	HandleWriteCommands(addr *models.Addressable, reqs []CommandRequest, params []*CommandValue) error
 
 	// Stop instructs the protocol-specific DS code to shutdown gracefully, or
@@ -54,3 +55,43 @@ type ProtocolDriver interface {
 	// readings (if supported).
 	Stop(force bool) error
 }
+
+// LivenessChecker may optionally be implemented by a ProtocolDriver to report
+// whether it is still responsive. It is part of the liveness check which
+// drives the systemd watchdog.
+type LivenessChecker interface {
+	// CheckLiveness returns an error if the driver is wedged, e.g. if its
+	// bus or connections are stuck. It must return promptly.
+	CheckLiveness() error
+}
+
+// DeviceLifecycleHandler may optionally be implemented by a ProtocolDriver to
+// be notified of the changes of the Devices of the DS, e.g. to set up or tear
+// down their connections, without polling the Device list.
+type DeviceLifecycleHandler interface {
+	// AddDevice is called when a Device is added. An error rejects the
+	// Device, e.g. if its protocol properties are invalid.
+	AddDevice(device models.Device) error
+	// UpdateDevice is called when a Device is updated. An error rejects
+	// the update.
+	UpdateDevice(device models.Device) error
+	// RemoveDevice is called when a Device is removed.
+	RemoveDevice(device models.Device) error
+}
+
+// PeerReporter may optionally be implemented by a ProtocolDriver to report
+// the state of the connections to its peers, e.g. Modbus TCP servers, which
+// is part of the health of the DS.
+type PeerReporter interface {
+	// Peers returns the state of the connection to each peer.
+	Peers() []PeerStatus
+}
+
+// DeviceNameSanitizer may optionally be implemented by a ProtocolDriver to
+// sanitize the names of the Devices created by discovery or import, e.g. to
+// shorten serial numbers, before the DeviceNames settings are applied.
+type DeviceNameSanitizer interface {
+	// SanitizeDeviceName returns the name to give a Device. An error rejects
+	// the Device.
+	SanitizeDeviceName(name string) (string, error)
+}
@@ -0,0 +1,89 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+var valueTypeNames = map[ValueType]string{
+	Bool:    "Bool",
+	String:  "String",
+	Uint8:   "Uint8",
+	Uint16:  "Uint16",
+	Uint32:  "Uint32",
+	Uint64:  "Uint64",
+	Int8:    "Int8",
+	Int16:   "Int16",
+	Int32:   "Int32",
+	Int64:   "Int64",
+	Float32: "Float32",
+	Float64: "Float64",
+
+	BoolArray:    "BoolArray",
+	Uint8Array:   "Uint8Array",
+	Uint16Array:  "Uint16Array",
+	Uint32Array:  "Uint32Array",
+	Uint64Array:  "Uint64Array",
+	Int8Array:    "Int8Array",
+	Int16Array:   "Int16Array",
+	Int32Array:   "Int32Array",
+	Int64Array:   "Int64Array",
+	Float32Array: "Float32Array",
+	Float64Array: "Float64Array",
+	Binary:       "Binary",
+}
+
+// ParseValueType returns the ValueType of the given (case insensitive) name,
+// as used for the Type of a PropertyValue, e.g. "Uint16".
+func ParseValueType(name string) (ValueType, error) {
+	for t, n := range valueTypeNames {
+		if strings.EqualFold(name, n) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported value type %s", name)
+}
+
+// Name returns the name of the ValueType.
+func (t ValueType) Name() string {
+	return valueTypeNames[t]
+}
+
+// IsArray returns whether the ValueType is an array.
+func (t ValueType) IsArray() bool {
+	return t >= BoolArray && t <= Float64Array
+}
+
+// ElementType returns the type of the elements of an array ValueType.
+func (t ValueType) ElementType() (ValueType, bool) {
+	if !t.IsArray() {
+		return 0, false
+	}
+	if t == BoolArray {
+		return Bool, true
+	}
+	// the numeric types follow the same order as their arrays
+	return Uint8 + (t - Uint8Array), true
+}
+
+// Size returns the size in bytes of a numeric ValueType, or 0 for String,
+// Binary and the arrays.
+func (t ValueType) Size() int {
+	switch t {
+	case Bool, Uint8, Int8:
+		return 1
+	case Uint16, Int16:
+		return 2
+	case Uint32, Int32, Float32:
+		return 4
+	case Uint64, Int64, Float64:
+		return 8
+	}
+	return 0
+}
@@ -0,0 +1,308 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// MQTT 3.1.1 control packet types
+const (
+	mqttConnect    byte = 0x10
+	mqttConnack    byte = 0x20
+	mqttPublish    byte = 0x30
+	mqttPuback     byte = 0x40
+	mqttPingreq    byte = 0xc0
+	mqttPingresp   byte = 0xd0
+	mqttDisconnect byte = 0xe0
+)
+
+const (
+	mqttDefaultKeepAlive = 60
+	mqttAckTimeout       = 10 * time.Second
+)
+
+var errMQTTClosed = errors.New("mqtt: connection closed")
+
+// mqttPublisher publishes events to an MQTT broker with QoS 0 or 1,
+// connecting on demand, so that a broker outage is recovered from by the
+// next event.
+type mqttPublisher struct {
+	cfg   Config
+	mutex sync.Mutex
+	conn  *mqttConn
+}
+
+// mqttConn is a connection to the broker.
+type mqttConn struct {
+	conn     net.Conn
+	writeMux sync.Mutex
+	acks     map[uint16]chan struct{}
+	ackMux   sync.Mutex
+	nextID   uint16
+	done     chan struct{}
+	err      error
+}
+
+func newMQTTPublisher(cfg Config) (Publisher, error) {
+	if cfg.QoS < 0 || cfg.QoS > 1 {
+		return nil, fmt.Errorf("mqtt: unsupported QoS %d, expected 0 or 1", cfg.QoS)
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = mqttDefaultKeepAlive
+	}
+	return &mqttPublisher{cfg: cfg}, nil
+}
+
+func (p *mqttPublisher) Publish(topic string, event *models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	c := p.conn
+	if c == nil || c.closed() {
+		if c, err = p.connect(); err != nil {
+			p.mutex.Unlock()
+			return err
+		}
+		p.conn = c
+	}
+	p.mutex.Unlock()
+
+	if err = c.publish(topic, payload, byte(p.cfg.QoS), p.cfg.Retained); err != nil {
+		c.close(err)
+	}
+	return err
+}
+
+func (p *mqttPublisher) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn != nil && !p.conn.closed() {
+		p.conn.write([]byte{mqttDisconnect, 0})
+		p.conn.close(errMQTTClosed)
+	}
+	p.conn = nil
+	return nil
+}
+
+// connect opens a connection and performs the MQTT handshake.
+func (p *mqttPublisher) connect() (*mqttConn, error) {
+	addr := net.JoinHostPort(p.cfg.Host, strconv.Itoa(p.cfg.Port))
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: mqttAckTimeout}
+	if p.cfg.Protocol == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: p.cfg.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var flags byte = 0x02 // clean session
+	payload := mqttString(p.cfg.ClientID)
+	if p.cfg.Username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(p.cfg.Username)...)
+		if p.cfg.Password != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(p.cfg.Password)...)
+		}
+	}
+	body := append(mqttString("MQTT"), 4, flags, byte(p.cfg.KeepAlive>>8), byte(p.cfg.KeepAlive))
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(mqttAckTimeout))
+	r := bufio.NewReader(conn)
+	if _, err = conn.Write(mqttPacket(mqttConnect, body)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	typ, ack, err := mqttRead(r)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if typ != mqttConnack || len(ack) != 2 || ack[1] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt: connection refused by %s: %v", addr, ack)
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := &mqttConn{conn: conn, acks: make(map[uint16]chan struct{}), done: make(chan struct{})}
+	go c.receive(r)
+	go c.keepAlive(time.Duration(p.cfg.KeepAlive) * time.Second)
+	return c, nil
+}
+
+func (c *mqttConn) publish(topic string, payload []byte, qos byte, retain bool) error {
+	header := mqttPublish | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := mqttString(topic)
+
+	var ack chan struct{}
+	if qos > 0 {
+		c.ackMux.Lock()
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID = 1
+		}
+		id := c.nextID
+		ack = make(chan struct{})
+		c.acks[id] = ack
+		c.ackMux.Unlock()
+		body = append(body, byte(id>>8), byte(id))
+	}
+	if err := c.write(mqttPacket(header, append(body, payload...))); err != nil {
+		return err
+	}
+	if ack == nil {
+		return nil
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-c.done:
+		return c.err
+	case <-time.After(mqttAckTimeout):
+		return errors.New("mqtt: publish not acknowledged")
+	}
+}
+
+func (c *mqttConn) write(packet []byte) error {
+	c.writeMux.Lock()
+	defer c.writeMux.Unlock()
+
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+// receive handles the acknowledgements until the connection is closed.
+func (c *mqttConn) receive(r *bufio.Reader) {
+	for {
+		typ, body, err := mqttRead(r)
+		if err != nil {
+			c.close(err)
+			return
+		}
+		if typ == mqttPuback && len(body) == 2 {
+			id := binary.BigEndian.Uint16(body)
+			c.ackMux.Lock()
+			if ack, ok := c.acks[id]; ok {
+				close(ack)
+				delete(c.acks, id)
+			}
+			c.ackMux.Unlock()
+		}
+	}
+}
+
+// keepAlive pings the broker, which otherwise closes an idle connection.
+func (c *mqttConn) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.write([]byte{mqttPingreq, 0}); err != nil {
+				c.close(err)
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *mqttConn) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *mqttConn) close(err error) {
+	c.ackMux.Lock()
+	defer c.ackMux.Unlock()
+
+	if !c.closed() {
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+	}
+}
+
+// mqttString returns a length-prefixed UTF-8 string.
+func mqttString(s string) []byte {
+	return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+}
+
+// mqttPacket returns a control packet with the given header and body.
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// mqttRead reads a control packet, returning its type and body.
+func mqttRead(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("mqtt: malformed remaining length")
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return header & 0xf0, body, err
+}
@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package publishes the events of the device service to a message
+// queue, alternatively or additionally to pushing them to Core Data. MQTT
+// is built in, and other transports can be registered by name.
+package publisher
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Config configures a Publisher.
+type Config struct {
+	// Type selects the transport, e.g. "mqtt".
+	Type string
+	Host string
+	Port int
+	// Protocol is "tcp", or "tls" for an encrypted connection.
+	Protocol string
+	ClientID string
+	Username string
+	Password string
+	// QoS is the quality of service of the published messages.
+	QoS int
+	// Retained requests the broker to retain the last message per topic.
+	Retained bool
+	// Topic is the topic template, in which {service}, {device} and
+	// {tenant} are replaced, e.g. "edgex/{service}/{device}".
+	Topic string
+	// KeepAlive is the keep alive interval, in seconds.
+	KeepAlive int
+}
+
+// Publisher publishes events.
+type Publisher interface {
+	// Publish publishes an event to the given topic.
+	Publish(topic string, event *models.Event) error
+	// Close releases the connection of the Publisher.
+	Close() error
+}
+
+// Factory creates a Publisher.
+type Factory func(cfg Config) (Publisher, error)
+
+var (
+	mutex     sync.Mutex
+	factories = map[string]Factory{
+		"mqtt": newMQTTPublisher,
+	}
+)
+
+// Register registers the Factory of a transport, replacing any with the
+// same name.
+func Register(name string, f Factory) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	factories[strings.ToLower(name)] = f
+}
+
+// New creates a Publisher for the configured transport.
+func New(cfg Config) (Publisher, error) {
+	mutex.Lock()
+	f, ok := factories[strings.ToLower(cfg.Type)]
+	mutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown publisher type %s", cfg.Type)
+	}
+	return f(cfg)
+}
+
+// Topic returns the topic of an event, replacing the placeholders of the
+// given template.
+func Topic(template string, service string, tenant string, event *models.Event) string {
+	return strings.NewReplacer("{service}", service, "{device}", event.Device, "{tenant}", tenant).Replace(template)
+}
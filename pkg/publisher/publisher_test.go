@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// message is a PUBLISH packet received by the broker.
+type message struct {
+	header  byte
+	topic   string
+	payload []byte
+}
+
+// broker accepts a connection and acknowledges the packets it receives.
+func broker(ln net.Listener, messages chan<- message) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		header, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		r.UnreadByte()
+		typ, body, err := mqttRead(r)
+		if err != nil {
+			return
+		}
+		switch typ {
+		case mqttConnect:
+			conn.Write([]byte{mqttConnack, 2, 0, 0})
+		case mqttPublish:
+			n := int(binary.BigEndian.Uint16(body))
+			m := message{header: header, topic: string(body[2 : 2+n])}
+			body = body[2+n:]
+			if header&0x06 != 0 {
+				conn.Write([]byte{mqttPuback, 2, body[0], body[1]})
+				body = body[2:]
+			}
+			m.payload = body
+			messages <- m
+		}
+	}
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	messages := make(chan message, 1)
+	go broker(ln, messages)
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	p, err := New(Config{Type: "MQTT", Host: "127.0.0.1", Port: port, ClientID: "test", QoS: 1, Retained: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	event := &models.Event{Device: "meter", Readings: []models.Reading{{Name: "Energy", Value: "10"}}}
+	topic := Topic("edgex/{service}/{device}", "device-simple", "", event)
+	if err = p.Publish(topic, event); err != nil {
+		t.Fatal(err)
+	}
+
+	m := <-messages
+	if m.topic != "edgex/device-simple/meter" || m.header != mqttPublish|0x02|0x01 {
+		t.Errorf("Unexpected message %s, %x", m.topic, m.header)
+	}
+	var received models.Event
+	if err = json.Unmarshal(m.payload, &received); err != nil || received.Device != "meter" {
+		t.Errorf("Unexpected payload %s, %v", m.payload, err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Type: "amqp"}); err == nil {
+		t.Error("Unknown type accepted")
+	}
+	if _, err := New(Config{Type: "mqtt", QoS: 2}); err == nil {
+		t.Error("Unsupported QoS accepted")
+	}
+}
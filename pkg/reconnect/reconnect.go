@@ -0,0 +1,278 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package paces the reconnections of drivers to their peers, e.g.
+// Modbus TCP servers, immediately or with an exponential backoff, and
+// possibly only within scheduled windows, for remote meters which only
+// accept connections at certain times of the day.
+package reconnect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Reconnect strategies
+const (
+	Immediate = "immediate"
+	Backoff   = "backoff"
+)
+
+// Setting names, e.g. in the Driver section of the configuration, or the
+// settings of a peer.
+const (
+	SettingStrategy = "ReconnectStrategy"
+	SettingDelay    = "ReconnectDelay"
+	SettingMaxDelay = "ReconnectMaxDelay"
+	SettingWindows  = "ReconnectWindows"
+)
+
+// Window is a daily time window, in local time, as offsets from midnight.
+// It spans midnight if End is before Start.
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Policy defines when a peer is reconnected after a failure.
+type Policy struct {
+	// Strategy is Immediate or Backoff, the delay doubling at each
+	// consecutive failure.
+	Strategy string
+	// Delay is the delay after the first failure, with Backoff.
+	Delay time.Duration
+	// MaxDelay caps the delay. Zero means unlimited.
+	MaxDelay time.Duration
+	// Windows restrict the connections to these times of the day, if any.
+	Windows []Window
+}
+
+// DefaultPolicy reconnects immediately, at any time.
+var DefaultPolicy = Policy{Strategy: Immediate}
+
+// ParseWindow parses a window as "HH:MM-HH:MM".
+func ParseWindow(s string) (Window, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("invalid window %q, expected HH:MM-HH:MM", s)
+	}
+	var w Window
+	for i, bound := range []*time.Duration{&w.Start, &w.End} {
+		t, err := time.Parse("15:04", strings.TrimSpace(parts[i]))
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid window %q, expected HH:MM-HH:MM", s)
+		}
+		*bound = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return w, nil
+}
+
+// Parse returns the base Policy overridden by the given settings, delays
+// being in milliseconds and windows comma-separated.
+func Parse(base Policy, settings map[string]string) (Policy, error) {
+	p := base
+	var err error
+	for name, v := range settings {
+		v = strings.TrimSpace(v)
+		switch name {
+		case SettingStrategy:
+			p.Strategy = strings.ToLower(v)
+			if p.Strategy != Immediate && p.Strategy != Backoff {
+				err = fmt.Errorf("expected %s or %s", Immediate, Backoff)
+			}
+		case SettingDelay:
+			p.Delay, err = parseMillis(v)
+		case SettingMaxDelay:
+			p.MaxDelay, err = parseMillis(v)
+		case SettingWindows:
+			p.Windows = nil
+			for _, s := range strings.Split(v, ",") {
+				if strings.TrimSpace(s) == "" {
+					continue
+				}
+				var w Window
+				if w, err = ParseWindow(s); err != nil {
+					break
+				}
+				p.Windows = append(p.Windows, w)
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return base, fmt.Errorf("invalid reconnect setting %s %q: %v", name, v, err)
+		}
+	}
+	return p, nil
+}
+
+func parseMillis(v string) (time.Duration, error) {
+	ms, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("negative value")
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// DelayFor returns the delay after the given number of consecutive
+// failures.
+func (p Policy) DelayFor(failures int) time.Duration {
+	if p.Strategy != Backoff || failures == 0 {
+		return 0
+	}
+	delay := p.Delay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// NextWindow returns t if it's within a window, or else the start of the
+// next window.
+func (p Policy) NextWindow(t time.Time) time.Time {
+	if len(p.Windows) == 0 {
+		return t
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	var next time.Time
+	for _, w := range p.Windows {
+		if w.contains(offset) {
+			return t
+		}
+		start := midnight.Add(w.Start)
+		if w.Start <= offset {
+			start = midnight.AddDate(0, 0, 1).Add(w.Start)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+func (w Window) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// NotAllowedError is returned by Peer.Allow while a reconnection is
+// delayed.
+type NotAllowedError struct {
+	Address     string
+	NextAttempt time.Time
+}
+
+func (e NotAllowedError) Error() string {
+	return fmt.Sprintf("reconnection to %s delayed until %s", e.Address, e.NextAttempt.Format(time.RFC3339))
+}
+
+// Peer tracks the connection state of a peer, applying its Policy.
+type Peer struct {
+	address string
+	policy  Policy
+
+	mutex       sync.Mutex
+	connected   bool
+	failures    int
+	lastError   string
+	nextAttempt time.Time
+}
+
+// NewPeer returns the state of a disconnected peer.
+func NewPeer(address string, policy Policy) *Peer {
+	return &Peer{address: address, policy: policy}
+}
+
+// Allow returns a NotAllowedError if the peer may not be connected at t.
+func (p *Peer) Allow(t time.Time) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	next := p.policy.NextWindow(maxTime(t, p.nextAttempt))
+	if next.After(t) {
+		return NotAllowedError{Address: p.address, NextAttempt: next}
+	}
+	return nil
+}
+
+// Connected records a successful connection.
+func (p *Peer) Connected() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.connected = true
+	p.failures = 0
+	p.lastError = ""
+	p.nextAttempt = time.Time{}
+}
+
+// Failed records a failed connection at t, delaying the next one.
+func (p *Peer) Failed(t time.Time, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.connected = false
+	p.failures++
+	p.lastError = err.Error()
+	p.nextAttempt = t.Add(p.policy.DelayFor(p.failures))
+}
+
+// Disconnected records the loss of a connection, which may be
+// reconnected immediately.
+func (p *Peer) Disconnected(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.connected = false
+	if err != nil {
+		p.lastError = err.Error()
+	}
+}
+
+// Status returns the state of the peer at t.
+func (p *Peer) Status(t time.Time) ds_models.PeerStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	status := ds_models.PeerStatus{
+		Address:   p.address,
+		State:     ds_models.PeerDisconnected,
+		Strategy:  p.policy.Strategy,
+		Failures:  p.failures,
+		LastError: p.lastError,
+	}
+	if p.connected {
+		status.State = ds_models.PeerConnected
+	} else if next := p.policy.NextWindow(maxTime(t, p.nextAttempt)); next.After(t) {
+		status.State = ds_models.PeerWaiting
+		status.NextAttempt = next.UnixNano() / int64(time.Millisecond)
+	}
+	return status
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reconnect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse(DefaultPolicy, map[string]string{
+		SettingStrategy: "Backoff",
+		SettingDelay:    "1000",
+		SettingMaxDelay: "8000",
+		SettingWindows:  "01:00-02:30, 23:00-00:30",
+		"PoolSize":      "2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Strategy != Backoff || p.Delay != time.Second || p.MaxDelay != 8*time.Second || len(p.Windows) != 2 {
+		t.Errorf("Unexpected policy %+v", p)
+	}
+	if p.Windows[1] != (Window{Start: 23 * time.Hour, End: 30 * time.Minute}) {
+		t.Errorf("Unexpected window %+v", p.Windows[1])
+	}
+
+	if _, err := Parse(DefaultPolicy, map[string]string{SettingWindows: "1-2"}); err == nil {
+		t.Errorf("Expected an error for an invalid window")
+	}
+	if _, err := Parse(DefaultPolicy, map[string]string{SettingStrategy: "never"}); err == nil {
+		t.Errorf("Expected an error for an invalid strategy")
+	}
+}
+
+func TestDelayFor(t *testing.T) {
+	p := Policy{Strategy: Backoff, Delay: time.Second, MaxDelay: 5 * time.Second}
+	for failures, want := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if d := p.DelayFor(failures); d != want {
+			t.Errorf("Expected %v after %d failures, got %v", want, failures, d)
+		}
+	}
+	if d := DefaultPolicy.DelayFor(3); d != 0 {
+		t.Errorf("Expected immediate reconnection, got %v", d)
+	}
+}
+
+func TestNextWindow(t *testing.T) {
+	p := Policy{Windows: []Window{{Start: 2 * time.Hour, End: 3 * time.Hour}, {Start: 23 * time.Hour, End: time.Hour}}}
+	at := func(h, m int) time.Time { return time.Date(2018, 6, 1, h, m, 0, 0, time.UTC) }
+
+	if next := p.NextWindow(at(2, 30)); !next.Equal(at(2, 30)) {
+		t.Errorf("Expected to be within the window, got %v", next)
+	}
+	if next := p.NextWindow(at(0, 30)); !next.Equal(at(0, 30)) {
+		t.Errorf("Expected to be within the window spanning midnight, got %v", next)
+	}
+	if next := p.NextWindow(at(1, 0)); !next.Equal(at(2, 0)) {
+		t.Errorf("Expected the next window at 02:00, got %v", next)
+	}
+	if next := p.NextWindow(at(12, 0)); !next.Equal(at(23, 0)) {
+		t.Errorf("Expected the next window at 23:00, got %v", next)
+	}
+}
+
+func TestPeer(t *testing.T) {
+	now := time.Now()
+	peer := NewPeer("meter:502", Policy{Strategy: Backoff, Delay: time.Minute})
+	if err := peer.Allow(now); err != nil {
+		t.Fatal(err)
+	}
+
+	peer.Failed(now, errors.New("connection refused"))
+	if _, ok := peer.Allow(now).(NotAllowedError); !ok {
+		t.Errorf("Expected the reconnection to be delayed")
+	}
+	status := peer.Status(now)
+	if status.State != ds_models.PeerWaiting || status.Failures != 1 || status.LastError != "connection refused" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if err := peer.Allow(now.Add(time.Minute)); err != nil {
+		t.Errorf("Expected the reconnection to be allowed after the delay, got %v", err)
+	}
+
+	peer.Connected()
+	if status := peer.Status(now); status.State != ds_models.PeerConnected || status.Failures != 0 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	peer.Disconnected(nil)
+	if status := peer.Status(now); status.State != ds_models.PeerDisconnected {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
@@ -0,0 +1,157 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package retries the failed operations of drivers, e.g. Modbus
+// transactions, with a configurable backoff between attempts.
+package retry
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backoff strategies
+const (
+	Fixed       = "fixed"
+	Exponential = "exponential"
+)
+
+// Setting names, e.g. in the Driver section of the configuration.
+const (
+	SettingMaxRetries = "MaxRetries"
+	SettingBackoff    = "RetryBackoff"
+	SettingDelay      = "RetryDelay"
+	SettingMaxDelay   = "RetryMaxDelay"
+	SettingJitter     = "RetryJitter"
+)
+
+// Policy defines how a failed operation is retried.
+type Policy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Backoff is Fixed or Exponential, the delay doubling at each retry.
+	Backoff string
+	// Delay is the delay before the first retry.
+	Delay time.Duration
+	// MaxDelay caps the exponential delay. Zero means unlimited.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay which is randomized, spreading
+	// the retries of several clients, from 0 to 1.
+	Jitter float64
+}
+
+// DefaultPolicy retries twice, 100ms apart.
+var DefaultPolicy = Policy{MaxRetries: 2, Backoff: Fixed, Delay: 100 * time.Millisecond}
+
+// permanentError is an error which isn't retried.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Permanent marks an error as not worth retrying, e.g. an exception
+// response of a device.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Do calls op until it succeeds, returns a Permanent error or the retries
+// are exhausted, and returns its last error.
+func (p Policy) Do(op func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = op()
+		if err == nil {
+			return nil
+		}
+		if perm, ok := err.(permanentError); ok {
+			return perm.err
+		}
+		if attempt >= p.MaxRetries {
+			return err
+		}
+		time.Sleep(p.DelayFor(attempt))
+	}
+}
+
+// DelayFor returns the delay before the given retry, counted from 0.
+func (p Policy) DelayFor(retry int) time.Duration {
+	delay := p.Delay
+	if p.Backoff == Exponential {
+		for i := 0; i < retry; i++ {
+			delay *= 2
+			if p.MaxDelay > 0 && delay >= p.MaxDelay {
+				break
+			}
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 && delay > 0 {
+		spread := time.Duration(p.Jitter * float64(delay))
+		delay += time.Duration(rand.Int63n(int64(spread)+1)) - spread/2
+	}
+	return delay
+}
+
+// Parse returns the base Policy overridden by the given settings, delays
+// being in milliseconds, so that a driver can layer its defaults, the
+// Driver section of the configuration and the settings of a Device.
+func Parse(base Policy, settings map[string]string) (Policy, error) {
+	p := base
+	var err error
+	for name, v := range settings {
+		v = strings.TrimSpace(v)
+		switch name {
+		case SettingMaxRetries:
+			p.MaxRetries, err = strconv.Atoi(v)
+			if err == nil && p.MaxRetries < 0 {
+				err = fmt.Errorf("negative value")
+			}
+		case SettingBackoff:
+			p.Backoff = strings.ToLower(v)
+			if p.Backoff != Fixed && p.Backoff != Exponential {
+				err = fmt.Errorf("expected %s or %s", Fixed, Exponential)
+			}
+		case SettingDelay:
+			p.Delay, err = parseMillis(v)
+		case SettingMaxDelay:
+			p.MaxDelay, err = parseMillis(v)
+		case SettingJitter:
+			p.Jitter, err = strconv.ParseFloat(v, 64)
+			if err == nil && (p.Jitter < 0 || p.Jitter > 1) {
+				err = fmt.Errorf("expected a fraction from 0 to 1")
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return base, fmt.Errorf("invalid retry setting %s %q: %v", name, v, err)
+		}
+	}
+	return p, nil
+}
+
+func parseMillis(v string) (time.Duration, error) {
+	ms, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("negative value")
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
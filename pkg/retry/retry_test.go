@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	p := Policy{MaxRetries: 2, Backoff: Fixed, Delay: time.Millisecond}
+	calls := 0
+	err := p.Do(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Unexpected result %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = p.Do(func() error {
+		calls++
+		return errors.New("timeout")
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("Unexpected result %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	exception := errors.New("illegal address")
+	err = p.Do(func() error {
+		calls++
+		return Permanent(exception)
+	})
+	if err != exception || calls != 1 {
+		t.Errorf("Permanent error retried: %v after %d calls", err, calls)
+	}
+}
+
+func TestDelayFor(t *testing.T) {
+	p := Policy{Backoff: Exponential, Delay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for i, e := range expected {
+		if d := p.DelayFor(i); d != e*time.Millisecond {
+			t.Errorf("Retry %d: expected %v, got %v", i, e*time.Millisecond, d)
+		}
+	}
+
+	p = Policy{Backoff: Fixed, Delay: 100 * time.Millisecond, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if d := p.DelayFor(i); d < 75*time.Millisecond || d > 125*time.Millisecond {
+			t.Fatalf("Delay %v out of the jitter range", d)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse(DefaultPolicy, map[string]string{
+		SettingMaxRetries: "5",
+		SettingBackoff:    "Exponential",
+		SettingDelay:      "50",
+		SettingMaxDelay:   "1000",
+		SettingJitter:     "0.2",
+		"Other":           "ignored",
+	})
+	expected := Policy{MaxRetries: 5, Backoff: Exponential, Delay: 50 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.2}
+	if err != nil || p != expected {
+		t.Errorf("Unexpected policy %+v, %v", p, err)
+	}
+
+	for _, settings := range []map[string]string{
+		{SettingMaxRetries: "-1"},
+		{SettingBackoff: "linear"},
+		{SettingDelay: "1s"},
+		{SettingJitter: "2"},
+	} {
+		if _, err = Parse(DefaultPolicy, settings); err == nil {
+			t.Errorf("Invalid settings %v accepted", settings)
+		}
+	}
+}
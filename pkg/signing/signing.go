@@ -0,0 +1,214 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signing signs the events pushed by a device service, so that
+// tamper-evident readings (e.g. for billing) can be verified anywhere along
+// the pipeline. The signature is carried by an additional reading of the
+// event, which is excluded from the signed payload.
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+const (
+	// ReadingName is the name of the reading carrying the signature.
+	ReadingName = "Signature"
+
+	AlgorithmHMAC  = "HMAC-SHA256"
+	AlgorithmECDSA = "ECDSA-P256"
+)
+
+// ErrInvalidSignature is returned when an event doesn't match its signature.
+var ErrInvalidSignature = errors.New("invalid event signature")
+
+// Signer signs a payload.
+type Signer interface {
+	Algorithm() string
+	Sign(payload []byte) ([]byte, error)
+}
+
+// Verifier verifies the signature of a payload.
+type Verifier interface {
+	Algorithm() string
+	Verify(payload []byte, signature []byte) error
+}
+
+// HMAC signs and verifies payloads with a shared secret key.
+type HMAC struct {
+	key []byte
+}
+
+// NewHMAC returns an HMAC-SHA256 Signer and Verifier using the given key.
+func NewHMAC(key []byte) *HMAC {
+	return &HMAC{key: key}
+}
+
+func (h *HMAC) Algorithm() string {
+	return AlgorithmHMAC
+}
+
+func (h *HMAC) Sign(payload []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+func (h *HMAC) Verify(payload []byte, signature []byte) error {
+	expected, _ := h.Sign(payload)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ECDSASigner signs payloads with an ECDSA P-256 private key.
+type ECDSASigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewECDSASigner returns a Signer using the PEM encoded EC private key.
+func NewECDSASigner(pemKey []byte) (*ECDSASigner, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &ECDSASigner{key: key}, nil
+}
+
+func (s *ECDSASigner) Algorithm() string {
+	return AlgorithmECDSA
+}
+
+func (s *ECDSASigner) Sign(payload []byte) ([]byte, error) {
+	digest := sha256.Sum256(payload)
+	r, ss, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal([]*big.Int{r, ss})
+}
+
+// ECDSAVerifier verifies payloads with an ECDSA P-256 public key.
+type ECDSAVerifier struct {
+	key *ecdsa.PublicKey
+}
+
+// NewECDSAVerifier returns a Verifier using the PEM encoded public key.
+func NewECDSAVerifier(pemKey []byte) (*ECDSAVerifier, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an ECDSA key")
+	}
+	return &ECDSAVerifier{key: ecKey}, nil
+}
+
+func (v *ECDSAVerifier) Algorithm() string {
+	return AlgorithmECDSA
+}
+
+func (v *ECDSAVerifier) Verify(payload []byte, signature []byte) error {
+	var rs []*big.Int
+	if err := json.Unmarshal(signature, &rs); err != nil || len(rs) != 2 || rs[0] == nil || rs[1] == nil {
+		return ErrInvalidSignature
+	}
+	digest := sha256.Sum256(payload)
+	if !ecdsa.Verify(v.key, digest[:], rs[0], rs[1]) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type payloadReading struct {
+	Name   string `json:"name"`
+	Device string `json:"device"`
+	Value  string `json:"value"`
+	Origin int64  `json:"origin"`
+}
+
+type payloadEvent struct {
+	Device   string           `json:"device"`
+	Origin   int64            `json:"origin"`
+	Readings []payloadReading `json:"readings"`
+}
+
+// Payload returns the canonical form of the event which is signed, i.e. its
+// device, origin and readings except for the signature.
+func Payload(event *models.Event) []byte {
+	p := payloadEvent{Device: event.Device, Origin: event.Origin, Readings: make([]payloadReading, 0, len(event.Readings))}
+	for _, r := range event.Readings {
+		if r.Name == ReadingName {
+			continue
+		}
+		p.Readings = append(p.Readings, payloadReading{Name: r.Name, Device: r.Device, Value: r.Value, Origin: r.Origin})
+	}
+	b, _ := json.Marshal(p)
+	return b
+}
+
+// Sign returns a copy of the event with an additional reading holding its
+// signature. The given event isn't modified.
+func Sign(event *models.Event, s Signer) (*models.Event, error) {
+	sig, err := s.Sign(Payload(event))
+	if err != nil {
+		return nil, err
+	}
+
+	e := *event
+	e.Readings = make([]models.Reading, 0, len(event.Readings)+1)
+	for _, r := range event.Readings {
+		if r.Name != ReadingName {
+			e.Readings = append(e.Readings, r)
+		}
+	}
+	value := s.Algorithm() + ":" + base64.StdEncoding.EncodeToString(sig)
+	e.Readings = append(e.Readings, models.Reading{Name: ReadingName, Device: event.Device, Value: value, Origin: event.Origin})
+	return &e, nil
+}
+
+// Verify checks the signature reading of the event.
+func Verify(event *models.Event, v Verifier) error {
+	for _, r := range event.Readings {
+		if r.Name != ReadingName {
+			continue
+		}
+		parts := strings.SplitN(r.Value, ":", 2)
+		if len(parts) != 2 || parts[0] != v.Algorithm() {
+			return fmt.Errorf("unexpected signature algorithm in %s", r.Value)
+		}
+		sig, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		return v.Verify(Payload(event), sig)
+	}
+	return fmt.Errorf("event of device %s isn't signed", event.Device)
+}
@@ -0,0 +1,76 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func testEvent() *models.Event {
+	return &models.Event{Device: "meter", Origin: 1000, Readings: []models.Reading{
+		{Name: "Energy", Device: "meter", Value: "1234.5", Origin: 1000},
+	}}
+}
+
+func TestHMAC(t *testing.T) {
+	h := NewHMAC([]byte("secret"))
+	event := testEvent()
+	signed, err := Sign(event, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(event.Readings) != 1 || len(signed.Readings) != 2 {
+		t.Fatalf("Unexpected readings: %v, %v", event.Readings, signed.Readings)
+	}
+	if err = Verify(signed, h); err != nil {
+		t.Errorf("Signed event not verified: %v", err)
+	}
+
+	signed.Readings[0].Value = "1.0"
+	if err = Verify(signed, h); err != ErrInvalidSignature {
+		t.Errorf("Tampered event verified: %v", err)
+	}
+	if err = Verify(event, h); err == nil {
+		t.Error("Unsigned event verified")
+	}
+}
+
+func TestECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalECPrivateKey(key)
+	signer, err := NewECDSASigner(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
+	verifier, err := NewECDSAVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := Sign(testEvent(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Verify(signed, verifier); err != nil {
+		t.Errorf("Signed event not verified: %v", err)
+	}
+	signed.Origin++
+	if err = Verify(signed, verifier); err != ErrInvalidSignature {
+		t.Errorf("Tampered event verified: %v", err)
+	}
+}
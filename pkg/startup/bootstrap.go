@@ -15,6 +15,7 @@ import (
 	"syscall"
 
 	"github.com/edgexfoundry/device-sdk-go"
+	"github.com/edgexfoundry/device-sdk-go/pkg/driverhost"
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
 )
 
@@ -22,6 +23,9 @@ var (
 	confProfile string
 	confDir     string
 	useRegistry bool
+	startMode   string
+	driverRef   string
+	migrate     bool
 )
 
 // Bootstrap the Device Service in a default way
@@ -33,8 +37,36 @@ func Bootstrap(serviceName string, serviceVersion string, driver ds_models.Proto
 	flag.StringVar(&confProfile, "p", "", "Specify a profile other than default.")
 	flag.StringVar(&confDir, "confdir", "", "Specify an alternate configuration directory.")
 	flag.StringVar(&confDir, "c", "", "Specify an alternate configuration directory.")
+	flag.StringVar(&startMode, "startmode", "", "Specify the start mode (cold, warm or hot) other than configured.")
+	flag.StringVar(&startMode, "s", "", "Specify the start mode (cold, warm or hot) other than configured.")
+	flag.StringVar(&driverRef, "driver", "", "Host the driver in a Go plugin (plugin:<file>) or a separate process (exec:<command>).")
+	flag.BoolVar(&migrate, "migrate", false, "Upgrade the configuration file from an older layout and exit.")
 	flag.Parse()
 
+	if migrate {
+		changes, err := device.MigrateConfig(confProfile, confDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(changes) == 0 {
+			fmt.Fprintf(os.Stdout, "Configuration is up to date.\n")
+		}
+		for _, c := range changes {
+			fmt.Fprintf(os.Stdout, "%s\n", c)
+		}
+		os.Exit(0)
+	}
+
+	if driverRef != "" {
+		d, err := driverhost.Load(driverRef)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		driver = d
+	}
+
 	if err := startService(serviceName, serviceVersion, driver); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -47,19 +79,40 @@ func startService(serviceName string, serviceVersion string, driver ds_models.Pr
 		return err
 	}
 
-	fmt.Fprintf(os.Stdout, "Calling service.Start.\n")
-
-	if err := s.Start(); err != nil {
-		return err
+	if startMode != "" {
+		if err := s.SetStartMode(startMode); err != nil {
+			return err
+		}
 	}
-	fmt.Fprintf(os.Stdout, "Setting up signals.\n")
 
-	ch := make(chan os.Signal)
+	// a hot restart is performed on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			fmt.Fprintf(os.Stdout, "Restarting on SIGHUP signal.\n")
+			if err := s.Restart(); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+		}
+	}()
+
+	// the service is stopped gracefully on SIGINT or SIGTERM, which makes
+	// Start return once its listener is closed
+	fmt.Fprintf(os.Stdout, "Setting up signals.\n")
+	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
-	select {
-	case sig := <-ch:
+	stopped := make(chan error, 1)
+	go func() {
+		sig := <-ch
 		fmt.Fprintf(os.Stderr, "Exiting on %s signal.\n", sig)
-	}
+		stopped <- s.Stop(false)
+	}()
+
+	fmt.Fprintf(os.Stdout, "Calling service.Start.\n")
 
-	return s.Stop(false)
+	if err := s.Start(); err != nil {
+		return err
+	}
+	return <-stopped
 }
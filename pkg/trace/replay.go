@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Load reads frames in the format downloaded from the REST API.
+func Load(r io.Reader) ([]Frame, error) {
+	var frames []Frame
+	if err := json.NewDecoder(r).Decode(&frames); err != nil {
+		return nil, err
+	}
+	for i, f := range frames {
+		if _, err := hex.DecodeString(f.Data); err != nil {
+			return nil, fmt.Errorf("frame %d: %v", i, err)
+		}
+	}
+	return frames, nil
+}
+
+// LoadFile reads frames from a file downloaded from the REST API.
+func LoadFile(path string) ([]Frame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Player replays recorded frames to a driver in tests, in place of its
+// serial port or TCP connection. Each frame written by the driver must match
+// the next recorded Tx frame, and the Rx frames following it are then
+// returned by Read.
+type Player struct {
+	frames  []Frame
+	next    int
+	pending bytes.Buffer
+}
+
+// NewPlayer returns a Player replaying the given frames. Leading Rx frames,
+// e.g. recorded while the driver was idle, are skipped.
+func NewPlayer(frames []Frame) *Player {
+	p := &Player{frames: frames}
+	for p.next < len(frames) && frames[p.next].Direction != Tx {
+		p.next++
+	}
+	return p
+}
+
+// Write checks that b is the next recorded Tx frame, and queues the
+// recorded response.
+func (p *Player) Write(b []byte) (int, error) {
+	if p.next >= len(p.frames) {
+		return 0, fmt.Errorf("unexpected frame %x, the trace is over", b)
+	}
+	f := p.frames[p.next]
+	if want := f.Data; hex.EncodeToString(b) != want {
+		return 0, fmt.Errorf("frame %d: sent %x, recorded %s", p.next, b, want)
+	}
+	p.next++
+	for p.next < len(p.frames) && p.frames[p.next].Direction == Rx {
+		data, _ := hex.DecodeString(p.frames[p.next].Data)
+		p.pending.Write(data)
+		p.next++
+	}
+	return len(b), nil
+}
+
+// Read returns the recorded response to the last frame written, and io.EOF
+// once it has been read, as if the device stopped answering.
+func (p *Player) Read(b []byte) (int, error) {
+	return p.pending.Read(b)
+}
+
+// Exchange writes a request frame and returns the whole recorded response,
+// for drivers which exchange frames rather than streams.
+func (p *Player) Exchange(request []byte) ([]byte, error) {
+	if _, err := p.Write(request); err != nil {
+		return nil, err
+	}
+	response := append([]byte(nil), p.pending.Bytes()...)
+	p.pending.Reset()
+	return response, nil
+}
+
+// Close implements io.Closer.
+func (p *Player) Close() error {
+	return nil
+}
+
+// Done returns whether all the recorded frames have been replayed.
+func (p *Player) Done() bool {
+	return p.next >= len(p.frames) && p.pending.Len() == 0
+}
+
+// CompareValues returns an error describing the first difference between
+// the CommandValues decoded from a replayed trace and the expected ones,
+// ignoring their origins.
+func CompareValues(want []*ds_models.CommandValue, got []*ds_models.CommandValue) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d values, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if want[i].RO != nil && got[i].RO != nil && want[i].RO.Object != got[i].RO.Object {
+			return fmt.Errorf("value %d: got resource %s, want %s", i, got[i].RO.Object, want[i].RO.Object)
+		}
+		if got[i].Type != want[i].Type || got[i].ValueToString() != want[i].ValueToString() {
+			return fmt.Errorf("value %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+	return nil
+}
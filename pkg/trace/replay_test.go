@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// readVoltage is a minimal driver, reading two holding registers.
+func readVoltage(p *Player, ro *models.ResourceOperation) (*ds_models.CommandValue, error) {
+	response, err := p.Exchange([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x02, 0xc4, 0x0b})
+	if err != nil {
+		return nil, err
+	}
+	return ds_models.NewUint16Value(ro, 0, binary.BigEndian.Uint16(response[3:5]))
+}
+
+func TestReplay(t *testing.T) {
+	frames, err := LoadFile("testdata/modbus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro := &models.ResourceOperation{Object: "Voltage"}
+	p := NewPlayer(frames)
+	got, err := readVoltage(p, ro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ds_models.NewUint16Value(ro, 0, 230)
+	if err = CompareValues([]*ds_models.CommandValue{want}, []*ds_models.CommandValue{got}); err != nil {
+		t.Error(err)
+	}
+	if !p.Done() {
+		t.Error("Trace not fully replayed")
+	}
+
+	if _, err = p.Write([]byte{0x01}); err == nil {
+		t.Error("Frame beyond the trace accepted")
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	frames, err := LoadFile("testdata/modbus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPlayer(frames)
+	if _, err = p.Write([]byte{0x02, 0x03}); err == nil {
+		t.Error("Unexpected frame accepted")
+	}
+	if _, err = p.Write([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x02, 0xc4, 0x0b}); err != nil {
+		t.Fatal(err)
+	}
+	response, _ := ioutil.ReadAll(p)
+	if len(response) != 9 {
+		t.Errorf("Unexpected response %x", response)
+	}
+}
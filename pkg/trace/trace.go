@@ -0,0 +1,201 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package records the frames exchanged by a driver on each bus, i.e.
+// serial port or TCP peer, into ring buffers which can be downloaded through
+// the REST API. Tracing is disabled by default, and can be toggled at
+// runtime; drivers call Record unconditionally, which is cheap when
+// disabled.
+package trace
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Direction of a Frame, relative to the device service.
+type Direction string
+
+const (
+	Tx Direction = "tx"
+	Rx Direction = "rx"
+)
+
+// CRCStatus is the result of the integrity check of a received Frame.
+type CRCStatus int
+
+const (
+	// CRCNone means that the frame has no checksum, or it isn't checked.
+	CRCNone CRCStatus = iota
+	CRCOK
+	CRCError
+)
+
+// DefaultSize is the number of frames kept per bus, unless set by SetSize.
+const DefaultSize = 1000
+
+// Frame is a recorded frame.
+type Frame struct {
+	Time      time.Time `json:"time"`
+	Direction Direction `json:"direction"`
+	// Data is the hex encoded content of the frame.
+	Data string `json:"data"`
+	// Gap is the time elapsed since the previous frame on the bus, in
+	// microseconds, e.g. the response time of a received frame.
+	Gap int64 `json:"gap"`
+	// CRC is "ok" or "error", or empty if not checked.
+	CRC string `json:"crc,omitempty"`
+}
+
+// BusStatus describes the trace of a bus.
+type BusStatus struct {
+	Bus     string `json:"bus"`
+	Frames  int    `json:"frames"`
+	Dropped int    `json:"dropped"`
+}
+
+// Status describes the trace mode.
+type Status struct {
+	Enabled bool        `json:"enabled"`
+	Size    int         `json:"size"`
+	Buses   []BusStatus `json:"buses"`
+}
+
+type ring struct {
+	frames  []Frame
+	next    int
+	full    bool
+	dropped int
+	last    time.Time
+}
+
+var (
+	mutex   sync.Mutex
+	enabled bool
+	size    = DefaultSize
+	buses   = make(map[string]*ring)
+)
+
+// SetSize sets the number of frames kept per bus, discarding the frames
+// recorded so far. Zero or less selects DefaultSize.
+func SetSize(n int) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if n <= 0 {
+		n = DefaultSize
+	}
+	size = n
+	buses = make(map[string]*ring)
+}
+
+// Enable starts recording frames.
+func Enable() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	enabled = true
+}
+
+// Disable stops recording frames. The recorded ones are kept until Clear
+// is called, or tracing is enabled again.
+func Disable() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	enabled = false
+}
+
+// Enabled returns whether frames are being recorded.
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return enabled
+}
+
+// Clear discards the recorded frames.
+func Clear() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	buses = make(map[string]*ring)
+}
+
+// Record records a frame sent or received on the given bus, if tracing is
+// enabled.
+func Record(bus string, dir Direction, data []byte, crc CRCStatus) {
+	now := time.Now()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !enabled {
+		return
+	}
+	r, ok := buses[bus]
+	if !ok {
+		r = &ring{frames: make([]Frame, size)}
+		buses[bus] = r
+	}
+
+	f := Frame{Time: now, Direction: dir, Data: hex.EncodeToString(data)}
+	if !r.last.IsZero() {
+		f.Gap = int64(now.Sub(r.last) / time.Microsecond)
+	}
+	switch crc {
+	case CRCOK:
+		f.CRC = "ok"
+	case CRCError:
+		f.CRC = "error"
+	}
+	r.last = now
+
+	if r.full {
+		r.dropped++
+	}
+	r.frames[r.next] = f
+	r.next = (r.next + 1) % len(r.frames)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Frames returns the frames recorded on the given bus, oldest first, and
+// false if none has been recorded.
+func Frames(bus string) ([]Frame, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	r, ok := buses[bus]
+	if !ok {
+		return nil, false
+	}
+	if !r.full {
+		return append([]Frame(nil), r.frames[:r.next]...), true
+	}
+	return append(append([]Frame(nil), r.frames[r.next:]...), r.frames[:r.next]...), true
+}
+
+// CurrentStatus returns the state of the trace mode, with the buses sorted
+// by name.
+func CurrentStatus() Status {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	s := Status{Enabled: enabled, Size: size, Buses: []BusStatus{}}
+	for name, r := range buses {
+		n := r.next
+		if r.full {
+			n = len(r.frames)
+		}
+		s.Buses = append(s.Buses, BusStatus{Bus: name, Frames: n, Dropped: r.dropped})
+	}
+	sort.Slice(s.Buses, func(i, j int) bool { return s.Buses[i].Bus < s.Buses[j].Bus })
+	return s
+}
@@ -0,0 +1,42 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import "testing"
+
+func TestRecord(t *testing.T) {
+	SetSize(3)
+	Record("/dev/ttyS0", Tx, []byte{1}, CRCNone)
+	if _, ok := Frames("/dev/ttyS0"); ok {
+		t.Error("Frame recorded while disabled")
+	}
+
+	Enable()
+	defer Disable()
+	for i := byte(1); i <= 4; i++ {
+		Record("/dev/ttyS0", Rx, []byte{i, 0xff}, CRCOK)
+	}
+	Record("10.0.0.1:502", Tx, []byte{0}, CRCError)
+
+	frames, ok := Frames("/dev/ttyS0")
+	if !ok || len(frames) != 3 {
+		t.Fatalf("Unexpected frames %v", frames)
+	}
+	if frames[0].Data != "02ff" || frames[2].Data != "04ff" || frames[2].CRC != "ok" || frames[2].Direction != Rx {
+		t.Errorf("Unexpected frames %v", frames)
+	}
+
+	s := CurrentStatus()
+	if !s.Enabled || len(s.Buses) != 2 || s.Buses[0].Bus != "/dev/ttyS0" || s.Buses[0].Dropped != 1 || s.Buses[1].Frames != 1 {
+		t.Errorf("Unexpected status %v", s)
+	}
+
+	Clear()
+	if _, ok = Frames("/dev/ttyS0"); ok {
+		t.Error("Frames not cleared")
+	}
+}
@@ -11,20 +11,47 @@
 package device
 
 import (
+	"context"
 	"fmt"
 	"github.com/edgexfoundry/device-sdk-go/internal/scheduler"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/anomaly"
+	"github.com/edgexfoundry/device-sdk-go/internal/async"
+	"github.com/edgexfoundry/device-sdk-go/internal/autoevent"
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/capture"
 	"github.com/edgexfoundry/device-sdk-go/internal/clients"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	configLoader "github.com/edgexfoundry/device-sdk-go/internal/config"
 	"github.com/edgexfoundry/device-sdk-go/internal/controller"
+	"github.com/edgexfoundry/device-sdk-go/internal/deadletter"
+	"github.com/edgexfoundry/device-sdk-go/internal/dedup"
+	"github.com/edgexfoundry/device-sdk-go/internal/derived"
+	"github.com/edgexfoundry/device-sdk-go/internal/discovery"
+	"github.com/edgexfoundry/device-sdk-go/internal/feature"
+	"github.com/edgexfoundry/device-sdk-go/internal/federation"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
+	"github.com/edgexfoundry/device-sdk-go/internal/history"
+	"github.com/edgexfoundry/device-sdk-go/internal/i18n"
+	"github.com/edgexfoundry/device-sdk-go/internal/job"
+	"github.com/edgexfoundry/device-sdk-go/internal/metrics"
+	"github.com/edgexfoundry/device-sdk-go/internal/opstate"
+	"github.com/edgexfoundry/device-sdk-go/internal/probe"
 	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/device-sdk-go/internal/proxy"
+	"github.com/edgexfoundry/device-sdk-go/internal/statedir"
+	"github.com/edgexfoundry/device-sdk-go/internal/throttle"
+	"github.com/edgexfoundry/device-sdk-go/internal/watchdog"
 	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/device-sdk-go/pkg/publisher"
+	"github.com/edgexfoundry/device-sdk-go/pkg/signing"
+	"github.com/edgexfoundry/device-sdk-go/pkg/trace"
 	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 	"gopkg.in/mgo.v2/bson"
@@ -38,11 +65,18 @@ var (
 type Service struct {
 	svcInfo      *common.ServiceInfo
 	discovery    ds_models.ProtocolDiscovery
+	confProfile  string
+	confDir      string
 	initAttempts int
 	initialized  bool
 	stopped      bool
 	cw           *Watchers
 	asyncCh      chan *ds_models.AsyncValues
+	compatMode   bool
+	standbyStop  chan struct{}
+	electionStop chan struct{}
+	writableStop chan struct{}
+	server       *http.Server
 }
 
 func (s *Service) Name() string {
@@ -61,47 +95,140 @@ func (s *Service) AsyncReadings() bool {
 	return common.CurrentConfig.Service.EnableAsyncReadings
 }
 
+// DriverConfigs returns the settings of the Driver section of the
+// configuration, overridden by the Driver subsection of Writable.
+func (s *Service) DriverConfigs() map[string]string {
+	return common.DriverConfig()
+}
+
 // Start the device service.
 func (s *Service) Start() (err error) {
+	err = statedir.Init(common.CurrentConfig.Service.StateDir)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't create the state directory: %v", err))
+		return err
+	}
+
+	initMessages()
+
+	err = common.InitTimestamps(common.CurrentConfig.Service)
+	if err != nil {
+		common.LoggingClient.Error(err.Error())
+		return err
+	}
+
+	err = feature.Init(common.CurrentConfig.Features)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("License verification failed, licensed features disabled: %v", err))
+	}
+	common.LoggingClient.Info("Build: " + handler.VersionHandler().String())
+
+	err = proxy.Configure(common.CurrentConfig.Proxy)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't configure the outbound proxy: %v", err))
+		return err
+	}
+
 	err = clients.InitDependencyClients()
 	if err != nil {
 		return err
 	}
 
-	err = selfRegister()
+	err = initEventSigner()
 	if err != nil {
-		err = common.LoggingClient.Error("Couldn't register to metadata service")
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't initialize event signing: %v", err))
 		return err
 	}
 
-	// initialize devices, objects & profiles
-	cache.InitCache()
-	err = provision.LoadProfiles(common.CurrentConfig.Device.ProfilesDir)
+	s.initStandby()
+
+	err = initEventPublisher()
 	if err != nil {
-		err = common.LoggingClient.Error("Failed to create the pre-defined Device Profiles")
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't initialize the message queue: %v", err))
 		return err
 	}
 
-	err = provision.LoadDevices(common.CurrentConfig.DeviceList)
+	initFaults()
+
+	err = selfRegister()
 	if err != nil {
-		err = common.LoggingClient.Error("Failed to create the pre-defined Devices")
+		err = common.LoggingClient.Error("Couldn't register to metadata service")
 		return err
 	}
 
-	err = provision.LoadSchedulesAndEvents(common.CurrentConfig)
+	// initialize devices, objects & profiles
+	mode := initCache()
+	err = provisionObjects()
 	if err != nil {
-		err = common.LoggingClient.Error("Failed to create the pre-defined Schedules or Schedule Events")
 		return err
 	}
 
 	s.cw = newWatchers()
 
+	s.initLeaderElection()
+	s.initWritable()
+
+	historySize := common.CurrentConfig.Device.HistorySize
+	if !feature.Enabled(feature.History) {
+		historySize = 0
+	}
+	err = history.Init(historySize, common.CurrentConfig.Device.HistoryFile)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the command history: %v", err))
+	}
+
+	osc := common.CurrentConfig.OperatingState
+	if err = opstate.Init(osc.HistorySize, osc.HistoryFile); err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the operating state history: %v", err))
+	}
+
+	err = derived.Init(common.CurrentConfig.Device.DerivedFile)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the accumulated energies: %v", err))
+	}
+
+	err = capture.Init(common.CurrentConfig.Device.CaptureDir, common.CurrentConfig.Device.MaxCaptures)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the captures: %v", err))
+	}
+
+	trace.SetSize(common.CurrentConfig.Device.TraceSize)
+
+	err = dedup.Init(common.CurrentConfig.Device.DedupFile, common.CurrentConfig.Device.DedupSize)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the event journal: %v", err))
+	}
+
+	pc := common.CurrentConfig.Publication
+	err = deadletter.Init(pc.DeadLetterFile, pc.DeadLetterSize)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the dead letters: %v", err))
+	}
+	deadletter.SetExpiry(deadLetterExpired)
+	registerPublicationMetrics()
+
+	err = job.Init(common.CurrentConfig.Device.JobDir)
+	if err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("Couldn't create the job directory: %v", err))
+	}
+
+	err = probe.WaitAll(common.CurrentConfig.StartupProbes)
+	if err != nil {
+		return err
+	}
+
 	// initialize driver
 	if common.CurrentConfig.Service.EnableAsyncReadings {
 		s.asyncCh = make(chan *ds_models.AsyncValues, common.CurrentConfig.Service.AsyncBufferSize)
+		async.Init(time.Duration(common.CurrentConfig.Service.AsyncBatchWindow)*time.Millisecond, common.CurrentConfig.Service.AsyncBatchMaxReadings)
 		go processAsyncResults()
 	}
-	err = common.Driver.Initialize(common.LoggingClient, s.asyncCh)
+	handler.SetAsyncChannel(s.asyncCh)
+	if ci, ok := common.Driver.(ds_models.ContextInitializer); ok {
+		err = ci.InitializeWithContext(s.driverContext())
+	} else {
+		err = common.Driver.Initialize(common.LoggingClient, s.asyncCh)
+	}
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("Driver.Initialize failure: %v; exiting.", err))
 		return err
@@ -110,18 +237,287 @@ func (s *Service) Start() (err error) {
 	// Setup REST API
 	r := controller.InitRestRoutes()
 
+	provision.ScheduleEventAdded = scheduler.AddScheduleEvent
+	provision.ScheduleEventRemoved = scheduler.RemoveScheduleEvent
+	handler.CurrentSchedulerStatus = scheduler.Status
+	handler.StartAutoEvents = autoevent.StartForDevice
+	handler.StopAutoEvents = autoevent.StopForDevice
+	handler.RestartAutoEvents = autoevent.RestartForDevice
 	scheduler.StartScheduler()
+	autoevent.Start()
+	handler.StartKeepalives()
+	handler.StartRecovery()
+	opstate.Start()
+	federation.Start()
+	deadletter.Start(time.Duration(pc.ReplayInterval)*time.Millisecond, common.ReplayEvent)
+	discovery.Start(time.Duration(common.CurrentConfig.Device.DiscoveryInterval) * time.Millisecond)
+	tc := common.CurrentConfig.Throttle
+	throttle.Start(tc.CPUThreshold, tc.MemoryThreshold, time.Duration(tc.Interval)*time.Millisecond)
+	if mode == common.StartModeCold {
+		common.SetStarted(mode, true)
+		cache.Persist()
+	} else {
+		common.SetStarted(mode, false)
+		go reconcileCache()
+	}
 	http.TimeoutHandler(nil, time.Millisecond*time.Duration(s.svcInfo.Timeout), "Request timed out")
 
-	// TODO: call ListenAndServe in a goroutine
-
 	common.LoggingClient.Info(fmt.Sprintf("*Service Start() called, name=%s, version=%s", common.ServiceName, common.ServiceVersion))
-	common.LoggingClient.Error(http.ListenAndServe(common.Colon+strconv.Itoa(s.svcInfo.Port), r).Error())
+	if s.compatMode {
+		common.LoggingClient.Warn(fmt.Sprintf("Driver built against SDK API %q, running in compatibility mode with SDK API %s", common.DriverAPIVersion, ds_models.APIVersion))
+	}
+	ln, err := net.Listen("tcp", common.Colon+strconv.Itoa(s.svcInfo.Port))
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't listen on port %d: %v", s.svcInfo.Port, err))
+		return err
+	}
+	watchdog.Start(checkLiveness)
+	// Start returns once Stop has closed the listener
+	s.server = &http.Server{Handler: r}
+	if err = s.server.Serve(ln); err == http.ErrServerClosed {
+		err = nil
+	} else {
+		common.LoggingClient.Error(err.Error())
+	}
 	common.LoggingClient.Debug("*Service Start() exit")
 
 	return err
 }
 
+// initCache initializes the caches according to the configured start mode,
+// and returns the start mode which has actually been performed. A warm start
+// falls back to a cold one if the caches can't be loaded from CacheFile.
+func initCache() string {
+	mode := common.CurrentConfig.Service.StartMode
+	if mode == common.StartModeWarm || mode == common.StartModeHot {
+		file := common.CurrentConfig.Service.CacheFile
+		if file == "" {
+			common.LoggingClient.Warn("No CacheFile configured, performing a cold start")
+		} else if err := cache.InitCacheFromFile(file); err != nil {
+			common.LoggingClient.Warn(fmt.Sprintf("Couldn't load the caches from %s, performing a cold start: %v", file, err))
+		} else {
+			common.LoggingClient.Info(fmt.Sprintf("Caches loaded from %s, performing a warm start", file))
+			return common.StartModeWarm
+		}
+	}
+	cache.InitCache()
+	return common.StartModeCold
+}
+
+// provisionObjects creates the pre-defined Profiles, Devices, Value
+// Descriptors, Schedules and Schedule Events which don't exist yet.
+func provisionObjects() error {
+	err := provision.LoadProfiles(common.CurrentConfig.Device.ProfilesDir)
+	if err != nil {
+		err = common.LoggingClient.Error("Failed to create the pre-defined Device Profiles")
+		return err
+	}
+
+	// the Schedules are required by the default auto events of the Devices
+	err = provision.LoadSchedulesAndEvents(common.CurrentConfig)
+	if err != nil {
+		err = common.LoggingClient.Error("Failed to create the pre-defined Schedules or Schedule Events")
+		return err
+	}
+	provision.ApplyScheduleFrequencies(common.CurrentConfig)
+
+	err = provision.LoadDevices(common.CurrentConfig.DeviceList)
+	if err != nil {
+		err = common.LoggingClient.Error("Failed to create the pre-defined Devices")
+		return err
+	}
+	loadAutoEvents(common.CurrentConfig.DeviceList)
+
+	err = provision.LoadSelfDevice(common.CurrentConfig.SelfDevice)
+	if err != nil {
+		err = common.LoggingClient.Error("Failed to create the gateway Device")
+		return err
+	}
+
+	provision.CreateDescriptorsForAliases(common.CurrentConfig.ReadingAliases)
+	if common.CurrentConfig.Service.Tenant != "" {
+		provision.CreateStringDescriptor(common.TenantReadingName, "Tenant of the device service")
+	}
+	if p := common.CurrentConfig.Service.OriginPrecision; p != "" && p != common.PrecisionMillis {
+		provision.CreateStringDescriptor(common.PrecisionReadingName, "Precision of the origins of the event")
+	}
+	if common.CurrentConfig.Service.Timezone != "" {
+		provision.CreateStringDescriptor(common.TimezoneReadingName, "UTC offset of the devices at the time of the event")
+	}
+	if len(common.CurrentConfig.Snapshots) > 0 {
+		provision.CreateStringDescriptor(common.SnapshotReadingName, "Name of the snapshot")
+	}
+	if anomaly.Enabled() {
+		provision.CreateStringDescriptor(common.AnomalyReadingName, "Anomaly of a reading")
+	}
+	if common.EventSigner != nil {
+		provision.CreateStringDescriptor(signing.ReadingName, "Signature of the event")
+	}
+
+	return nil
+}
+
+// loadAutoEvents sets the AutoEvents of the pre-defined Devices given in
+// the configuration.
+func loadAutoEvents(devices []common.DeviceConfig) {
+	for _, dc := range devices {
+		if len(dc.AutoEvents) > 0 {
+			autoevent.SetAutoEvents(dc.Name, dc.AutoEvents)
+		}
+	}
+}
+
+// reconcileCache brings the caches loaded on a warm start in sync with Core
+// Metadata, and runs the resulting Schedule Events in the internal
+// Scheduler.
+func reconcileCache() {
+	common.LoggingClient.Info("Reconciling the caches with Core Metadata")
+	for _, schEvt := range cache.Reconcile() {
+		scheduler.AddScheduleEvent(schEvt)
+	}
+	// pre-defined Devices removed from Core Metadata meanwhile are created again
+	if err := provision.LoadDevices(common.CurrentConfig.DeviceList); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Failed to create the pre-defined Devices: %v", err))
+	}
+	if err := provision.LoadSelfDevice(common.CurrentConfig.SelfDevice); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Failed to create the gateway Device: %v", err))
+	}
+	common.SetReconciled()
+	cache.Persist()
+	common.LoggingClient.Info("Caches reconciled with Core Metadata")
+}
+
+// SetStartMode overrides the start mode given by the configuration. It must
+// be called before Start.
+func (s *Service) SetStartMode(mode string) error {
+	switch mode {
+	case common.StartModeCold, common.StartModeWarm, common.StartModeHot:
+		common.CurrentConfig.Service.StartMode = mode
+		return nil
+	}
+	return fmt.Errorf("invalid start mode: %s", mode)
+}
+
+// Restart performs a hot restart of the Service: the configuration is
+// reloaded and the pre-defined objects are provisioned again, without
+// dropping the REST listener nor the connections held by the Driver. The
+// Service, Registry, Clients and Logging settings can't be changed this way.
+func (s *Service) Restart() error {
+	if common.CurrentConfig.Service.StartMode != common.StartModeHot {
+		return fmt.Errorf("hot restart requires the %s start mode", common.StartModeHot)
+	}
+
+	config, err := configLoader.LoadConfig(false, s.confProfile, s.confDir)
+	if err != nil {
+		return err
+	}
+	config.Service = common.CurrentConfig.Service
+	config.Registry = common.CurrentConfig.Registry
+	config.Clients = common.CurrentConfig.Clients
+	config.Logging = common.CurrentConfig.Logging
+	config.Writable = common.CurrentConfig.Writable
+	common.CurrentConfig = config
+	s.svcInfo = &config.Service
+
+	common.LoggingClient.Info(fmt.Sprintf("*Service Restart() called, name=%s", common.ServiceName))
+	scheduler.StopScheduler()
+	err = provisionObjects()
+	scheduler.StartScheduler()
+	if err != nil {
+		return err
+	}
+
+	common.SetStarted(common.StartModeHot, common.CurrentStartStatus().Reconciled)
+	cache.Persist()
+	return nil
+}
+
+// initMessages sets up the message catalog of the REST API.
+func initMessages() {
+	if file := common.CurrentConfig.Service.MessageCatalog; file != "" {
+		if err := i18n.LoadCatalog(file); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("Couldn't load the message catalog %s: %v", file, err))
+		}
+	}
+	if err := i18n.SetLocale(common.CurrentConfig.Service.Locale); err != nil {
+		common.LoggingClient.Warn(fmt.Sprintf("%v, using %s", err, i18n.DefaultLocale))
+	}
+}
+
+// initEventPublisher creates the publisher of the events to the configured
+// message queue, replacing any previous one.
+func initEventPublisher() error {
+	if common.EventPublisher != nil {
+		common.EventPublisher.Close()
+		common.EventPublisher = nil
+	}
+	cfg := common.CurrentConfig.MessageQueue.Config
+	if cfg.Type == "" {
+		return nil
+	}
+
+	if cfg.Password != "" {
+		password, err := common.LookupSecret(cfg.Password)
+		if err != nil {
+			return err
+		}
+		cfg.Password = string(password)
+	}
+	p, err := publisher.New(cfg)
+	if err != nil {
+		return err
+	}
+	common.EventPublisher = p
+	return nil
+}
+
+// initEventSigner sets up the signing of events according to the [Signing]
+// configuration.
+func initEventSigner() error {
+	sc := common.CurrentConfig.Signing
+	if sc.Algorithm == "" {
+		return nil
+	}
+
+	key, err := common.LookupSecret(sc.Key)
+	if err != nil {
+		return err
+	}
+	switch sc.Algorithm {
+	case signing.AlgorithmHMAC:
+		common.EventSigner = signing.NewHMAC(key)
+	case signing.AlgorithmECDSA:
+		common.EventSigner, err = signing.NewECDSASigner(key)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm: %s", sc.Algorithm)
+	}
+	return err
+}
+
+// initStandby sets the initial standby mode, and follows the StandbyKey of
+// the registry, if configured, until the Service is stopped.
+func (s *Service) initStandby() {
+	if common.SetStandby(s.svcInfo.Standby) && s.svcInfo.Standby {
+		common.LoggingClient.Info("Starting in standby mode")
+	}
+	if !common.UseRegistry || s.svcInfo.StandbyKey == "" || configLoader.RegistryClient == nil {
+		return
+	}
+
+	s.standbyStop = make(chan struct{})
+	go configLoader.RegistryClient.WatchKey(s.svcInfo.StandbyKey, s.standbyStop, func(value string) {
+		if value == "" {
+			return
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			common.LoggingClient.Warn(fmt.Sprintf("Invalid value %q of the standby key %s", value, s.svcInfo.StandbyKey))
+			return
+		}
+		handler.StandbyHandler(enabled)
+	})
+}
+
 func selfRegister() error {
 	common.LoggingClient.Debug("Trying to find Device Service: " + common.ServiceName)
 
@@ -152,10 +548,14 @@ func createNewDeviceService() (models.DeviceService, error) {
 		return models.DeviceService{}, err
 	}
 	millis := time.Now().UnixNano() / int64(time.Millisecond)
+	labels := append([]string(nil), svc.svcInfo.Labels...)
+	if svc.svcInfo.Tenant != "" {
+		labels = append(labels, common.TenantLabelPrefix+svc.svcInfo.Tenant)
+	}
 	ds := models.DeviceService{
 		Service: models.Service{
 			Name:           common.ServiceName,
-			Labels:         svc.svcInfo.Labels,
+			Labels:         labels,
 			OperatingState: "ENABLED",
 			Addressable:    *addr,
 		},
@@ -196,7 +596,7 @@ func makeNewAddressable() (*models.Addressable, error) {
 				Protocol:   common.HttpProto,
 				Address:    svc.svcInfo.Host,
 				Port:       svc.svcInfo.Port,
-				Path:       common.APICallbackRoute,
+				Path:       common.APIRoute(common.APICallbackRoute),
 			}
 			id, err := common.AddressableClient.Add(&addr)
 			if err != nil {
@@ -218,14 +618,125 @@ func makeNewAddressable() (*models.Addressable, error) {
 	return &addr, nil
 }
 
-// Stop shuts down the Service
+// Stop shuts down the Service. It carries on when a step fails, and returns
+// the failures of the driver, the registry, the HTTP server and the state
+// saved.
 func (s *Service) Stop(force bool) error {
+	var failures []string
+	failed := func(msg string) {
+		common.LoggingClient.Error(msg)
+		failures = append(failures, msg)
+	}
+
 	s.stopped = true
-	common.Driver.Stop(force)
+	if s.standbyStop != nil {
+		close(s.standbyStop)
+		s.standbyStop = nil
+	}
+	if s.electionStop != nil {
+		close(s.electionStop)
+		s.electionStop = nil
+	}
+	if s.writableStop != nil {
+		close(s.writableStop)
+		s.writableStop = nil
+	}
+	timeout := common.CurrentConfig.Service.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = common.DefaultShutdownTimeout
+	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+
+	watchdog.Stop()
+	job.Stop()
 	scheduler.StopScheduler()
+	autoevent.Stop()
+	handler.StopKeepalives()
+	handler.StopRecovery()
+	opstate.Stop()
+	federation.Stop()
+	discovery.Stop()
+	deadletter.Stop()
+	s.drainAsync(deadline)
+	if err := common.Driver.Stop(force); err != nil {
+		failed(fmt.Sprintf("Couldn't stop the driver: %v", err))
+	}
+	if common.UseRegistry && configLoader.RegistryClient != nil {
+		if err := configLoader.RegistryClient.Deregister(common.ServiceName); err != nil {
+			failed(fmt.Sprintf("Couldn't deregister from the registry: %v", err))
+		}
+	}
+	if s.server != nil {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		if err := s.server.Shutdown(ctx); err != nil {
+			failed(fmt.Sprintf("Requests still in progress on shutdown: %v", err))
+			s.server.Close()
+		}
+		cancel()
+	}
+	throttle.Stop()
+	cache.Persist()
+	if err := history.Save(); err != nil {
+		failed(fmt.Sprintf("Couldn't save the command history: %v", err))
+	}
+	if err := opstate.Save(); err != nil {
+		failed(fmt.Sprintf("Couldn't save the operating state history: %v", err))
+	}
+	if err := derived.Save(); err != nil {
+		failed(fmt.Sprintf("Couldn't save the accumulated energies: %v", err))
+	}
+	if common.EventPublisher != nil {
+		common.EventPublisher.Close()
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("Stop: %s", strings.Join(failures, "; "))
+	}
 	return nil
 }
 
+// registerPublicationMetrics reports the outcomes of the pushes of events to
+// Core Data, and the dead letters, as metrics.
+func registerPublicationMetrics() {
+	var r metrics.Registrar
+	r.RegisterGauge("events.delivered", func() float64 { return float64(common.CurrentPublicationStats().Delivered) })
+	r.RegisterGauge("events.retried", func() float64 { return float64(common.CurrentPublicationStats().Retried) })
+	r.RegisterGauge("events.uncertain", func() float64 { return float64(common.CurrentPublicationStats().Uncertain) })
+	r.RegisterGauge("events.deadLettered", func() float64 { return float64(common.CurrentPublicationStats().DeadLettered) })
+	r.RegisterGauge("events.failed", func() float64 { return float64(common.CurrentPublicationStats().Failed) })
+	r.RegisterGauge("deadLetters.buffered", func() float64 { return float64(deadletter.CurrentStats().Buffered) })
+	r.RegisterGauge("deadLetters.replayed", func() float64 { return float64(deadletter.CurrentStats().Replayed) })
+	r.RegisterGauge("deadLetters.dropped", func() float64 { return float64(deadletter.CurrentStats().Dropped) })
+	r.RegisterGauge("deadLetters.expired", func() float64 { return float64(deadletter.CurrentStats().Expired) })
+}
+
+// deadLetterExpired applies the max age policy of the Publication settings
+// to a buffered reading, with the labels of its Device.
+func deadLetterExpired(event *models.Event, reading models.Reading, now time.Time) bool {
+	d, _ := cache.Devices().ForName(event.Device)
+	return common.ReadingExpired(reading, d.Labels, now)
+}
+
+// drainAsync processes the asynchronous readings left in the channel, until
+// the deadline, and pushes the pending batches.
+func (s *Service) drainAsync(deadline time.Time) {
+	if s.asyncCh == nil {
+		return
+	}
+drain:
+	for time.Now().Before(deadline) {
+		select {
+		case acv := <-s.asyncCh:
+			async.Process(acv)
+		default:
+			break drain
+		}
+	}
+	if n := len(s.asyncCh); n > 0 {
+		common.LoggingClient.Warn(fmt.Sprintf("Dropping %d asynchronous readings on shutdown", n))
+	}
+	async.Flush()
+}
+
 // NewService create a new device service instance with the given
 // version number, config profile, config directory, whether to use registry, and Driver, which cannot be nil.
 // Note - this function is a singleton, if called more than once,
@@ -241,11 +752,11 @@ func NewService(serviceName string, serviceVersion string, confProfile string, c
 		return nil, err
 	}
 	common.ServiceName = serviceName
+	common.UseRegistry = useRegistry
 
 	config, err := configLoader.LoadConfig(useRegistry, confProfile, confDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error loading config file: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("NewService: error loading config file: %v\n", err)
 	}
 	common.CurrentConfig = config
 
@@ -260,7 +771,15 @@ func NewService(serviceName string, serviceVersion string, confProfile string, c
 		return nil, err
 	}
 
-	svc = &Service{}
+	if vd, ok := proto.(ds_models.VersionedDriver); ok {
+		common.DriverAPIVersion = vd.APIVersion()
+	}
+	compat, err := ds_models.CheckAPIVersion(common.DriverAPIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("NewService: %v\n", err)
+	}
+
+	svc = &Service{confProfile: confProfile, confDir: confDir, compatMode: compat}
 	svc.svcInfo = &config.Service
 	common.Driver = proto
 
@@ -271,3 +790,27 @@ func NewService(serviceName string, serviceVersion string, confProfile string, c
 func RunningService() *Service {
 	return svc
 }
+
+// driverStateDir is the directory of the driver within the state directory.
+const driverStateDir = "driver"
+
+// driverContext returns the context initializing the driver.
+func (s *Service) driverContext() ds_models.DriverContext {
+	ctx := ds_models.DriverContext{
+		Logger:      common.LoggingClient,
+		AsyncCh:     s.asyncCh,
+		Config:      common.DriverConfig(),
+		Metrics:     metrics.Registrar{},
+		Scheduler:   scheduler.DriverScheduler{},
+		Discovered:  discovery.Results(),
+		Credentials: common.CredentialsProvider{},
+	}
+	// the driver has its own directory, not to clash with the state of the DS
+	if statedir.Path("") != "" {
+		ctx.StateDir = statedir.Path(driverStateDir)
+		if err := os.MkdirAll(ctx.StateDir, 0755); err != nil {
+			common.LoggingClient.Warn(fmt.Sprintf("Couldn't create the driver state directory: %v", err))
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// warmStart guards the start loading the caches, which can only be
+// initialized once.
+var warmStart sync.Once
+
+func TestSetStartMode(t *testing.T) {
+	common.CurrentConfig = &common.Config{}
+	s := &Service{}
+	for _, mode := range []string{common.StartModeCold, common.StartModeWarm, common.StartModeHot} {
+		if err := s.SetStartMode(mode); err != nil || common.CurrentConfig.Service.StartMode != mode {
+			t.Errorf("SetStartMode(%s) = %v, start mode %s", mode, err, common.CurrentConfig.Service.StartMode)
+		}
+	}
+	if err := s.SetStartMode("lukewarm"); err == nil || common.CurrentConfig.Service.StartMode != common.StartModeHot {
+		t.Errorf("SetStartMode(lukewarm) = %v, start mode %s", err, common.CurrentConfig.Service.StartMode)
+	}
+}
+
+func TestRestartRequiresHotStartMode(t *testing.T) {
+	common.CurrentConfig = &common.Config{}
+	common.CurrentConfig.Service.StartMode = common.StartModeWarm
+	if err := (&Service{}).Restart(); err == nil {
+		t.Error("Hot restart allowed in the warm start mode")
+	}
+}
+
+func TestInitCache(t *testing.T) {
+	common.LoggingClient = logger.NewClient("service_test", false, "", "DEBUG")
+	dir, err := ioutil.TempDir("", "service_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	snap := cache.Snapshot{Devices: []models.Device{{Id: bson.NewObjectId(), Name: "meter"}}}
+	contents, _ := json.Marshal(snap)
+	file := filepath.Join(dir, "cache.json")
+	if err = ioutil.WriteFile(file, contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// a hot start loads the caches like a warm one
+	warmStart.Do(func() {
+		common.CurrentConfig = &common.Config{}
+		common.CurrentConfig.Service.StartMode = common.StartModeHot
+		common.CurrentConfig.Service.CacheFile = file
+		if mode := initCache(); mode != common.StartModeWarm {
+			t.Errorf("Hot start with a CacheFile performed a %s start", mode)
+		}
+		if _, ok := cache.Devices().ForName("meter"); !ok {
+			t.Error("Device meter not loaded from the CacheFile")
+		}
+	})
+
+	// the caches being initialized, the cold starts don't query Core Metadata
+	tests := []struct {
+		name      string
+		startMode string
+		cacheFile string
+	}{
+		{"Cold", common.StartModeCold, file},
+		{"WarmWithoutCacheFile", common.StartModeWarm, ""},
+		{"WarmWithMissingCacheFile", common.StartModeWarm, filepath.Join(dir, "missing.json")},
+		{"HotWithMissingCacheFile", common.StartModeHot, filepath.Join(dir, "missing.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.CurrentConfig = &common.Config{}
+			common.CurrentConfig.Service.StartMode = tt.startMode
+			common.CurrentConfig.Service.CacheFile = tt.cacheFile
+			if mode := initCache(); mode != common.StartModeCold {
+				t.Errorf("Performed a %s start", mode)
+			}
+		})
+	}
+}
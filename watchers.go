@@ -1,6 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2017-2018 Canonical Ltd
+// Copyright (C) 2018 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -9,11 +10,22 @@ package device
 import (
 	"sync"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
 type Watchers struct {
 	devices map[string]models.Device
+	matcher *provision.Matcher
+}
+
+// WatcherMatch is a discovered Device matched by a provision watcher, with
+// the Device Profile and name it would be provisioned with.
+type WatcherMatch struct {
+	Watcher    string
+	Profile    string
+	DeviceName string
 }
 
 var (
@@ -25,8 +37,17 @@ var (
 func newWatchers() *Watchers {
 
 	wcOnce.Do(func() {
-		watchers = &Watchers{}
+		watchers = &Watchers{matcher: provision.LoadWatchers(common.CurrentConfig.Watchers)}
 	})
 
 	return watchers
 }
+
+// MatchWatchers runs the identifiers of a discovered Device, e.g. returned
+// by the Discover function of a driver, through the provision watchers of
+// the configuration, whose identifiers are regular expressions. It returns
+// the match of the first watcher, by name, matching them.
+func (s *Service) MatchWatchers(identifiers map[string]string) (WatcherMatch, bool, error) {
+	m, ok, err := newWatchers().matcher.Match(identifiers)
+	return WatcherMatch(m), ok, err
+}
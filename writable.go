@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2018 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/clients"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	configLoader "github.com/edgexfoundry/device-sdk-go/internal/config"
+	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/device-sdk-go/internal/scheduler"
+	ds_models "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// initWritable follows the Writable section of the configuration held by the
+// registry, if used, until the Service is stopped.
+func (s *Service) initWritable() {
+	if !common.UseRegistry || configLoader.RegistryClient == nil {
+		return
+	}
+
+	s.writableStop = make(chan struct{})
+	go configLoader.WatchWritable(s.confProfile, s.writableStop, applyWritable)
+}
+
+// applyWritable applies a change of the Writable section.
+func applyWritable(writable common.WritableInfo) {
+	previous := common.CurrentConfig.Writable
+	common.CurrentConfig.Writable = writable
+
+	if writable.LogLevel != previous.LogLevel {
+		clients.ResetLoggingClient()
+		common.LoggingClient.Info(fmt.Sprintf("Log level changed to %q", writable.LogLevel))
+	}
+	if provision.ApplyScheduleFrequencies(common.CurrentConfig) {
+		scheduler.RestartScheduler()
+	}
+	if !reflect.DeepEqual(writable.Driver, previous.Driver) {
+		updateDriverConfig()
+	}
+}
+
+// updateDriverConfig passes the updated settings of the driver to it, if it
+// applies them at runtime.
+func updateDriverConfig() {
+	u, ok := common.Driver.(ds_models.ConfigUpdater)
+	if !ok {
+		common.LoggingClient.Warn("Driver settings changed, the driver applies them after a restart")
+		return
+	}
+	if err := u.UpdateConfig(common.DriverConfig()); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Driver rejected the updated settings: %v", err))
+		return
+	}
+	common.LoggingClient.Info("Driver settings updated")
+}